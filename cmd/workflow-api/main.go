@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/cfrs2005/GoWorkFlow/internal/artifact"
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
 	"github.com/cfrs2005/GoWorkFlow/internal/config"
 	"github.com/cfrs2005/GoWorkFlow/internal/engine"
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
 	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/grpcserver"
 	"github.com/cfrs2005/GoWorkFlow/internal/handler"
+	"github.com/cfrs2005/GoWorkFlow/internal/metrics"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/repository"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
+	"github.com/cfrs2005/GoWorkFlow/migrations"
 	"github.com/cfrs2005/GoWorkFlow/pkg/database"
 	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
 )
@@ -20,10 +32,17 @@ import (
 func main() {
 	// 加载配置
 	cfg := config.Load()
+	if err := logger.Setup(os.Stdout, cfg.Log.Format); err != nil {
+		log.Fatalf("Failed to configure logger: %v", err)
+	}
+	if err := logger.SetLevel(cfg.Log.Level); err != nil {
+		log.Fatalf("Failed to configure logger: %v", err)
+	}
 	logger.Infof("Starting workflow API server...")
 
 	// 连接数据库
 	db, err := database.NewDB(database.Config{
+		Driver:          cfg.Database.Driver,
 		DSN:             cfg.Database.GetDSN(),
 		MaxOpenConns:    100,
 		MaxIdleConns:    10,
@@ -35,6 +54,42 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected successfully")
 
+	// SQLite、PostgreSQL 执行内置迁移；MySQL 的迁移手动导入，Migrate 不做任何操作
+	if cfg.Database.AutoMigrate {
+		applied, err := db.Migrate(migrations.FS)
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		for _, version := range applied {
+			logger.Infof("Applied migration %s", version)
+		}
+	}
+
+	// 子命令：create-api-key、grant-role 用于在启用认证前签发首个 API Key 并授予管理员角色
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "create-api-key":
+			if err := runCreateAPIKey(db.DB, os.Args[2:]); err != nil {
+				log.Fatalf("Failed to create api key: %v", err)
+			}
+			return
+		case "grant-role":
+			if err := runGrantRole(db.DB, os.Args[2:]); err != nil {
+				log.Fatalf("Failed to grant role: %v", err)
+			}
+			return
+		}
+	}
+
+	// 初始化追踪
+	if exporter, err := newTraceExporter(cfg.Tracing); err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	} else if exporter != nil {
+		shutdown := tracing.Init(exporter)
+		defer shutdown(context.Background())
+		logger.Infof("Tracing enabled, exporter: %s", cfg.Tracing.Exporter)
+	}
+
 	// 注册任务执行器
 	logger.Info("Registering task executors...")
 	registerExecutors()
@@ -46,15 +101,59 @@ func main() {
 	flowTaskRepo := repository.NewFlowTaskRepository(db.DB)
 	jobRepo := repository.NewJobRepository(db.DB)
 	jobTaskRepo := repository.NewJobTaskRepository(db.DB)
+	jobTaskLogRepo := repository.NewJobTaskLogRepository(db.DB)
 	jobContextRepo := repository.NewJobContextRepository(db.DB)
+	jobBatchRepo := repository.NewJobBatchRepository(db.DB)
+	artifactRepo := repository.NewArtifactRepository(db.DB)
+	executionLogRepo := repository.NewExecutionLogRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	roleBindingRepo := repository.NewRoleBindingRepository(db.DB)
+	authorizer := service.NewAuthorizer(roleBindingRepo)
+	retentionRepo := repository.NewRetentionRepository(db.DB)
+	projectRepo := repository.NewProjectRepository(db.DB)
+	projectSecretRepo := repository.NewProjectSecretRepository(db.DB)
+	projectService := service.NewProjectService(projectRepo, projectSecretRepo, authorizer)
+
+	// 初始化制品存储
+	artifactStore, err := newArtifactStore(cfg.Artifact)
+	if err != nil {
+		log.Fatalf("Failed to initialize artifact store: %v", err)
+	}
+	logger.Infof("Artifact store backend: %s", artifactStore.Backend())
+	artifactService := service.NewArtifactService(artifactStore, artifactRepo)
+	executionLogService := service.NewExecutionLogService(executionLogRepo, jobTaskRepo)
+
+	// 初始化事件总线
+	eventBus := events.NewBus()
+
+	// 初始化指标
+	appMetrics := metrics.New()
+	appMetrics.ConsumeEvents(context.Background(), eventBus)
+	appMetrics.RegisterDBStats(db.DB)
+	appMetrics.RegisterTaskGauges(
+		func() (float64, error) {
+			n, err := jobTaskRepo.CountByStatus(models.JobStatusRunning, models.JobTaskStatusPending)
+			return float64(n), err
+		},
+		func() (float64, error) {
+			n, err := jobTaskRepo.CountByStatus(models.JobStatusRunning, models.JobTaskStatusRunning)
+			return float64(n), err
+		},
+	)
 
 	// 初始化工作流引擎
 	workflowEngine := engine.NewWorkflowEngine(
 		db.DB,
 		jobRepo,
 		jobTaskRepo,
+		jobTaskLogRepo,
 		flowRepo,
 		flowTaskRepo,
+		eventBus,
+		jobContextRepo,
+		jobBatchRepo,
 	)
 
 	// 初始化服务层
@@ -65,7 +164,11 @@ func main() {
 		flowTaskRepo,
 		jobRepo,
 		jobTaskRepo,
+		jobTaskLogRepo,
 		workflowEngine,
+		authorizer,
+		jobBatchRepo,
+		jobContextRepo,
 	)
 
 	// 初始化任务执行服务
@@ -74,22 +177,132 @@ func main() {
 		jobTaskRepo,
 		jobContextRepo,
 		taskRepo,
+		flowTaskRepo,
 		workflowEngine,
+		artifactService,
+		executionLogService,
+		appMetrics,
+		authorizer,
+		projectService,
+	)
+
+	// 补记作业根 Span
+	service.TraceJobs(context.Background(), eventBus, jobRepo)
+
+	// 初始化 Webhook 服务并启动投递
+	webhookService := service.NewWebhookService(
+		webhookRepo,
+		webhookDeliveryRepo,
+		flowRepo,
+		authorizer,
+		eventBus,
+		cfg.Webhook.MaxAttempts,
+		cfg.Webhook.RetryBase,
+		cfg.Webhook.Timeout,
 	)
+	webhookService.Start(context.Background())
+
+	// 初始化作业保留服务并启动后台清理
+	retentionService := service.NewRetentionService(
+		db.DB,
+		retentionRepo,
+		flowRepo,
+		jobRepo,
+		jobTaskLogRepo,
+		jobContextRepo,
+		artifactService,
+		authorizer,
+		cfg.Retention.Interval,
+		cfg.Retention.BatchSize,
+		cfg.Retention.ArchiveDir,
+		"./reports",
+	)
+	retentionService.Start(context.Background())
+
+	// 初始化 API Key 服务
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
 
 	// 设置路由
-	router := handler.NewRouter(workflowService, jobContextRepo, taskExecutorService)
+	router := handler.NewRouter(workflowService, jobContextRepo, taskExecutorService, artifactService, executionLogService, eventBus, webhookService, apiKeyService, authorizer, projectService, retentionService)
 	mux := router.Setup()
+	mux.Handle("/metrics", appMetrics.Handler())
+
+	var h http.Handler = handler.ProjectScope(projectService, tracing.Middleware(mux, appMetrics.Middleware(mux)))
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		var verifier *auth.JWTVerifier
+		if cfg.Auth.JWTSecret != "" || cfg.Auth.JWKSURL != "" {
+			verifier = auth.NewJWTVerifier(auth.JWTConfig{
+				Secret:        cfg.Auth.JWTSecret,
+				JWKSURL:       cfg.Auth.JWKSURL,
+				Issuer:        cfg.Auth.JWTIssuer,
+				Audience:      cfg.Auth.JWTAudience,
+				UserClaim:     cfg.Auth.JWTUserClaim,
+				AllowNoExpiry: cfg.Auth.JWTAllowNoExp,
+			})
+		}
+		authenticator = auth.NewAuthenticator(apiKeyRepo, verifier)
+		h = authenticator.Middleware(h)
+		logger.Infof("Authentication enabled, jwt: %t", verifier != nil)
+	}
+
+	// 启动 gRPC 服务，与 REST 接口共用服务层
+	if cfg.Server.GRPCPort != 0 {
+		grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPCPort)
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s: %v", grpcAddr, err)
+		}
+		grpcServer := grpcserver.NewServer(workflowService, projectService, eventBus, authenticator)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
 
 	// 启动服务器
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	logger.Infof("Server listening on %s", addr)
 
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	h = handler.CORS(cfg.Server.CORSOrigins, handler.MaxBodySize(cfg.Server.MaxBodyBytes, h))
+	if err := http.ListenAndServe(addr, handler.RequestLogger(handler.Recovery(h))); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
 
+// runCreateAPIKey 为指定用户签发 API Key 并打印明文（只显示一次）
+func runCreateAPIKey(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("create-api-key", flag.ExitOnError)
+	userID := fs.Int64("user", 0, "owner user id")
+	name := fs.String("name", "bootstrap", "key name")
+	expiresIn := fs.Duration("expires-in", 0, "validity, e.g. 720h; 0 means never expires")
+	fs.Parse(args)
+
+	key, err := service.NewAPIKeyService(repository.NewAPIKeyRepository(db)).Create(*userID, *name, *expiresIn)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("API key %d (%s) created for user %d:\n%s\n", key.ID, key.Prefix, key.UserID, key.Key)
+	return nil
+}
+
+// runGrantRole 为用户授予全局或流程级角色
+func runGrantRole(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("grant-role", flag.ExitOnError)
+	userID := fs.Int64("user", 0, "user id")
+	role := fs.String("role", service.RoleAdmin, "role name")
+	flowID := fs.Int64("flow", 0, "flow id; 0 grants the role globally")
+	fs.Parse(args)
+
+	binding := &models.RoleBinding{UserID: *userID, Role: *role, FlowID: *flowID}
+	if err := service.NewAuthorizer(repository.NewRoleBindingRepository(db)).Grant(context.Background(), binding); err != nil {
+		return err
+	}
+	fmt.Printf("Role %q granted to user %d (flow %d)\n", binding.Role, binding.UserID, binding.FlowID)
+	return nil
+}
+
 // registerExecutors 注册所有任务执行器
 func registerExecutors() {
 	// 获取 BigModel API Key（从环境变量）
@@ -104,3 +317,36 @@ func registerExecutors() {
 	executor.RegisterExecutor(executor.NewBigModelExecutor(apiKey))
 	executor.RegisterExecutor(executor.NewHTMLReportExecutor("./reports"))
 }
+
+// newArtifactStore 根据配置创建制品存储后端
+func newArtifactStore(cfg config.ArtifactConfig) (artifact.Store, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return artifact.NewLocalStore(cfg.Dir)
+	case "s3":
+		return artifact.NewS3Store(artifact.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Prefix:    cfg.S3Prefix,
+		})
+	default:
+		return nil, fmt.Errorf("unknown artifact backend: %s", cfg.Backend)
+	}
+}
+
+// newTraceExporter 根据配置创建追踪导出器，未启用时返回 nil
+func newTraceExporter(cfg config.TracingConfig) (tracing.Exporter, error) {
+	switch cfg.Exporter {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return tracing.NewOTLPExporter(cfg.OTLPEndpoint, cfg.OTLPHeaders, cfg.ServiceName), nil
+	case "stdout":
+		return tracing.NewStdoutExporter(), nil
+	default:
+		return nil, fmt.Errorf("unknown traces exporter: %s", cfg.Exporter)
+	}
+}
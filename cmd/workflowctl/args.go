@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseIDs 将位置参数解析为 ID，个数须与 names 一致
+func parseIDs(args []string, names ...string) ([]int64, error) {
+	if len(args) != len(names) {
+		return nil, fmt.Errorf("expected %s", strings.Join(names, " "))
+	}
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		v, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", names[i], arg)
+		}
+		ids[i] = v
+	}
+	return ids, nil
+}
+
+// keyValues 可重复的 key=value 标志，值按 JSON 解析，解析失败时作为字符串
+type keyValues map[string]interface{}
+
+func (kv keyValues) String() string {
+	return ""
+}
+
+func (kv keyValues) Set(s string) error {
+	key, raw, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	kv[key] = parseValue(raw)
+	return nil
+}
+
+// parseValue 将 42、true、{"a":1} 等解析为对应 JSON 类型，其余视为字符串
+func parseValue(raw string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		return v
+	}
+	return raw
+}
+
+// parseKeyValues 解析位置参数形式的 key=value 列表
+func parseKeyValues(args []string) (map[string]interface{}, error) {
+	kv := keyValues{}
+	for _, arg := range args {
+		if err := kv.Set(arg); err != nil {
+			return nil, err
+		}
+	}
+	return kv, nil
+}
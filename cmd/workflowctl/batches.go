@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+var batchCommands = map[string]command{
+	"create": {
+		usage: "-flow <flow-id> -f <rows.csv|rows.json>",
+		help:  "create one job per input row in a single transaction, optionally auto-executing them",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			flowID := fs.Int64("flow", 0, "flow id")
+			file := fs.String("f", "", "CSV with a header row, or JSON array of objects; job_name sets the job name, other columns are flow inputs (- for stdin)")
+			format := fs.String("format", "", "csv or json (default: by file extension, json for stdin)")
+			name := fs.String("name", "", "batch name (default: <flow name> <time>)")
+			auto := fs.Bool("auto", false, "start the jobs and auto-execute them on the server")
+			concurrency := fs.Int("concurrency", 0, "max jobs auto-executed at once (default: server default)")
+			wait := fs.Bool("wait", false, "wait for all jobs to finish (use with -auto)")
+			timeout := fs.Duration("timeout", 0, "give up waiting after this duration (0: no limit)")
+			return func(a *app, args []string) error {
+				if *flowID <= 0 {
+					return errors.New("-flow is required")
+				}
+				if *file == "" {
+					return errors.New("-f is required")
+				}
+				rows, err := readBatchRows(*file, *format)
+				if err != nil {
+					return err
+				}
+
+				detail, err := a.client.CreateJobBatch(a.ctx, client.CreateJobBatchRequest{
+					FlowID:      *flowID,
+					Name:        *name,
+					Rows:        rows,
+					AutoExecute: *auto,
+					Concurrency: *concurrency,
+				})
+				if err != nil {
+					return err
+				}
+				if !*wait {
+					if a.output == "json" {
+						return a.printJSON(detail)
+					}
+					b := detail.Batch
+					fmt.Fprintf(a.out, "Batch %d: %s (%d jobs, auto-execute: %t, concurrency: %d)\n\n",
+						b.ID, b.Name, b.TotalJobs, b.AutoExecute, b.Concurrency)
+					return a.printJobs(detail.Jobs)
+				}
+				return a.waitBatch(detail.Batch.ID, *timeout)
+			}
+		},
+	},
+	"get": {
+		usage: "<batch-id>",
+		help:  "show a batch and how many of its jobs are in each status",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "batch-id")
+				if err != nil {
+					return err
+				}
+				summary, err := a.client.GetJobBatch(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printBatchSummary(summary)
+			}
+		},
+	},
+	"jobs": {
+		usage: "<batch-id>",
+		help:  "list the jobs of a batch",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			lf := addListFlags(fs)
+			status := fs.String("status", "", "comma-separated statuses, e.g. failed,cancelled")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "batch-id")
+				if err != nil {
+					return err
+				}
+				q := client.JobQuery{ListOptions: lf.options(), Filter: lf.filter(), Statuses: jobStatuses(*status), BatchID: ids[0]}
+				return a.listJobs(q, lf.all)
+			}
+		},
+	},
+	"cancel": {
+		usage: "<batch-id>",
+		help:  "cancel all pending and running jobs of a batch",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			reason := fs.String("reason", "", "why the jobs are cancelled")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "batch-id")
+				if err != nil {
+					return err
+				}
+				result, err := a.client.CancelJobBatch(a.ctx, ids[0], *reason)
+				if err != nil {
+					return err
+				}
+				return a.printBatchResult(result, "cancelled")
+			}
+		},
+	},
+	"retry": {
+		usage: "<batch-id>",
+		help:  "retry the failed task of every failed job in a batch",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "batch-id")
+				if err != nil {
+					return err
+				}
+				result, err := a.client.RetryFailedJobBatch(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printBatchResult(result, "retried")
+			}
+		},
+	},
+	"wait": {
+		usage: "<batch-id>",
+		help:  "wait for all jobs of a batch to finish; exits non-zero if any failed or was cancelled",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			timeout := fs.Duration("timeout", 0, "give up after this duration (0: no limit)")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "batch-id")
+				if err != nil {
+					return err
+				}
+				return a.waitBatch(ids[0], *timeout)
+			}
+		},
+	},
+}
+
+// readBatchRows 读取 CSV 或 JSON 数组形式的输入行
+func readBatchRows(path, format string) ([]map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format = "json"
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			format = "csv"
+		}
+	}
+	switch format {
+	case "json":
+		var rows []map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&rows); err != nil {
+			return nil, fmt.Errorf("parse %s: expected a JSON array of objects: %w", path, err)
+		}
+		return rows, nil
+	case "csv":
+		return parseCSVRows(data)
+	}
+	return nil, fmt.Errorf("unknown -format %q, expected csv or json", format)
+}
+
+// parseCSVRows 解析带列名的 CSV，空单元格视为未提供
+func parseCSVRows(data []byte) ([]map[string]interface{}, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV header row is required")
+	}
+
+	header := records[0]
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				row[strings.TrimSpace(header[i])] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// waitBatch 等待批次中所有作业结束并输出统计，存在失败或取消的作业时返回错误
+func (a *app) waitBatch(batchID int64, timeout time.Duration) error {
+	ctx := a.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	summary, err := a.client.WaitForJobBatch(ctx, batchID, 0)
+	if summary != nil {
+		if perr := a.printBatchSummary(summary); perr != nil {
+			return perr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if n := summary.Counts[client.JobStatusFailed] + summary.Counts[client.JobStatusCancelled]; n > 0 {
+		return fmt.Errorf("%d job(s) of batch %d failed or were cancelled", n, batchID)
+	}
+	return nil
+}
+
+func (a *app) printBatchSummary(summary *client.JobBatchSummary) error {
+	if a.output == "json" {
+		return a.printJSON(summary)
+	}
+	b := summary.Batch
+	fmt.Fprintf(a.out, "Batch %d: %s\nFlow: %d  Jobs: %d  Auto-execute: %t  Concurrency: %d  Created: %s  Finished: %t\n\n",
+		b.ID, b.Name, b.FlowID, b.TotalJobs, b.AutoExecute, b.Concurrency, timestamp(b.CreatedAt), summary.Finished)
+
+	statuses := []client.JobStatus{client.JobStatusPending, client.JobStatusRunning, client.JobStatusCompleted,
+		client.JobStatusFailed, client.JobStatusCancelled}
+	rows := make([][]string, len(statuses))
+	for i, s := range statuses {
+		rows[i] = []string{string(s), fmt.Sprint(summary.Counts[s])}
+	}
+	return a.print(summary, []string{"STATUS", "JOBS"}, rows)
+}
+
+// printBatchResult 输出批次操作的结果，逐个列出处理失败的作业
+func (a *app) printBatchResult(result *client.JobBatchResult, verb string) error {
+	if a.output == "json" {
+		return a.printJSON(result)
+	}
+	fmt.Fprintf(a.out, "%d job(s) %s\n", len(result.JobIDs), verb)
+	jobIDs := make([]string, 0, len(result.Errors))
+	for jobID := range result.Errors {
+		jobIDs = append(jobIDs, jobID)
+	}
+	// 作业 ID 按数值排序
+	sort.Slice(jobIDs, func(i, j int) bool {
+		if len(jobIDs[i]) != len(jobIDs[j]) {
+			return len(jobIDs[i]) < len(jobIDs[j])
+		}
+		return jobIDs[i] < jobIDs[j]
+	})
+	for _, jobID := range jobIDs {
+		fmt.Fprintf(a.out, "job %s: %s\n", jobID, result.Errors[jobID])
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const sharedScope = "shared"
+
+var contextCommands = map[string]command{
+	"dump": {
+		usage: "<job-id>",
+		help:  "print the job context as JSON (all scopes, or one with -scope)",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			scope := fs.String("scope", "", "shared or task:<task name>")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				if *scope != "" {
+					values, err := a.client.GetJobContextScope(a.ctx, ids[0], *scope)
+					if err != nil {
+						return err
+					}
+					return a.printJSON(values)
+				}
+				jc, err := a.client.GetJobContext(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printJSON(jc)
+			}
+		},
+	},
+	"set": {
+		usage: "<job-id> key=value ...",
+		help:  "set job context keys; values are parsed as JSON when possible",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			scope := fs.String("scope", sharedScope, "shared or task:<task name>")
+			return func(a *app, args []string) error {
+				if len(args) < 2 {
+					return errors.New("expected <job-id> key=value ...")
+				}
+				ids, err := parseIDs(args[:1], "job-id")
+				if err != nil {
+					return err
+				}
+				values, err := parseKeyValues(args[1:])
+				if err != nil {
+					return err
+				}
+				if err := a.client.UpdateJobContext(a.ctx, ids[0], *scope, values); err != nil {
+					return err
+				}
+				return a.printDone("set %d key(s) in %s context of job %d", len(values), *scope, ids[0])
+			}
+		},
+	},
+	"edit": {
+		usage: "<job-id>",
+		help:  "edit one context scope in $EDITOR and save the changed keys",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			scope := fs.String("scope", sharedScope, "shared or task:<task name>")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				before, err := a.client.GetJobContextScope(a.ctx, ids[0], *scope)
+				if err != nil {
+					return err
+				}
+				after, err := editJSON(before)
+				if err != nil {
+					return err
+				}
+
+				// 接口按键合并写入，不支持删除键
+				var removed []string
+				for key := range before {
+					if _, ok := after[key]; !ok {
+						removed = append(removed, key)
+					}
+				}
+				if len(removed) > 0 {
+					sort.Strings(removed)
+					return fmt.Errorf("context keys cannot be removed (%s); set them to null instead", strings.Join(removed, ", "))
+				}
+
+				changed := map[string]interface{}{}
+				for key, value := range after {
+					if old, ok := before[key]; !ok || !equalJSON(old, value) {
+						changed[key] = value
+					}
+				}
+				if len(changed) == 0 {
+					return a.printDone("no changes")
+				}
+				if err := a.client.UpdateJobContext(a.ctx, ids[0], *scope, changed); err != nil {
+					return err
+				}
+				return a.printDone("updated %d key(s) in %s context of job %d", len(changed), *scope, ids[0])
+			}
+		},
+	},
+}
+
+// editJSON 将 values 写入临时文件，用 $EDITOR（默认 vi）编辑后读回
+func editJSON(values map[string]interface{}) (map[string]interface{}, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp("", "workflowctl-context-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor: %w", err)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(edited))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("edited context is not a JSON object: %w", err)
+	}
+	return result, nil
+}
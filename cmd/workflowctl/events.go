@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+var eventCommands = map[string]command{
+	"tail": {
+		usage: "[-job <job-id>] [-flow <flow-id>] [-types a,b]",
+		help:  "stream job events until interrupted",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			jobID := fs.Int64("job", 0, "only events of this job")
+			flowID := fs.Int64("flow", 0, "only events of this flow")
+			types := fs.String("types", "", "comma-separated event types, e.g. job.completed,job_task.failed")
+			return func(a *app, args []string) error {
+				if len(args) > 0 {
+					return fmt.Errorf("unexpected argument %q", args[0])
+				}
+				filter := client.EventFilter{JobID: *jobID, FlowID: *flowID}
+				if *types != "" {
+					filter.Types = strings.Split(*types, ",")
+				}
+				err := a.client.StreamEvents(a.ctx, filter, a.printEvent)
+				if errors.Is(err, context.Canceled) {
+					return nil
+				}
+				return err
+			}
+		},
+	},
+}
+
+// printEvent 每个事件输出一行；json 格式时输出 JSON Lines，便于管道处理
+func (a *app) printEvent(e client.Event) error {
+	if a.output == "json" {
+		return json.NewEncoder(a.out).Encode(e)
+	}
+	line := fmt.Sprintf("%s  %-22s job=%d", timestamp(e.OccurredAt), e.Type, e.JobID)
+	if e.JobTaskID > 0 {
+		line += fmt.Sprintf(" job_task=%d", e.JobTaskID)
+	}
+	if e.Status != "" {
+		line += " status=" + e.Status
+	}
+	if len(e.Data) > 0 {
+		data, _ := json.Marshal(e.Data)
+		line += " " + truncate(string(data), 120)
+	}
+	_, err := fmt.Fprintln(a.out, line)
+	return err
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+var flowCommands = map[string]command{
+	"list": {
+		help: "list flows",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			lf := addListFlags(fs)
+			active := fs.String("active", "", "true, false or all (default true)")
+			return func(a *app, args []string) error {
+				q := client.FlowQuery{ListOptions: lf.options(), Filter: lf.filter()}
+				var err error
+				if q.IsActive, err = activeFlag(*active); err != nil {
+					return err
+				}
+				var flows []client.Flow
+				var page *client.Page
+				if lf.all {
+					flows, err = a.client.IterateFlows(q).All(a.ctx)
+				} else {
+					flows, page, err = a.client.SearchFlows(a.ctx, q)
+				}
+				if err != nil {
+					return err
+				}
+				rows := make([][]string, len(flows))
+				for i, f := range flows {
+					rows[i] = []string{id(f.ID), f.Name, f.Version, strings.Join(f.Inputs, ","), boolText(f.IsActive), timestamp(f.UpdatedAt)}
+				}
+				if err := a.print(flows, []string{"ID", "NAME", "VERSION", "INPUTS", "ACTIVE", "UPDATED"}, rows); err != nil {
+					return err
+				}
+				a.printPageFooter(page, len(flows))
+				return nil
+			}
+		},
+	},
+	"get": {
+		usage: "<flow-id>",
+		help:  "show a flow and its tasks",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "flow-id")
+				if err != nil {
+					return err
+				}
+				detail, err := a.client.GetFlow(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				if a.output == "json" {
+					return a.printJSON(detail)
+				}
+				f := detail.Flow
+				fmt.Fprintf(a.out, "Flow %d: %s (version %s)\n", f.ID, f.Name, f.Version)
+				if f.Description != "" {
+					fmt.Fprintf(a.out, "Description: %s\n", f.Description)
+				}
+				if len(f.Inputs) > 0 {
+					fmt.Fprintf(a.out, "Inputs: %s\n", strings.Join(f.Inputs, ", "))
+				}
+				fmt.Fprintln(a.out)
+				rows := make([][]string, len(detail.FlowTasks))
+				for i, ft := range detail.FlowTasks {
+					name, typ := "-", "-"
+					if ft.Task != nil {
+						name, typ = ft.Task.Name, string(ft.Task.TaskType)
+					}
+					rows[i] = []string{fmt.Sprint(ft.Sequence), id(ft.TaskID), name, typ, boolText(ft.IsOptional), boolText(ft.AllowRollback)}
+				}
+				return a.print(nil, []string{"SEQ", "TASK", "NAME", "TYPE", "OPTIONAL", "ROLLBACK"}, rows)
+			}
+		},
+	},
+	"apply": {
+		usage: "-f <file.json>",
+		help:  "create or update flows from a definition file",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			file := fs.String("f", "", "flow definition file (JSON object or array), - for stdin")
+			dryRun := fs.Bool("dry-run", false, "show what would change without applying")
+			return func(a *app, args []string) error {
+				if *file == "" {
+					return errors.New("-f is required")
+				}
+				defs, err := readFlowDefinitions(*file)
+				if err != nil {
+					return err
+				}
+				ap := &applier{app: a, dryRun: *dryRun}
+				for _, def := range defs {
+					if err := ap.apply(def); err != nil {
+						return fmt.Errorf("flow %q: %w", def.Name, err)
+					}
+				}
+				return nil
+			}
+		},
+	},
+}
+
+// flowDefinition 流程定义文件格式：任务可按 task_id 或名称（task）引用
+//
+//	{
+//	  "name": "视频分析", "version": "1.0.0", "inputs": ["video_url"],
+//	  "tasks": [{"task": "YouTube ASR 获取"}, {"task_id": 12, "config_overrides": {"model": "glm-4"}}]
+//	}
+type flowDefinition struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Version     string               `json:"version"`
+	Inputs      []string             `json:"inputs"`
+	IsActive    *bool                `json:"is_active"`
+	Tasks       []flowTaskDefinition `json:"tasks"`
+}
+
+type flowTaskDefinition struct {
+	Task            string                 `json:"task"`
+	TaskID          int64                  `json:"task_id"`
+	IsOptional      bool                   `json:"is_optional"`
+	AllowRollback   *bool                  `json:"allow_rollback"`
+	ConfigOverrides map[string]interface{} `json:"config_overrides"`
+}
+
+// readFlowDefinitions 读取单个流程定义或流程定义数组
+func readFlowDefinitions(path string) ([]flowDefinition, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	var defs []flowDefinition
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &defs)
+	} else {
+		var def flowDefinition
+		err = json.Unmarshal(data, &def)
+		defs = []flowDefinition{def}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, def := range defs {
+		if def.Name == "" || len(def.Tasks) == 0 {
+			return nil, fmt.Errorf("parse %s: every flow needs a name and at least one task", path)
+		}
+	}
+	return defs, nil
+}
+
+// applier 按名称与版本匹配已有流程：不存在时创建；存在且任务编排一致时更新描述、输入与启用状态；
+// 任务编排不同时报错，需提升版本号创建新流程（已有作业引用旧编排）
+type applier struct {
+	*app
+	dryRun bool
+	tasks  map[string][]int64 // 任务名称 -> ID，按需加载
+	flows  []client.Flow
+}
+
+func (ap *applier) apply(def flowDefinition) error {
+	specs, err := ap.resolveTasks(def.Tasks)
+	if err != nil {
+		return err
+	}
+
+	existing, err := ap.findFlow(def.Name, def.Version)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if ap.dryRun {
+			return ap.printDone("flow %q version %s would be created", def.Name, def.Version)
+		}
+		flow, err := ap.client.CreateFlow(ap.ctx, client.CreateFlowRequest{
+			Name:        def.Name,
+			Description: def.Description,
+			Version:     def.Version,
+			Inputs:      def.Inputs,
+			Tasks:       specs,
+		})
+		if err != nil {
+			return err
+		}
+		return ap.printDone("flow %q version %s created (id %d)", flow.Name, flow.Version, flow.ID)
+	}
+
+	detail, err := ap.client.GetFlow(ap.ctx, existing.ID)
+	if err != nil {
+		return err
+	}
+	if !sameTasks(specs, detail.FlowTasks) {
+		return fmt.Errorf("version %s (id %d) already exists with different tasks; bump the version to apply", def.Version, existing.ID)
+	}
+
+	flow := *detail.Flow
+	flow.Description = def.Description
+	flow.Inputs = def.Inputs
+	if def.IsActive != nil {
+		flow.IsActive = *def.IsActive
+	}
+	if flow.Description == detail.Flow.Description && equalJSON(flow.Inputs, detail.Flow.Inputs) && flow.IsActive == detail.Flow.IsActive {
+		return ap.printDone("flow %q version %s unchanged (id %d)", flow.Name, flow.Version, flow.ID)
+	}
+	if ap.dryRun {
+		return ap.printDone("flow %q version %s would be updated (id %d)", flow.Name, flow.Version, flow.ID)
+	}
+	if _, err := ap.client.UpdateFlow(ap.ctx, &flow); err != nil {
+		return err
+	}
+	return ap.printDone("flow %q version %s updated (id %d)", flow.Name, flow.Version, flow.ID)
+}
+
+// resolveTasks 将按名称引用的任务解析为 ID
+func (ap *applier) resolveTasks(defs []flowTaskDefinition) ([]client.FlowTaskSpec, error) {
+	specs := make([]client.FlowTaskSpec, len(defs))
+	for i, d := range defs {
+		taskID := d.TaskID
+		if taskID == 0 {
+			if d.Task == "" {
+				return nil, fmt.Errorf("task %d: task or task_id is required", i+1)
+			}
+			if ap.tasks == nil {
+				all, err := ap.client.IterateTasks(client.TaskQuery{}).All(ap.ctx)
+				if err != nil {
+					return nil, err
+				}
+				ap.tasks = make(map[string][]int64)
+				for _, t := range all {
+					ap.tasks[t.Name] = append(ap.tasks[t.Name], t.ID)
+				}
+			}
+			switch ids := ap.tasks[d.Task]; len(ids) {
+			case 0:
+				return nil, fmt.Errorf("task %q not found", d.Task)
+			case 1:
+				taskID = ids[0]
+			default:
+				return nil, fmt.Errorf("task name %q is ambiguous (ids %v), use task_id", d.Task, ids)
+			}
+		}
+		specs[i] = client.FlowTaskSpec{
+			TaskID:          taskID,
+			IsOptional:      d.IsOptional,
+			AllowRollback:   d.AllowRollback,
+			ConfigOverrides: d.ConfigOverrides,
+		}
+	}
+	return specs, nil
+}
+
+func (ap *applier) findFlow(name, version string) (*client.Flow, error) {
+	if ap.flows == nil {
+		// 包含已停用的流程，避免重复创建同名同版本的流程
+		flows, err := ap.client.IterateFlows(client.FlowQuery{IsActive: client.ActiveAny}).All(ap.ctx)
+		if err != nil {
+			return nil, err
+		}
+		ap.flows = flows
+	}
+	for i := range ap.flows {
+		if ap.flows[i].Name == name && ap.flows[i].Version == version {
+			return &ap.flows[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameTasks 比较定义中的任务编排与已有流程是否一致
+func sameTasks(specs []client.FlowTaskSpec, existing []client.FlowTask) bool {
+	if len(specs) != len(existing) {
+		return false
+	}
+	for i, s := range specs {
+		e := existing[i]
+		allowRollback := s.AllowRollback == nil || *s.AllowRollback
+		if s.TaskID != e.TaskID || s.IsOptional != e.IsOptional || allowRollback != e.AllowRollback {
+			return false
+		}
+		if len(s.ConfigOverrides) > 0 || len(e.ConfigOverrides) > 0 {
+			if !equalJSON(s.ConfigOverrides, e.ConfigOverrides) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalJSON 按 JSON 值比较，忽略数字类型等表示差异
+func equalJSON(a, b interface{}) bool {
+	var va, vb interface{}
+	da, _ := json.Marshal(a)
+	db, _ := json.Marshal(b)
+	json.Unmarshal(da, &va)
+	json.Unmarshal(db, &vb)
+	if isEmpty(va) && isEmpty(vb) {
+		return true
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func isEmpty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+// jobTaskAction 作业任务动作命令：参数为 <job-id> <job-task-id>
+func jobTaskAction(help string, setup func(fs *flag.FlagSet) func(a *app, jobID, jobTaskID int64) (string, error)) command {
+	return command{
+		usage: "<job-id> <job-task-id>",
+		help:  help,
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			run := setup(fs)
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id", "job-task-id")
+				if err != nil {
+					return err
+				}
+				msg, err := run(a, ids[0], ids[1])
+				if err != nil {
+					return err
+				}
+				return a.printDone("%s", msg)
+			}
+		},
+	}
+}
+
+// operatorFlag 未启用认证时的操作人，启用认证时服务端以当前用户为准
+func operatorFlag(fs *flag.FlagSet) *int64 {
+	return fs.Int64("operator", 0, "operator user id (ignored when the server has auth enabled)")
+}
+
+var jobTaskCommands = map[string]command{
+	"list": {
+		usage: "<job-id>",
+		help:  "list the tasks of a job",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				tasks, err := a.client.ListJobTasks(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printJobTasks(tasks)
+			}
+		},
+	},
+	"start": jobTaskAction("start a task", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		executor := fs.Int64("executor", 0, "executor user id (ignored when the server has auth enabled)")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d started", jobTaskID), a.client.StartTask(a.ctx, jobID, jobTaskID, *executor)
+		}
+	}),
+	"complete": jobTaskAction("complete a task, e.g. approve with -result approved=true", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		result := keyValues{}
+		fs.Var(result, "result", "result key=value, JSON values allowed (repeatable)")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d completed", jobTaskID), a.client.CompleteTask(a.ctx, jobID, jobTaskID, result)
+		}
+	}),
+	"fail": jobTaskAction("mark a running task as failed", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		reason := fs.String("reason", "", "error message")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d failed", jobTaskID), a.client.FailTask(a.ctx, jobID, jobTaskID, *reason)
+		}
+	}),
+	"cancel": jobTaskAction("cancel a running task: marks it failed with an operator reason, which fails the job", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		reason := fs.String("reason", "", "why the task is cancelled")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			msg := "cancelled by operator"
+			if *reason != "" {
+				msg += ": " + *reason
+			}
+			return fmt.Sprintf("job task %d cancelled", jobTaskID), a.client.FailTask(a.ctx, jobID, jobTaskID, msg)
+		}
+	}),
+	"skip": jobTaskAction("skip an optional task", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		operator := operatorFlag(fs)
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d skipped", jobTaskID), a.client.SkipTask(a.ctx, jobID, jobTaskID, *operator)
+		}
+	}),
+	"rollback": jobTaskAction("send the job back to an earlier task", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		operator := operatorFlag(fs)
+		target := fs.Int("to", 0, "target sequence to roll back to")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			if *target <= 0 {
+				return "", fmt.Errorf("-to is required")
+			}
+			return fmt.Sprintf("job task %d rolled back to sequence %d", jobTaskID, *target),
+				a.client.RollbackTask(a.ctx, jobID, jobTaskID, *operator, *target)
+		}
+	}),
+	"retry": jobTaskAction("retry a failed task", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		operator := operatorFlag(fs)
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d reset for retry", jobTaskID), a.client.RetryTask(a.ctx, jobID, jobTaskID, *operator)
+		}
+	}),
+	"reassign": jobTaskAction("reassign a task to another user", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		operator := operatorFlag(fs)
+		assignee := fs.Int64("to", 0, "assignee user id")
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			if *assignee <= 0 {
+				return "", fmt.Errorf("-to is required")
+			}
+			return fmt.Sprintf("job task %d reassigned to user %d", jobTaskID, *assignee),
+				a.client.ReassignTask(a.ctx, jobID, jobTaskID, *operator, *assignee)
+		}
+	}),
+	"execute": jobTaskAction("run an automated task on the server", func(fs *flag.FlagSet) func(*app, int64, int64) (string, error) {
+		return func(a *app, jobID, jobTaskID int64) (string, error) {
+			return fmt.Sprintf("job task %d execution started", jobTaskID), a.client.ExecuteTask(a.ctx, jobID, jobTaskID)
+		}
+	}),
+	"logs": {
+		usage: "<job-id> <job-task-id>",
+		help:  "show execution logs of a task; -f follows until the task ends",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			follow := fs.Bool("f", false, "follow new log lines")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id", "job-task-id")
+				if err != nil {
+					return err
+				}
+				print := func(l client.ExecutionLog) error {
+					if a.output == "json" {
+						return a.printJSON(l)
+					}
+					_, err := fmt.Fprintf(a.out, "%s %-5s %s\n", l.CreatedAt.Local().Format(time.RFC3339), l.Level, l.Message)
+					return err
+				}
+				if *follow {
+					status, err := a.client.StreamExecutionLogs(a.ctx, ids[0], ids[1], 0, print)
+					if err != nil && err != context.Canceled {
+						return err
+					}
+					if status != "" && a.output != "json" {
+						fmt.Fprintf(a.out, "-- task %s --\n", status)
+					}
+					return nil
+				}
+				logs, err := a.client.ListExecutionLogs(a.ctx, ids[0], ids[1], 0, 0)
+				if err != nil {
+					return err
+				}
+				if a.output == "json" {
+					return a.printJSON(logs)
+				}
+				for _, l := range logs {
+					if err := print(l); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		},
+	},
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+var jobCommands = map[string]command{
+	"list": {
+		help: "list jobs",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			lf := addListFlags(fs)
+			status := fs.String("status", "", "comma-separated statuses, e.g. failed,cancelled")
+			flowID := fs.Int64("flow", 0, "only jobs of this flow")
+			since := fs.String("since", "", "created at or after: RFC 3339 time, YYYY-MM-DD, or a duration ago such as 168h")
+			return func(a *app, args []string) error {
+				q := client.JobQuery{ListOptions: lf.options(), Filter: lf.filter(), Statuses: jobStatuses(*status), FlowID: *flowID}
+				if *since != "" {
+					t, err := parseSince(*since)
+					if err != nil {
+						return err
+					}
+					q.CreatedAfter = t
+				}
+				return a.listJobs(q, lf.all)
+			}
+		},
+	},
+	"get": {
+		usage: "<job-id>",
+		help:  "show a job and its tasks",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				detail, err := a.client.GetJob(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printJobDetail(detail)
+			}
+		},
+	},
+	"create": {
+		usage: "-flow <flow-id> [-name <name>] [-input key=value ...]",
+		help:  "create a job, optionally with inputs, and start or run it",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			flowID := fs.Int64("flow", 0, "flow id")
+			name := fs.String("name", "", "job name (default: <flow name> <time>)")
+			inputs := keyValues{}
+			fs.Var(inputs, "input", "flow input key=value, JSON values allowed (repeatable)")
+			inputsFile := fs.String("inputs-file", "", "JSON object file with flow inputs")
+			start := fs.Bool("start", false, "start the job after creating it")
+			auto := fs.Bool("auto", false, "start the job and auto-execute its tasks on the server")
+			wait := fs.Bool("wait", false, "wait for the job to finish (implies -start)")
+			timeout := fs.Duration("timeout", 0, "give up waiting after this duration (0: no limit)")
+			return func(a *app, args []string) error {
+				if *flowID <= 0 {
+					return errors.New("-flow is required")
+				}
+				if *inputsFile != "" {
+					data, err := os.ReadFile(*inputsFile)
+					if err != nil {
+						return err
+					}
+					var fileInputs map[string]interface{}
+					if err := json.Unmarshal(data, &fileInputs); err != nil {
+						return fmt.Errorf("parse %s: %w", *inputsFile, err)
+					}
+					// -input 覆盖文件中的同名输入
+					for k, v := range fileInputs {
+						if _, ok := inputs[k]; !ok {
+							inputs[k] = v
+						}
+					}
+				}
+
+				jobName := *name
+				if jobName == "" {
+					detail, err := a.client.GetFlow(a.ctx, *flowID)
+					if err != nil {
+						return err
+					}
+					jobName = fmt.Sprintf("%s %s", detail.Flow.Name, time.Now().Format("2006-01-02 15:04:05"))
+				}
+
+				job, err := a.client.CreateJob(a.ctx, client.CreateJobRequest{FlowID: *flowID, JobName: jobName})
+				if err != nil {
+					return err
+				}
+				// 流程输入写入作业共享上下文，须在启动前完成
+				if len(inputs) > 0 {
+					if err := a.client.UpdateJobContext(a.ctx, job.ID, "", inputs); err != nil {
+						return fmt.Errorf("job %d created but setting inputs failed: %w", job.ID, err)
+					}
+				}
+				if !*start && !*auto && !*wait {
+					return a.printJobs([]client.Job{*job})
+				}
+
+				if err := a.client.StartJob(a.ctx, job.ID); err != nil {
+					return fmt.Errorf("job %d created but start failed: %w", job.ID, err)
+				}
+				if *auto {
+					if err := a.client.AutoExecuteJob(a.ctx, job.ID); err != nil {
+						return fmt.Errorf("job %d started but auto-execute failed: %w", job.ID, err)
+					}
+				}
+				if !*wait {
+					detail, err := a.client.GetJob(a.ctx, job.ID)
+					if err != nil {
+						return err
+					}
+					return a.printJobDetail(detail)
+				}
+				return a.waitJob(job.ID, *timeout)
+			}
+		},
+	},
+	"start": {
+		usage: "<job-id>",
+		help:  "start a pending job",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			auto := fs.Bool("auto", false, "also auto-execute its tasks on the server")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				if err := a.client.StartJob(a.ctx, ids[0]); err != nil {
+					return err
+				}
+				if *auto {
+					if err := a.client.AutoExecuteJob(a.ctx, ids[0]); err != nil {
+						return err
+					}
+					return a.printDone("job %d started, auto-execution running", ids[0])
+				}
+				return a.printDone("job %d started", ids[0])
+			}
+		},
+	},
+	"cancel": {
+		usage: "<job-id>",
+		help:  "cancel a pending or running job; its running task is marked failed",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			reason := fs.String("reason", "", "why the job is cancelled")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				if err := a.client.CancelJob(a.ctx, ids[0], *reason); err != nil {
+					return err
+				}
+				return a.printDone("job %d cancelled", ids[0])
+			}
+		},
+	},
+	"rerun": {
+		usage: "<job-id> [-input key=value ...]",
+		help:  "create a new job with the inputs of an existing one, and start or run it",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			name := fs.String("name", "", "job name (default: <original name> (rerun))")
+			inputs := keyValues{}
+			fs.Var(inputs, "input", "override an input key=value, JSON values allowed (repeatable)")
+			latest := fs.Bool("latest", false, "use the latest active version of the flow")
+			reuse := fs.Bool("reuse-outputs", false, "reuse outputs of unchanged upstream tasks")
+			start := fs.Bool("start", false, "start the job after creating it")
+			auto := fs.Bool("auto", false, "start the job and auto-execute its tasks on the server")
+			wait := fs.Bool("wait", false, "wait for the job to finish (implies -start)")
+			timeout := fs.Duration("timeout", 0, "give up waiting after this duration (0: no limit)")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				rerun, err := a.client.RerunJob(a.ctx, ids[0], client.RerunJobRequest{
+					JobName:       *name,
+					LatestVersion: *latest,
+					Inputs:        inputs,
+					ReuseOutputs:  *reuse,
+				})
+				if err != nil {
+					return err
+				}
+				job := rerun.Job
+				if !*start && !*auto && !*wait {
+					if a.output == "json" {
+						return a.printJSON(rerun)
+					}
+					for _, t := range rerun.ReusedTasks {
+						fmt.Fprintf(a.out, "Reused task %d (%s) from job task %d\n", t.Sequence, t.TaskName, t.SourceJobTaskID)
+					}
+					return a.printJobs([]client.Job{*job})
+				}
+
+				if err := a.client.StartJob(a.ctx, job.ID); err != nil {
+					return fmt.Errorf("job %d created but start failed: %w", job.ID, err)
+				}
+				if *auto {
+					if err := a.client.AutoExecuteJob(a.ctx, job.ID); err != nil {
+						return fmt.Errorf("job %d started but auto-execute failed: %w", job.ID, err)
+					}
+				}
+				if !*wait {
+					detail, err := a.client.GetJob(a.ctx, job.ID)
+					if err != nil {
+						return err
+					}
+					return a.printJobDetail(detail)
+				}
+				return a.waitJob(job.ID, *timeout)
+			}
+		},
+	},
+	"wait": {
+		usage: "<job-id>",
+		help:  "wait for a job to finish; exits non-zero if it failed",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			timeout := fs.Duration("timeout", 0, "give up after this duration (0: no limit)")
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				return a.waitJob(ids[0], *timeout)
+			}
+		},
+	},
+	"logs": {
+		usage: "<job-id>",
+		help:  "show the audit log of a job",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "job-id")
+				if err != nil {
+					return err
+				}
+				logs, err := a.client.ListJobLogs(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				return a.printAuditLogs(logs)
+			}
+		},
+	},
+}
+
+// jobStatuses 解析逗号分隔的作业状态
+func jobStatuses(s string) []client.JobStatus {
+	var statuses []client.JobStatus
+	for _, status := range strings.Split(s, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, client.JobStatus(status))
+		}
+	}
+	return statuses
+}
+
+// listJobs 输出一页作业及分页信息，all 为 true 时输出所有页
+func (a *app) listJobs(q client.JobQuery, all bool) error {
+	if all {
+		jobs, err := a.client.IterateJobs(q).All(a.ctx)
+		if err != nil {
+			return err
+		}
+		return a.printJobs(jobs)
+	}
+	jobs, page, err := a.client.SearchJobs(a.ctx, q)
+	if err != nil {
+		return err
+	}
+	if err := a.printJobs(jobs); err != nil {
+		return err
+	}
+	a.printPageFooter(page, len(jobs))
+	return nil
+}
+
+// parseSince 解析 RFC 3339 时间、日期或相对当前的时长
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid -since %q", s)
+}
+
+// waitJob 等待作业结束并输出详情，作业失败时返回错误
+func (a *app) waitJob(jobID int64, timeout time.Duration) error {
+	ctx := a.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	detail, err := a.client.WaitForJob(ctx, jobID, 0)
+	if detail != nil {
+		if perr := a.printJobDetail(detail); perr != nil {
+			return perr
+		}
+	}
+	return err
+}
+
+func (a *app) printJobs(jobs []client.Job) error {
+	rows := make([][]string, len(jobs))
+	for i, j := range jobs {
+		rows[i] = []string{id(j.ID), truncate(j.JobName, 40), id(j.FlowID), string(j.Status), nullID(j.CurrentTaskSeq), nullTime(j.StartedAt), nullTime(j.CompletedAt)}
+	}
+	return a.print(jobs, []string{"ID", "NAME", "FLOW", "STATUS", "SEQ", "STARTED", "COMPLETED"}, rows)
+}
+
+func (a *app) printJobDetail(detail *client.JobDetail) error {
+	if a.output == "json" {
+		return a.printJSON(detail)
+	}
+	j := detail.Job
+	fmt.Fprintf(a.out, "Job %d: %s\nFlow: %d  Status: %s  Started: %s  Completed: %s\n\n",
+		j.ID, j.JobName, j.FlowID, j.Status, nullTime(j.StartedAt), nullTime(j.CompletedAt))
+	return a.printJobTasks(detail.JobTasks)
+}
+
+func (a *app) printJobTasks(tasks []client.JobTask) error {
+	rows := make([][]string, len(tasks))
+	for i, t := range tasks {
+		name := "-"
+		if t.Task != nil {
+			name = t.Task.Name
+		}
+		rows[i] = []string{fmt.Sprint(t.Sequence), id(t.ID), truncate(name, 30), string(t.Status), nullID(t.ExecutorID), nullTime(t.StartedAt), nullTime(t.CompletedAt), truncate(t.ErrorMessage, 50)}
+	}
+	return a.print(tasks, []string{"SEQ", "ID", "TASK", "STATUS", "EXECUTOR", "STARTED", "COMPLETED", "ERROR"}, rows)
+}
+
+func (a *app) printAuditLogs(logs []client.JobTaskLog) error {
+	rows := make([][]string, len(logs))
+	for i, l := range logs {
+		rows[i] = []string{timestamp(l.CreatedAt), id(l.JobTaskID), l.Action, nullID(l.OperatorID), truncate(l.Message, 60)}
+	}
+	return a.print(logs, []string{"TIME", "JOB TASK", "ACTION", "OPERATOR", "MESSAGE"}, rows)
+}
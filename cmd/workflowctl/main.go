@@ -0,0 +1,165 @@
+// workflowctl 是 GoWorkFlow 的命令行运维工具，基于 pkg/client 访问 /api/v1 接口
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+// 连接参数的环境变量，命令行参数优先
+const (
+	envServer  = "WORKFLOW_SERVER"
+	envToken   = "WORKFLOW_TOKEN"
+	envProject = "WORKFLOW_PROJECT"
+)
+
+// app 命令执行环境
+type app struct {
+	ctx    context.Context
+	client *client.Client
+	output string // table 或 json
+	out    io.Writer
+}
+
+// command 子命令
+type command struct {
+	usage string // 参数说明
+	help  string // 一句话描述
+	setup func(fs *flag.FlagSet) func(a *app, args []string) error
+}
+
+// commands 资源 -> 动作 -> 子命令
+var commands = map[string]map[string]command{
+	"tasks":     taskCommands,
+	"flows":     flowCommands,
+	"jobs":      jobCommands,
+	"batches":   batchCommands,
+	"job-tasks": jobTaskCommands,
+	"context":   contextCommands,
+	"events":    eventCommands,
+	"retention": retentionCommands,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	// events 只有一个动作，允许省略
+	if len(args) > 0 && args[0] == "events" && (len(args) == 1 || args[1] != "tail") {
+		args = append([]string{"events", "tail"}, args[1:]...)
+	}
+	if len(args) < 2 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(out)
+		return nil
+	}
+
+	verbs, ok := commands[args[0]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown resource %q", args[0])
+	}
+	cmd, ok := verbs[args[1]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown command %q for %s", args[1], args[0])
+	}
+
+	name := args[0] + " " + args[1]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	server := fs.String("server", envOr(envServer, "http://localhost:8080"), "server address ($"+envServer+")")
+	token := fs.String("token", os.Getenv(envToken), "API key or JWT ($"+envToken+")")
+	project := fs.String("project", os.Getenv(envProject), "project id or slug ($"+envProject+")")
+	output := fs.String("o", "table", "output format: table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(out, "Usage: workflowctl %s [flags] %s\n\n%s\n\nFlags:\n", name, cmd.usage, cmd.help)
+		fs.PrintDefaults()
+	}
+	runCmd := cmd.setup(fs)
+
+	positional, err := parseInterspersed(fs, args[2:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	a := &app{
+		ctx:    ctx,
+		client: client.New(*server, client.WithToken(*token), client.WithProject(*project), client.WithUserAgent("workflowctl")),
+		output: *output,
+		out:    out,
+	}
+	return runCmd(a, positional)
+}
+
+// parseInterspersed 解析参数，允许标志出现在位置参数之后，如 jobs get 5 -o json
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "workflowctl - GoWorkFlow command-line tool")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Usage: workflowctl <resource> <command> [flags] [args]")
+	fmt.Fprintln(out)
+
+	resources := make([]string, 0, len(commands))
+	for r := range commands {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	for _, r := range resources {
+		verbs := make([]string, 0, len(commands[r]))
+		for v := range commands[r] {
+			verbs = append(verbs, v)
+		}
+		sort.Strings(verbs)
+		for _, v := range verbs {
+			cmd := commands[r][v]
+			line := strings.TrimSpace(r + " " + v + " " + cmd.usage)
+			fmt.Fprintf(out, "  %-52s %s\n", line, cmd.help)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Connection: -server, -token, -project or $%s, $%s, $%s\n", envServer, envToken, envProject)
+	fmt.Fprintln(out, "Run 'workflowctl <resource> <command> -h' for command flags.")
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// print 以 JSON 输出 v，或以表格输出 header 与 rows
+func (a *app) print(v interface{}, header []string, rows [][]string) error {
+	if a.output == "json" {
+		return a.printJSON(v)
+	}
+	tw := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (a *app) printJSON(v interface{}) error {
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printDone 输出操作结果，json 格式时输出 {"ok": true, ...}
+func (a *app) printDone(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if a.output == "json" {
+		return a.printJSON(map[string]interface{}{"ok": true, "message": msg})
+	}
+	_, err := fmt.Fprintln(a.out, msg)
+	return err
+}
+
+func id(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func nullID(v sql.NullInt64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return id(v.Int64)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func nullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return "-"
+	}
+	return timestamp(t.Time)
+}
+
+// truncate 截断过长的单元格，避免表格换行
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+var retentionCommands = map[string]command{
+	"policies": {
+		help: "list job retention policies",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				policies, err := a.client.ListRetentionPolicies(a.ctx)
+				if err != nil {
+					return err
+				}
+				rows := make([][]string, len(policies))
+				for i, p := range policies {
+					flow := "default"
+					if p.FlowID.Valid {
+						flow = id(p.FlowID.Int64)
+					}
+					rows[i] = []string{id(p.ID), flow, days(p.KeepDays), fmt.Sprint(p.KeepJobs), days(p.FailedKeepDays),
+						p.Action, fmt.Sprint(p.IsActive)}
+				}
+				return a.print(policies, []string{"ID", "FLOW", "KEEP DAYS", "KEEP JOBS", "FAILED KEEP DAYS", "ACTION", "ACTIVE"}, rows)
+			}
+		},
+	},
+	"set": {
+		help: "set the retention policy of a flow, or the default policy when -flow is omitted",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			flowID := fs.Int64("flow", 0, "flow id (default: the policy for flows without their own)")
+			keepDays := fs.Int("keep-days", 0, "keep completed and cancelled jobs for this many days (0: no age limit)")
+			keepJobs := fs.Int("keep-jobs", 0, "keep this many most recent completed and cancelled jobs per flow (0: no count limit)")
+			failedKeepDays := fs.Int("failed-keep-days", 0, "keep failed jobs for this many days (0: same as -keep-days)")
+			action := fs.String("action", "delete", "delete, archive_file or archive_table")
+			disabled := fs.Bool("disabled", false, "save the policy but do not apply it")
+			return func(a *app, args []string) error {
+				active := !*disabled
+				req := client.RetentionPolicyRequest{
+					KeepDays:       *keepDays,
+					KeepJobs:       *keepJobs,
+					FailedKeepDays: *failedKeepDays,
+					Action:         *action,
+					IsActive:       &active,
+				}
+				if *flowID > 0 {
+					req.FlowID = flowID
+				}
+				policy, err := a.client.SetRetentionPolicy(a.ctx, req)
+				if err != nil {
+					return err
+				}
+				if a.output == "json" {
+					return a.printJSON(policy)
+				}
+				return a.printDone("retention policy %d saved", policy.ID)
+			}
+		},
+	},
+	"delete": {
+		usage: "<policy-id>",
+		help:  "delete a retention policy; the flow falls back to the default policy",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "policy-id")
+				if err != nil {
+					return err
+				}
+				if err := a.client.DeleteRetentionPolicy(a.ctx, ids[0]); err != nil {
+					return err
+				}
+				return a.printDone("retention policy %d deleted", ids[0])
+			}
+		},
+	},
+	"run": {
+		help: "purge expired jobs now and show what was removed",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			dryRun := fs.Bool("dry-run", false, "only report the jobs that would be removed")
+			return func(a *app, args []string) error {
+				run, err := a.client.RunRetention(a.ctx, *dryRun)
+				if err != nil {
+					return err
+				}
+				return a.printRetentionRun(run)
+			}
+		},
+	},
+	"runs": {
+		help: "list past retention runs",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			limit := fs.Int("limit", 20, "max runs to show")
+			return func(a *app, args []string) error {
+				runs, err := a.client.ListRetentionRuns(a.ctx, client.ListOptions{Limit: *limit})
+				if err != nil {
+					return err
+				}
+				rows := make([][]string, len(runs))
+				for i, r := range runs {
+					rows[i] = []string{id(r.ID), timestamp(r.StartedAt), nullTime(r.FinishedAt), fmt.Sprint(r.DryRun),
+						fmt.Sprint(r.JobsDeleted), fmt.Sprint(r.JobsArchived), fmt.Sprint(r.FilesRemoved),
+						fmt.Sprint(r.ArtifactsRemoved), truncate(r.Error, 40)}
+				}
+				return a.print(runs, []string{"ID", "STARTED", "FINISHED", "DRY RUN", "JOBS", "ARCHIVED", "FILES", "ARTIFACTS", "ERROR"}, rows)
+			}
+		},
+	},
+}
+
+// printRetentionRun 输出一次清理的统计与按流程的明细
+func (a *app) printRetentionRun(run *client.RetentionRun) error {
+	if a.output == "json" {
+		return a.printJSON(run)
+	}
+	verb := "removed"
+	if run.DryRun {
+		verb = "would remove"
+	}
+	fmt.Fprintf(a.out, "Run %d %s %d job(s) (%d archived), %d report file(s) and %d artifact(s)\n",
+		run.ID, verb, run.JobsDeleted, run.JobsArchived, run.FilesRemoved, run.ArtifactsRemoved)
+	if run.Error != "" {
+		fmt.Fprintf(a.out, "Stopped early: %s\n", run.Error)
+	}
+	if len(run.Details) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(a.out)
+	rows := make([][]string, len(run.Details))
+	for i, d := range run.Details {
+		rows[i] = []string{id(d.FlowID), id(d.PolicyID), d.Action, fmt.Sprint(len(d.JobIDs)), fmt.Sprint(len(d.FilesRemoved)),
+			fmt.Sprint(d.Artifacts), truncate(strings.Join(d.ArchiveFiles, ", "), 60)}
+	}
+	return a.print(run, []string{"FLOW", "POLICY", "ACTION", "JOBS", "FILES", "ARTIFACTS", "ARCHIVE FILES"}, rows)
+}
+
+// days 输出保留天数，0 表示不限
+func days(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/client"
+)
+
+// listFlags 列表命令的分页、排序与名称搜索标志
+type listFlags struct {
+	limit  int
+	offset int
+	sort   string
+	cursor string
+	name   string
+	all    bool
+}
+
+func addListFlags(fs *flag.FlagSet) *listFlags {
+	lf := &listFlags{}
+	fs.IntVar(&lf.limit, "limit", 20, "page size")
+	fs.IntVar(&lf.offset, "offset", 0, "offset")
+	fs.StringVar(&lf.sort, "sort", "", "sort field, prefix - for descending, e.g. -created_at")
+	fs.StringVar(&lf.cursor, "cursor", "", "continue from the cursor printed by the previous page")
+	fs.StringVar(&lf.name, "name", "", "only names containing this text")
+	fs.BoolVar(&lf.all, "all", false, "fetch all pages")
+	return lf
+}
+
+func (lf *listFlags) options() client.ListOptions {
+	return client.ListOptions{Limit: lf.limit, Offset: lf.offset, Sort: lf.sort, Cursor: lf.cursor}
+}
+
+func (lf *listFlags) filter() client.Filter {
+	return client.Filter{Name: lf.name}
+}
+
+// printPageFooter 表格下方输出总数与下一页游标
+func (a *app) printPageFooter(page *client.Page, shown int) {
+	if a.output == "json" || page == nil {
+		return
+	}
+	fmt.Fprintf(a.out, "\n%d of %d", shown, page.Total)
+	if page.NextCursor != "" {
+		fmt.Fprintf(a.out, "; next page: -cursor %s", page.NextCursor)
+	}
+	fmt.Fprintln(a.out)
+}
+
+// activeFlag 解析 -active 标志：true、false 或 all
+func activeFlag(s string) (client.ActiveFilter, error) {
+	switch f := client.ActiveFilter(s); f {
+	case client.ActiveDefault, client.ActiveOnly, client.InactiveOnly, client.ActiveAny:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid -active %q, expected true, false or all", s)
+}
+
+var taskCommands = map[string]command{
+	"list": {
+		help: "list task definitions",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			lf := addListFlags(fs)
+			taskType := fs.String("type", "", "task type: manual, automated or approval")
+			active := fs.String("active", "", "true, false or all (default all)")
+			return func(a *app, args []string) error {
+				q := client.TaskQuery{ListOptions: lf.options(), Filter: lf.filter(), TaskType: client.TaskType(*taskType)}
+				var err error
+				if q.IsActive, err = activeFlag(*active); err != nil {
+					return err
+				}
+				if lf.all {
+					tasks, err := a.client.IterateTasks(q).All(a.ctx)
+					if err != nil {
+						return err
+					}
+					return a.printTasks(tasks)
+				}
+				tasks, page, err := a.client.SearchTasks(a.ctx, q)
+				if err != nil {
+					return err
+				}
+				if err := a.printTasks(tasks); err != nil {
+					return err
+				}
+				a.printPageFooter(page, len(tasks))
+				return nil
+			}
+		},
+	},
+	"get": {
+		usage: "<task-id>",
+		help:  "show a task definition",
+		setup: func(fs *flag.FlagSet) func(*app, []string) error {
+			return func(a *app, args []string) error {
+				ids, err := parseIDs(args, "task-id")
+				if err != nil {
+					return err
+				}
+				task, err := a.client.GetTask(a.ctx, ids[0])
+				if err != nil {
+					return err
+				}
+				if a.output == "json" {
+					return a.printJSON(task)
+				}
+				if err := a.printTasks([]client.Task{*task}); err != nil {
+					return err
+				}
+				return a.printJSON(task.Config)
+			}
+		},
+	},
+}
+
+func (a *app) printTasks(tasks []client.Task) error {
+	rows := make([][]string, len(tasks))
+	for i, t := range tasks {
+		rows[i] = []string{id(t.ID), t.Name, string(t.TaskType), executorOf(t), boolText(t.IsActive), timestamp(t.UpdatedAt)}
+	}
+	return a.print(tasks, []string{"ID", "NAME", "TYPE", "EXECUTOR", "ACTIVE", "UPDATED"}, rows)
+}
+
+// executorOf 返回自动化任务配置的执行器
+func executorOf(t client.Task) string {
+	if name, ok := t.Config["executor"].(string); ok {
+		return name
+	}
+	return "-"
+}
+
+func boolText(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,45 @@
+package artifact
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorePath(t *testing.T) {
+	s := &LocalStore{baseDir: "/data/artifacts"}
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "jobs/1/report.txt", want: "/data/artifacts/jobs/1/report.txt"},
+		{key: "jobs/1/v1..v2.diff", want: "/data/artifacts/jobs/1/v1..v2.diff"},
+		{key: "jobs/1/..hidden", want: "/data/artifacts/jobs/1/..hidden"},
+		{key: "jobs/./1//a.txt", want: "/data/artifacts/jobs/1/a.txt"},
+		{key: "jobs/1/../2/a.txt", want: "/data/artifacts/jobs/2/a.txt"},
+		{key: "/jobs/1/a.txt", want: "/data/artifacts/jobs/1/a.txt"},
+		{key: "../etc/passwd", wantErr: true},
+		{key: "jobs/../../etc/passwd", wantErr: true},
+		{key: "..", wantErr: true},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "jobs/..", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := s.path(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("path(%q) = %q, want error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("path(%q) error = %v", tt.key, err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Fatalf("path(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
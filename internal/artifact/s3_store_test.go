@@ -0,0 +1,175 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "minio"
+	testSecretKey = "minio-secret"
+	testRegion    = "cn-north-1"
+)
+
+var authorizationPattern = regexp.MustCompile(
+	`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=([0-9a-f]{64})$`)
+
+// fakeS3 MinIO 风格的 S3 替身：path-style 寻址，校验 SigV4 签名后在内存中读写对象；
+// t 非空时签名不匹配记为测试失败
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if err := verifySigV4(r, body); err != nil {
+		if f.t != nil {
+			f.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verifySigV4 按服务端收到的请求重新计算 SigV4 签名并与 Authorization 比较
+func verifySigV4(r *http.Request, body []byte) error {
+	m := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil {
+		return fmt.Errorf("malformed Authorization header %q", r.Header.Get("Authorization"))
+	}
+	accessKey, date, region, signature := m[1], m[2], m[3], m[4]
+	if accessKey != testAccessKey || region != testRegion {
+		return fmt.Errorf("credential %s/%s, want %s/%s", accessKey, region, testAccessKey, testRegion)
+	}
+	amzDate := r.Header.Get("x-amz-date")
+	if !strings.HasPrefix(amzDate, date) {
+		return fmt.Errorf("x-amz-date %q does not match credential date %s", amzDate, date)
+	}
+	payloadHash := sha256Hex(body)
+	if r.Header.Get("x-amz-content-sha256") != payloadHash {
+		return fmt.Errorf("x-amz-content-sha256 does not match the body")
+	}
+
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		"host:" + r.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" + payloadHash
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + date + "/" + region + "/s3/aws4_request\n" + sha256Hex([]byte(canonicalRequest))
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	if want := hex.EncodeToString(hmacSHA256(key, stringToSign)); signature != want {
+		return fmt.Errorf("signature %s, want %s", signature, want)
+	}
+	return nil
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{t: t, objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  server.URL + "/",
+		Region:    testRegion,
+		Bucket:    "artifacts",
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		Prefix:    "gwf/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		key      string
+		wantPath string
+	}{
+		{key: "jobs/1/report.txt", wantPath: "/artifacts/gwf/jobs/1/report.txt"},
+		{key: "jobs/2/月报 (final).txt", wantPath: "/artifacts/gwf/jobs/2/月报 (final).txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			data := []byte("content of " + tt.key)
+			if err := store.Put(ctx, tt.key, bytes.NewReader(data), int64(len(data)), "text/plain"); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			fake.mu.Lock()
+			stored, contentType := fake.objects[tt.wantPath], fake.types[tt.wantPath]
+			fake.mu.Unlock()
+			if !bytes.Equal(stored, data) || contentType != "text/plain" {
+				t.Fatalf("object %s = %q (%s), want %q (text/plain)", tt.wantPath, stored, contentType, data)
+			}
+
+			rc, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			got, _ := io.ReadAll(rc)
+			rc.Close()
+			if !bytes.Equal(got, data) {
+				t.Fatalf("Get() = %q, want %q", got, data)
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := store.Get(ctx, tt.key); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() after Delete() error = %v, want ErrNotFound", err)
+			}
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete() of missing object error = %v", err)
+			}
+		})
+	}
+}
+
+func TestS3StoreRejectedSignature(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Region: testRegion, Bucket: "artifacts", AccessKey: testAccessKey, SecretKey: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Put(context.Background(), "a.txt", strings.NewReader("x"), 1, "")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Put() with wrong secret error = %v, want 403 error", err)
+	}
+	if _, err := store.Get(context.Background(), "a.txt"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() with wrong secret error = %v, want non-ErrNotFound error", err)
+	}
+}
@@ -0,0 +1,25 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound 制品不存在
+var ErrNotFound = errors.New("artifact not found")
+
+// Store 制品存储后端接口
+type Store interface {
+	// Put 写入对象
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get 读取对象，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+
+	// Backend 返回后端名称（local / s3）
+	Backend() string
+}
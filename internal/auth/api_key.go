@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix API Key 前缀，用于区分 API Key 与 JWT 并便于密钥扫描工具识别
+const APIKeyPrefix = "gwf_"
+
+// GenerateAPIKey 生成新的 API Key，返回明文与用于展示的前缀（明文只在创建时返回一次）
+func GenerateAPIKey() (key, displayPrefix string, err error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b[:])
+	return key, key[:len(APIKeyPrefix)+8], nil
+}
+
+// HashAPIKey 计算 API Key 的存储哈希；API Key 为高熵随机串，SHA-256 即可防止泄露的数据库被直接使用
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey 判断凭据是否为 API Key 格式
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -0,0 +1,355 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockSkew 校验 exp/nbf 时允许的时钟偏差
+const clockSkew = time.Minute
+
+// JWTConfig JWT 校验配置，Secret 与 JWKSURL 至少配置一个
+type JWTConfig struct {
+	Secret        string // HS256/384/512 共享密钥
+	JWKSURL       string // RS*/ES* 公钥集合地址
+	Issuer        string // 非空时校验 iss
+	Audience      string // 非空时校验 aud
+	UserClaim     string // 用户 ID 所在的声明，默认 sub
+	AllowNoExpiry bool   // 接受不含 exp 的令牌，默认拒绝
+}
+
+// JWTVerifier JWT 校验器
+type JWTVerifier struct {
+	cfg  JWTConfig
+	jwks *jwksCache
+}
+
+// NewJWTVerifier 创建 JWT 校验器
+func NewJWTVerifier(cfg JWTConfig) *JWTVerifier {
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+	v := &JWTVerifier{cfg: cfg}
+	if cfg.JWKSURL != "" {
+		v.jwks = &jwksCache{url: cfg.JWKSURL, client: &http.Client{Timeout: 10 * time.Second}}
+	}
+	return v
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify 校验签名与标准声明，返回认证主体
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid token signature encoding")
+	}
+	if err := v.verifySignature(header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	userID, err := numericClaim(claims[v.cfg.UserClaim])
+	if err != nil {
+		return nil, fmt.Errorf("claim %s: %w", v.cfg.UserClaim, err)
+	}
+	subject, _ := claims["sub"].(string)
+	return &Principal{UserID: userID, Subject: subject, Method: MethodJWT}, nil
+}
+
+func (v *JWTVerifier) verifySignature(header jwtHeader, signingInput string, signature []byte) error {
+	switch header.Alg {
+	case "HS256", "HS384", "HS512":
+		if v.cfg.Secret == "" {
+			return fmt.Errorf("algorithm %s not accepted", header.Alg)
+		}
+		mac := hmac.New(hashFor(header.Alg), []byte(v.cfg.Secret))
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512":
+		if v.jwks == nil {
+			return fmt.Errorf("algorithm %s not accepted", header.Alg)
+		}
+		key, err := v.jwks.key(header.Kid)
+		if err != nil {
+			return err
+		}
+		h := hashFor(header.Alg)()
+		h.Write([]byte(signingInput))
+		digest := h.Sum(nil)
+
+		switch pub := key.(type) {
+		case *rsa.PublicKey:
+			if header.Alg[0] != 'R' {
+				return errors.New("key type does not match algorithm")
+			}
+			if err := rsa.VerifyPKCS1v15(pub, cryptoHash(header.Alg), digest, signature); err != nil {
+				return errors.New("invalid token signature")
+			}
+			return nil
+		case *ecdsa.PublicKey:
+			if header.Alg[0] != 'E' {
+				return errors.New("key type does not match algorithm")
+			}
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			if len(signature) != 2*size {
+				return errors.New("invalid token signature")
+			}
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if !ecdsa.Verify(pub, digest, r, s) {
+				return errors.New("invalid token signature")
+			}
+			return nil
+		default:
+			return errors.New("unsupported key type")
+		}
+	default:
+		// 拒绝 none 及其他算法
+		return fmt.Errorf("algorithm %q not accepted", header.Alg)
+	}
+}
+
+func (v *JWTVerifier) validateClaims(claims map[string]interface{}) error {
+	now := time.Now()
+	if exp, ok := claims["exp"]; ok {
+		t, err := numericClaim(exp)
+		if err != nil || now.After(time.Unix(t, 0).Add(clockSkew)) {
+			return errors.New("token expired")
+		}
+	} else if !v.cfg.AllowNoExpiry {
+		return errors.New("token has no expiration")
+	}
+	if nbf, ok := claims["nbf"]; ok {
+		t, err := numericClaim(nbf)
+		if err != nil || now.Add(clockSkew).Before(time.Unix(t, 0)) {
+			return errors.New("token not yet valid")
+		}
+	}
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+			return errors.New("invalid token issuer")
+		}
+	}
+	if v.cfg.Audience != "" && !audienceContains(claims["aud"], v.cfg.Audience) {
+		return errors.New("invalid token audience")
+	}
+	return nil
+}
+
+func audienceContains(aud interface{}, want string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == want
+	case []interface{}:
+		for _, item := range a {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numericClaim 解析数字或数字字符串形式的声明
+func numericClaim(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), nil
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := n.Float64()
+		return int64(f), err
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	default:
+		return 0, errors.New("missing or not numeric")
+	}
+}
+
+// decodeSegment 解码 base64url 编码的 JSON 段，数字保留为 json.Number 以免大整数丢失精度
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func hashFor(alg string) func() hash.Hash {
+	switch alg[2:] {
+	case "384":
+		return sha512.New384
+	case "512":
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+func cryptoHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+// jwksCache 缓存 JWKS 公钥，遇到未知 kid 时刷新（最多每分钟一次），并每小时定期刷新
+type jwksCache struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *jwksCache) key(kid string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stale := time.Since(c.fetchedAt) > time.Hour
+	if _, ok := c.keys[kid]; (!ok && time.Since(c.fetchedAt) > time.Minute) || stale {
+		if err := c.refresh(); err != nil && c.keys == nil {
+			return nil, err
+		}
+	}
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	// 未指定 kid 且只有一个密钥时直接使用
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *jwksCache) refresh() error {
+	c.fetchedAt = time.Now()
+
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	c.keys = keys
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
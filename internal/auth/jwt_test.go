@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateClaims(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name    string
+		cfg     JWTConfig
+		claims  map[string]interface{}
+		wantErr string
+	}{
+		{name: "valid", claims: map[string]interface{}{"exp": float64(now + 60)}},
+		{name: "numeric string exp", claims: map[string]interface{}{"exp": "4102444800"}},
+		{name: "expired", claims: map[string]interface{}{"exp": float64(now - 3600)}, wantErr: "token expired"},
+		{name: "expired within skew", claims: map[string]interface{}{"exp": float64(now - 30)}},
+		{name: "missing exp", claims: map[string]interface{}{"sub": "1"}, wantErr: "token has no expiration"},
+		{name: "missing exp allowed", cfg: JWTConfig{AllowNoExpiry: true}, claims: map[string]interface{}{"sub": "1"}},
+		{name: "not yet valid", claims: map[string]interface{}{"exp": float64(now + 7200), "nbf": float64(now + 3600)}, wantErr: "token not yet valid"},
+		{name: "issuer mismatch", cfg: JWTConfig{Issuer: "a"}, claims: map[string]interface{}{"exp": float64(now + 60), "iss": "b"}, wantErr: "invalid token issuer"},
+		{name: "audience in list", cfg: JWTConfig{Audience: "api"}, claims: map[string]interface{}{"exp": float64(now + 60), "aud": []interface{}{"web", "api"}}},
+		{name: "audience mismatch", cfg: JWTConfig{Audience: "api"}, claims: map[string]interface{}{"exp": float64(now + 60), "aud": "web"}, wantErr: "invalid token audience"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewJWTVerifier(tt.cfg).validateClaims(tt.claims)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateClaims() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("validateClaims() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// lastUsedInterval API Key 最近使用时间的更新间隔，避免每个请求都写库
+const lastUsedInterval = time.Minute
+
+// Authenticator 校验请求凭据：以 gwf_ 开头的为 API Key，其余按 JWT 校验
+type Authenticator struct {
+	apiKeys repository.APIKeyRepository
+	jwt     *JWTVerifier
+}
+
+// NewAuthenticator 创建认证器，jwt 为 nil 时只接受 API Key
+func NewAuthenticator(apiKeys repository.APIKeyRepository, jwt *JWTVerifier) *Authenticator {
+	return &Authenticator{apiKeys: apiKeys, jwt: jwt}
+}
+
+// Authenticate 校验请求凭据并返回认证主体
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	return a.AuthenticateToken(credential(r))
+}
+
+// AuthenticateToken 校验凭据字符串并返回认证主体，供 gRPC 等非 HTTP 传输复用
+func (a *Authenticator) AuthenticateToken(token string) (*Principal, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if IsAPIKey(token) {
+		return a.authenticateAPIKey(token)
+	}
+	if a.jwt == nil {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrUnauthenticated)
+	}
+	p, err := a.jwt.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	return p, nil
+}
+
+func (a *Authenticator) authenticateAPIKey(token string) (*Principal, error) {
+	key, err := a.apiKeys.GetByHash(HashAPIKey(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid api key", ErrUnauthenticated)
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, fmt.Errorf("%w: api key revoked or expired", ErrUnauthenticated)
+	}
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) > lastUsedInterval {
+		go func() {
+			if err := a.apiKeys.TouchLastUsed(key.ID, now); err != nil {
+				logger.Warnf("Failed to update api key %d last used time: %v", key.ID, err)
+			}
+		}()
+	}
+
+	return &Principal{
+		UserID:   key.UserID,
+		Subject:  key.Prefix,
+		Method:   MethodAPIKey,
+		APIKeyID: key.ID,
+	}, nil
+}
+
+// accessTokenParam 查询参数形式的凭据，仅 SSE 接口接受
+const accessTokenParam = "access_token"
+
+// isStreamPath 判断是否为 SSE 接口（事件流与执行日志流，含旧版路径）
+func isStreamPath(path string) bool {
+	return path == "/api/events" || path == "/api/v1/events" ||
+		strings.HasSuffix(path, "/execution-logs:stream") || strings.HasSuffix(path, "/execution-logs/stream")
+}
+
+// StripAccessToken 返回去掉 access_token 查询参数的 URL 副本，避免凭据出现在日志与追踪中
+func StripAccessToken(u *url.URL) *url.URL {
+	if !strings.Contains(u.RawQuery, accessTokenParam) {
+		return u
+	}
+	query := u.Query()
+	query.Del(accessTokenParam)
+	stripped := *u
+	stripped.RawQuery = query.Encode()
+	return &stripped
+}
+
+// credential 依次从 Authorization: Bearer、X-API-Key 请求头获取凭据；
+// SSE 接口的 GET 请求还接受 access_token 查询参数，供无法设置请求头的 EventSource 使用
+func credential(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if scheme, token, ok := strings.Cut(h, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if r.Method == http.MethodGet && isStreamPath(r.URL.Path) {
+		return r.URL.Query().Get(accessTokenParam)
+	}
+	return ""
+}
+
+// publicAPIPaths /api/ 下无需认证的路径
+var publicAPIPaths = map[string]bool{
+	"/api/openapi.json": true,
+}
+
+// Middleware 要求 /api/ 下的请求通过认证，并将认证主体写入请求上下文；
+// 静态页面、/health、/metrics 与 OpenAPI 文档不需要认证
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || publicAPIPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := a.Authenticate(r)
+		if err != nil {
+			logger.Ctx(r.Context()).Debugf("Authentication failed for %s %s: %v", r.Method, r.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goworkflow"`)
+			response.Error(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), p)
+		ctx = logger.WithFields(ctx, "user_id", p.UserID, "auth_method", string(p.Method))
+		r = r.WithContext(ctx)
+		r.URL = StripAccessToken(r.URL)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCredential(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header map[string]string
+		want   string
+	}{
+		{name: "bearer header", method: http.MethodPost, target: "/api/v1/jobs", header: map[string]string{"Authorization": "Bearer abc"}, want: "abc"},
+		{name: "api key header", method: http.MethodGet, target: "/api/v1/jobs", header: map[string]string{"X-API-Key": "gwf_key"}, want: "gwf_key"},
+		{name: "header wins over query", method: http.MethodGet, target: "/api/v1/events?access_token=q", header: map[string]string{"Authorization": "Bearer h"}, want: "h"},
+		{name: "event stream", method: http.MethodGet, target: "/api/v1/events?access_token=q", want: "q"},
+		{name: "legacy event stream", method: http.MethodGet, target: "/api/events?job_id=1&access_token=q", want: "q"},
+		{name: "execution log stream", method: http.MethodGet, target: "/api/v1/jobs/1/tasks/2/execution-logs:stream?access_token=q", want: "q"},
+		{name: "legacy execution log stream", method: http.MethodGet, target: "/api/job-tasks/2/execution-logs/stream?access_token=q", want: "q"},
+		{name: "other GET endpoint", method: http.MethodGet, target: "/api/v1/jobs?access_token=q"},
+		{name: "execution log list", method: http.MethodGet, target: "/api/v1/jobs/1/tasks/2/execution-logs?access_token=q"},
+		{name: "POST to stream path", method: http.MethodPost, target: "/api/v1/events?access_token=q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			if got := credential(r); got != tt.want {
+				t.Fatalf("credential() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripAccessToken(t *testing.T) {
+	tests := []struct {
+		target    string
+		wantQuery string
+	}{
+		{target: "/api/v1/events", wantQuery: ""},
+		{target: "/api/v1/events?access_token=secret", wantQuery: ""},
+		{target: "/api/v1/events?job_id=1&access_token=secret&types=job.completed", wantQuery: "job_id=1&types=job.completed"},
+		{target: "/api/v1/jobs?status=running", wantQuery: "status=running"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := u.String()
+		if got := StripAccessToken(u).RawQuery; got != tt.wantQuery {
+			t.Errorf("StripAccessToken(%q).RawQuery = %q, want %q", tt.target, got, tt.wantQuery)
+		}
+		if u.String() != original {
+			t.Errorf("StripAccessToken(%q) modified its input to %q", tt.target, u.String())
+		}
+	}
+}
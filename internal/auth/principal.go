@@ -0,0 +1,39 @@
+// Package auth REST API 认证：API Key 与 JWT Bearer Token，认证后的主体写入请求上下文
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Method 认证方式
+type Method string
+
+const (
+	MethodAPIKey Method = "api_key"
+	MethodJWT    Method = "jwt"
+)
+
+// ErrUnauthenticated 未提供或无法验证凭据
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Principal 已认证的调用方
+type Principal struct {
+	UserID   int64  `json:"user_id"`
+	Subject  string `json:"subject"`
+	Method   Method `json:"method"`
+	APIKeyID int64  `json:"api_key_id,omitempty"`
+}
+
+type principalKey struct{}
+
+// WithPrincipal 返回携带认证主体的上下文
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext 获取上下文中的认证主体，未启用认证或未认证时返回 false
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -3,32 +3,100 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config 应用配置
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Artifact  ArtifactConfig
+	Webhook   WebhookConfig
+	Retention RetentionConfig
+	Tracing   TracingConfig
+	Log       LogConfig
+	Auth      AuthConfig
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host string
-	Port int
+	Host         string
+	Port         int
+	MaxBodyBytes int64    // 请求体大小上限，0 表示不限制
+	CORSOrigins  []string // 允许跨域访问的来源，空表示不启用 CORS
+	GRPCPort     int      // gRPC 监听端口，0 表示不启用
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DBName   string
-	Charset  string
+	Driver      string // mysql、sqlite 或 postgres
+	Host        string
+	Port        int
+	User        string
+	Password    string
+	DBName      string
+	Charset     string
+	Path        string // sqlite 数据库文件
+	SSLMode     string // postgres 的 sslmode
+	AutoMigrate bool   // 启动时执行 sqlite/postgres 的内置迁移，MySQL 仍手动导入 migrations/*.sql
+}
+
+// ArtifactConfig 制品存储配置
+type ArtifactConfig struct {
+	Backend     string // local 或 s3
+	Dir         string // local 后端的根目录
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3Prefix    string
+}
+
+// WebhookConfig Webhook 投递配置
+type WebhookConfig struct {
+	MaxAttempts int           // 最大投递次数（含首次）
+	RetryBase   time.Duration // 首次重试间隔，之后按指数退避
+	Timeout     time.Duration // 单次请求超时
+}
+
+// RetentionConfig 作业清理配置，保留策略本身通过接口按流程设置
+type RetentionConfig struct {
+	Interval   time.Duration // 后台清理间隔，0 表示只在手动触发时清理
+	BatchSize  int           // 每个事务清理的作业数
+	ArchiveDir string        // archive_file 方式的归档目录
+}
+
+// TracingConfig 追踪导出配置，变量名与 OpenTelemetry SDK 保持一致
+type TracingConfig struct {
+	Exporter     string // none、otlp 或 stdout
+	OTLPEndpoint string // OTLP/HTTP 地址，如 http://localhost:4318
+	OTLPHeaders  string // 附加请求头，格式 k1=v1,k2=v2
+	ServiceName  string
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Format string // logfmt 或 json
+	Level  string // debug、info、warn、error，可通过 PUT /api/log-level 运行时调整
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	Enabled       bool   // 启用后 /api/ 下的请求需携带 API Key 或 JWT
+	JWTSecret     string // HS256/384/512 共享密钥
+	JWKSURL       string // RS*/ES* 公钥集地址
+	JWTIssuer     string // 非空时校验 iss
+	JWTAudience   string // 非空时校验 aud
+	JWTUserClaim  string // 承载用户 ID 的声明名
+	JWTAllowNoExp bool   // 接受不含 exp 的 JWT
 }
 
 // Load 加载配置
@@ -38,24 +106,95 @@ func Load() *Config {
 		log.Printf("Warning: Could not load .env file: %v", err)
 	}
 
+	dbDriver := strings.ToLower(getEnv("DB_DRIVER", "mysql"))
+
 	return &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Port: getEnvAsInt("SERVER_PORT", 8080),
+			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:         getEnvAsInt("SERVER_PORT", 8080),
+			MaxBodyBytes: int64(getEnvAsInt("SERVER_MAX_BODY_BYTES", 1<<20)),
+			CORSOrigins:  getEnvAsList("CORS_ALLOWED_ORIGINS"),
+			GRPCPort:     getEnvAsInt("GRPC_PORT", 9090),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 3306),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "workflow"),
-			Charset:  getEnv("DB_CHARSET", "utf8mb4"),
+			Driver:      dbDriver,
+			Host:        getEnv("DB_HOST", "localhost"),
+			Port:        getEnvAsInt("DB_PORT", defaultDBPort(dbDriver)),
+			User:        getEnv("DB_USER", "root"),
+			Password:    getEnv("DB_PASSWORD", ""),
+			DBName:      getEnv("DB_NAME", "workflow"),
+			Charset:     getEnv("DB_CHARSET", "utf8mb4"),
+			Path:        getEnv("DB_PATH", "./workflow.db"),
+			SSLMode:     getEnv("DB_SSLMODE", "disable"),
+			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", true),
+		},
+		Artifact: ArtifactConfig{
+			Backend:     getEnv("ARTIFACT_BACKEND", "local"),
+			Dir:         getEnv("ARTIFACT_DIR", "./artifacts"),
+			S3Endpoint:  getEnv("ARTIFACT_S3_ENDPOINT", ""),
+			S3Region:    getEnv("ARTIFACT_S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("ARTIFACT_S3_BUCKET", ""),
+			S3AccessKey: getEnv("ARTIFACT_S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("ARTIFACT_S3_SECRET_KEY", ""),
+			S3Prefix:    getEnv("ARTIFACT_S3_PREFIX", ""),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			RetryBase:   getEnvAsDuration("WEBHOOK_RETRY_BASE", 10*time.Second),
+			Timeout:     getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+		Retention: RetentionConfig{
+			Interval:   getEnvAsDuration("RETENTION_INTERVAL", time.Hour),
+			BatchSize:  getEnvAsInt("RETENTION_BATCH_SIZE", 100),
+			ArchiveDir: getEnv("RETENTION_ARCHIVE_DIR", "./archives"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("OTEL_TRACES_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+			OTLPHeaders:  getEnv("OTEL_EXPORTER_OTLP_HEADERS", ""),
+			ServiceName:  getEnv("OTEL_SERVICE_NAME", "goworkflow"),
+		},
+		Log: LogConfig{
+			Format: getEnv("LOG_FORMAT", "logfmt"),
+			Level:  getEnv("LOG_LEVEL", "info"),
+		},
+		Auth: AuthConfig{
+			Enabled:       getEnvAsBool("AUTH_ENABLED", false),
+			JWTSecret:     getEnv("AUTH_JWT_SECRET", ""),
+			JWKSURL:       getEnv("AUTH_JWKS_URL", ""),
+			JWTIssuer:     getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience:   getEnv("AUTH_JWT_AUDIENCE", ""),
+			JWTUserClaim:  getEnv("AUTH_JWT_USER_CLAIM", "sub"),
+			JWTAllowNoExp: getEnvAsBool("AUTH_JWT_ALLOW_NO_EXP", false),
 		},
 	}
 }
 
+// defaultDBPort 返回驱动的默认端口
+func defaultDBPort(driver string) int {
+	switch driver {
+	case "postgres", "postgresql", "pgx":
+		return 5432
+	}
+	return 3306
+}
+
 // GetDSN 获取数据库连接字符串
 func (c *DatabaseConfig) GetDSN() string {
+	switch c.Driver {
+	case "sqlite", "sqlite3":
+		// 启用外键约束；WAL 与 busy_timeout 允许读写并发，事务开始即取得写锁以避免升级锁时死锁
+		return c.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	case "postgres", "postgresql", "pgx":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.User, c.Password),
+			Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+			Path:     "/" + c.DBName,
+			RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+		}
+		return dsn.String()
+	}
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
 		c.User,
 		c.Password,
@@ -82,3 +221,30 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsDuration 获取环境变量并解析为 time.Duration（如 10s、1m）
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsBool 获取环境变量并解析为 bool（true/false/1/0）
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsList 读取逗号分隔的环境变量，忽略空项
+func getEnvAsList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
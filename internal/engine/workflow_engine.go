@@ -1,12 +1,17 @@
 package engine
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
 )
 
 // WorkflowEngine 工作流引擎接口
@@ -14,9 +19,18 @@ type WorkflowEngine interface {
 	// CreateJob 创建作业实例
 	CreateJob(flowID int64, jobName string, createdBy int64) (*models.Job, error)
 
+	// CreateJobBatch 在同一事务中创建作业批次及其作业，每个作业的流程输入写入共享上下文
+	CreateJobBatch(batch *models.JobBatch, items []models.JobBatchItem) ([]models.Job, error)
+
+	// RerunJob 在同一事务中创建重新运行的作业：写入输入，并将 reuse 中的任务标记为已完成、复制其输出
+	RerunJob(job *models.Job, inputs map[string]interface{}, reuse []models.TaskReuse) error
+
 	// StartJob 启动作业
 	StartJob(jobID int64) error
 
+	// CancelJob 取消待执行或执行中的作业
+	CancelJob(jobID int64, operatorID int64, reason string) error
+
 	// StartTask 开始执行任务
 	StartTask(jobTaskID int64, executorID int64) error
 
@@ -32,71 +46,383 @@ type WorkflowEngine interface {
 	// RollbackTask 打回任务
 	RollbackTask(jobTaskID int64, operatorID int64, targetSequence int) error
 
+	// RetryTask 重试失败的任务
+	RetryTask(jobTaskID int64, operatorID int64) error
+
+	// ReassignTask 转派任务给其他执行人
+	ReassignTask(jobTaskID int64, operatorID int64, assigneeID int64) error
+
 	// GetNextTask 获取下一个待执行的任务
 	GetNextTask(jobID int64) (*models.JobTask, error)
 
 	// GetCurrentTask 获取当前执行中的任务
 	GetCurrentTask(jobID int64) (*models.JobTask, error)
+
+	// WithContext 返回在 ctx 下执行的引擎，状态变更及其 SQL 会记录为 ctx 中追踪的子 Span
+	WithContext(ctx context.Context) WorkflowEngine
 }
 
 type workflowEngine struct {
-	db              *sql.DB
-	jobRepo         repository.JobRepository
-	jobTaskRepo     repository.JobTaskRepository
-	flowRepo        repository.FlowRepository
-	flowTaskRepo    repository.FlowTaskRepository
+	db             *sql.DB
+	jobRepo        repository.JobRepository
+	jobTaskRepo    repository.JobTaskRepository
+	jobTaskLogRepo repository.JobTaskLogRepository
+	flowRepo       repository.FlowRepository
+	flowTaskRepo   repository.FlowTaskRepository
+	jobContextRepo repository.JobContextRepository
+	jobBatchRepo   repository.JobBatchRepository
+	bus            *events.Bus
+	ctx            context.Context
+	// mu 未使用数据库（db 为 nil）时串行执行状态变更
+	mu *sync.Mutex
 }
 
-// NewWorkflowEngine 创建工作流引擎
+// NewWorkflowEngine 创建工作流引擎；db 为 nil 时（如使用内存仓储）不开启数据库事务，状态变更串行执行，
+// 内存仓储在状态变更失败时撤销其全部写入
 func NewWorkflowEngine(
 	db *sql.DB,
 	jobRepo repository.JobRepository,
 	jobTaskRepo repository.JobTaskRepository,
+	jobTaskLogRepo repository.JobTaskLogRepository,
 	flowRepo repository.FlowRepository,
 	flowTaskRepo repository.FlowTaskRepository,
+	bus *events.Bus,
+	jobContextRepo repository.JobContextRepository,
+	jobBatchRepo repository.JobBatchRepository,
 ) WorkflowEngine {
 	return &workflowEngine{
-		db:           db,
-		jobRepo:      jobRepo,
-		jobTaskRepo:  jobTaskRepo,
-		flowRepo:     flowRepo,
-		flowTaskRepo: flowTaskRepo,
+		db:             db,
+		jobRepo:        jobRepo,
+		jobTaskRepo:    jobTaskRepo,
+		jobTaskLogRepo: jobTaskLogRepo,
+		flowRepo:       flowRepo,
+		flowTaskRepo:   flowTaskRepo,
+		jobContextRepo: jobContextRepo,
+		jobBatchRepo:   jobBatchRepo,
+		bus:            bus,
+		ctx:            context.Background(),
+		mu:             &sync.Mutex{},
 	}
 }
 
-// CreateJob 创建作业实例
-func (e *workflowEngine) CreateJob(flowID int64, jobName string, createdBy int64) (*models.Job, error) {
-	// 获取流程及其任务
-	flow, flowTasks, err := e.flowRepo.GetFlowWithTasks(flowID)
+// WithContext 返回在 ctx 下执行的引擎
+func (e *workflowEngine) WithContext(ctx context.Context) WorkflowEngine {
+	engine := *e
+	engine.ctx = ctx
+	return &engine
+}
+
+// txRepos 绑定到同一事务的仓储，以及事务提交后待发布的事件
+type txRepos struct {
+	jobs      repository.JobRepository
+	jobTasks  repository.JobTaskRepository
+	logs      repository.JobTaskLogRepository
+	contexts  repository.JobContextRepository
+	batches   repository.JobBatchRepository
+	flowTasks repository.FlowTaskRepository
+	events    *[]events.Event
+}
+
+// atomicRepository 由内存仓储实现，见 repository.MemoryStore.Atomically
+type atomicRepository interface {
+	Atomically(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// inTx 在事务中执行状态变更，状态更新与审计日志同时提交或回滚；提交成功后发布状态变更事件。
+// operation 为追踪中的 Span 名称
+func (e *workflowEngine) inTx(operation string, fn func(r txRepos) error) (err error) {
+	ctx, span := tracing.Start(e.ctx, "engine."+operation)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if e.db == nil {
+		return e.inMemory(ctx, operation, fn)
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get flow: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if !flow.IsActive {
-		return nil, fmt.Errorf("flow is not active")
+	var pending []events.Event
+	if err := fn(txRepos{
+		jobs:      e.jobRepo.WithTx(tx).WithContext(ctx),
+		jobTasks:  e.jobTaskRepo.WithTx(tx).WithContext(ctx),
+		logs:      e.jobTaskLogRepo.WithTx(tx).WithContext(ctx),
+		contexts:  e.jobContextRepo.WithTx(tx).WithContext(ctx),
+		batches:   e.jobBatchRepo.WithTx(tx).WithContext(ctx),
+		flowTasks: e.flowTaskRepo.WithTx(tx).WithContext(ctx),
+		events:    &pending,
+	}); err != nil {
+		return err
 	}
 
-	if len(flowTasks) == 0 {
-		return nil, fmt.Errorf("flow has no tasks")
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	logger.Ctx(ctx).Debugf("Engine %s committed with %d events", operation, len(pending))
 
-	// 开始事务
-	tx, err := e.db.Begin()
+	e.publish(pending)
+	return nil
+}
+
+// inMemory 不使用数据库事务执行状态变更，各状态变更之间互斥；完成后发布状态变更事件。
+// 作业仓储实现 atomicRepository（内存仓储）时，状态变更失败会撤销其全部写入
+func (e *workflowEngine) inMemory(ctx context.Context, operation string, fn func(r txRepos) error) error {
+	var pending []events.Event
+	apply := func(ctx context.Context) error {
+		pending = nil
+		return fn(txRepos{
+			jobs:      e.jobRepo.WithContext(ctx),
+			jobTasks:  e.jobTaskRepo.WithContext(ctx),
+			logs:      e.jobTaskLogRepo.WithContext(ctx),
+			contexts:  e.jobContextRepo.WithContext(ctx),
+			batches:   e.jobBatchRepo.WithContext(ctx),
+			flowTasks: e.flowTaskRepo.WithContext(ctx),
+			events:    &pending,
+		})
+	}
+
+	e.mu.Lock()
+	var err error
+	if atomic, ok := e.jobRepo.(atomicRepository); ok {
+		err = atomic.Atomically(ctx, apply)
+	} else {
+		err = apply(ctx)
+	}
+	e.mu.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return err
+	}
+	logger.Ctx(ctx).Debugf("Engine %s applied with %d events", operation, len(pending))
+
+	e.publish(pending)
+	return nil
+}
+
+// publish 补全事件所属项目与流程后发布到事件总线
+func (e *workflowEngine) publish(pending []events.Event) {
+	if e.bus == nil {
+		return
+	}
+
+	jobs := make(map[int64]*models.Job)
+	for _, event := range pending {
+		if event.FlowID == 0 || event.ProjectID == 0 {
+			job, ok := jobs[event.JobID]
+			if !ok {
+				job, _ = e.jobRepo.GetByID(event.JobID)
+				jobs[event.JobID] = job
+			}
+			if job != nil {
+				event.FlowID = job.FlowID
+				event.ProjectID = job.ProjectID
+			}
+		}
+		e.bus.Publish(event)
+	}
+}
+
+// emit 记录事务提交后待发布的事件
+func (r txRepos) emit(event events.Event) {
+	*r.events = append(*r.events, event)
+}
+
+// emitJobTask 记录作业任务状态变更事件
+func (r txRepos) emitJobTask(eventType events.Type, jobTask *models.JobTask, data map[string]interface{}) {
+	r.emit(events.Event{
+		Type:      eventType,
+		JobID:     jobTask.JobID,
+		JobTaskID: jobTask.ID,
+		Status:    string(jobTask.Status),
+		Data:      data,
+	})
+}
+
+// writeLog 写入作业任务审计日志
+func (r txRepos) writeLog(jobTaskID int64, action models.LogAction, operatorID int64, message string, metadata models.LogMetadata) error {
+	log := &models.JobTaskLog{
+		JobTaskID:  jobTaskID,
+		Action:     action,
+		OperatorID: sql.NullInt64{Int64: operatorID, Valid: operatorID > 0},
+		Message:    message,
+		Metadata:   metadata,
+	}
+	return r.logs.Create(log)
+}
+
+// CreateJob 创建作业实例
+func (e *workflowEngine) CreateJob(flowID int64, jobName string, createdBy int64) (*models.Job, error) {
+	flow, flowTasks, err := e.flowForJobs(flowID)
+	if err != nil {
+		return nil, err
 	}
-	defer tx.Rollback()
 
 	// 创建作业
 	job := &models.Job{
+		ProjectID: flow.ProjectID,
 		FlowID:    flowID,
 		JobName:   jobName,
 		Status:    models.JobStatusPending,
 		CreatedBy: createdBy,
 	}
 
-	if err := e.jobRepo.Create(job); err != nil {
-		return nil, fmt.Errorf("failed to create job: %w", err)
+	err = e.inTx("CreateJob", func(r txRepos) error {
+		return createJob(r, job, flowTasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// CreateJobBatch 在同一事务中创建作业批次及其作业，任一作业创建失败时整批回滚
+func (e *workflowEngine) CreateJobBatch(batch *models.JobBatch, items []models.JobBatchItem) ([]models.Job, error) {
+	flow, flowTasks, err := e.flowForJobs(batch.FlowID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.ProjectID = flow.ProjectID
+	batch.TotalJobs = len(items)
+	jobs := make([]models.Job, len(items))
+
+	err = e.inTx("CreateJobBatch", func(r txRepos) error {
+		if err := r.batches.Create(batch); err != nil {
+			return err
+		}
+
+		for i, item := range items {
+			jobs[i] = models.Job{
+				ProjectID: flow.ProjectID,
+				FlowID:    batch.FlowID,
+				BatchID:   sql.NullInt64{Int64: batch.ID, Valid: true},
+				JobName:   item.JobName,
+				Status:    models.JobStatusPending,
+				CreatedBy: batch.CreatedBy,
+			}
+			if err := createJob(r, &jobs[i], flowTasks); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+
+			// 流程输入写入作业共享上下文
+			for key, value := range item.Inputs {
+				if err := r.contexts.Set(jobs[i].ID, models.ContextScopeShared, key, value); err != nil {
+					return fmt.Errorf("row %d: failed to set input %s: %w", i+1, key, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// RerunJob 创建重新运行 job.RerunOf 的作业。reuse 中的任务按序号对应新作业的作业任务，
+// 直接标记为已完成并复制来源作业任务的结果与输出命名空间，启动后从第一个待执行任务开始
+func (e *workflowEngine) RerunJob(job *models.Job, inputs map[string]interface{}, reuse []models.TaskReuse) error {
+	flow, flowTasks, err := e.flowForJobs(job.FlowID)
+	if err != nil {
+		return err
+	}
+
+	job.ProjectID = flow.ProjectID
+	job.Status = models.JobStatusPending
+
+	return e.inTx("RerunJob", func(r txRepos) error {
+		if err := createJob(r, job, flowTasks); err != nil {
+			return err
+		}
+
+		for key, value := range inputs {
+			if err := r.contexts.Set(job.ID, models.ContextScopeShared, key, value); err != nil {
+				return fmt.Errorf("failed to set input %s: %w", key, err)
+			}
+		}
+		if len(reuse) == 0 {
+			return nil
+		}
+
+		jobTasks, err := r.jobTasks.GetByJobID(job.ID)
+		if err != nil {
+			return err
+		}
+		bySequence := make(map[int]*models.JobTask, len(jobTasks))
+		for i := range jobTasks {
+			bySequence[jobTasks[i].Sequence] = &jobTasks[i]
+		}
+
+		now := time.Now()
+		for i := range reuse {
+			jobTask, ok := bySequence[reuse[i].Sequence]
+			if !ok {
+				return fmt.Errorf("no job task at sequence %d", reuse[i].Sequence)
+			}
+
+			jobTask.Status = models.JobTaskStatusCompleted
+			jobTask.StartedAt = sql.NullTime{Time: now, Valid: true}
+			jobTask.CompletedAt = sql.NullTime{Time: now, Valid: true}
+			jobTask.Result = reuse[i].Result
+			if err := r.jobTasks.Update(jobTask); err != nil {
+				return err
+			}
+			reuse[i].JobTaskID = jobTask.ID
+
+			// 复制任务输出命名空间，供后续任务的 ${tasks.*} 表达式与执行器读取
+			scope := models.TaskContextScope(reuse[i].TaskName)
+			outputs, err := r.contexts.GetScope(job.RerunOf.Int64, scope)
+			if err != nil {
+				return err
+			}
+			for key, value := range outputs {
+				if err := r.contexts.Set(job.ID, scope, key, value); err != nil {
+					return fmt.Errorf("failed to copy output %s of task %s: %w", key, reuse[i].TaskName, err)
+				}
+			}
+
+			if err := r.writeLog(jobTask.ID, models.LogActionReuse, job.CreatedBy,
+				fmt.Sprintf("reused output of job %d", job.RerunOf.Int64), models.LogMetadata{
+					"sequence":           jobTask.Sequence,
+					"source_job_id":      job.RerunOf.Int64,
+					"source_job_task_id": reuse[i].SourceJobTaskID,
+				}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// flowForJobs 获取用于创建作业的流程及其任务，流程须已启用且包含任务
+func (e *workflowEngine) flowForJobs(flowID int64) (*models.Flow, []models.FlowTask, error) {
+	// 获取流程及其任务
+	flow, flowTasks, err := e.flowRepo.GetFlowWithTasks(flowID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get flow: %w", err)
+	}
+
+	if !flow.IsActive {
+		return nil, nil, fmt.Errorf("flow is not active")
+	}
+
+	if len(flowTasks) == 0 {
+		return nil, nil, fmt.Errorf("flow has no tasks")
+	}
+
+	return flow, flowTasks, nil
+}
+
+// createJob 创建作业及其作业任务
+func createJob(r txRepos, job *models.Job, flowTasks []models.FlowTask) error {
+	if err := r.jobs.Create(job); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
 	}
 
 	// 为每个流程任务创建作业任务
@@ -112,237 +438,483 @@ func (e *workflowEngine) CreateJob(flowID int64, jobName string, createdBy int64
 		}
 	}
 
-	if err := e.jobTaskRepo.BatchCreate(jobTasks); err != nil {
-		return nil, fmt.Errorf("failed to create job tasks: %w", err)
-	}
-
-	// 提交事务
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	if err := r.jobTasks.BatchCreate(jobTasks); err != nil {
+		return fmt.Errorf("failed to create job tasks: %w", err)
 	}
 
-	return job, nil
+	r.emit(events.Event{Type: events.JobCreated, ProjectID: job.ProjectID, JobID: job.ID, FlowID: job.FlowID, Status: string(job.Status)})
+	return nil
 }
 
 // StartJob 启动作业
 func (e *workflowEngine) StartJob(jobID int64) error {
-	job, err := e.jobRepo.GetByID(jobID)
-	if err != nil {
-		return err
-	}
+	return e.inTx("StartJob", func(r txRepos) error {
+		job, err := r.jobs.GetByID(jobID)
+		if err != nil {
+			return err
+		}
 
-	if job.Status != models.JobStatusPending {
-		return fmt.Errorf("job is not in pending status")
-	}
+		if job.Status != models.JobStatusPending {
+			return fmt.Errorf("job is not in pending status")
+		}
+
+		// 从第一个待执行的任务开始（重新运行的作业中复用输出的任务已完成）
+		sequence := int64(1)
+		nextTask, err := nextPendingTask(r.jobTasks, jobID)
+		if err != nil {
+			return err
+		}
+		if nextTask != nil {
+			sequence = int64(nextTask.Sequence)
+		}
 
-	// 更新作业状态
-	job.Status = models.JobStatusRunning
-	job.StartedAt = sql.NullTime{Time: time.Now(), Valid: true}
-	job.CurrentTaskSeq = sql.NullInt64{Int64: 1, Valid: true}
+		// 更新作业状态
+		job.Status = models.JobStatusRunning
+		job.StartedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		job.CurrentTaskSeq = sql.NullInt64{Int64: sequence, Valid: true}
 
-	return e.jobRepo.Update(job)
+		if err := r.jobs.Update(job); err != nil {
+			return err
+		}
+
+		r.emit(events.Event{Type: events.JobStarted, ProjectID: job.ProjectID, JobID: job.ID, FlowID: job.FlowID, Status: string(job.Status)})
+		return emitApprovalRequired(r, job.ID)
+	})
+}
+
+// CancelJob 取消待执行或执行中的作业：执行中的任务标记为失败，其余任务保持不变，作业不再推进
+func (e *workflowEngine) CancelJob(jobID int64, operatorID int64, reason string) error {
+	return e.inTx("CancelJob", func(r txRepos) error {
+		job, err := r.jobs.GetByID(jobID)
+		if err != nil {
+			return err
+		}
+
+		if job.Status != models.JobStatusPending && job.Status != models.JobStatusRunning {
+			return fmt.Errorf("job is not in pending or running status")
+		}
+
+		message := "job cancelled"
+		if reason != "" {
+			message += ": " + reason
+		}
+
+		jobTasks, err := r.jobTasks.GetByJobID(jobID)
+		if err != nil {
+			return err
+		}
+
+		// 审计日志记录在执行中的任务上，没有时记录在第一个待执行的任务上
+		var logged *models.JobTask
+		for i := range jobTasks {
+			jobTask := &jobTasks[i]
+			if jobTask.Status == models.JobTaskStatusRunning {
+				jobTask.Status = models.JobTaskStatusFailed
+				jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				jobTask.ErrorMessage = message
+				if err := r.jobTasks.Update(jobTask); err != nil {
+					return err
+				}
+				r.emitJobTask(events.JobTaskFailed, jobTask, map[string]interface{}{"sequence": jobTask.Sequence, "error": message})
+				logged = jobTask
+			}
+			if logged == nil && jobTask.Status == models.JobTaskStatusPending {
+				logged = jobTask
+			}
+		}
+		if logged != nil {
+			if err := r.writeLog(logged.ID, models.LogActionCancel, operatorID, message, models.LogMetadata{
+				"sequence":        logged.Sequence,
+				"previous_status": job.Status,
+				"reason":          reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		job.Status = models.JobStatusCancelled
+		job.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		if err := r.jobs.Update(job); err != nil {
+			return err
+		}
+
+		r.emit(events.Event{Type: events.JobCancelled, ProjectID: job.ProjectID, JobID: job.ID, FlowID: job.FlowID, Status: string(job.Status),
+			Data: map[string]interface{}{"reason": reason}})
+		return nil
+	})
 }
 
 // StartTask 开始执行任务
 func (e *workflowEngine) StartTask(jobTaskID int64, executorID int64) error {
-	jobTask, err := e.jobTaskRepo.GetByID(jobTaskID)
-	if err != nil {
-		return err
-	}
+	return e.inTx("StartTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
 
-	if jobTask.Status != models.JobTaskStatusPending {
-		return fmt.Errorf("job task is not in pending status")
-	}
+		if jobTask.Status != models.JobTaskStatusPending {
+			return fmt.Errorf("job task is not in pending status")
+		}
+
+		// 更新任务状态
+		jobTask.Status = models.JobTaskStatusRunning
+		jobTask.StartedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		jobTask.ExecutorID = sql.NullInt64{Int64: executorID, Valid: true}
+
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
 
-	// 更新任务状态
-	jobTask.Status = models.JobTaskStatusRunning
-	jobTask.StartedAt = sql.NullTime{Time: time.Now(), Valid: true}
-	jobTask.ExecutorID = sql.NullInt64{Int64: executorID, Valid: true}
+		if err := r.writeLog(jobTaskID, models.LogActionStart, executorID, "task started", models.LogMetadata{
+			"sequence": jobTask.Sequence,
+		}); err != nil {
+			return err
+		}
 
-	return e.jobTaskRepo.Update(jobTask)
+		r.emitJobTask(events.JobTaskStarted, jobTask, map[string]interface{}{"sequence": jobTask.Sequence})
+		return nil
+	})
 }
 
 // CompleteTask 完成任务
 func (e *workflowEngine) CompleteTask(jobTaskID int64, result models.TaskResult) error {
-	jobTask, err := e.jobTaskRepo.GetByID(jobTaskID)
-	if err != nil {
-		return err
-	}
-
-	if jobTask.Status != models.JobTaskStatusRunning {
-		return fmt.Errorf("job task is not in running status")
-	}
+	return e.inTx("CompleteTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
 
-	// 更新任务状态
-	jobTask.Status = models.JobTaskStatusCompleted
-	jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
-	jobTask.Result = result
+		if jobTask.Status != models.JobTaskStatusRunning {
+			return fmt.Errorf("job task is not in running status")
+		}
 
-	if err := e.jobTaskRepo.Update(jobTask); err != nil {
-		return err
-	}
+		// 更新任务状态
+		jobTask.Status = models.JobTaskStatusCompleted
+		jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		jobTask.Result = result
 
-	// 检查是否有下一个任务
-	nextTask, err := e.GetNextTask(jobTask.JobID)
-	if err != nil || nextTask == nil {
-		// 没有下一个任务，完成作业
-		return e.completeJob(jobTask.JobID)
-	}
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
 
-	// 更新作业的当前任务序号
-	job, err := e.jobRepo.GetByID(jobTask.JobID)
-	if err != nil {
-		return err
-	}
+		resultKeys := make([]string, 0, len(result))
+		for key := range result {
+			resultKeys = append(resultKeys, key)
+		}
+		if err := r.writeLog(jobTaskID, models.LogActionComplete, jobTask.ExecutorID.Int64, "task completed", models.LogMetadata{
+			"sequence":    jobTask.Sequence,
+			"result_keys": resultKeys,
+		}); err != nil {
+			return err
+		}
 
-	job.CurrentTaskSeq = sql.NullInt64{Int64: int64(nextTask.Sequence), Valid: true}
-	return e.jobRepo.Update(job)
+		r.emitJobTask(events.JobTaskCompleted, jobTask, map[string]interface{}{"sequence": jobTask.Sequence})
+		return advanceJob(r, jobTask.JobID)
+	})
 }
 
 // FailTask 任务失败
 func (e *workflowEngine) FailTask(jobTaskID int64, errorMessage string) error {
-	jobTask, err := e.jobTaskRepo.GetByID(jobTaskID)
-	if err != nil {
-		return err
-	}
+	return e.inTx("FailTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
 
-	if jobTask.Status != models.JobTaskStatusRunning {
-		return fmt.Errorf("job task is not in running status")
-	}
+		if jobTask.Status != models.JobTaskStatusRunning {
+			return fmt.Errorf("job task is not in running status")
+		}
 
-	// 更新任务状态
-	jobTask.Status = models.JobTaskStatusFailed
-	jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
-	jobTask.ErrorMessage = errorMessage
+		// 更新任务状态
+		jobTask.Status = models.JobTaskStatusFailed
+		jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		jobTask.ErrorMessage = errorMessage
 
-	if err := e.jobTaskRepo.Update(jobTask); err != nil {
-		return err
-	}
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
 
-	// 更新作业状态为失败
-	return e.jobRepo.UpdateStatus(jobTask.JobID, models.JobStatusFailed)
+		if err := r.writeLog(jobTaskID, models.LogActionFail, jobTask.ExecutorID.Int64, "task failed", models.LogMetadata{
+			"sequence": jobTask.Sequence,
+			"error":    errorMessage,
+		}); err != nil {
+			return err
+		}
+
+		// 更新作业状态为失败
+		if err := r.jobs.UpdateStatus(jobTask.JobID, models.JobStatusFailed); err != nil {
+			return err
+		}
+
+		r.emitJobTask(events.JobTaskFailed, jobTask, map[string]interface{}{"sequence": jobTask.Sequence, "error": errorMessage})
+		r.emit(events.Event{Type: events.JobFailed, JobID: jobTask.JobID, JobTaskID: jobTask.ID, Status: string(models.JobStatusFailed)})
+		return nil
+	})
 }
 
 // SkipTask 跳过任务
 func (e *workflowEngine) SkipTask(jobTaskID int64, operatorID int64) error {
-	jobTask, err := e.jobTaskRepo.GetByID(jobTaskID)
-	if err != nil {
-		return err
-	}
-
-	if jobTask.Status != models.JobTaskStatusPending {
-		return fmt.Errorf("job task is not in pending status")
-	}
+	return e.inTx("SkipTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
 
-	// 检查任务是否可跳过
-	flowTask, err := e.flowTaskRepo.GetByID(jobTask.FlowTaskID)
-	if err != nil {
-		return err
-	}
+		if jobTask.Status != models.JobTaskStatusPending {
+			return fmt.Errorf("job task is not in pending status")
+		}
 
-	if !flowTask.IsOptional {
-		return fmt.Errorf("task is not optional and cannot be skipped")
-	}
+		// 检查任务是否可跳过
+		flowTask, err := r.flowTasks.GetByID(jobTask.FlowTaskID)
+		if err != nil {
+			return err
+		}
 
-	// 更新任务状态
-	jobTask.Status = models.JobTaskStatusSkipped
-	jobTask.IsSkipped = true
-	jobTask.ExecutorID = sql.NullInt64{Int64: operatorID, Valid: true}
-	jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		if !flowTask.IsOptional {
+			return fmt.Errorf("task is not optional and cannot be skipped")
+		}
 
-	if err := e.jobTaskRepo.Update(jobTask); err != nil {
-		return err
-	}
+		// 更新任务状态
+		jobTask.Status = models.JobTaskStatusSkipped
+		jobTask.IsSkipped = true
+		jobTask.ExecutorID = sql.NullInt64{Int64: operatorID, Valid: true}
+		jobTask.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
 
-	// 检查是否有下一个任务
-	nextTask, err := e.GetNextTask(jobTask.JobID)
-	if err != nil || nextTask == nil {
-		// 没有下一个任务，完成作业
-		return e.completeJob(jobTask.JobID)
-	}
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
 
-	// 更新作业的当前任务序号
-	job, err := e.jobRepo.GetByID(jobTask.JobID)
-	if err != nil {
-		return err
-	}
+		if err := r.writeLog(jobTaskID, models.LogActionSkip, operatorID, "task skipped", models.LogMetadata{
+			"sequence": jobTask.Sequence,
+		}); err != nil {
+			return err
+		}
 
-	job.CurrentTaskSeq = sql.NullInt64{Int64: int64(nextTask.Sequence), Valid: true}
-	return e.jobRepo.Update(job)
+		r.emitJobTask(events.JobTaskSkipped, jobTask, map[string]interface{}{"sequence": jobTask.Sequence})
+		return advanceJob(r, jobTask.JobID)
+	})
 }
 
 // RollbackTask 打回任务
 func (e *workflowEngine) RollbackTask(jobTaskID int64, operatorID int64, targetSequence int) error {
-	jobTask, err := e.jobTaskRepo.GetByID(jobTaskID)
-	if err != nil {
-		return err
-	}
+	return e.inTx("RollbackTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
 
-	// 检查任务是否允许打回
-	flowTask, err := e.flowTaskRepo.GetByID(jobTask.FlowTaskID)
-	if err != nil {
-		return err
-	}
+		// 检查任务是否允许打回
+		flowTask, err := r.flowTasks.GetByID(jobTask.FlowTaskID)
+		if err != nil {
+			return err
+		}
 
-	if !flowTask.AllowRollback {
-		return fmt.Errorf("task does not allow rollback")
-	}
+		if !flowTask.AllowRollback {
+			return fmt.Errorf("task does not allow rollback")
+		}
 
-	// 获取目标任务
-	targetTask, err := e.jobTaskRepo.GetBySequence(jobTask.JobID, targetSequence)
-	if err != nil {
-		return fmt.Errorf("target task not found: %w", err)
-	}
+		// 获取目标任务
+		targetTask, err := r.jobTasks.GetBySequence(jobTask.JobID, targetSequence)
+		if err != nil {
+			return fmt.Errorf("target task not found: %w", err)
+		}
 
-	if targetSequence >= jobTask.Sequence {
-		return fmt.Errorf("can only rollback to previous tasks")
-	}
+		if targetSequence >= jobTask.Sequence {
+			return fmt.Errorf("can only rollback to previous tasks")
+		}
 
-	// 更新当前任务状态为已打回
-	jobTask.Status = models.JobTaskStatusRolledBack
-	jobTask.ExecutorID = sql.NullInt64{Int64: operatorID, Valid: true}
+		previousStatus := jobTask.Status
 
-	if err := e.jobTaskRepo.Update(jobTask); err != nil {
-		return err
-	}
+		// 更新当前任务状态为已打回
+		jobTask.Status = models.JobTaskStatusRolledBack
+		jobTask.ExecutorID = sql.NullInt64{Int64: operatorID, Valid: true}
 
-	// 重置目标任务及之后的所有任务状态
-	jobTasks, err := e.jobTaskRepo.GetByJobID(jobTask.JobID)
-	if err != nil {
-		return err
-	}
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
 
-	for i := range jobTasks {
-		if jobTasks[i].Sequence >= targetSequence {
-			jobTasks[i].Status = models.JobTaskStatusPending
-			jobTasks[i].IsSkipped = false
-			jobTasks[i].ExecutorID = sql.NullInt64{}
-			jobTasks[i].Result = nil
-			jobTasks[i].ErrorMessage = ""
-			jobTasks[i].StartedAt = sql.NullTime{}
-			jobTasks[i].CompletedAt = sql.NullTime{}
-
-			if err := e.jobTaskRepo.Update(&jobTasks[i]); err != nil {
-				return err
+		// 重置目标任务及之后的所有任务状态，并清除其输出，避免后续任务引用打回前的结果
+		_, jobTasks, err := r.jobs.GetJobWithTasks(jobTask.JobID)
+		if err != nil {
+			return err
+		}
+
+		resetIDs := make([]int64, 0, len(jobTasks))
+		for i := range jobTasks {
+			if jobTasks[i].Sequence >= targetSequence {
+				jobTasks[i].Status = models.JobTaskStatusPending
+				jobTasks[i].IsSkipped = false
+				jobTasks[i].ExecutorID = sql.NullInt64{}
+				jobTasks[i].Result = nil
+				jobTasks[i].ErrorMessage = ""
+				jobTasks[i].StartedAt = sql.NullTime{}
+				jobTasks[i].CompletedAt = sql.NullTime{}
+
+				if err := r.jobTasks.Update(&jobTasks[i]); err != nil {
+					return err
+				}
+				if jobTasks[i].Task != nil {
+					if err := r.contexts.DeleteScope(jobTask.JobID, models.TaskContextScope(jobTasks[i].Task.Name)); err != nil {
+						return fmt.Errorf("failed to clear outputs of task %s: %w", jobTasks[i].Task.Name, err)
+					}
+				}
+				resetIDs = append(resetIDs, jobTasks[i].ID)
 			}
 		}
-	}
 
-	// 更新作业的当前任务序号
-	job, err := e.jobRepo.GetByID(jobTask.JobID)
-	if err != nil {
-		return err
+		if err := r.writeLog(jobTaskID, models.LogActionRollback, operatorID,
+			fmt.Sprintf("task rolled back to sequence %d", targetSequence),
+			models.LogMetadata{
+				"sequence":           jobTask.Sequence,
+				"previous_status":    previousStatus,
+				"target_sequence":    targetSequence,
+				"target_job_task_id": targetTask.ID,
+				"reset_job_task_ids": resetIDs,
+			},
+		); err != nil {
+			return err
+		}
+
+		// 更新作业的当前任务序号
+		job, err := r.jobs.GetByID(jobTask.JobID)
+		if err != nil {
+			return err
+		}
+
+		job.CurrentTaskSeq = sql.NullInt64{Int64: int64(targetTask.Sequence), Valid: true}
+		job.Status = models.JobStatusRunning
+
+		if err := r.jobs.Update(job); err != nil {
+			return err
+		}
+
+		r.emitJobTask(events.JobTaskRolledBack, jobTask, map[string]interface{}{
+			"sequence":        jobTask.Sequence,
+			"target_sequence": targetSequence,
+		})
+		return emitApprovalRequired(r, jobTask.JobID)
+	})
+}
+
+// RetryTask 重试失败作业中失败的任务：任务重置为待执行，作业恢复运行
+func (e *workflowEngine) RetryTask(jobTaskID int64, operatorID int64) error {
+	return e.inTx("RetryTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
+
+		if jobTask.Status != models.JobTaskStatusFailed {
+			return fmt.Errorf("job task is not in failed status")
+		}
+
+		// 已取消或已重新进入运行的作业不允许重试
+		job, err := r.jobs.GetByID(jobTask.JobID)
+		if err != nil {
+			return err
+		}
+		if job.Status != models.JobStatusFailed {
+			return fmt.Errorf("job is not in failed status")
+		}
+
+		previousError := jobTask.ErrorMessage
+
+		jobTask.Status = models.JobTaskStatusPending
+		jobTask.ExecutorID = sql.NullInt64{}
+		jobTask.Result = nil
+		jobTask.ErrorMessage = ""
+		jobTask.StartedAt = sql.NullTime{}
+		jobTask.CompletedAt = sql.NullTime{}
+
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
+
+		if err := r.writeLog(jobTaskID, models.LogActionRetry, operatorID, "task retried", models.LogMetadata{
+			"sequence":       jobTask.Sequence,
+			"previous_error": previousError,
+		}); err != nil {
+			return err
+		}
+
+		job.CurrentTaskSeq = sql.NullInt64{Int64: int64(jobTask.Sequence), Valid: true}
+		job.Status = models.JobStatusRunning
+		job.CompletedAt = sql.NullTime{}
+
+		if err := r.jobs.Update(job); err != nil {
+			return err
+		}
+
+		r.emitJobTask(events.JobTaskRetried, jobTask, map[string]interface{}{"sequence": jobTask.Sequence})
+		return emitApprovalRequired(r, jobTask.JobID)
+	})
+}
+
+// ReassignTask 转派任务给其他执行人（仅限待执行或执行中的任务）
+func (e *workflowEngine) ReassignTask(jobTaskID int64, operatorID int64, assigneeID int64) error {
+	if assigneeID <= 0 {
+		return fmt.Errorf("assignee id is required")
 	}
 
-	job.CurrentTaskSeq = sql.NullInt64{Int64: int64(targetTask.Sequence), Valid: true}
-	job.Status = models.JobStatusRunning
+	return e.inTx("ReassignTask", func(r txRepos) error {
+		jobTask, err := r.jobTasks.GetByID(jobTaskID)
+		if err != nil {
+			return err
+		}
+
+		if jobTask.Status != models.JobTaskStatusPending && jobTask.Status != models.JobTaskStatusRunning {
+			return fmt.Errorf("job task is not in pending or running status")
+		}
+
+		metadata := models.LogMetadata{
+			"sequence":    jobTask.Sequence,
+			"assignee_id": assigneeID,
+		}
+		if jobTask.ExecutorID.Valid {
+			metadata["previous_executor_id"] = jobTask.ExecutorID.Int64
+		}
+
+		jobTask.ExecutorID = sql.NullInt64{Int64: assigneeID, Valid: true}
 
-	return e.jobRepo.Update(job)
+		if err := r.jobTasks.Update(jobTask); err != nil {
+			return err
+		}
+
+		if err := r.writeLog(jobTaskID, models.LogActionReassign, operatorID,
+			fmt.Sprintf("task reassigned to %d", assigneeID), metadata); err != nil {
+			return err
+		}
+
+		r.emitJobTask(events.JobTaskReassigned, jobTask, map[string]interface{}{
+			"sequence":    jobTask.Sequence,
+			"assignee_id": assigneeID,
+		})
+		return nil
+	})
 }
 
 // GetNextTask 获取下一个待执行的任务
 func (e *workflowEngine) GetNextTask(jobID int64) (*models.JobTask, error) {
-	jobTasks, err := e.jobTaskRepo.GetByJobID(jobID)
+	return nextPendingTask(e.jobTaskRepo, jobID)
+}
+
+// GetCurrentTask 获取当前执行中的任务
+func (e *workflowEngine) GetCurrentTask(jobID int64) (*models.JobTask, error) {
+	job, err := e.jobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !job.CurrentTaskSeq.Valid {
+		return nil, fmt.Errorf("no current task")
+	}
+
+	return e.jobTaskRepo.GetBySequence(jobID, int(job.CurrentTaskSeq.Int64))
+}
+
+// nextPendingTask 按序号返回第一个待执行的任务
+func nextPendingTask(jobTaskRepo repository.JobTaskRepository, jobID int64) (*models.JobTask, error) {
+	jobTasks, err := jobTaskRepo.GetByJobID(jobID)
 	if err != nil {
 		return nil, err
 	}
@@ -356,29 +928,60 @@ func (e *workflowEngine) GetNextTask(jobID int64) (*models.JobTask, error) {
 	return nil, nil
 }
 
-// GetCurrentTask 获取当前执行中的任务
-func (e *workflowEngine) GetCurrentTask(jobID int64) (*models.JobTask, error) {
-	job, err := e.jobRepo.GetByID(jobID)
+// advanceJob 推进作业到下一个待执行任务，没有时完成作业
+func advanceJob(r txRepos, jobID int64) error {
+	job, err := r.jobs.GetByID(jobID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if !job.CurrentTaskSeq.Valid {
-		return nil, fmt.Errorf("no current task")
+	// 已取消的作业不再推进
+	if job.Status == models.JobStatusCancelled {
+		return nil
 	}
 
-	return e.jobTaskRepo.GetBySequence(jobID, int(job.CurrentTaskSeq.Int64))
+	// 检查是否有下一个任务
+	nextTask, err := nextPendingTask(r.jobTasks, jobID)
+	if err != nil || nextTask == nil {
+		// 没有下一个任务，完成作业
+		job.Status = models.JobStatusCompleted
+		job.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		if err := r.jobs.Update(job); err != nil {
+			return err
+		}
+
+		r.emit(events.Event{Type: events.JobCompleted, ProjectID: job.ProjectID, JobID: job.ID, FlowID: job.FlowID, Status: string(job.Status)})
+		return nil
+	}
+
+	// 更新作业的当前任务序号
+	job.CurrentTaskSeq = sql.NullInt64{Int64: int64(nextTask.Sequence), Valid: true}
+	if err := r.jobs.Update(job); err != nil {
+		return err
+	}
+
+	return emitApprovalRequired(r, jobID)
 }
 
-// completeJob 完成作业
-func (e *workflowEngine) completeJob(jobID int64) error {
-	job, err := e.jobRepo.GetByID(jobID)
+// emitApprovalRequired 下一个待执行任务为审批任务时记录 approval.required 事件
+func emitApprovalRequired(r txRepos, jobID int64) error {
+	_, jobTasks, err := r.jobs.GetJobWithTasks(jobID)
 	if err != nil {
 		return err
 	}
 
-	job.Status = models.JobStatusCompleted
-	job.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
-
-	return e.jobRepo.Update(job)
+	for i := range jobTasks {
+		if jobTasks[i].Status != models.JobTaskStatusPending {
+			continue
+		}
+		if jobTasks[i].Task != nil && jobTasks[i].Task.TaskType == models.TaskTypeApproval {
+			r.emitJobTask(events.ApprovalRequired, &jobTasks[i], map[string]interface{}{
+				"sequence":  jobTasks[i].Sequence,
+				"task_id":   jobTasks[i].TaskID,
+				"task_name": jobTasks[i].Task.Name,
+			})
+		}
+		return nil
+	}
+	return nil
 }
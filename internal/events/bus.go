@@ -0,0 +1,173 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type 事件类型
+type Type string
+
+const (
+	JobCreated   Type = "job.created"
+	JobStarted   Type = "job.started"
+	JobCompleted Type = "job.completed"
+	JobFailed    Type = "job.failed"
+	JobCancelled Type = "job.cancelled"
+
+	JobTaskStarted    Type = "job_task.started"
+	JobTaskCompleted  Type = "job_task.completed"
+	JobTaskFailed     Type = "job_task.failed"
+	JobTaskSkipped    Type = "job_task.skipped"
+	JobTaskRolledBack Type = "job_task.rolled_back"
+	JobTaskRetried    Type = "job_task.retried"
+	JobTaskReassigned Type = "job_task.reassigned"
+
+	// ApprovalRequired 下一个待执行任务为审批任务，等待人工处理
+	ApprovalRequired Type = "approval.required"
+)
+
+// Types 返回所有事件类型
+func Types() []Type {
+	return []Type{
+		JobCreated, JobStarted, JobCompleted, JobFailed, JobCancelled,
+		JobTaskStarted, JobTaskCompleted, JobTaskFailed, JobTaskSkipped,
+		JobTaskRolledBack, JobTaskRetried, JobTaskReassigned,
+		ApprovalRequired,
+	}
+}
+
+// IsValid 判断是否为已知事件类型
+func (t Type) IsValid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriberBuffer 订阅通道缓冲大小，订阅者跟不上时丢弃事件而不阻塞发布方
+const subscriberBuffer = 64
+
+// Event 作业/作业任务状态变更事件
+type Event struct {
+	ID         int64                  `json:"id"`
+	Type       Type                   `json:"type"`
+	ProjectID  int64                  `json:"project_id"`
+	JobID      int64                  `json:"job_id"`
+	FlowID     int64                  `json:"flow_id"`
+	JobTaskID  int64                  `json:"job_task_id,omitempty"`
+	Status     string                 `json:"status"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// Filter 订阅过滤条件，零值字段表示不过滤
+type Filter struct {
+	ProjectID int64
+	JobID     int64
+	FlowID    int64
+	Types     []Type
+}
+
+// Match 判断事件是否满足过滤条件
+func (f Filter) Match(e Event) bool {
+	if f.ProjectID != 0 && e.ProjectID != f.ProjectID {
+		return false
+	}
+	if f.JobID != 0 && e.JobID != f.JobID {
+		return false
+	}
+	if f.FlowID != 0 && e.FlowID != f.FlowID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription 事件订阅
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus 进程内事件总线
+type Bus struct {
+	mu   sync.RWMutex
+	seq  int64
+	subs map[*Subscription]struct{}
+}
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish 发布事件，为事件分配递增 ID 与发生时间
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.seq++
+	e.ID = b.seq
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	b.mu.Unlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe 按过滤条件订阅事件
+func (b *Bus) Subscribe(filter Filter) *Subscription {
+	return b.SubscribeBuffered(filter, subscriberBuffer)
+}
+
+// SubscribeBuffered 按过滤条件订阅事件并指定缓冲大小，适用于不能轻易丢弃事件的订阅者
+func (b *Bus) SubscribeBuffered(filter Filter, size int) *Subscription {
+	ch := make(chan Event, size)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
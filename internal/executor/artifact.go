@@ -0,0 +1,30 @@
+package executor
+
+import "context"
+
+// ArtifactRef 已写入制品的引用
+type ArtifactRef struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+}
+
+// ArtifactWriter 执行器写入制品（大文本、报告文件等）的接口
+type ArtifactWriter interface {
+	WriteArtifact(ctx context.Context, name, contentType string, data []byte) (*ArtifactRef, error)
+}
+
+type artifactWriterKey struct{}
+
+// WithArtifactWriter 将制品写入器附加到执行上下文
+func WithArtifactWriter(ctx context.Context, writer ArtifactWriter) context.Context {
+	return context.WithValue(ctx, artifactWriterKey{}, writer)
+}
+
+// ArtifactWriterFromContext 获取执行上下文中的制品写入器
+func ArtifactWriterFromContext(ctx context.Context) (ArtifactWriter, bool) {
+	writer, ok := ctx.Value(artifactWriterKey{}).(ArtifactWriter)
+	return writer, ok && writer != nil
+}
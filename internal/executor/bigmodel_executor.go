@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
 )
 
 // BigModelExecutor 智谱 AI BigModel 执行器
@@ -33,10 +35,27 @@ func (e *BigModelExecutor) Name() string {
 	return "bigmodel_analysis"
 }
 
+// Describe 返回执行器输入/输出契约
+func (e *BigModelExecutor) Describe() Descriptor {
+	return Descriptor{
+		Params: []ParamSpec{
+			{Name: "transcript", Type: ParamTypeString, Required: true, Description: "待分析的视频转录文本"},
+			{Name: "model", Type: ParamTypeString, Default: "glm-4-air", Description: "BigModel 模型名称"},
+		},
+		Outputs: []OutputSpec{
+			{Name: "summary", Type: ParamTypeString},
+			{Name: "mindmap", Type: ParamTypeString},
+			{Name: "key_points", Type: ParamTypeString},
+			{Name: "insights", Type: ParamTypeString},
+			{Name: "analysis_artifact_id", Type: ParamTypeInteger, Optional: true, Description: "分析结果制品 ID"},
+		},
+	}
+}
+
 // Execute 执行任务
-func (e *BigModelExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext map[string]string) (map[string]interface{}, error) {
+func (e *BigModelExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext *JobContext) (map[string]interface{}, error) {
 	// 从 job context 获取转录文本
-	transcript := jobContext["transcript"]
+	transcript := jobContext.String("transcript")
 	if transcript == "" {
 		// 如果 context 中没有，尝试从 input 获取
 		if t, ok := input["transcript"].(string); ok {
@@ -51,53 +70,90 @@ func (e *BigModelExecutor) Execute(ctx context.Context, input map[string]interfa
 		transcript = transcript[:10000] + "..." // 截断过长的文本
 	}
 
+	model, _ := input["model"].(string)
+	if model == "" {
+		model = "glm-4-air"
+	}
+
+	log := Logger(ctx)
+	if e.apiKey == "" || e.apiKey == "your_api_key_here" {
+		log.Warnf("BIGMODEL_API_KEY not configured, using mock analysis")
+	}
+	log.Infof("Analyzing transcript (%d bytes) with model %s", len(transcript), model)
+
 	// 生成多个分析结果
 	results := make(map[string]interface{})
 
 	// 1. 生成阅读摘要
-	summary, err := e.generateContent(ctx, transcript, "summary")
+	summary, err := e.generateContent(ctx, model, transcript, "summary")
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
 	results["summary"] = summary
 
 	// 2. 生成思维导图
-	mindmap, err := e.generateContent(ctx, transcript, "mindmap")
+	mindmap, err := e.generateContent(ctx, model, transcript, "mindmap")
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate mindmap: %w", err)
 	}
 	results["mindmap"] = mindmap
 
 	// 3. 重点分析
-	keyPoints, err := e.generateContent(ctx, transcript, "key_points")
+	keyPoints, err := e.generateContent(ctx, model, transcript, "key_points")
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate key points: %w", err)
 	}
 	results["key_points"] = keyPoints
 
 	// 4. 个人认知
-	insights, err := e.generateContent(ctx, transcript, "insights")
+	insights, err := e.generateContent(ctx, model, transcript, "insights")
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate insights: %w", err)
 	}
 	results["insights"] = insights
 
+	// 将完整分析结果保存为制品
+	if writer, ok := ArtifactWriterFromContext(ctx); ok {
+		analysis := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n", summary, mindmap, keyPoints, insights)
+		ref, err := writer.WriteArtifact(ctx, "analysis.md", "text/markdown; charset=utf-8", []byte(analysis))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save analysis artifact: %w", err)
+		}
+		results["analysis_artifact_id"] = ref.ID
+	}
+
 	return results, nil
 }
 
 // generateContent 生成特定类型的内容
-func (e *BigModelExecutor) generateContent(ctx context.Context, transcript, contentType string) (string, error) {
+func (e *BigModelExecutor) generateContent(ctx context.Context, model, transcript, contentType string) (content string, err error) {
+	Logger(ctx).Infof("Generating %s", contentType)
+
 	// 根据内容类型构建不同的提示词
 	prompt := e.buildPrompt(transcript, contentType)
 
+	ctx, span := tracing.Start(ctx, "bigmodel.generateContent",
+		tracing.WithKind(tracing.SpanKindClient),
+		tracing.WithAttributes(
+			tracing.String("bigmodel.model", model),
+			tracing.String("bigmodel.content_type", contentType),
+			tracing.Int("bigmodel.prompt_length", len(prompt)),
+		),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// 如果没有配置 API key，返回模拟数据
 	if e.apiKey == "" || e.apiKey == "your_api_key_here" {
+		span.SetAttributes(tracing.Bool("bigmodel.mock", true))
 		return e.getMockContent(contentType, transcript), nil
 	}
 
 	// 调用 BigModel API
 	request := BigModelRequest{
-		Model: "glm-4-air",
+		Model: model,
 		Messages: []Message{
 			{
 				Role:    "user",
@@ -120,6 +176,7 @@ func (e *BigModelExecutor) generateContent(ctx context.Context, transcript, cont
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.apiKey))
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := e.client.Do(req)
 	if err != nil {
@@ -132,9 +189,11 @@ func (e *BigModelExecutor) generateContent(ctx context.Context, transcript, cont
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
+	span.SetAttributes(tracing.Int("http.status_code", resp.StatusCode), tracing.Int("bigmodel.response_bytes", len(body)))
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("API returned error: %s, body: %s", resp.Status, string(body))
 	}
+	Logger(ctx).Infof("BigModel responded %s for %s (%d bytes)", resp.Status, contentType, len(body))
 
 	var response BigModelResponse
 	if err := json.Unmarshal(body, &response); err != nil {
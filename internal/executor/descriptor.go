@@ -0,0 +1,252 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/resolver"
+)
+
+// ParamType 参数/输出值类型
+type ParamType string
+
+const (
+	ParamTypeString  ParamType = "string"
+	ParamTypeInteger ParamType = "integer"
+	ParamTypeNumber  ParamType = "number"
+	ParamTypeBoolean ParamType = "boolean"
+	ParamTypeObject  ParamType = "object"
+	ParamTypeArray   ParamType = "array"
+	ParamTypeAny     ParamType = "any"
+)
+
+// ParamSpec 执行器输入参数声明
+type ParamSpec struct {
+	Name        string      `json:"name"`
+	Type        ParamType   `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// OutputSpec 执行器输出声明
+type OutputSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Optional    bool      `json:"optional,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Descriptor 执行器输入/输出契约
+type Descriptor struct {
+	Name    string       `json:"name"`
+	Params  []ParamSpec  `json:"params"`
+	Outputs []OutputSpec `json:"outputs"`
+}
+
+// Describer 可选接口：执行器实现后即声明其输入/输出契约
+type Describer interface {
+	Describe() Descriptor
+}
+
+// ContractError 契约校验错误
+type ContractError struct {
+	Executor   string
+	Stage      string
+	Violations []string
+}
+
+// Error 实现 error 接口
+func (e *ContractError) Error() string {
+	if e.Executor == "" {
+		return fmt.Sprintf("%s contract violated: %s", e.Stage, strings.Join(e.Violations, "; "))
+	}
+	return fmt.Sprintf("executor %s %s contract violated: %s", e.Executor, e.Stage, strings.Join(e.Violations, "; "))
+}
+
+// Param 根据名称查找参数声明
+func (d Descriptor) Param(name string) (ParamSpec, bool) {
+	for _, p := range d.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ParamSpec{}, false
+}
+
+// OutputNames 返回声明的输出键
+func (d Descriptor) OutputNames() []string {
+	names := make([]string, 0, len(d.Outputs))
+	for _, o := range d.Outputs {
+		names = append(names, o.Name)
+	}
+	return names
+}
+
+// ValidateConfig 校验任务配置中已提供参数的类型（创建任务时调用）
+// 缺失的必填参数允许由上游任务或流程输入提供，此处不做要求
+func (d Descriptor) ValidateConfig(config map[string]interface{}) error {
+	var violations []string
+	for _, p := range d.Params {
+		value, ok := config[p.Name]
+		if !ok || value == nil {
+			continue
+		}
+		// 模板表达式在执行前才解析，此处不校验类型
+		if s, isString := value.(string); isString && resolver.HasExpression(s) {
+			continue
+		}
+		if _, err := coerce(value, p.Type); err != nil {
+			violations = append(violations, fmt.Sprintf("parameter %s: %v", p.Name, err))
+		}
+	}
+	return d.contractError("config", violations)
+}
+
+// PrepareInput 应用默认值、转换类型并检查必填参数（执行前调用）
+func (d Descriptor) PrepareInput(input map[string]interface{}) (map[string]interface{}, error) {
+	prepared := make(map[string]interface{}, len(input))
+	for k, v := range input {
+		prepared[k] = v
+	}
+
+	var violations []string
+	for _, p := range d.Params {
+		value, ok := prepared[p.Name]
+		if !ok || value == nil || value == "" {
+			if p.Default != nil {
+				prepared[p.Name] = p.Default
+				continue
+			}
+			if p.Required {
+				violations = append(violations, fmt.Sprintf("missing required parameter: %s", p.Name))
+			}
+			continue
+		}
+
+		converted, err := coerce(value, p.Type)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("parameter %s: %v", p.Name, err))
+			continue
+		}
+		prepared[p.Name] = converted
+	}
+
+	if err := d.contractError("input", violations); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// ValidateOutput 校验执行结果是否包含声明的输出且类型匹配（执行后调用）
+func (d Descriptor) ValidateOutput(output map[string]interface{}) error {
+	var violations []string
+	for _, o := range d.Outputs {
+		value, ok := output[o.Name]
+		if !ok || value == nil {
+			if !o.Optional {
+				violations = append(violations, fmt.Sprintf("missing output: %s", o.Name))
+			}
+			continue
+		}
+		if !matches(value, o.Type) {
+			violations = append(violations, fmt.Sprintf("output %s: expected %s, got %T", o.Name, o.Type, value))
+		}
+	}
+	return d.contractError("output", violations)
+}
+
+func (d Descriptor) contractError(stage string, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+	return &ContractError{Executor: d.Name, Stage: stage, Violations: violations}
+}
+
+// coerce 将值转换为声明的类型，作业上下文中的字符串值会按需解析
+func coerce(value interface{}, t ParamType) (interface{}, error) {
+	if matches(value, t) {
+		return value, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected %s, got %T", t, value)
+	}
+
+	switch t {
+	case ParamTypeString, ParamTypeAny, "":
+		return s, nil
+	case ParamTypeInteger:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer, got %q", s)
+		}
+		return n, nil
+	case ParamTypeNumber:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected number, got %q", s)
+		}
+		return f, nil
+	case ParamTypeBoolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", s)
+		}
+		return b, nil
+	case ParamTypeObject:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return nil, fmt.Errorf("expected object, got %q", s)
+		}
+		return m, nil
+	case ParamTypeArray:
+		var a []interface{}
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			return nil, fmt.Errorf("expected array, got %q", s)
+		}
+		return a, nil
+	}
+
+	return nil, fmt.Errorf("unknown parameter type: %s", t)
+}
+
+// matches 判断值是否已是声明的类型
+func matches(value interface{}, t ParamType) bool {
+	switch t {
+	case ParamTypeAny, "":
+		return true
+	case ParamTypeString:
+		_, ok := value.(string)
+		return ok
+	case ParamTypeInteger:
+		switch v := value.(type) {
+		case int, int32, int64, uint, uint32, uint64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case ParamTypeNumber:
+		switch value.(type) {
+		case int, int32, int64, uint, uint32, uint64, float32, float64:
+			return true
+		}
+		return false
+	case ParamTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case ParamTypeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case ParamTypeArray:
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package executor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrepareInput(t *testing.T) {
+	d := Descriptor{
+		Name: "demo",
+		Params: []ParamSpec{
+			{Name: "url", Type: ParamTypeString, Required: true},
+			{Name: "count", Type: ParamTypeInteger},
+			{Name: "ratio", Type: ParamTypeNumber},
+			{Name: "verbose", Type: ParamTypeBoolean, Default: false},
+			{Name: "options", Type: ParamTypeObject},
+			{Name: "tags", Type: ParamTypeArray},
+			{Name: "extra", Type: ParamTypeAny},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   map[string]interface{}
+		want    map[string]interface{}
+		wantErr []string
+	}{
+		{
+			name:  "native types pass through",
+			input: map[string]interface{}{"url": "u", "count": float64(3), "ratio": 1, "verbose": true, "options": map[string]interface{}{"a": "b"}, "tags": []interface{}{"x"}},
+			want:  map[string]interface{}{"url": "u", "count": float64(3), "ratio": 1, "verbose": true, "options": map[string]interface{}{"a": "b"}, "tags": []interface{}{"x"}},
+		},
+		{
+			name:  "strings are coerced",
+			input: map[string]interface{}{"url": "u", "count": " 42 ", "ratio": "0.5", "verbose": "true", "options": `{"a":1}`, "tags": `["x","y"]`, "extra": "anything"},
+			want:  map[string]interface{}{"url": "u", "count": int64(42), "ratio": 0.5, "verbose": true, "options": map[string]interface{}{"a": float64(1)}, "tags": []interface{}{"x", "y"}, "extra": "anything"},
+		},
+		{
+			name:  "defaults fill missing and empty values",
+			input: map[string]interface{}{"url": "u", "verbose": ""},
+			want:  map[string]interface{}{"url": "u", "verbose": false},
+		},
+		{
+			name:  "unknown keys are kept",
+			input: map[string]interface{}{"url": "u", "other": 1},
+			want:  map[string]interface{}{"url": "u", "other": 1, "verbose": false},
+		},
+		{
+			name:    "missing required",
+			input:   map[string]interface{}{"url": ""},
+			wantErr: []string{"missing required parameter: url"},
+		},
+		{
+			name:    "non integral float is not an integer",
+			input:   map[string]interface{}{"url": "u", "count": 1.5},
+			wantErr: []string{"parameter count: expected integer, got float64"},
+		},
+		{
+			name:    "all violations are reported",
+			input:   map[string]interface{}{"count": "x", "verbose": "maybe", "tags": "{}"},
+			wantErr: []string{"missing required parameter: url", `parameter count: expected integer, got "x"`, `parameter tags: expected array, got "{}"`, `parameter verbose: expected boolean, got "maybe"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.PrepareInput(tt.input)
+			if tt.wantErr != nil {
+				var contractErr *ContractError
+				if !errors.As(err, &contractErr) {
+					t.Fatalf("PrepareInput() error = %v, want ContractError", err)
+				}
+				if contractErr.Stage != "input" || !reflect.DeepEqual(contractErr.Violations, tt.wantErr) {
+					t.Fatalf("PrepareInput() violations = %q, want %q", contractErr.Violations, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PrepareInput() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("PrepareInput() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareInputDoesNotModifyInput(t *testing.T) {
+	d := Descriptor{Params: []ParamSpec{{Name: "n", Type: ParamTypeInteger, Default: 1}}}
+	input := map[string]interface{}{"n": "5"}
+	if _, err := d.PrepareInput(input); err != nil {
+		t.Fatal(err)
+	}
+	if input["n"] != "5" {
+		t.Fatalf("PrepareInput() modified its input: %v", input)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	d := Descriptor{
+		Name: "demo",
+		Outputs: []OutputSpec{
+			{Name: "text", Type: ParamTypeString},
+			{Name: "count", Type: ParamTypeInteger},
+			{Name: "meta", Type: ParamTypeObject, Optional: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		output  map[string]interface{}
+		wantErr []string
+	}{
+		{name: "valid", output: map[string]interface{}{"text": "t", "count": 2}},
+		{name: "integral float counts as integer", output: map[string]interface{}{"text": "t", "count": float64(2)}},
+		{name: "optional may be nil", output: map[string]interface{}{"text": "t", "count": 1, "meta": nil}},
+		{name: "missing required output", output: map[string]interface{}{"count": 1}, wantErr: []string{"missing output: text"}},
+		{name: "no coercion of outputs", output: map[string]interface{}{"text": "t", "count": "2"}, wantErr: []string{"output count: expected integer, got string"}},
+		{name: "wrong optional type", output: map[string]interface{}{"text": 1, "count": 1, "meta": []interface{}{}}, wantErr: []string{"output meta: expected object, got []interface {}", "output text: expected string, got int"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.ValidateOutput(tt.output)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateOutput() error = %v", err)
+				}
+				return
+			}
+			var contractErr *ContractError
+			if !errors.As(err, &contractErr) || contractErr.Stage != "output" || !reflect.DeepEqual(contractErr.Violations, tt.wantErr) {
+				t.Fatalf("ValidateOutput() error = %v, want violations %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	d := Descriptor{
+		Name: "demo",
+		Params: []ParamSpec{
+			{Name: "url", Type: ParamTypeString, Required: true},
+			{Name: "count", Type: ParamTypeInteger},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "required may come from context", config: map[string]interface{}{}},
+		{name: "coercible string", config: map[string]interface{}{"count": "3"}},
+		{name: "template is not checked", config: map[string]interface{}{"count": "${tasks.a.result.n}"}},
+		{name: "wrong type", config: map[string]interface{}{"count": "many"}, wantErr: `executor demo config contract violated: parameter count: expected integer, got "many"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.ValidateConfig(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
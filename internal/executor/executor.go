@@ -3,20 +3,61 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 // Executor 任务执行器接口
 type Executor interface {
 	// Execute 执行任务
 	// input: 任务输入参数
-	// context: 作业上下文（用于任务间数据共享）
+	// jobContext: 类型化作业上下文（用于任务间数据共享）
 	// 返回: 输出结果和错误
-	Execute(ctx context.Context, input map[string]interface{}, jobContext map[string]string) (map[string]interface{}, error)
+	Execute(ctx context.Context, input map[string]interface{}, jobContext *JobContext) (map[string]interface{}, error)
 
 	// Name 返回执行器名称
 	Name() string
 }
 
+// LegacyExecutor 旧版执行器接口，作业上下文为字符串键值
+type LegacyExecutor interface {
+	Execute(ctx context.Context, input map[string]interface{}, jobContext map[string]string) (map[string]interface{}, error)
+	Name() string
+}
+
+// AdaptLegacy 将旧版执行器包装为 Executor，上下文值按字符串传入
+func AdaptLegacy(legacy LegacyExecutor) Executor {
+	adapter := &legacyAdapter{legacy: legacy}
+	if _, ok := legacy.(Describer); ok {
+		return &describedLegacyAdapter{adapter}
+	}
+	return adapter
+}
+
+type legacyAdapter struct {
+	legacy LegacyExecutor
+}
+
+func (a *legacyAdapter) Name() string {
+	return a.legacy.Name()
+}
+
+func (a *legacyAdapter) Execute(ctx context.Context, input map[string]interface{}, jobContext *JobContext) (map[string]interface{}, error) {
+	flat := jobContext.Flatten()
+	legacyContext := make(map[string]string, len(flat))
+	for k, v := range flat {
+		legacyContext[k] = stringifyContextValue(v)
+	}
+	return a.legacy.Execute(ctx, input, legacyContext)
+}
+
+type describedLegacyAdapter struct {
+	*legacyAdapter
+}
+
+func (a *describedLegacyAdapter) Describe() Descriptor {
+	return a.legacy.(Describer).Describe()
+}
+
 // ExecutorRegistry 执行器注册表
 type ExecutorRegistry struct {
 	executors map[string]Executor
@@ -69,3 +110,39 @@ func GetExecutor(name string) (Executor, error) {
 func ListExecutors() []string {
 	return globalRegistry.List()
 }
+
+// GetDescriptor 获取执行器契约，未实现 Describer 的执行器返回 false
+func (r *ExecutorRegistry) GetDescriptor(name string) (Descriptor, bool) {
+	executor, ok := r.executors[name]
+	if !ok {
+		return Descriptor{}, false
+	}
+	describer, ok := executor.(Describer)
+	if !ok {
+		return Descriptor{}, false
+	}
+	descriptor := describer.Describe()
+	descriptor.Name = name
+	return descriptor, true
+}
+
+// GetDescriptor 获取全局执行器契约
+func GetDescriptor(name string) (Descriptor, bool) {
+	return globalRegistry.GetDescriptor(name)
+}
+
+// ListDescriptors 列出所有执行器契约（按名称排序）
+func ListDescriptors() []Descriptor {
+	names := globalRegistry.List()
+	sort.Strings(names)
+
+	descriptors := make([]Descriptor, 0, len(names))
+	for _, name := range names {
+		descriptor, ok := globalRegistry.GetDescriptor(name)
+		if !ok {
+			descriptor = Descriptor{Name: name}
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
@@ -8,8 +8,6 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
-
-	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
 )
 
 // HTMLReportExecutor HTML 报告生成执行器
@@ -35,10 +33,30 @@ func (e *HTMLReportExecutor) Name() string {
 	return "html_report"
 }
 
+// Describe 返回执行器输入/输出契约
+func (e *HTMLReportExecutor) Describe() Descriptor {
+	return Descriptor{
+		Params: []ParamSpec{
+			{Name: "video_id", Type: ParamTypeString, Description: "视频 ID，缺省为 unknown"},
+			{Name: "summary", Type: ParamTypeString, Default: "摘要生成中..."},
+			{Name: "mindmap", Type: ParamTypeString, Default: "思维导图生成中..."},
+			{Name: "key_points", Type: ParamTypeString, Default: "重点分析生成中..."},
+			{Name: "insights", Type: ParamTypeString, Default: "个人认知生成中..."},
+		},
+		Outputs: []OutputSpec{
+			{Name: "report_path", Type: ParamTypeString},
+			{Name: "report_url", Type: ParamTypeString},
+			{Name: "filename", Type: ParamTypeString},
+			{Name: "size", Type: ParamTypeInteger},
+			{Name: "report_artifact_id", Type: ParamTypeInteger, Optional: true, Description: "报告制品 ID"},
+		},
+	}
+}
+
 // Execute 执行任务
-func (e *HTMLReportExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext map[string]string) (map[string]interface{}, error) {
+func (e *HTMLReportExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext *JobContext) (map[string]interface{}, error) {
 	// 从 job context 获取分析结果
-	videoID := jobContext["video_id"]
+	videoID := jobContext.String("video_id")
 	if videoID == "" {
 		videoID = "unknown"
 	}
@@ -75,14 +93,25 @@ func (e *HTMLReportExecutor) Execute(ctx context.Context, input map[string]inter
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
-	logger.Infof("HTML report generated: %s", filepath)
+	Logger(ctx).Infof("HTML report generated: %s", filepath)
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"report_path": filepath,
 		"report_url":  fmt.Sprintf("/reports/%s", filename),
 		"filename":    filename,
 		"size":        len(htmlContent),
-	}, nil
+	}
+
+	// 将报告保存为制品，使作业记录可以引用
+	if writer, ok := ArtifactWriterFromContext(ctx); ok {
+		ref, err := writer.WriteArtifact(ctx, filename, "text/html; charset=utf-8", []byte(htmlContent))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save report artifact: %w", err)
+		}
+		result["report_artifact_id"] = ref.ID
+	}
+
+	return result, nil
 }
 
 // getStringValue 安全获取字符串值
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TaskOutputs 单个任务的输出命名空间
+type TaskOutputs struct {
+	Name   string
+	Values map[string]interface{}
+}
+
+// JobContext 类型化作业上下文：共享作用域 + 按执行顺序排列的任务输出命名空间
+type JobContext struct {
+	Shared map[string]interface{}
+	Tasks  []TaskOutputs
+}
+
+// Lookup 查找上下文值：先查共享作用域，再从最近执行的任务输出向前查找
+func (c *JobContext) Lookup(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	if value, ok := c.Shared[key]; ok {
+		return value, true
+	}
+	for i := len(c.Tasks) - 1; i >= 0; i-- {
+		if value, ok := c.Tasks[i].Values[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// String 以字符串形式获取上下文值，不存在时返回空字符串
+func (c *JobContext) String(key string) string {
+	value, ok := c.Lookup(key)
+	if !ok || value == nil {
+		return ""
+	}
+	return stringifyContextValue(value)
+}
+
+// Task 获取指定任务的输出命名空间
+func (c *JobContext) Task(name string) (map[string]interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	for i := len(c.Tasks) - 1; i >= 0; i-- {
+		if c.Tasks[i].Name == name {
+			return c.Tasks[i].Values, true
+		}
+	}
+	return nil, false
+}
+
+// Flatten 按 Lookup 的优先级展开为单层键值
+func (c *JobContext) Flatten() map[string]interface{} {
+	flat := make(map[string]interface{})
+	if c == nil {
+		return flat
+	}
+	for _, t := range c.Tasks {
+		for k, v := range t.Values {
+			flat[k] = v
+		}
+	}
+	for k, v := range c.Shared {
+		flat[k] = v
+	}
+	return flat
+}
+
+// stringifyContextValue 将上下文值转换为字符串（复杂类型转 JSON）
+func stringifyContextValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int, int64, float64, bool:
+		return fmt.Sprintf("%v", v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
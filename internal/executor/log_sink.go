@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// LogLevel 执行日志级别
+type LogLevel string
+
+const (
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+// LogSink 单次执行的日志接收器，由执行服务按作业任务提供
+type LogSink interface {
+	WriteLog(level LogLevel, message string)
+}
+
+type logSinkKey struct{}
+
+// WithLogSink 将日志接收器附加到执行上下文
+func WithLogSink(ctx context.Context, sink LogSink) context.Context {
+	return context.WithValue(ctx, logSinkKey{}, sink)
+}
+
+// LogSinkFromContext 获取执行上下文中的日志接收器
+func LogSinkFromContext(ctx context.Context) (LogSink, bool) {
+	sink, ok := ctx.Value(logSinkKey{}).(LogSink)
+	return sink, ok && sink != nil
+}
+
+// TaskLogger 执行器使用的日志记录器：写入全局日志（携带上下文中的作业、任务等关联字段），
+// 有日志接收器时同时写入当前作业任务的执行日志
+type TaskLogger struct {
+	sink LogSink
+	ctx  context.Context
+}
+
+// Logger 返回当前执行上下文的日志记录器
+func Logger(ctx context.Context) *TaskLogger {
+	sink, _ := LogSinkFromContext(ctx)
+	return &TaskLogger{sink: sink, ctx: ctx}
+}
+
+// Infof 记录信息日志
+func (l *TaskLogger) Infof(format string, v ...interface{}) {
+	l.write(LogLevelInfo, fmt.Sprintf(format, v...))
+}
+
+// Warnf 记录警告日志
+func (l *TaskLogger) Warnf(format string, v ...interface{}) {
+	l.write(LogLevelWarn, fmt.Sprintf(format, v...))
+}
+
+// Errorf 记录错误日志
+func (l *TaskLogger) Errorf(format string, v ...interface{}) {
+	l.write(LogLevelError, fmt.Sprintf(format, v...))
+}
+
+// Writer 返回按行写入日志的 io.Writer，用于转发外部命令的输出；使用完毕后需调用 Close 输出残留内容
+func (l *TaskLogger) Writer(level LogLevel) *LineWriter {
+	return &LineWriter{logger: l, level: level}
+}
+
+func (l *TaskLogger) write(level LogLevel, message string) {
+	logger.Ctx(l.ctx).Log(string(level), message)
+	if l.sink != nil {
+		l.sink.WriteLog(level, message)
+	}
+}
+
+// LineWriter 将写入内容按行转为日志
+type LineWriter struct {
+	logger *TaskLogger
+	level  LogLevel
+	mu     sync.Mutex
+	buf    bytes.Buffer
+}
+
+// Write 实现 io.Writer 接口
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		idx := bytes.IndexAny(data, "\r\n")
+		if idx < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(data[:idx]))
+		w.buf.Next(idx + 1)
+		if line != "" {
+			w.logger.write(w.level, line)
+		}
+	}
+	return len(p), nil
+}
+
+// Close 输出未以换行结尾的残留内容
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if line := strings.TrimSpace(w.buf.String()); line != "" {
+		w.logger.write(w.level, line)
+	}
+	w.buf.Reset()
+	return nil
+}
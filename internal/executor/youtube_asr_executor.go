@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
 )
 
 // YouTubeASRExecutor YouTube ASR 下载执行器
@@ -29,8 +32,27 @@ func (e *YouTubeASRExecutor) Name() string {
 	return "youtube_asr"
 }
 
+// Describe 返回执行器输入/输出契约
+func (e *YouTubeASRExecutor) Describe() Descriptor {
+	return Descriptor{
+		Params: []ParamSpec{
+			{Name: "video_url", Type: ParamTypeString, Required: true, Description: "YouTube 视频 URL 或视频 ID"},
+			{Name: "language", Type: ParamTypeString, Default: "en", Description: "字幕语言"},
+		},
+		Outputs: []OutputSpec{
+			{Name: "video_id", Type: ParamTypeString},
+			{Name: "transcript", Type: ParamTypeString},
+			{Name: "language", Type: ParamTypeString},
+			{Name: "method", Type: ParamTypeString, Description: "字幕获取方式"},
+			{Name: "length", Type: ParamTypeInteger},
+			{Name: "warning", Type: ParamTypeString, Optional: true},
+			{Name: "transcript_artifact_id", Type: ParamTypeInteger, Optional: true, Description: "字幕制品 ID"},
+		},
+	}
+}
+
 // Execute 执行任务
-func (e *YouTubeASRExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext map[string]string) (map[string]interface{}, error) {
+func (e *YouTubeASRExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext *JobContext) (map[string]interface{}, error) {
 	// 获取 YouTube URL
 	videoURL, ok := input["video_url"].(string)
 	if !ok || videoURL == "" {
@@ -49,49 +71,63 @@ func (e *YouTubeASRExecutor) Execute(ctx context.Context, input map[string]inter
 		language = lang
 	}
 
+	log := Logger(ctx)
+	log.Infof("Fetching transcript for video %s (language: %s)", videoID, language)
+
 	// 尝试多种方式获取字幕
 	var transcript string
 
 	// 方式 1: 使用 yt-dlp（如果安装了）
 	transcript, err = e.getTranscriptWithYtDlp(ctx, videoID, language)
 	if err == nil && transcript != "" {
-		return map[string]interface{}{
-			"video_id":   videoID,
-			"transcript": transcript,
-			"language":   language,
-			"method":     "yt-dlp",
-			"length":     len(transcript),
-		}, nil
+		log.Infof("Transcript fetched with yt-dlp (%d bytes)", len(transcript))
+		return e.buildResult(ctx, videoID, language, "yt-dlp", transcript, "")
 	}
+	log.Warnf("yt-dlp unavailable: %v", err)
 
 	// 方式 2: 使用 YouTube Transcript API（Python 脚本）
 	transcript, err = e.getTranscriptWithPython(ctx, videoID, language)
 	if err == nil && transcript != "" {
-		return map[string]interface{}{
-			"video_id":   videoID,
-			"transcript": transcript,
-			"language":   language,
-			"method":     "youtube-transcript-api",
-			"length":     len(transcript),
-		}, nil
+		log.Infof("Transcript fetched with youtube-transcript-api (%d bytes)", len(transcript))
+		return e.buildResult(ctx, videoID, language, "youtube-transcript-api", transcript, "")
 	}
+	log.Warnf("youtube-transcript-api unavailable: %v", err)
 
 	// 方式 3: 模拟数据（用于演示）
 	if transcript == "" {
+		log.Warnf("Falling back to mock transcript")
 		transcript = e.getMockTranscript()
-		return map[string]interface{}{
-			"video_id":   videoID,
-			"transcript": transcript,
-			"language":   language,
-			"method":     "mock",
-			"length":     len(transcript),
-			"warning":    "Using mock data. Please install yt-dlp or youtube-transcript-api for real transcripts.",
-		}, nil
+		return e.buildResult(ctx, videoID, language, "mock", transcript,
+			"Using mock data. Please install yt-dlp or youtube-transcript-api for real transcripts.")
 	}
 
 	return nil, fmt.Errorf("failed to get transcript from all methods")
 }
 
+// buildResult 构建执行结果，并在可用时将字幕保存为制品
+func (e *YouTubeASRExecutor) buildResult(ctx context.Context, videoID, language, method, transcript, warning string) (map[string]interface{}, error) {
+	result := map[string]interface{}{
+		"video_id":   videoID,
+		"transcript": transcript,
+		"language":   language,
+		"method":     method,
+		"length":     len(transcript),
+	}
+	if warning != "" {
+		result["warning"] = warning
+	}
+
+	if writer, ok := ArtifactWriterFromContext(ctx); ok {
+		ref, err := writer.WriteArtifact(ctx, fmt.Sprintf("transcript_%s.txt", videoID), "text/plain; charset=utf-8", []byte(transcript))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save transcript artifact: %w", err)
+		}
+		result["transcript_artifact_id"] = ref.ID
+	}
+
+	return result, nil
+}
+
 // extractVideoID 从 URL 提取视频 ID
 func (e *YouTubeASRExecutor) extractVideoID(videoURL string) (string, error) {
 	// 支持多种 YouTube URL 格式
@@ -116,12 +152,22 @@ func (e *YouTubeASRExecutor) extractVideoID(videoURL string) (string, error) {
 }
 
 // getTranscriptWithYtDlp 使用 yt-dlp 获取字幕
-func (e *YouTubeASRExecutor) getTranscriptWithYtDlp(ctx context.Context, videoID, language string) (string, error) {
+func (e *YouTubeASRExecutor) getTranscriptWithYtDlp(ctx context.Context, videoID, language string) (transcript string, err error) {
 	// 检查 yt-dlp 是否安装
 	if _, err := exec.LookPath("yt-dlp"); err != nil {
 		return "", fmt.Errorf("yt-dlp not found")
 	}
 
+	ctx, span := tracing.Start(ctx, "yt-dlp", tracing.WithAttributes(
+		tracing.String("youtube.video_id", videoID),
+		tracing.String("youtube.language", language),
+	))
+	defer func() {
+		span.RecordError(err)
+		span.SetAttributes(tracing.Int("youtube.transcript_length", len(transcript)))
+		span.End()
+	}()
+
 	// 构建命令
 	cmd := exec.CommandContext(ctx, "yt-dlp",
 		"--skip-download",
@@ -132,18 +178,33 @@ func (e *YouTubeASRExecutor) getTranscriptWithYtDlp(ctx context.Context, videoID
 		fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
 	)
 
-	output, err := cmd.CombinedOutput()
+	// 标准输出用于解析字幕，标准错误（进度与警告）实时写入执行日志
+	var stdout bytes.Buffer
+	stderr := Logger(ctx).Writer(LogLevelInfo)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	stderr.Close()
 	if err != nil {
-		return "", fmt.Errorf("yt-dlp failed: %w, output: %s", err, string(output))
+		return "", fmt.Errorf("yt-dlp failed: %w", err)
 	}
 
 	// 解析输出
-	transcript := e.parseYtDlpOutput(string(output))
-	return transcript, nil
+	return e.parseYtDlpOutput(stdout.String()), nil
 }
 
 // getTranscriptWithPython 使用 Python youtube-transcript-api
-func (e *YouTubeASRExecutor) getTranscriptWithPython(ctx context.Context, videoID, language string) (string, error) {
+func (e *YouTubeASRExecutor) getTranscriptWithPython(ctx context.Context, videoID, language string) (transcript string, err error) {
+	ctx, span := tracing.Start(ctx, "youtube-transcript-api", tracing.WithAttributes(
+		tracing.String("youtube.video_id", videoID),
+		tracing.String("youtube.language", language),
+	))
+	defer func() {
+		span.RecordError(err)
+		span.SetAttributes(tracing.Int("youtube.transcript_length", len(transcript)))
+		span.End()
+	}()
+
 	// Python 脚本
 	pythonScript := fmt.Sprintf(`
 from youtube_transcript_api import YouTubeTranscriptApi
@@ -159,12 +220,17 @@ except Exception as e:
 
 	// 执行 Python 脚本
 	cmd := exec.CommandContext(ctx, "python3", "-c", pythonScript)
-	output, err := cmd.CombinedOutput()
+	var stdout bytes.Buffer
+	stderr := Logger(ctx).Writer(LogLevelWarn)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	stderr.Close()
 	if err != nil {
 		return "", fmt.Errorf("python script failed: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return strings.TrimSpace(stdout.String()), nil
 }
 
 // parseYtDlpOutput 解析 yt-dlp 输出
@@ -0,0 +1,186 @@
+package grpcserver
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	workflowv1 "github.com/cfrs2005/GoWorkFlow/api/workflow/v1"
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// toStruct 将 JSON 对象转换为 protobuf Struct；含结构体、时间等非 JSON 基础类型时经 JSON 编码转换
+func toStruct(m map[string]interface{}) *structpb.Struct {
+	if m == nil {
+		return nil
+	}
+	if s, err := structpb.NewStruct(m); err == nil {
+		return s
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// fromStruct 将 protobuf Struct 转换为 JSON 对象
+func fromStruct(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toNullTimestamp(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+func toTask(t *models.Task) *workflowv1.Task {
+	if t == nil {
+		return nil
+	}
+	return &workflowv1.Task{
+		Id:          t.ID,
+		ProjectId:   t.ProjectID,
+		Name:        t.Name,
+		Description: t.Description,
+		TaskType:    string(t.TaskType),
+		Config:      toStruct(t.Config),
+		IsActive:    t.IsActive,
+		CreatedAt:   toTimestamp(t.CreatedAt),
+		UpdatedAt:   toTimestamp(t.UpdatedAt),
+	}
+}
+
+func fromTask(t *workflowv1.Task) *models.Task {
+	if t == nil {
+		return &models.Task{}
+	}
+	return &models.Task{
+		ID:          t.GetId(),
+		Name:        t.GetName(),
+		Description: t.GetDescription(),
+		TaskType:    models.TaskType(t.GetTaskType()),
+		Config:      models.TaskConfig(fromStruct(t.GetConfig())),
+		IsActive:    t.GetIsActive(),
+	}
+}
+
+func toFlow(f *models.Flow) *workflowv1.Flow {
+	if f == nil {
+		return nil
+	}
+	return &workflowv1.Flow{
+		Id:          f.ID,
+		ProjectId:   f.ProjectID,
+		Name:        f.Name,
+		Description: f.Description,
+		Version:     f.Version,
+		Inputs:      f.Inputs,
+		IsActive:    f.IsActive,
+		CreatedBy:   f.CreatedBy,
+		CreatedAt:   toTimestamp(f.CreatedAt),
+		UpdatedAt:   toTimestamp(f.UpdatedAt),
+	}
+}
+
+func fromFlow(f *workflowv1.Flow) *models.Flow {
+	if f == nil {
+		return &models.Flow{}
+	}
+	return &models.Flow{
+		ID:          f.GetId(),
+		Name:        f.GetName(),
+		Description: f.GetDescription(),
+		Version:     f.GetVersion(),
+		Inputs:      f.GetInputs(),
+		IsActive:    f.GetIsActive(),
+		CreatedBy:   f.GetCreatedBy(),
+	}
+}
+
+func toFlowTask(ft *models.FlowTask) *workflowv1.FlowTask {
+	return &workflowv1.FlowTask{
+		Id:              ft.ID,
+		FlowId:          ft.FlowID,
+		TaskId:          ft.TaskID,
+		Sequence:        int32(ft.Sequence),
+		IsOptional:      ft.IsOptional,
+		AllowRollback:   ft.AllowRollback,
+		ConditionConfig: toStruct(ft.ConditionConfig),
+		ConfigOverrides: toStruct(ft.ConfigOverrides),
+		Task:            toTask(ft.Task),
+	}
+}
+
+func toJob(j *models.Job) *workflowv1.Job {
+	if j == nil {
+		return nil
+	}
+	return &workflowv1.Job{
+		Id:             j.ID,
+		ProjectId:      j.ProjectID,
+		FlowId:         j.FlowID,
+		JobName:        j.JobName,
+		Status:         string(j.Status),
+		CurrentTaskSeq: j.CurrentTaskSeq.Int64,
+		StartedAt:      toNullTimestamp(j.StartedAt),
+		CompletedAt:    toNullTimestamp(j.CompletedAt),
+		CreatedBy:      j.CreatedBy,
+		CreatedAt:      toTimestamp(j.CreatedAt),
+		UpdatedAt:      toTimestamp(j.UpdatedAt),
+	}
+}
+
+func toJobTask(jt *models.JobTask) *workflowv1.JobTask {
+	return &workflowv1.JobTask{
+		Id:           jt.ID,
+		JobId:        jt.JobID,
+		FlowTaskId:   jt.FlowTaskID,
+		TaskId:       jt.TaskID,
+		Sequence:     int32(jt.Sequence),
+		Status:       string(jt.Status),
+		IsSkipped:    jt.IsSkipped,
+		ExecutorId:   jt.ExecutorID.Int64,
+		Result:       toStruct(jt.Result),
+		ErrorMessage: jt.ErrorMessage,
+		StartedAt:    toNullTimestamp(jt.StartedAt),
+		CompletedAt:  toNullTimestamp(jt.CompletedAt),
+		CreatedAt:    toTimestamp(jt.CreatedAt),
+		UpdatedAt:    toTimestamp(jt.UpdatedAt),
+		Task:         toTask(jt.Task),
+	}
+}
+
+func toJobEvent(e events.Event) *workflowv1.JobEvent {
+	return &workflowv1.JobEvent{
+		Id:         e.ID,
+		Type:       string(e.Type),
+		JobId:      e.JobID,
+		FlowId:     e.FlowID,
+		JobTaskId:  e.JobTaskID,
+		Status:     e.Status,
+		Data:       toStruct(e.Data),
+		OccurredAt: toTimestamp(e.OccurredAt),
+	}
+}
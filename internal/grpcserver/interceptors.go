@@ -0,0 +1,149 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// 与 REST 接口对应的 metadata 键（gRPC metadata 键均为小写）
+const (
+	requestIDKey = "x-request-id"
+	projectKey   = "x-project-id"
+	apiKeyKey    = "x-api-key"
+)
+
+// scope 为每个调用建立上下文：请求 ID、认证主体与项目；与 HTTP 中间件链的顺序一致
+func (s *Server) scope(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, requestIDKey)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = newRequestID()
+	}
+	ctx = logger.WithFields(ctx, "request_id", requestID)
+
+	if s.authenticator != nil {
+		p, err := s.authenticator.AuthenticateToken(credential(md))
+		if err != nil {
+			logger.Ctx(ctx).Debugf("Authentication failed: %v", err)
+			return ctx, status.Error(codes.Unauthenticated, "authentication required")
+		}
+		ctx = auth.WithPrincipal(ctx, p)
+		ctx = logger.WithFields(ctx, "user_id", p.UserID, "auth_method", string(p.Method))
+	}
+
+	project, err := s.projects.Resolve(first(md, projectKey))
+	if err != nil {
+		return ctx, status.Error(codes.NotFound, "project not found")
+	}
+	if err := s.projects.Authorize(ctx, project.ID); err != nil {
+		return ctx, toStatus(err)
+	}
+	ctx = service.WithProjectID(ctx, project.ID)
+	ctx = logger.WithFields(ctx, "project_id", project.ID)
+	return ctx, nil
+}
+
+// unaryInterceptor 建立调用上下文、捕获 panic 并记录调用日志
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	start := time.Now()
+	ctx, err = s.scope(ctx)
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Ctx(ctx).Errorf("Panic in %s: %v\n%s", info.FullMethod, v, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor 流式调用版本的 unaryInterceptor
+func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	ctx, err := s.scope(ss.Context())
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Ctx(ctx).Errorf("Panic in %s: %v\n%s", info.FullMethod, v, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+	if err != nil {
+		return err
+	}
+	return handler(srv, &scopedStream{ServerStream: ss, ctx: ctx})
+}
+
+// scopedStream 以建立好的调用上下文替换流的上下文
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回调用上下文
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	logger.Ctx(ctx).With(
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	).Debugf("gRPC %s %s", method, code)
+}
+
+// credential 依次从 authorization: Bearer、x-api-key 获取凭据
+func credential(md metadata.MD) string {
+	if h := first(md, "authorization"); h != "" {
+		if scheme, token, ok := strings.Cut(h, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return first(md, apiKeyKey)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// toStatus 将服务层错误映射为 gRPC 状态码，与 REST 的 writeServiceError 对应
+func toStatus(err error) error {
+	var contractErr *executor.ContractError
+	if errors.As(err, &contractErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var forbiddenErr *service.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
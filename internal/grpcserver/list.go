@@ -0,0 +1,70 @@
+package grpcserver
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	workflowv1 "github.com/cfrs2005/GoWorkFlow/api/workflow/v1"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// listRequest 任务、流程与作业列表请求的通用字段
+type listRequest interface {
+	GetLimit() int32
+	GetOffset() int32
+	GetSort() string
+	GetCursor() string
+	GetName() string
+	GetCreatedAfter() *timestamppb.Timestamp
+	GetCreatedBefore() *timestamppb.Timestamp
+	GetUpdatedAfter() *timestamppb.Timestamp
+	GetUpdatedBefore() *timestamppb.Timestamp
+}
+
+// listQueryOf 转换列表通用查询参数，默认分页由服务层补全
+func listQueryOf(req listRequest) models.ListQuery {
+	return models.ListQuery{
+		Limit:         int(req.GetLimit()),
+		Offset:        int(req.GetOffset()),
+		Sort:          req.GetSort(),
+		Cursor:        req.GetCursor(),
+		Name:          req.GetName(),
+		CreatedAfter:  fromTimestamp(req.GetCreatedAfter()),
+		CreatedBefore: fromTimestamp(req.GetCreatedBefore()),
+		UpdatedAfter:  fromTimestamp(req.GetUpdatedAfter()),
+		UpdatedBefore: fromTimestamp(req.GetUpdatedBefore()),
+	}
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// activeOf 解析 is_active：true、false，或 all 表示不限；为空时返回 def
+func activeOf(s string, def *bool) (*bool, error) {
+	switch s {
+	case "":
+		return def, nil
+	case "all":
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid is_active %q, expected true, false or all", s)
+	}
+	return &v, nil
+}
+
+func toPage(p *models.Page) *workflowv1.Page {
+	if p == nil {
+		return nil
+	}
+	return &workflowv1.Page{Total: p.Total, Limit: int32(p.Limit), Offset: int32(p.Offset), NextCursor: p.NextCursor}
+}
@@ -0,0 +1,402 @@
+// Package grpcserver 提供 gRPC 接口，供服务间集成使用；
+// 与 REST 接口共用 service.WorkflowService，认证、项目隔离与权限规则保持一致
+package grpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	workflowv1 "github.com/cfrs2005/GoWorkFlow/api/workflow/v1"
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// Server gRPC 服务
+type Server struct {
+	workflowv1.UnimplementedWorkflowServiceServer
+
+	service       service.WorkflowService
+	projects      *service.ProjectService
+	eventBus      *events.Bus
+	authenticator *auth.Authenticator
+	grpc          *grpc.Server
+}
+
+// NewServer 创建 gRPC 服务，authenticator 为 nil 时不校验凭据
+func NewServer(svc service.WorkflowService, projects *service.ProjectService, eventBus *events.Bus, authenticator *auth.Authenticator) *Server {
+	s := &Server{
+		service:       svc,
+		projects:      projects,
+		eventBus:      eventBus,
+		authenticator: authenticator,
+	}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	)
+	workflowv1.RegisterWorkflowServiceServer(s.grpc, s)
+	return s
+}
+
+// Serve 在 lis 上提供服务，直到 Stop 被调用
+func (s *Server) Serve(lis net.Listener) error {
+	logger.Infof("gRPC server listening on %s", lis.Addr())
+	return s.grpc.Serve(lis)
+}
+
+// Stop 停止接受新调用并等待进行中的调用结束
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// svc 返回以调用上下文做权限校验与项目隔离的服务
+func (s *Server) svc(ctx context.Context) service.WorkflowService {
+	return s.service.WithContext(ctx)
+}
+
+// actorID 启用认证时以认证主体为操作人，否则使用请求中的 ID
+func actorID(ctx context.Context, reqID int64) int64 {
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		return p.UserID
+	}
+	return reqID
+}
+
+// jobTaskInProject 校验作业任务属于当前项目
+func (s *Server) jobTaskInProject(ctx context.Context, jobTaskID int64) error {
+	if _, err := s.svc(ctx).GetJobTask(jobTaskID); err != nil {
+		return status.Error(codes.NotFound, "job task not found")
+	}
+	return nil
+}
+
+// CreateTask 创建任务定义
+func (s *Server) CreateTask(ctx context.Context, req *workflowv1.CreateTaskRequest) (*workflowv1.Task, error) {
+	task := fromTask(req.GetTask())
+	task.ID = 0
+	task.IsActive = true
+	if err := s.svc(ctx).CreateTask(task); err != nil {
+		return nil, toStatus(err)
+	}
+	return toTask(task), nil
+}
+
+// GetTask 获取任务定义
+func (s *Server) GetTask(ctx context.Context, req *workflowv1.GetTaskRequest) (*workflowv1.Task, error) {
+	task, err := s.svc(ctx).GetTask(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	return toTask(task), nil
+}
+
+// ListTasks 列出任务定义
+func (s *Server) ListTasks(ctx context.Context, req *workflowv1.ListTasksRequest) (*workflowv1.ListTasksResponse, error) {
+	filter := models.TaskFilter{ListQuery: listQueryOf(req), TaskType: models.TaskType(req.GetTaskType())}
+	var err error
+	if filter.IsActive, err = activeOf(req.GetIsActive(), nil); err != nil {
+		return nil, err
+	}
+	tasks, page, err := s.svc(ctx).ListTasks(filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &workflowv1.ListTasksResponse{Tasks: make([]*workflowv1.Task, len(tasks)), Page: toPage(page)}
+	for i := range tasks {
+		resp.Tasks[i] = toTask(&tasks[i])
+	}
+	return resp, nil
+}
+
+// UpdateTask 更新任务定义
+func (s *Server) UpdateTask(ctx context.Context, req *workflowv1.UpdateTaskRequest) (*workflowv1.Task, error) {
+	task := fromTask(req.GetTask())
+	if task.ID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "task.id is required")
+	}
+	if _, err := s.svc(ctx).GetTask(task.ID); err != nil {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	if err := s.svc(ctx).UpdateTask(task); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.GetTask(ctx, &workflowv1.GetTaskRequest{Id: task.ID})
+}
+
+// DeleteTask 删除任务定义
+func (s *Server) DeleteTask(ctx context.Context, req *workflowv1.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if _, err := s.svc(ctx).GetTask(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	if err := s.svc(ctx).DeleteTask(req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CreateFlow 创建流程
+func (s *Server) CreateFlow(ctx context.Context, req *workflowv1.CreateFlowRequest) (*workflowv1.Flow, error) {
+	flow := &models.Flow{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Version:     req.GetVersion(),
+		Inputs:      req.GetInputs(),
+		IsActive:    true,
+		CreatedBy:   actorID(ctx, req.GetCreatedBy()),
+	}
+
+	var err error
+	if len(req.GetTasks()) > 0 {
+		flowTasks := make([]models.FlowTask, len(req.GetTasks()))
+		for i, t := range req.GetTasks() {
+			flowTasks[i] = models.FlowTask{
+				TaskID:          t.GetTaskId(),
+				Sequence:        i + 1,
+				IsOptional:      t.GetIsOptional(),
+				AllowRollback:   t.AllowRollback == nil || t.GetAllowRollback(),
+				ConfigOverrides: models.TaskConfig(fromStruct(t.GetConfigOverrides())),
+			}
+		}
+		err = s.svc(ctx).CreateFlowWithTasks(flow, flowTasks)
+	} else {
+		err = s.svc(ctx).CreateFlow(flow, req.GetTaskIds())
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toFlow(flow), nil
+}
+
+// GetFlow 获取流程及其任务编排
+func (s *Server) GetFlow(ctx context.Context, req *workflowv1.GetFlowRequest) (*workflowv1.GetFlowResponse, error) {
+	flow, flowTasks, err := s.svc(ctx).GetFlowWithTasks(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "flow not found")
+	}
+	resp := &workflowv1.GetFlowResponse{
+		Flow:      toFlow(flow),
+		FlowTasks: make([]*workflowv1.FlowTask, len(flowTasks)),
+	}
+	for i := range flowTasks {
+		resp.FlowTasks[i] = toFlowTask(&flowTasks[i])
+	}
+	return resp, nil
+}
+
+// ListFlows 列出流程
+func (s *Server) ListFlows(ctx context.Context, req *workflowv1.ListFlowsRequest) (*workflowv1.ListFlowsResponse, error) {
+	filter := models.FlowFilter{ListQuery: listQueryOf(req), CreatedBy: req.GetCreatedBy()}
+	active := true
+	var err error
+	if filter.IsActive, err = activeOf(req.GetIsActive(), &active); err != nil {
+		return nil, err
+	}
+	flows, page, err := s.svc(ctx).ListFlows(filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &workflowv1.ListFlowsResponse{Flows: make([]*workflowv1.Flow, len(flows)), Page: toPage(page)}
+	for i := range flows {
+		resp.Flows[i] = toFlow(&flows[i])
+	}
+	return resp, nil
+}
+
+// UpdateFlow 更新流程基本信息
+func (s *Server) UpdateFlow(ctx context.Context, req *workflowv1.UpdateFlowRequest) (*workflowv1.Flow, error) {
+	flow := fromFlow(req.GetFlow())
+	if flow.ID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "flow.id is required")
+	}
+	if _, err := s.svc(ctx).GetFlow(flow.ID); err != nil {
+		return nil, status.Error(codes.NotFound, "flow not found")
+	}
+	if err := s.svc(ctx).UpdateFlow(flow); err != nil {
+		return nil, toStatus(err)
+	}
+	updated, err := s.svc(ctx).GetFlow(flow.ID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toFlow(updated), nil
+}
+
+// DeleteFlow 删除流程
+func (s *Server) DeleteFlow(ctx context.Context, req *workflowv1.DeleteFlowRequest) (*emptypb.Empty, error) {
+	if _, err := s.svc(ctx).GetFlow(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, "flow not found")
+	}
+	if err := s.svc(ctx).DeleteFlow(req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CreateJob 基于流程创建作业
+func (s *Server) CreateJob(ctx context.Context, req *workflowv1.CreateJobRequest) (*workflowv1.Job, error) {
+	job, err := s.svc(ctx).CreateJob(req.GetFlowId(), req.GetJobName(), actorID(ctx, req.GetCreatedBy()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toJob(job), nil
+}
+
+// GetJob 获取作业及其作业任务
+func (s *Server) GetJob(ctx context.Context, req *workflowv1.GetJobRequest) (*workflowv1.GetJobResponse, error) {
+	job, jobTasks, err := s.svc(ctx).GetJobWithTasks(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "job not found")
+	}
+	resp := &workflowv1.GetJobResponse{
+		Job:      toJob(job),
+		JobTasks: make([]*workflowv1.JobTask, len(jobTasks)),
+	}
+	for i := range jobTasks {
+		resp.JobTasks[i] = toJobTask(&jobTasks[i])
+	}
+	return resp, nil
+}
+
+// ListJobs 列出作业
+func (s *Server) ListJobs(ctx context.Context, req *workflowv1.ListJobsRequest) (*workflowv1.ListJobsResponse, error) {
+	filter := models.JobFilter{ListQuery: listQueryOf(req), FlowID: req.GetFlowId(), CreatedBy: req.GetCreatedBy()}
+	for _, st := range req.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, models.JobStatus(st))
+	}
+	jobs, page, err := s.svc(ctx).ListJobs(filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &workflowv1.ListJobsResponse{Jobs: make([]*workflowv1.Job, len(jobs)), Page: toPage(page)}
+	for i := range jobs {
+		resp.Jobs[i] = toJob(&jobs[i])
+	}
+	return resp, nil
+}
+
+// StartJob 启动作业
+func (s *Server) StartJob(ctx context.Context, req *workflowv1.StartJobRequest) (*emptypb.Empty, error) {
+	if _, err := s.svc(ctx).GetJob(req.GetJobId()); err != nil {
+		return nil, status.Error(codes.NotFound, "job not found")
+	}
+	if err := s.svc(ctx).StartJob(req.GetJobId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// jobTaskAction 校验作业任务属于当前项目后执行操作
+func (s *Server) jobTaskAction(ctx context.Context, jobTaskID int64, action func(service.WorkflowService) error) (*emptypb.Empty, error) {
+	if err := s.jobTaskInProject(ctx, jobTaskID); err != nil {
+		return nil, err
+	}
+	if err := action(s.svc(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// StartTask 开始执行作业任务
+func (s *Server) StartTask(ctx context.Context, req *workflowv1.StartTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.StartTask(req.GetJobTaskId(), actorID(ctx, req.GetExecutorId()))
+	})
+}
+
+// CompleteTask 完成作业任务
+func (s *Server) CompleteTask(ctx context.Context, req *workflowv1.CompleteTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.CompleteTask(req.GetJobTaskId(), models.TaskResult(fromStruct(req.GetResult())))
+	})
+}
+
+// FailTask 标记作业任务失败
+func (s *Server) FailTask(ctx context.Context, req *workflowv1.FailTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.FailTask(req.GetJobTaskId(), req.GetErrorMessage())
+	})
+}
+
+// SkipTask 跳过可选作业任务
+func (s *Server) SkipTask(ctx context.Context, req *workflowv1.SkipTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.SkipTask(req.GetJobTaskId(), actorID(ctx, req.GetOperatorId()))
+	})
+}
+
+// RollbackTask 回滚到指定序号的作业任务
+func (s *Server) RollbackTask(ctx context.Context, req *workflowv1.RollbackTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.RollbackTask(req.GetJobTaskId(), actorID(ctx, req.GetOperatorId()), int(req.GetTargetSequence()))
+	})
+}
+
+// RetryTask 重试失败的作业任务
+func (s *Server) RetryTask(ctx context.Context, req *workflowv1.RetryTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.RetryTask(req.GetJobTaskId(), actorID(ctx, req.GetOperatorId()))
+	})
+}
+
+// ReassignTask 将作业任务转派给其他执行人
+func (s *Server) ReassignTask(ctx context.Context, req *workflowv1.ReassignTaskRequest) (*emptypb.Empty, error) {
+	return s.jobTaskAction(ctx, req.GetJobTaskId(), func(svc service.WorkflowService) error {
+		return svc.ReassignTask(req.GetJobTaskId(), actorID(ctx, req.GetOperatorId()), req.GetAssigneeId())
+	})
+}
+
+// WatchJob 推送作业当前状态及后续事件，作业结束或客户端取消时返回
+func (s *Server) WatchJob(req *workflowv1.WatchJobRequest, stream workflowv1.WorkflowService_WatchJobServer) error {
+	ctx := stream.Context()
+	svc := s.svc(ctx)
+
+	// 先订阅再读取当前状态，避免两者之间的事件丢失
+	sub := s.eventBus.Subscribe(events.Filter{JobID: req.GetJobId()})
+	defer sub.Close()
+
+	job, err := svc.GetJob(req.GetJobId())
+	if err != nil {
+		return status.Error(codes.NotFound, "job not found")
+	}
+	if err := stream.Send(&workflowv1.WatchJobResponse{Job: toJob(job)}); err != nil {
+		return err
+	}
+
+	for !isFinished(job.Status) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if job, err = svc.GetJob(req.GetJobId()); err != nil {
+				return toStatus(err)
+			}
+			if err := stream.Send(&workflowv1.WatchJobResponse{Event: toJobEvent(e), Job: toJob(job)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isFinished(s models.JobStatus) bool {
+	return s == models.JobStatusCompleted || s == models.JobStatusFailed || s == models.JobStatusCancelled
+}
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
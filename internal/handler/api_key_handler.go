@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// APIKeyHandler API Key 处理器
+type APIKeyHandler struct {
+	service *service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API Key 处理器
+func NewAPIKeyHandler(service *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// CreateAPIKeyRequest 创建 API Key 请求
+type CreateAPIKeyRequest struct {
+	Name      string `json:"name"`
+	UserID    int64  `json:"user_id"`    // 仅在未启用认证时使用
+	ExpiresIn string `json:"expires_in"` // 有效期，如 720h；为空表示不过期
+}
+
+// CreateAPIKey 为当前用户创建 API Key，响应中的 key 只返回一次
+// POST /api/api-keys
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.ExpiresIn); err != nil {
+			response.BadRequest(w, "invalid expires_in")
+			return
+		}
+	}
+
+	key, err := h.service.Create(actorID(r, req.UserID), req.Name, ttl)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, key)
+}
+
+// ListAPIKeys 获取当前用户的 API Key 列表
+// GET /api/api-keys
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+	keys, err := h.service.List(actorID(r, userID))
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.Success(w, keys)
+}
+
+// RevokeAPIKey 吊销当前用户的 API Key
+// DELETE /api/v1/api-keys/{id}（旧接口 DELETE /api/api-keys?id=）
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request, id int64) {
+	userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+
+	if err := h.service.Revoke(actorID(r, userID), id); err != nil {
+		response.NotFound(w, "api key not found")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "api key revoked successfully"})
+}
+
+// WhoAmI 返回当前认证主体
+// GET /api/auth/me
+func WhoAmI(w http.ResponseWriter, r *http.Request) {
+	p, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		response.NotFound(w, "authentication is not enabled")
+		return
+	}
+	response.Success(w, p)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/artifact"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// ArtifactHandler 制品处理器
+type ArtifactHandler struct {
+	service *service.ArtifactService
+}
+
+// NewArtifactHandler 创建制品处理器
+func NewArtifactHandler(service *service.ArtifactService) *ArtifactHandler {
+	return &ArtifactHandler{service: service}
+}
+
+// ListArtifacts 获取作业的制品列表
+// GET /api/jobs/{id}/artifacts
+func (h *ArtifactHandler) ListArtifacts(w http.ResponseWriter, r *http.Request, jobID int64) {
+	artifacts, err := h.service.ListByJob(jobID)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.Success(w, artifacts)
+}
+
+// DownloadArtifact 下载作业制品
+// GET /api/jobs/{id}/artifacts/{artifactId}
+func (h *ArtifactHandler) DownloadArtifact(w http.ResponseWriter, r *http.Request, jobID int64, artifactIDStr string) {
+	artifactID, err := strconv.ParseInt(artifactIDStr, 10, 64)
+	if err != nil {
+		response.BadRequest(w, "invalid artifact id")
+		return
+	}
+
+	record, body, err := h.service.Open(r.Context(), jobID, artifactID)
+	if err != nil {
+		if errors.Is(err, artifact.ErrNotFound) {
+			response.NotFound(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", record.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(record.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", record.Name))
+	w.Header().Set("X-Checksum-Sha256", record.Checksum)
+	if _, err := io.Copy(w, body); err != nil {
+		logger.Ctx(r.Context()).Errorf("Failed to stream artifact %d: %v", artifactID, err)
+	}
+}
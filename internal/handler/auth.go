@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+)
+
+// actorID 返回操作人 ID：已认证时使用认证主体，忽略请求体中自报的 ID；未启用认证时沿用请求体中的值
+func actorID(r *http.Request, bodyID int64) int64 {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return p.UserID
+	}
+	return bodyID
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// writeServiceError 根据服务层错误类型返回对应的 HTTP 状态码
+func writeServiceError(w http.ResponseWriter, err error) {
+	var contractErr *executor.ContractError
+	if errors.As(err, &contractErr) {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	var forbiddenErr *service.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		response.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.InternalServerError(w, err.Error())
+}
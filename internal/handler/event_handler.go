@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// EventHandler 事件流处理器
+type EventHandler struct {
+	bus *events.Bus
+}
+
+// NewEventHandler 创建事件流处理器
+func NewEventHandler(bus *events.Bus) *EventHandler {
+	return &EventHandler{bus: bus}
+}
+
+// StreamEvents 以 Server-Sent Events 推送当前项目的作业与作业任务状态变更
+// GET /api/events?job_id=&flow_id=&types=job.completed,job_task.failed
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.InternalServerError(w, "streaming not supported")
+		return
+	}
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	filter.ProjectID = service.ProjectIDFromContext(r.Context())
+
+	sub := h.bus.Subscribe(filter)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			writeSSE(w, string(event.Type), event.ID, event)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// parseEventFilter 解析事件过滤参数
+func parseEventFilter(r *http.Request) (events.Filter, error) {
+	var filter events.Filter
+	query := r.URL.Query()
+
+	if v := query.Get("job_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid job_id")
+		}
+		filter.JobID = id
+	}
+	if v := query.Get("flow_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid flow_id")
+		}
+		filter.FlowID = id
+	}
+	if v := query.Get("types"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types = append(filter.Types, events.Type(t))
+			}
+		}
+	}
+
+	return filter, nil
+}
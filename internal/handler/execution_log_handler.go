@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// sseKeepAlive SSE 心跳间隔，执行日志流同时借此检查非自动执行任务的状态变化
+const sseKeepAlive = 15 * time.Second
+
+// ExecutionLogHandler 执行日志处理器
+type ExecutionLogHandler struct {
+	service *service.ExecutionLogService
+}
+
+// NewExecutionLogHandler 创建执行日志处理器
+func NewExecutionLogHandler(service *service.ExecutionLogService) *ExecutionLogHandler {
+	return &ExecutionLogHandler{service: service}
+}
+
+// ListExecutionLogs 获取作业任务的执行日志
+// GET /api/job-tasks/{id}/execution-logs?after_id=&limit=
+func (h *ExecutionLogHandler) ListExecutionLogs(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	afterID, _ := strconv.ParseInt(r.URL.Query().Get("after_id"), 10, 64)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if _, err := h.service.JobTaskStatus(jobTaskID); err != nil {
+		response.NotFound(w, "job task not found")
+		return
+	}
+
+	logs, err := h.service.List(jobTaskID, afterID, limit)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.Success(w, logs)
+}
+
+// StreamExecutionLogs 以 Server-Sent Events 跟踪作业任务的执行日志
+// GET /api/job-tasks/{id}/execution-logs/stream
+// 先回放已有日志（支持 Last-Event-ID / after_id 断点续传），任务结束后发送 end 事件并关闭连接
+func (h *ExecutionLogHandler) StreamExecutionLogs(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.InternalServerError(w, "streaming not supported")
+		return
+	}
+
+	status, err := h.service.JobTaskStatus(jobTaskID)
+	if err != nil {
+		response.NotFound(w, "job task not found")
+		return
+	}
+
+	lastID, _ := strconv.ParseInt(r.URL.Query().Get("after_id"), 10, 64)
+	if id, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		lastID = id
+	}
+
+	// 先订阅再回放，避免回放与订阅之间产生的日志丢失
+	lines, cancel := h.service.Subscribe(jobTaskID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	replay := func() bool {
+		backlog, err := h.service.List(jobTaskID, lastID, 0)
+		if err != nil {
+			writeSSE(w, "error", 0, map[string]string{"message": err.Error()})
+			flusher.Flush()
+			return false
+		}
+		for _, line := range backlog {
+			writeSSE(w, "log", line.ID, line)
+			lastID = line.ID
+		}
+		flusher.Flush()
+		return true
+	}
+	end := func(status models.JobTaskStatus) {
+		writeSSE(w, "end", 0, map[string]string{"status": string(status)})
+		flusher.Flush()
+	}
+
+	if !replay() {
+		return
+	}
+	if !isActiveJobTask(status) {
+		end(status)
+		return
+	}
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				// 执行结束：补读可能因缓冲区已满而丢弃的日志
+				replay()
+				status, _ = h.service.JobTaskStatus(jobTaskID)
+				end(status)
+				return
+			}
+			if line.ID <= lastID {
+				continue
+			}
+			writeSSE(w, "log", line.ID, line)
+			lastID = line.ID
+			flusher.Flush()
+		case <-ticker.C:
+			status, err = h.service.JobTaskStatus(jobTaskID)
+			if err == nil && !isActiveJobTask(status) {
+				replay()
+				end(status)
+				return
+			}
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// isActiveJobTask 判断作业任务是否仍可能产生执行日志
+func isActiveJobTask(status models.JobTaskStatus) bool {
+	return status == models.JobTaskStatusPending || status == models.JobTaskStatusRunning
+}
+
+// writeSSE 写入一条 Server-Sent Events 消息
+func writeSSE(w http.ResponseWriter, event string, id int64, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id > 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
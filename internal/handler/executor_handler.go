@@ -2,11 +2,10 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
 	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
 	"github.com/cfrs2005/GoWorkFlow/pkg/response"
@@ -24,33 +23,39 @@ func NewExecutorHandler(taskExecutorService *service.TaskExecutorService) *Execu
 	}
 }
 
-// AutoExecuteJob 自动执行作业的所有任务
-// POST /api/jobs/auto-execute
-func (h *ExecutorHandler) AutoExecuteJob(w http.ResponseWriter, r *http.Request) {
+// AutoExecuteJob 自动执行作业的所有任务，jobID 为 0 时取请求体中的 job_id
+// POST /api/v1/jobs/{id}:auto-execute（旧接口 POST /api/jobs/auto-execute）
+func (h *ExecutorHandler) AutoExecuteJob(w http.ResponseWriter, r *http.Request, jobID int64) {
 	var req struct {
 		JobID int64 `json:"job_id"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	req.JobID = pathOrBodyID(jobID, req.JobID)
 
 	if req.JobID == 0 {
 		response.Error(w, http.StatusBadRequest, "job_id is required")
 		return
 	}
 
-	logger.Infof("Starting auto execution for job %d", req.JobID)
+	if err := h.taskExecutorService.AuthorizeJob(r.Context(), req.JobID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
-	// 创建超时上下文（最多30分钟）
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-	defer cancel()
+	logger.Ctx(r.Context()).Infof("Starting auto execution for job %d", req.JobID)
+
+	// 创建超时上下文（最多30分钟）：沿用请求的日志字段（如 request_id），但不随请求结束而取消
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Minute)
 
 	// 在后台执行
 	go func() {
+		defer cancel()
 		if err := h.taskExecutorService.AutoExecuteJobTasks(ctx, req.JobID); err != nil {
-			logger.Errorf("Auto execution failed for job %d: %v", req.JobID, err)
+			logger.Ctx(ctx).Errorf("Auto execution failed for job %d: %v", req.JobID, err)
 		}
 	}()
 
@@ -61,28 +66,21 @@ func (h *ExecutorHandler) AutoExecuteJob(w http.ResponseWriter, r *http.Request)
 }
 
 // ExecuteTask 执行单个任务
-// POST /api/tasks/execute
-func (h *ExecutorHandler) ExecuteTask(w http.ResponseWriter, r *http.Request) {
-	jobTaskIDStr := r.URL.Query().Get("job_task_id")
-	if jobTaskIDStr == "" {
-		response.Error(w, http.StatusBadRequest, "job_task_id is required")
-		return
-	}
-
-	jobTaskID, err := strconv.ParseInt(jobTaskIDStr, 10, 64)
-	if err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid job_task_id")
+// POST /api/v1/jobs/{id}/tasks/{taskId}:execute（旧接口 POST /api/tasks/execute?job_task_id=）
+func (h *ExecutorHandler) ExecuteTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	if err := h.taskExecutorService.AuthorizeJobTask(r.Context(), jobTaskID); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
-	// 创建超时上下文（最多10分钟）
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	// 创建超时上下文（最多10分钟）：沿用请求的日志字段（如 request_id），但不随请求结束而取消
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 10*time.Minute)
 
 	// 在后台执行
 	go func() {
+		defer cancel()
 		if err := h.taskExecutorService.ExecuteTask(ctx, jobTaskID); err != nil {
-			logger.Errorf("Task execution failed for job_task %d: %v", jobTaskID, err)
+			logger.Ctx(ctx).Errorf("Task execution failed for job_task %d: %v", jobTaskID, err)
 		}
 	}()
 
@@ -91,3 +89,9 @@ func (h *ExecutorHandler) ExecuteTask(w http.ResponseWriter, r *http.Request) {
 		"job_task_id": jobTaskID,
 	})
 }
+
+// ListExecutors 列出已注册执行器及其输入/输出契约
+// GET /api/executors
+func (h *ExecutorHandler) ListExecutors(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, executor.ListDescriptors())
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
@@ -21,12 +20,29 @@ func NewFlowHandler(service service.WorkflowService) *FlowHandler {
 }
 
 // CreateFlowRequest 创建流程请求
+// Tasks 与 TaskIDs 二选一，Tasks 可指定流程级配置覆盖
 type CreateFlowRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Version     string  `json:"version"`
-	TaskIDs     []int64 `json:"task_ids"`
-	CreatedBy   int64   `json:"created_by"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Version     string                  `json:"version"`
+	Inputs      []string                `json:"inputs"`
+	TaskIDs     []int64                 `json:"task_ids"`
+	Tasks       []CreateFlowTaskRequest `json:"tasks"`
+	CreatedBy   int64                   `json:"created_by"`
+}
+
+// CreateFlowTaskRequest 流程任务请求
+type CreateFlowTaskRequest struct {
+	TaskID          int64             `json:"task_id"`
+	IsOptional      bool              `json:"is_optional"`
+	AllowRollback   *bool             `json:"allow_rollback"`
+	ConfigOverrides models.TaskConfig `json:"config_overrides"`
+}
+
+// FlowDetail 流程详情（包含任务编排）
+type FlowDetail struct {
+	Flow      *models.Flow      `json:"flow"`
+	FlowTasks []models.FlowTask `json:"flow_tasks"`
 }
 
 // CreateFlow 创建流程
@@ -41,12 +57,29 @@ func (h *FlowHandler) CreateFlow(w http.ResponseWriter, r *http.Request) {
 		Name:        req.Name,
 		Description: req.Description,
 		Version:     req.Version,
+		Inputs:      req.Inputs,
 		IsActive:    true,
-		CreatedBy:   req.CreatedBy,
+		CreatedBy:   actorID(r, req.CreatedBy),
 	}
 
-	if err := h.service.CreateFlow(flow, req.TaskIDs); err != nil {
-		response.InternalServerError(w, err.Error())
+	var err error
+	if len(req.Tasks) > 0 {
+		flowTasks := make([]models.FlowTask, len(req.Tasks))
+		for i, t := range req.Tasks {
+			flowTasks[i] = models.FlowTask{
+				TaskID:          t.TaskID,
+				Sequence:        i + 1,
+				IsOptional:      t.IsOptional,
+				AllowRollback:   t.AllowRollback == nil || *t.AllowRollback,
+				ConfigOverrides: t.ConfigOverrides,
+			}
+		}
+		err = h.service.WithContext(r.Context()).CreateFlowWithTasks(flow, flowTasks)
+	} else {
+		err = h.service.WithContext(r.Context()).CreateFlow(flow, req.TaskIDs)
+	}
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -54,55 +87,44 @@ func (h *FlowHandler) CreateFlow(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetFlow 获取流程
-func (h *FlowHandler) GetFlow(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
-	if err != nil {
-		response.BadRequest(w, "invalid flow id")
-		return
-	}
-
-	flow, flowTasks, err := h.service.GetFlowWithTasks(id)
+func (h *FlowHandler) GetFlow(w http.ResponseWriter, r *http.Request, id int64) {
+	flow, flowTasks, err := h.service.WithContext(r.Context()).GetFlowWithTasks(id)
 	if err != nil {
 		response.NotFound(w, "flow not found")
 		return
 	}
 
-	result := map[string]interface{}{
-		"flow":       flow,
-		"flow_tasks": flowTasks,
-	}
-
-	response.Success(w, result)
+	response.Success(w, FlowDetail{Flow: flow, FlowTasks: flowTasks})
 }
 
 // ListFlows 获取流程列表
 func (h *FlowHandler) ListFlows(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 {
-		limit = 20
+	filter, err := parseFlowFilter(r.URL.Query())
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	flows, err := h.service.ListFlows(limit, offset)
+	flows, page, err := h.service.WithContext(r.Context()).ListFlows(filter)
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		writeServiceError(w, err)
 		return
 	}
 
-	response.Success(w, flows)
+	response.Paged(w, flows, page)
 }
 
-// UpdateFlow 更新流程
-func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request) {
+// UpdateFlow 更新流程，id 为 0 时取请求体中的 id
+func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request, id int64) {
 	var flow models.Flow
 	if err := json.NewDecoder(r.Body).Decode(&flow); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	flow.ID = pathOrBodyID(id, flow.ID)
 
-	if err := h.service.UpdateFlow(&flow); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).UpdateFlow(&flow); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -110,17 +132,23 @@ func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteFlow 删除流程
-func (h *FlowHandler) DeleteFlow(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
-	if err != nil {
-		response.BadRequest(w, "invalid flow id")
+func (h *FlowHandler) DeleteFlow(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := h.service.WithContext(r.Context()).DeleteFlow(id); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
-	if err := h.service.DeleteFlow(id); err != nil {
-		response.InternalServerError(w, err.Error())
+	response.Success(w, map[string]string{"message": "flow deleted successfully"})
+}
+
+// ListFlowTasks 获取流程的任务编排
+// GET /api/v1/flows/{id}/tasks
+func (h *FlowHandler) ListFlowTasks(w http.ResponseWriter, r *http.Request, id int64) {
+	_, flowTasks, err := h.service.WithContext(r.Context()).GetFlowWithTasks(id)
+	if err != nil {
+		response.NotFound(w, "flow not found")
 		return
 	}
 
-	response.Success(w, map[string]string{"message": "flow deleted successfully"})
+	response.Success(w, flowTasks)
 }
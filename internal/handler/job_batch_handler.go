@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// batchJobTimeout 批次中每个作业自动执行的最长时间，与单个作业自动执行相同
+const batchJobTimeout = 30 * time.Minute
+
+// jobNameColumn 输入行中作为作业名称的列，其余列为流程输入
+const jobNameColumn = "job_name"
+
+// JobBatchHandler 作业批次处理器
+type JobBatchHandler struct {
+	service             service.WorkflowService
+	taskExecutorService *service.TaskExecutorService
+}
+
+// NewJobBatchHandler 创建作业批次处理器
+func NewJobBatchHandler(service service.WorkflowService, taskExecutorService *service.TaskExecutorService) *JobBatchHandler {
+	return &JobBatchHandler{service: service, taskExecutorService: taskExecutorService}
+}
+
+// CreateJobBatchRequest 批量创建作业请求。
+// 请求体为 JSON 对象；或为 JSON 数组、CSV（Content-Type: text/csv，首行为列名）形式的输入行，其余字段经同名查询参数传入
+type CreateJobBatchRequest struct {
+	FlowID      int64                    `json:"flow_id"`
+	Name        string                   `json:"name"`         // 批次名称，默认为流程名称加创建时间
+	Rows        []map[string]interface{} `json:"rows"`         // 每行创建一个作业：job_name 为作业名称，其余键为流程输入
+	AutoExecute bool                     `json:"auto_execute"` // 创建后启动并自动执行
+	Concurrency int                      `json:"concurrency"`  // 自动执行的最大并发作业数，默认 4
+	CreatedBy   int64                    `json:"created_by"`
+}
+
+// JobBatchDetail 批次及其作业
+type JobBatchDetail struct {
+	Batch *models.JobBatch `json:"batch"`
+	Jobs  []models.Job     `json:"jobs"`
+}
+
+// CancelJobBatchRequest 取消批次请求
+type CancelJobBatchRequest struct {
+	Reason     string `json:"reason"`
+	OperatorID int64  `json:"operator_id"`
+}
+
+// RetryJobBatchRequest 重试批次失败作业请求
+type RetryJobBatchRequest struct {
+	OperatorID  int64 `json:"operator_id"`
+	AutoExecute *bool `json:"auto_execute"` // 重试后自动执行，默认沿用批次设置
+}
+
+// CreateJobBatch 按输入行批量创建作业，可选自动执行
+// POST /api/v1/job-batches
+func (h *JobBatchHandler) CreateJobBatch(w http.ResponseWriter, r *http.Request) {
+	req, err := parseCreateJobBatchRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	items := make([]models.JobBatchItem, len(req.Rows))
+	for i, row := range req.Rows {
+		item := models.JobBatchItem{Inputs: make(map[string]interface{}, len(row))}
+		for key, value := range row {
+			if key != jobNameColumn {
+				item.Inputs[key] = value
+				continue
+			}
+			name, ok := value.(string)
+			if !ok {
+				response.BadRequest(w, fmt.Sprintf("row %d: %s must be a string", i+1, jobNameColumn))
+				return
+			}
+			item.JobName = strings.TrimSpace(name)
+		}
+		items[i] = item
+	}
+
+	batch := &models.JobBatch{
+		FlowID:      req.FlowID,
+		Name:        req.Name,
+		AutoExecute: req.AutoExecute,
+		Concurrency: req.Concurrency,
+		CreatedBy:   actorID(r, req.CreatedBy),
+	}
+	jobs, err := h.service.WithContext(r.Context()).CreateJobBatch(batch, items)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if batch.AutoExecute {
+		jobIDs := make([]int64, len(jobs))
+		for i := range jobs {
+			jobIDs[i] = jobs[i].ID
+		}
+		h.autoExecute(r, batch, jobIDs)
+	}
+
+	response.Created(w, JobBatchDetail{Batch: batch, Jobs: jobs})
+}
+
+// GetJobBatch 获取批次及其作业按状态的统计
+// GET /api/v1/job-batches/{id}
+func (h *JobBatchHandler) GetJobBatch(w http.ResponseWriter, r *http.Request, id int64) {
+	summary, err := h.service.WithContext(r.Context()).GetJobBatch(id)
+	if err != nil {
+		response.NotFound(w, "job batch not found")
+		return
+	}
+
+	response.Success(w, summary)
+}
+
+// ListBatchJobs 获取批次中的作业，支持作业列表的查询参数
+// GET /api/v1/job-batches/{id}/jobs
+func (h *JobBatchHandler) ListBatchJobs(w http.ResponseWriter, r *http.Request, id int64) {
+	svc := h.service.WithContext(r.Context())
+	if _, err := svc.GetJobBatch(id); err != nil {
+		response.NotFound(w, "job batch not found")
+		return
+	}
+
+	filter, err := parseJobFilter(r.URL.Query())
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	filter.BatchID = id
+
+	jobs, page, err := svc.ListJobs(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Paged(w, jobs, page)
+}
+
+// CancelJobBatch 取消批次中所有待执行与执行中的作业
+// POST /api/v1/job-batches/{id}:cancel
+func (h *JobBatchHandler) CancelJobBatch(w http.ResponseWriter, r *http.Request, id int64) {
+	var req CancelJobBatchRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	svc := h.service.WithContext(r.Context())
+	if _, err := svc.GetJobBatch(id); err != nil {
+		response.NotFound(w, "job batch not found")
+		return
+	}
+	result, err := svc.CancelJobBatch(id, actorID(r, req.OperatorID), req.Reason)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// RetryFailedJobBatch 重试批次中所有失败的作业，批次开启自动执行时按批次并发数重新执行
+// POST /api/v1/job-batches/{id}:retry-failed
+func (h *JobBatchHandler) RetryFailedJobBatch(w http.ResponseWriter, r *http.Request, id int64) {
+	var req RetryJobBatchRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	svc := h.service.WithContext(r.Context())
+	summary, err := svc.GetJobBatch(id)
+	if err != nil {
+		response.NotFound(w, "job batch not found")
+		return
+	}
+	result, err := svc.RetryFailedJobBatch(id, actorID(r, req.OperatorID))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	autoExecute := summary.Batch.AutoExecute
+	if req.AutoExecute != nil {
+		autoExecute = *req.AutoExecute
+	}
+	if autoExecute && len(result.JobIDs) > 0 {
+		h.autoExecute(r, summary.Batch, result.JobIDs)
+	}
+
+	response.Success(w, result)
+}
+
+// autoExecute 在后台按批次并发数自动执行作业：沿用请求的日志字段，但不随请求结束而取消
+func (h *JobBatchHandler) autoExecute(r *http.Request, batch *models.JobBatch, jobIDs []int64) {
+	ctx := logger.WithFields(context.WithoutCancel(r.Context()), "batch_id", batch.ID)
+	logger.Ctx(ctx).Infof("Starting auto execution for %d jobs of batch %d (concurrency %d)", len(jobIDs), batch.ID, batch.Concurrency)
+
+	go h.taskExecutorService.AutoExecuteJobs(ctx, jobIDs, batch.Concurrency, batchJobTimeout)
+}
+
+// parseCreateJobBatchRequest 按 Content-Type 解析 JSON 对象、JSON 数组或 CSV 请求体
+func parseCreateJobBatchRequest(r *http.Request) (CreateJobBatchRequest, error) {
+	var req CreateJobBatchRequest
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return req, fmt.Errorf("invalid request body")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "text/csv":
+		if req.Rows, err = parseBatchCSV(body); err != nil {
+			return req, err
+		}
+	case bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")):
+		if err := json.Unmarshal(body, &req.Rows); err != nil {
+			return req, fmt.Errorf("invalid request body")
+		}
+	default:
+		if err := json.Unmarshal(body, &req); err != nil {
+			return req, fmt.Errorf("invalid request body")
+		}
+		return req, nil
+	}
+
+	// 输入行之外的字段取自查询参数
+	err = batchQueryParams(r.URL.Query(), &req)
+	return req, err
+}
+
+// batchQueryParams 解析 flow_id、name、auto_execute、concurrency、created_by 查询参数
+func batchQueryParams(q url.Values, req *CreateJobBatchRequest) error {
+	var err error
+	if req.FlowID, err = int64Param(q, "flow_id"); err != nil {
+		return err
+	}
+	if req.Concurrency, err = intParam(q, "concurrency"); err != nil {
+		return err
+	}
+	if req.CreatedBy, err = int64Param(q, "created_by"); err != nil {
+		return err
+	}
+	req.Name = strings.TrimSpace(q.Get("name"))
+	if s := q.Get("auto_execute"); s != "" {
+		if req.AutoExecute, err = strconv.ParseBool(s); err != nil {
+			return fmt.Errorf("invalid auto_execute %q", s)
+		}
+	}
+	return nil
+}
+
+// parseBatchCSV 解析 CSV 输入行：首行为列名，空单元格视为未提供
+func parseBatchCSV(data []byte) ([]map[string]interface{}, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV header row is required")
+	}
+
+	header := records[0]
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			return nil, fmt.Errorf("CSV column %d: empty or duplicate column name %q", i+1, name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
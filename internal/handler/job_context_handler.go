@@ -3,9 +3,8 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
-	"strings"
 
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/repository"
 	"github.com/cfrs2005/GoWorkFlow/pkg/response"
 )
@@ -21,36 +20,52 @@ func NewJobContextHandler(repo repository.JobContextRepository) *JobContextHandl
 }
 
 // GetJobContext 获取作业上下文
-// GET /api/jobs/{id}/context
-func (h *JobContextHandler) GetJobContext(w http.ResponseWriter, r *http.Request) {
-	// 从 URL 路径中提取 job_id
-	jobID, err := h.extractJobID(r)
-	if err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid job ID")
+// GET /api/jobs/{id}/context[?scope=shared|task:<任务名称>]
+// 未指定 scope 时返回 {"shared": {...}, "tasks": {"<任务名称>": {...}}}
+func (h *JobContextHandler) GetJobContext(w http.ResponseWriter, r *http.Request, jobID int64) {
+	if scope := r.URL.Query().Get("scope"); scope != "" {
+		values, err := h.repo.GetScope(jobID, scope)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Success(w, values)
 		return
 	}
 
-	context, err := h.repo.GetByJobID(jobID)
+	scopes, err := h.repo.GetByJobID(jobID)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.Success(w, context)
+	shared := scopes[models.ContextScopeShared]
+	if shared == nil {
+		shared = make(map[string]interface{})
+	}
+	tasks := make(map[string]map[string]interface{})
+	for scope, values := range scopes {
+		if name, ok := models.ParseTaskContextScope(scope); ok {
+			tasks[name] = values
+		}
+	}
+
+	response.Success(w, map[string]interface{}{
+		"shared": shared,
+		"tasks":  tasks,
+	})
 }
 
-// UpdateJobContext 更新作业上下文
-// PUT /api/jobs/{id}/context
-func (h *JobContextHandler) UpdateJobContext(w http.ResponseWriter, r *http.Request) {
-	// 从 URL 路径中提取 job_id
-	jobID, err := h.extractJobID(r)
-	if err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid job ID")
-		return
+// UpdateJobContext 更新作业上下文（值可为任意 JSON 类型）
+// PUT /api/jobs/{id}/context[?scope=shared|task:<任务名称>]
+func (h *JobContextHandler) UpdateJobContext(w http.ResponseWriter, r *http.Request, jobID int64) {
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		scope = models.ContextScopeShared
 	}
 
 	// 解析请求体
-	var contextData map[string]string
+	var contextData map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&contextData); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
@@ -58,7 +73,7 @@ func (h *JobContextHandler) UpdateJobContext(w http.ResponseWriter, r *http.Requ
 
 	// 更新每个键值对
 	for key, value := range contextData {
-		if err := h.repo.Set(jobID, key, value); err != nil {
+		if err := h.repo.Set(jobID, scope, key, value); err != nil {
 			response.Error(w, http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -66,14 +81,3 @@ func (h *JobContextHandler) UpdateJobContext(w http.ResponseWriter, r *http.Requ
 
 	response.Success(w, map[string]string{"message": "Context updated successfully"})
 }
-
-// extractJobID 从请求路径中提取 job_id
-func (h *JobContextHandler) extractJobID(r *http.Request) (int64, error) {
-	// 路径格式: /api/jobs/{id}/context
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 3 {
-		return 0, strconv.ErrSyntax
-	}
-
-	return strconv.ParseInt(parts[2], 10, 64)
-}
@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
@@ -27,6 +26,12 @@ type CreateJobRequest struct {
 	CreatedBy int64  `json:"created_by"`
 }
 
+// JobDetail 作业详情（包含所有作业任务）
+type JobDetail struct {
+	Job      *models.Job      `json:"job"`
+	JobTasks []models.JobTask `json:"job_tasks"`
+}
+
 // CreateJob 创建作业
 func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 	var req CreateJobRequest
@@ -35,9 +40,9 @@ func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	job, err := h.service.CreateJob(req.FlowID, req.JobName, req.CreatedBy)
+	job, err := h.service.WithContext(r.Context()).CreateJob(req.FlowID, req.JobName, actorID(r, req.CreatedBy))
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		writeServiceError(w, err)
 		return
 	}
 
@@ -45,43 +50,31 @@ func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetJob 获取作业
-func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
-	if err != nil {
-		response.BadRequest(w, "invalid job id")
-		return
-	}
-
-	job, jobTasks, err := h.service.GetJobWithTasks(id)
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request, id int64) {
+	job, jobTasks, err := h.service.WithContext(r.Context()).GetJobWithTasks(id)
 	if err != nil {
 		response.NotFound(w, "job not found")
 		return
 	}
 
-	result := map[string]interface{}{
-		"job":       job,
-		"job_tasks": jobTasks,
-	}
-
-	response.Success(w, result)
+	response.Success(w, JobDetail{Job: job, JobTasks: jobTasks})
 }
 
 // ListJobs 获取作业列表
 func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 {
-		limit = 20
+	filter, err := parseJobFilter(r.URL.Query())
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	jobs, err := h.service.ListJobs(limit, offset)
+	jobs, page, err := h.service.WithContext(r.Context()).ListJobs(filter)
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		writeServiceError(w, err)
 		return
 	}
 
-	response.Success(w, jobs)
+	response.Paged(w, jobs, page)
 }
 
 // StartJobRequest 启动作业请求
@@ -89,16 +82,16 @@ type StartJobRequest struct {
 	JobID int64 `json:"job_id"`
 }
 
-// StartJob 启动作业
-func (h *JobHandler) StartJob(w http.ResponseWriter, r *http.Request) {
+// StartJob 启动作业，jobID 为 0 时取请求体中的 job_id
+func (h *JobHandler) StartJob(w http.ResponseWriter, r *http.Request, jobID int64) {
 	var req StartJobRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
 
-	if err := h.service.StartJob(req.JobID); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).StartJob(pathOrBodyID(jobID, req.JobID)); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -111,16 +104,17 @@ type StartTaskRequest struct {
 	ExecutorID int64 `json:"executor_id"`
 }
 
-// StartTask 开始任务
-func (h *JobHandler) StartTask(w http.ResponseWriter, r *http.Request) {
+// StartTask 开始任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) StartTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
 	var req StartTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
 
-	if err := h.service.StartTask(req.JobTaskID, req.ExecutorID); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).StartTask(req.JobTaskID, actorID(r, req.ExecutorID)); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -133,16 +127,17 @@ type CompleteTaskRequest struct {
 	Result    models.TaskResult  `json:"result"`
 }
 
-// CompleteTask 完成任务
-func (h *JobHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
+// CompleteTask 完成任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) CompleteTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
 	var req CompleteTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
 
-	if err := h.service.CompleteTask(req.JobTaskID, req.Result); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).CompleteTask(req.JobTaskID, req.Result); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -155,16 +150,17 @@ type FailTaskRequest struct {
 	ErrorMessage string `json:"error_message"`
 }
 
-// FailTask 任务失败
-func (h *JobHandler) FailTask(w http.ResponseWriter, r *http.Request) {
+// FailTask 任务失败，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) FailTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
 	var req FailTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
 
-	if err := h.service.FailTask(req.JobTaskID, req.ErrorMessage); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).FailTask(req.JobTaskID, req.ErrorMessage); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -177,16 +173,17 @@ type SkipTaskRequest struct {
 	OperatorID int64 `json:"operator_id"`
 }
 
-// SkipTask 跳过任务
-func (h *JobHandler) SkipTask(w http.ResponseWriter, r *http.Request) {
+// SkipTask 跳过任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) SkipTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
 	var req SkipTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
 
-	if err := h.service.SkipTask(req.JobTaskID, req.OperatorID); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).SkipTask(req.JobTaskID, actorID(r, req.OperatorID)); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -200,31 +197,97 @@ type RollbackTaskRequest struct {
 	TargetSequence int   `json:"target_sequence"`
 }
 
-// RollbackTask 打回任务
-func (h *JobHandler) RollbackTask(w http.ResponseWriter, r *http.Request) {
+// RollbackTask 打回任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) RollbackTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
 	var req RollbackTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeOptionalJSON(r, &req); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
 
-	if err := h.service.RollbackTask(req.JobTaskID, req.OperatorID, req.TargetSequence); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).RollbackTask(req.JobTaskID, actorID(r, req.OperatorID), req.TargetSequence); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
 	response.Success(w, map[string]string{"message": "task rolled back successfully"})
 }
 
-// GetNextTask 获取下一个待执行的任务
-func (h *JobHandler) GetNextTask(w http.ResponseWriter, r *http.Request) {
-	jobID, err := strconv.ParseInt(r.URL.Query().Get("job_id"), 10, 64)
+// RetryTaskRequest 重试任务请求
+type RetryTaskRequest struct {
+	JobTaskID  int64 `json:"job_task_id"`
+	OperatorID int64 `json:"operator_id"`
+}
+
+// RetryTask 重试失败的任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) RetryTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	var req RetryTaskRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
+
+	if err := h.service.WithContext(r.Context()).RetryTask(req.JobTaskID, actorID(r, req.OperatorID)); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "task retried successfully"})
+}
+
+// ReassignTaskRequest 转派任务请求
+type ReassignTaskRequest struct {
+	JobTaskID  int64 `json:"job_task_id"`
+	OperatorID int64 `json:"operator_id"`
+	AssigneeID int64 `json:"assignee_id"`
+}
+
+// ReassignTask 转派任务，jobTaskID 为 0 时取请求体中的 job_task_id
+func (h *JobHandler) ReassignTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	var req ReassignTaskRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	req.JobTaskID = pathOrBodyID(jobTaskID, req.JobTaskID)
+
+	if err := h.service.WithContext(r.Context()).ReassignTask(req.JobTaskID, actorID(r, req.OperatorID), req.AssigneeID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "task reassigned successfully"})
+}
+
+// ListJobLogs 获取作业的审计日志
+// GET /api/jobs/{id}/logs
+func (h *JobHandler) ListJobLogs(w http.ResponseWriter, r *http.Request, jobID int64) {
+	logs, err := h.service.WithContext(r.Context()).ListJobLogs(jobID)
+	if err != nil {
+		response.NotFound(w, err.Error())
+		return
+	}
+
+	response.Success(w, logs)
+}
+
+// ListJobTaskLogs 获取作业任务的审计日志
+// GET /api/job-tasks/{id}/logs
+func (h *JobHandler) ListJobTaskLogs(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	logs, err := h.service.WithContext(r.Context()).ListJobTaskLogs(jobTaskID)
 	if err != nil {
-		response.BadRequest(w, "invalid job id")
+		response.NotFound(w, err.Error())
 		return
 	}
 
-	task, err := h.service.GetNextTask(jobID)
+	response.Success(w, logs)
+}
+
+// GetNextTask 获取下一个待执行的任务
+func (h *JobHandler) GetNextTask(w http.ResponseWriter, r *http.Request, jobID int64) {
+	task, err := h.service.WithContext(r.Context()).GetNextTask(jobID)
 	if err != nil {
 		response.InternalServerError(w, err.Error())
 		return
@@ -237,3 +300,83 @@ func (h *JobHandler) GetNextTask(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, task)
 }
+
+// ListJobTasks 获取作业的任务列表
+// GET /api/v1/jobs/{id}/tasks
+func (h *JobHandler) ListJobTasks(w http.ResponseWriter, r *http.Request, jobID int64) {
+	_, jobTasks, err := h.service.WithContext(r.Context()).GetJobWithTasks(jobID)
+	if err != nil {
+		response.NotFound(w, "job not found")
+		return
+	}
+
+	response.Success(w, jobTasks)
+}
+
+// GetJobTask 获取作业任务
+// GET /api/v1/jobs/{id}/tasks/{taskId}
+func (h *JobHandler) GetJobTask(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+	jobTask, err := h.service.WithContext(r.Context()).GetJobTask(jobTaskID)
+	if err != nil {
+		response.NotFound(w, "job task not found")
+		return
+	}
+
+	response.Success(w, jobTask)
+}
+
+// CancelJobRequest 取消作业请求
+type CancelJobRequest struct {
+	Reason     string `json:"reason"`
+	OperatorID int64  `json:"operator_id"`
+}
+
+// CancelJob 取消待执行或执行中的作业，执行中的任务标记为失败
+// POST /api/v1/jobs/{id}:cancel
+func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request, jobID int64) {
+	var req CancelJobRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.service.WithContext(r.Context()).CancelJob(jobID, actorID(r, req.OperatorID), req.Reason); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "job cancelled successfully"})
+}
+
+// RerunJobRequest 重新运行作业请求
+type RerunJobRequest struct {
+	JobName       string                 `json:"job_name"`       // 默认为来源作业名称加 (rerun)
+	LatestVersion bool                   `json:"latest_version"` // 使用同名流程的最新启用版本
+	Inputs        map[string]interface{} `json:"inputs"`         // 覆盖来源作业的输入
+	ReuseOutputs  bool                   `json:"reuse_outputs"`  // 复用未变化的上游任务输出
+	CreatedBy     int64                  `json:"created_by"`
+}
+
+// RerunJob 以来源作业的输入创建新作业，可覆盖输入并复用未变化的上游任务输出
+// POST /api/v1/jobs/{id}:rerun
+func (h *JobHandler) RerunJob(w http.ResponseWriter, r *http.Request, jobID int64) {
+	var req RerunJobRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	rerun, err := h.service.WithContext(r.Context()).RerunJob(jobID, models.JobRerunOptions{
+		JobName:       req.JobName,
+		LatestVersion: req.LatestVersion,
+		Inputs:        req.Inputs,
+		ReuseOutputs:  req.ReuseOutputs,
+		CreatedBy:     actorID(r, req.CreatedBy),
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, rerun)
+}
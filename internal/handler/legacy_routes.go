@@ -0,0 +1,454 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// handleLegacy 注册旧版路由：行为不变，响应带 Deprecation 头与指向 v1 接口的 Link 头
+func handleLegacy(mux *http.ServeMux, pattern, successor string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		handler(w, r)
+	})
+}
+
+// queryID 读取查询参数中的整数 ID，解析失败时返回 400
+func queryID(w http.ResponseWriter, r *http.Request, name, label string) (int64, bool) {
+	id, err := strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "invalid "+label+" id")
+		return 0, false
+	}
+	return id, true
+}
+
+// setupLegacy 注册 v1 之前的接口，已弃用，新客户端请使用 /api/v1
+func (router *Router) setupLegacy(mux *http.ServeMux) {
+	// Task 路由
+	handleLegacy(mux, "/api/tasks", "/api/v1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.taskHandler.CreateTask(w, r)
+		case http.MethodGet:
+			if r.URL.Query().Get("id") == "" {
+				router.taskHandler.ListTasks(w, r)
+				return
+			}
+			if id, ok := queryID(w, r, "id", "task"); ok {
+				router.taskHandler.GetTask(w, r, id)
+			}
+		case http.MethodPut:
+			router.taskHandler.UpdateTask(w, r, 0)
+		case http.MethodDelete:
+			if id, ok := queryID(w, r, "id", "task"); ok {
+				router.taskHandler.DeleteTask(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Flow 路由
+	handleLegacy(mux, "/api/flows", "/api/v1/flows", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.flowHandler.CreateFlow(w, r)
+		case http.MethodGet:
+			if r.URL.Query().Get("id") == "" {
+				router.flowHandler.ListFlows(w, r)
+				return
+			}
+			if id, ok := queryID(w, r, "id", "flow"); ok {
+				router.flowHandler.GetFlow(w, r, id)
+			}
+		case http.MethodPut:
+			router.flowHandler.UpdateFlow(w, r, 0)
+		case http.MethodDelete:
+			if id, ok := queryID(w, r, "id", "flow"); ok {
+				router.flowHandler.DeleteFlow(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Job 路由
+	handleLegacy(mux, "/api/jobs", "/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.jobHandler.CreateJob(w, r)
+		case http.MethodGet:
+			if r.URL.Query().Get("id") == "" {
+				router.jobHandler.ListJobs(w, r)
+				return
+			}
+			if id, ok := queryID(w, r, "id", "job"); ok {
+				router.jobHandler.GetJob(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	handleLegacy(mux, "/api/jobs/start", "/api/v1/jobs/{id}:start", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		router.jobHandler.StartJob(w, r, 0)
+	})
+
+	// 自动执行路由（新增）
+	handleLegacy(mux, "/api/jobs/auto-execute", "/api/v1/jobs/{id}:auto-execute", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		router.executorHandler.AutoExecuteJob(w, r, 0)
+	})
+
+	handleLegacy(mux, "/api/jobs/next-task", "/api/v1/jobs/{id}/next-task", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if jobID, ok := queryID(w, r, "job_id", "job"); ok {
+			router.jobHandler.GetNextTask(w, r, jobID)
+		}
+	})
+
+	// Job 子资源路由：/api/jobs/{id}/context、/api/jobs/{id}/rerun、/api/jobs/{id}/logs、/api/jobs/{id}/artifacts[/{artifactId}]
+	handleLegacy(mux, "/api/jobs/", "/api/v1/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/"), "/")
+		if len(parts) < 2 {
+			http.NotFound(w, r)
+			return
+		}
+		jobID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			response.BadRequest(w, "invalid job id")
+			return
+		}
+		if !router.jobInProject(w, r, jobID) {
+			return
+		}
+
+		switch {
+		case len(parts) == 2 && parts[1] == "context":
+			switch r.Method {
+			case http.MethodGet:
+				router.jobContextHandler.GetJobContext(w, r, jobID)
+			case http.MethodPut:
+				router.jobContextHandler.UpdateJobContext(w, r, jobID)
+			default:
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			}
+		case len(parts) == 2 && parts[1] == "rerun":
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			router.jobHandler.RerunJob(w, r, jobID)
+		case len(parts) == 2 && parts[1] == "logs":
+			if r.Method != http.MethodGet {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			router.jobHandler.ListJobLogs(w, r, jobID)
+		case parts[1] == "artifacts" && len(parts) <= 3:
+			if r.Method != http.MethodGet {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if len(parts) == 2 {
+				router.artifactHandler.ListArtifacts(w, r, jobID)
+			} else {
+				router.artifactHandler.DownloadArtifact(w, r, jobID, parts[2])
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// JobTask 路由：与任务定义共用 /api/tasks 前缀，作业任务 ID 位于请求体
+	jobTaskActions := map[string]func(http.ResponseWriter, *http.Request, int64){
+		"start":    router.jobHandler.StartTask,
+		"complete": router.jobHandler.CompleteTask,
+		"fail":     router.jobHandler.FailTask,
+		"skip":     router.jobHandler.SkipTask,
+		"rollback": router.jobHandler.RollbackTask,
+		"retry":    router.jobHandler.RetryTask,
+		"reassign": router.jobHandler.ReassignTask,
+	}
+	for action, handle := range jobTaskActions {
+		handle := handle
+		handleLegacy(mux, "/api/tasks/"+action, "/api/v1/jobs/{id}/tasks/{taskId}:"+action, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			handle(w, r, 0)
+		})
+	}
+
+	// JobTask 子资源路由：/api/job-tasks/{id}/logs、/api/job-tasks/{id}/execution-logs[/stream]
+	handleLegacy(mux, "/api/job-tasks/", "/api/v1/jobs/{id}/tasks/{taskId}", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/job-tasks/"), "/"), "/")
+		if len(parts) < 2 {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		jobTaskID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			response.BadRequest(w, "invalid job task id")
+			return
+		}
+		if !router.jobTaskInProject(w, r, jobTaskID) {
+			return
+		}
+
+		switch {
+		case len(parts) == 2 && parts[1] == "logs":
+			router.jobHandler.ListJobTaskLogs(w, r, jobTaskID)
+		case len(parts) == 2 && parts[1] == "execution-logs":
+			router.executionLogHandler.ListExecutionLogs(w, r, jobTaskID)
+		case len(parts) == 3 && parts[1] == "execution-logs" && parts[2] == "stream":
+			router.executionLogHandler.StreamExecutionLogs(w, r, jobTaskID)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// 任务执行路由（新增）
+	handleLegacy(mux, "/api/tasks/execute", "/api/v1/jobs/{id}/tasks/{taskId}:execute", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if jobTaskID, ok := queryID(w, r, "job_task_id", "job task"); ok {
+			router.executorHandler.ExecuteTask(w, r, jobTaskID)
+		}
+	})
+
+	// 事件流路由
+	handleLegacy(mux, "/api/events", "/api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		router.eventHandler.StreamEvents(w, r)
+	})
+
+	// Webhook 路由
+	handleLegacy(mux, "/api/webhooks", "/api/v1/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.webhookHandler.CreateWebhook(w, r)
+		case http.MethodGet:
+			if r.URL.Query().Get("id") == "" {
+				router.webhookHandler.ListWebhooks(w, r)
+				return
+			}
+			if id, ok := queryID(w, r, "id", "webhook"); ok {
+				router.webhookHandler.GetWebhook(w, r, id)
+			}
+		case http.MethodPut:
+			router.webhookHandler.UpdateWebhook(w, r, 0)
+		case http.MethodDelete:
+			if id, ok := queryID(w, r, "id", "webhook"); ok {
+				router.webhookHandler.DeleteWebhook(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Webhook 子资源路由：/api/webhooks/{id}/deliveries
+	handleLegacy(mux, "/api/webhooks/", "/api/v1/webhooks/{id}/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhooks/"), "/"), "/")
+		if len(parts) != 2 || parts[1] != "deliveries" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		subscriptionID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			response.BadRequest(w, "invalid webhook id")
+			return
+		}
+		router.webhookHandler.ListDeliveries(w, r, subscriptionID)
+	})
+
+	// 投递记录路由：/api/webhook-deliveries/{id}、/api/webhook-deliveries/{id}/redeliver
+	handleLegacy(mux, "/api/webhook-deliveries/", "/api/v1/webhook-deliveries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhook-deliveries/"), "/"), "/")
+		deliveryID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			response.BadRequest(w, "invalid delivery id")
+			return
+		}
+
+		switch {
+		case len(parts) == 1:
+			if r.Method != http.MethodGet {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			router.webhookHandler.GetDelivery(w, r, deliveryID)
+		case len(parts) == 2 && parts[1] == "redeliver":
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			router.webhookHandler.Redeliver(w, r, deliveryID)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// 执行器契约路由
+	handleLegacy(mux, "/api/executors", "/api/v1/executors", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		router.executorHandler.ListExecutors(w, r)
+	})
+
+	// 日志级别路由
+	handleLegacy(mux, "/api/log-level", "/api/v1/log-level", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			router.logLevelHandler.GetLogLevel(w, r)
+		case http.MethodPut:
+			router.logLevelHandler.SetLogLevel(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API Key 路由
+	handleLegacy(mux, "/api/api-keys", "/api/v1/api-keys", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.apiKeyHandler.CreateAPIKey(w, r)
+		case http.MethodGet:
+			router.apiKeyHandler.ListAPIKeys(w, r)
+		case http.MethodDelete:
+			if id, ok := queryID(w, r, "id", "api key"); ok {
+				router.apiKeyHandler.RevokeAPIKey(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 角色绑定路由
+	handleLegacy(mux, "/api/role-bindings", "/api/v1/role-bindings", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.roleBindingHandler.CreateRoleBinding(w, r)
+		case http.MethodGet:
+			router.roleBindingHandler.ListRoleBindings(w, r)
+		case http.MethodDelete:
+			if id, ok := queryID(w, r, "id", "role binding"); ok {
+				router.roleBindingHandler.DeleteRoleBinding(w, r, id)
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 内置角色
+	handleLegacy(mux, "/api/roles", "/api/v1/roles", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		ListRoles(w, r)
+	})
+
+	// Project 路由
+	handleLegacy(mux, "/api/projects", "/api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			router.projectHandler.CreateProject(w, r)
+		case http.MethodGet:
+			router.projectHandler.ListProjects(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Project 子资源路由：/api/projects/{id}、/api/projects/{id}/members、/api/projects/{id}/secrets
+	handleLegacy(mux, "/api/projects/", "/api/v1/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/"), "/")
+		projectID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			response.BadRequest(w, "invalid project id")
+			return
+		}
+
+		switch {
+		case len(parts) == 1:
+			switch r.Method {
+			case http.MethodGet:
+				router.projectHandler.GetProject(w, r, projectID)
+			case http.MethodPut:
+				router.projectHandler.UpdateProject(w, r, projectID)
+			default:
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			}
+		case len(parts) == 2 && parts[1] == "members":
+			switch r.Method {
+			case http.MethodGet:
+				router.projectHandler.ListMembers(w, r, projectID)
+			case http.MethodPost:
+				router.projectHandler.AddMember(w, r, projectID)
+			case http.MethodDelete:
+				userID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+				if err != nil {
+					response.BadRequest(w, "invalid user_id")
+					return
+				}
+				router.projectHandler.RemoveMember(w, r, projectID, userID)
+			default:
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			}
+		case len(parts) == 2 && parts[1] == "secrets":
+			switch r.Method {
+			case http.MethodGet:
+				router.projectHandler.ListSecrets(w, r, projectID)
+			case http.MethodPut:
+				router.projectHandler.SetSecret(w, r, projectID)
+			case http.MethodDelete:
+				router.projectHandler.DeleteSecret(w, r, projectID, r.URL.Query().Get("name"))
+			default:
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// 当前认证主体
+	handleLegacy(mux, "/api/auth/me", "/api/v1/auth/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		WhoAmI(w, r)
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// parseListQuery 解析列表接口的通用查询参数：limit、offset、sort、cursor、name 与创建、更新时间范围
+func parseListQuery(q url.Values) (models.ListQuery, error) {
+	var lq models.ListQuery
+	var err error
+	if lq.Limit, err = intParam(q, "limit"); err != nil {
+		return lq, err
+	}
+	if lq.Offset, err = intParam(q, "offset"); err != nil {
+		return lq, err
+	}
+	lq.Sort = q.Get("sort")
+	lq.Cursor = q.Get("cursor")
+	lq.Name = strings.TrimSpace(q.Get("name"))
+
+	for name, dst := range map[string]*time.Time{
+		"created_after":  &lq.CreatedAfter,
+		"created_before": &lq.CreatedBefore,
+		"updated_after":  &lq.UpdatedAfter,
+		"updated_before": &lq.UpdatedBefore,
+	} {
+		if *dst, err = timeParam(q, name); err != nil {
+			return lq, err
+		}
+	}
+	return lq, nil
+}
+
+// parseTaskFilter 解析任务列表查询条件
+func parseTaskFilter(q url.Values) (models.TaskFilter, error) {
+	var f models.TaskFilter
+	var err error
+	if f.ListQuery, err = parseListQuery(q); err != nil {
+		return f, err
+	}
+	f.TaskType = models.TaskType(q.Get("task_type"))
+	f.IsActive, err = activeParam(q, nil)
+	return f, err
+}
+
+// parseFlowFilter 解析流程列表查询条件，未指定 is_active 时只返回启用的流程
+func parseFlowFilter(q url.Values) (models.FlowFilter, error) {
+	var f models.FlowFilter
+	var err error
+	if f.ListQuery, err = parseListQuery(q); err != nil {
+		return f, err
+	}
+	if f.CreatedBy, err = int64Param(q, "created_by"); err != nil {
+		return f, err
+	}
+	active := true
+	f.IsActive, err = activeParam(q, &active)
+	return f, err
+}
+
+// parseJobFilter 解析作业列表查询条件，status 可为逗号分隔的多个状态
+func parseJobFilter(q url.Values) (models.JobFilter, error) {
+	var f models.JobFilter
+	var err error
+	if f.ListQuery, err = parseListQuery(q); err != nil {
+		return f, err
+	}
+	if f.FlowID, err = int64Param(q, "flow_id"); err != nil {
+		return f, err
+	}
+	if f.BatchID, err = int64Param(q, "batch_id"); err != nil {
+		return f, err
+	}
+	if f.RerunOf, err = int64Param(q, "rerun_of"); err != nil {
+		return f, err
+	}
+	if f.CreatedBy, err = int64Param(q, "created_by"); err != nil {
+		return f, err
+	}
+	for _, s := range strings.Split(q.Get("status"), ",") {
+		switch status := models.JobStatus(strings.TrimSpace(s)); status {
+		case "":
+		case models.JobStatusPending, models.JobStatusRunning, models.JobStatusCompleted,
+			models.JobStatusFailed, models.JobStatusCancelled:
+			f.Statuses = append(f.Statuses, status)
+		default:
+			return f, fmt.Errorf("invalid status %q", status)
+		}
+	}
+	return f, nil
+}
+
+func intParam(q url.Values, name string) (int, error) {
+	s := q.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+func int64Param(q url.Values, name string) (int64, error) {
+	s := q.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+// timeParam 解析 RFC 3339 时间或日期（2006-01-02，按 UTC 零点）
+func timeParam(q url.Values, name string) (time.Time, error) {
+	s := q.Get(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q, expected RFC 3339 time or YYYY-MM-DD", name, s)
+}
+
+// activeParam 解析 is_active：true、false，或 all 表示不限；未指定时返回 def
+func activeParam(q url.Values, def *bool) (*bool, error) {
+	s := q.Get("is_active")
+	switch s {
+	case "":
+		return def, nil
+	case "all":
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid is_active %q, expected true, false or all", s)
+	}
+	return &v, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// LogLevelHandler 日志级别处理器
+type LogLevelHandler struct {
+	authz *service.Authorizer
+}
+
+// NewLogLevelHandler 创建日志级别处理器
+func NewLogLevelHandler(authz *service.Authorizer) *LogLevelHandler {
+	return &LogLevelHandler{authz: authz}
+}
+
+// GetLogLevel 获取当前日志级别
+// GET /api/log-level
+func (h *LogLevelHandler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, map[string]string{"level": logger.GetLevel()})
+}
+
+// LogLevelRequest 调整日志级别请求
+type LogLevelRequest struct {
+	Level string `json:"level"` // debug、info、warn 或 error
+}
+
+// SetLogLevel 运行时调整日志级别；debug 级别会记录请求、执行器与 SQL 细节，须拥有全局项目管理权限
+// PUT /api/log-level
+func (h *LogLevelHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	if err := h.authz.Check(r.Context(), service.PermProjectAdmin, 0); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := logger.SetLevel(req.Level); err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	logger.Ctx(r.Context()).Infof("Log level set to %s", logger.GetLevel())
+	response.Success(w, map[string]string{"level": logger.GetLevel()})
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// RequestIDHeader 请求 ID 请求头，未提供时自动生成并在响应中返回
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger 为每个请求分配请求 ID 并写入上下文日志字段，同时以 debug 级别记录访问日志（查询参数中的 access_token 不记录）
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := logger.WithFields(r.Context(), "request_id", requestID)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		fields := []interface{}{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+		}
+		if query := auth.StripAccessToken(r.URL).RawQuery; query != "" {
+			fields = append(fields, "query", query)
+		}
+		logger.Ctx(ctx).With(fields...).Debugf("%s %s %d", r.Method, r.URL.Path, rec.status)
+	})
+}
+
+// ProjectHeader 选择项目的请求头，值为项目ID或标识；也可使用 project_id 查询参数
+const ProjectHeader = "X-Project-ID"
+
+// projectScopedPrefixes 按项目隔离的接口路径前缀
+var projectScopedPrefixes = []string{
+	"/api/tasks", "/api/flows", "/api/jobs", "/api/job-tasks/",
+	"/api/v1/tasks", "/api/v1/flows", "/api/v1/jobs", "/api/v1/job-batches",
+	"/api/events", "/api/webhooks", "/api/webhook-deliveries/",
+	"/api/v1/events", "/api/v1/webhooks", "/api/v1/webhook-deliveries",
+	"/api/v1/retention-policies",
+}
+
+// ProjectScope 解析请求所选项目（缺省为默认项目）并校验访问权限，将项目写入上下文供服务层隔离数据
+func ProjectScope(projects *service.ProjectService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isProjectScoped(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ref := r.Header.Get(ProjectHeader)
+		if ref == "" {
+			ref = r.URL.Query().Get("project_id")
+		}
+		project, err := projects.Resolve(ref)
+		if err != nil {
+			response.NotFound(w, "project not found")
+			return
+		}
+		if err := projects.Authorize(r.Context(), project.ID); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		ctx := service.WithProjectID(r.Context(), project.ID)
+		ctx = logger.WithFields(ctx, "project_id", project.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func isProjectScoped(path string) bool {
+	for _, prefix := range projectScopedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Recovery 捕获处理器 panic，记录堆栈并返回 500，避免单个请求拖垮服务
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logger.Ctx(r.Context()).With("stack", string(debug.Stack())).
+				Errorf("panic serving %s %s: %v", r.Method, r.URL.Path, v)
+			response.InternalServerError(w, "internal server error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowHeaders 跨域请求允许携带的请求头
+const corsAllowHeaders = "Authorization, Content-Type, X-API-Key, " + ProjectHeader + ", " + RequestIDHeader
+
+// CORS 为允许的来源返回跨域响应头并直接应答预检请求；origins 为空时不启用，"*" 表示允许任意来源
+func CORS(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return next
+	}
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[strings.TrimRight(o, "/")] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !(allowed["*"] || allowed[origin]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Expose-Headers", RequestIDHeader+", Deprecation, Link")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MaxBodySize 限制请求体大小，超出时读取请求体返回错误（处理器据此返回 400）；limit <= 0 表示不限制
+func MaxBodySize(limit int64, next http.Handler) http.Handler {
+	if limit <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// statusRecorder 记录响应状态码，并保留 Flush 以支持 SSE
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
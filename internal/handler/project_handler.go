@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// ProjectHandler 项目处理器
+type ProjectHandler struct {
+	service *service.ProjectService
+}
+
+// NewProjectHandler 创建项目处理器
+func NewProjectHandler(service *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+// ProjectRequest 创建/更新项目请求
+type ProjectRequest struct {
+	Name             string   `json:"name"`
+	Slug             string   `json:"slug"`
+	Description      string   `json:"description"`
+	AllowedExecutors []string `json:"allowed_executors"`
+}
+
+// CreateProject 创建项目，创建人成为项目 owner
+// POST /api/projects
+func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	var req ProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	project := &models.Project{
+		Name:             req.Name,
+		Slug:             req.Slug,
+		Description:      req.Description,
+		AllowedExecutors: req.AllowedExecutors,
+	}
+	if err := h.service.Create(r.Context(), project); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, project)
+}
+
+// ListProjects 获取当前用户可访问的项目
+// GET /api/projects
+func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	projects, err := h.service.List(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, projects)
+}
+
+// GetProject 获取项目
+// GET /api/projects/{id}
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request, projectID int64) {
+	project, err := h.service.Get(r.Context(), projectID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, project)
+}
+
+// UpdateProject 更新项目名称、描述与执行器白名单
+// PUT /api/projects/{id}
+func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request, projectID int64) {
+	var req ProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	project := &models.Project{
+		ID:               projectID,
+		Name:             req.Name,
+		Description:      req.Description,
+		AllowedExecutors: req.AllowedExecutors,
+	}
+	if err := h.service.Update(r.Context(), project); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, project)
+}
+
+// ListMembers 获取项目成员
+// GET /api/projects/{id}/members
+func (h *ProjectHandler) ListMembers(w http.ResponseWriter, r *http.Request, projectID int64) {
+	members, err := h.service.ListMembers(r.Context(), projectID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, members)
+}
+
+// AddMember 添加成员或修改成员角色
+// POST /api/projects/{id}/members
+func (h *ProjectHandler) AddMember(w http.ResponseWriter, r *http.Request, projectID int64) {
+	var member models.ProjectMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	member.ProjectID = projectID
+
+	if err := h.service.AddMember(r.Context(), &member); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, member)
+}
+
+// RemoveMember 移除成员
+// DELETE /api/v1/projects/{id}/members/{userId}（旧接口 DELETE /api/projects/{id}/members?user_id=）
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request, projectID, userID int64) {
+	if err := h.service.RemoveMember(r.Context(), projectID, userID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "project member removed successfully"})
+}
+
+// ListSecrets 获取项目密钥名称
+// GET /api/projects/{id}/secrets
+func (h *ProjectHandler) ListSecrets(w http.ResponseWriter, r *http.Request, projectID int64) {
+	secrets, err := h.service.ListSecrets(r.Context(), projectID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, secrets)
+}
+
+// SetSecretRequest 写入密钥请求
+type SetSecretRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SetSecret 写入项目密钥，同名时覆盖
+// PUT /api/projects/{id}/secrets
+func (h *ProjectHandler) SetSecret(w http.ResponseWriter, r *http.Request, projectID int64) {
+	var req SetSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	secret := &models.ProjectSecret{ProjectID: projectID, Name: req.Name, Value: req.Value}
+	if err := h.service.SetSecret(r.Context(), secret); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "project secret saved successfully"})
+}
+
+// DeleteSecret 删除项目密钥
+// DELETE /api/v1/projects/{id}/secrets/{name}（旧接口 DELETE /api/projects/{id}/secrets?name=）
+func (h *ProjectHandler) DeleteSecret(w http.ResponseWriter, r *http.Request, projectID int64, name string) {
+	if err := h.service.DeleteSecret(r.Context(), projectID, name); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "project secret deleted successfully"})
+}
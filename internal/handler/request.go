@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// decodeOptionalJSON 解析 JSON 请求体，允许请求体为空（v1 动作接口的 ID 位于路径中，请求体可省略）
+func decodeOptionalJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// pathOrBodyID 优先使用路径中的 ID；旧接口传 0，沿用请求体中的 ID
+func pathOrBodyID(pathID, bodyID int64) int64 {
+	if pathID != 0 {
+		return pathID
+	}
+	return bodyID
+}
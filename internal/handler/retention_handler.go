@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// RetentionHandler 作业保留策略与清理处理器
+type RetentionHandler struct {
+	service *service.RetentionService
+}
+
+// NewRetentionHandler 创建作业保留处理器
+func NewRetentionHandler(service *service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{service: service}
+}
+
+// RetentionPolicyRequest 设置保留策略请求，按 flow_id 覆盖已有策略
+type RetentionPolicyRequest struct {
+	FlowID         *int64                 `json:"flow_id"`          // 为空表示默认策略
+	KeepDays       int                    `json:"keep_days"`        // 已完成、已取消作业保留天数，0 表示不按时间清理
+	KeepJobs       int                    `json:"keep_jobs"`        // 保留最近的已完成、已取消作业数，0 表示不按数量清理
+	FailedKeepDays int                    `json:"failed_keep_days"` // 失败作业保留天数，0 表示沿用 keep_days
+	Action         models.RetentionAction `json:"action"`           // delete（默认）、archive_file 或 archive_table
+	IsActive       *bool                  `json:"is_active"`        // 默认启用
+}
+
+// RetentionRunRequest 手动清理请求
+type RetentionRunRequest struct {
+	DryRun bool `json:"dry_run"` // 只统计将被清理的作业
+}
+
+// ListRetentionPolicies 获取全部保留策略
+// GET /api/v1/retention-policies
+func (h *RetentionHandler) ListRetentionPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.service.ListPolicies(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, policies)
+}
+
+// SetRetentionPolicy 设置流程或默认保留策略
+// PUT /api/v1/retention-policies
+func (h *RetentionHandler) SetRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	var req RetentionPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	policy := &models.RetentionPolicy{
+		KeepDays:       req.KeepDays,
+		KeepJobs:       req.KeepJobs,
+		FailedKeepDays: req.FailedKeepDays,
+		Action:         req.Action,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+	}
+	if req.FlowID != nil && *req.FlowID != 0 {
+		policy.FlowID = sql.NullInt64{Int64: *req.FlowID, Valid: true}
+	}
+	if err := h.service.SetPolicy(r.Context(), policy); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, policy)
+}
+
+// DeleteRetentionPolicy 删除保留策略
+// DELETE /api/v1/retention-policies/{id}
+func (h *RetentionHandler) DeleteRetentionPolicy(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, err := h.service.GetPolicy(r.Context(), id); err != nil {
+		response.NotFound(w, "retention policy not found")
+		return
+	}
+	if err := h.service.DeletePolicy(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "retention policy deleted successfully"})
+}
+
+// RunRetention 立即按当前策略清理一次并返回清理结果
+// POST /api/v1/retention-runs
+func (h *RetentionHandler) RunRetention(w http.ResponseWriter, r *http.Request) {
+	var req RetentionRunRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	// 客户端断开时继续完成清理，结果可通过清理记录查看；清理中断时返回已完成部分，错误见 error 字段
+	run, err := h.service.Run(context.WithoutCancel(r.Context()), req.DryRun)
+	if run == nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, run)
+}
+
+// ListRetentionRuns 获取清理记录
+// GET /api/v1/retention-runs
+func (h *RetentionHandler) ListRetentionRuns(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	runs, err := h.service.ListRuns(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, runs)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// RoleBindingHandler 角色绑定处理器
+type RoleBindingHandler struct {
+	authz *service.Authorizer
+}
+
+// NewRoleBindingHandler 创建角色绑定处理器
+func NewRoleBindingHandler(authz *service.Authorizer) *RoleBindingHandler {
+	return &RoleBindingHandler{authz: authz}
+}
+
+// CreateRoleBindingRequest 授予角色请求
+type CreateRoleBindingRequest struct {
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role"`
+	FlowID int64  `json:"flow_id"` // 为 0 表示全局角色
+}
+
+// CreateRoleBinding 授予角色
+// POST /api/role-bindings
+func (h *RoleBindingHandler) CreateRoleBinding(w http.ResponseWriter, r *http.Request) {
+	var req CreateRoleBindingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	binding := &models.RoleBinding{UserID: req.UserID, Role: req.Role, FlowID: req.FlowID}
+	if err := h.authz.Grant(r.Context(), binding); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, binding)
+}
+
+// ListRoleBindings 按用户或流程查询角色绑定
+// GET /api/role-bindings?user_id=&flow_id=
+func (h *RoleBindingHandler) ListRoleBindings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	flowID, _ := strconv.ParseInt(query.Get("flow_id"), 10, 64)
+
+	var (
+		bindings []models.RoleBinding
+		err      error
+	)
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		userID, parseErr := strconv.ParseInt(userIDStr, 10, 64)
+		if parseErr != nil {
+			response.BadRequest(w, "invalid user_id")
+			return
+		}
+		bindings, err = h.authz.ListByUser(r.Context(), userID, flowID)
+	} else {
+		bindings, err = h.authz.ListByFlow(r.Context(), flowID)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, bindings)
+}
+
+// DeleteRoleBinding 撤销角色
+// DELETE /api/v1/role-bindings/{id}（旧接口 DELETE /api/role-bindings?id=）
+func (h *RoleBindingHandler) DeleteRoleBinding(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := h.authz.Revoke(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "role binding deleted successfully"})
+}
+
+// ListRoles 列出内置角色及其权限
+// GET /api/roles
+func ListRoles(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, service.Roles())
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"net/http"
 
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
 	"github.com/cfrs2005/GoWorkFlow/internal/repository"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
 )
 
 // Router 路由器
@@ -12,8 +14,18 @@ type Router struct {
 	taskHandler         *TaskHandler
 	flowHandler         *FlowHandler
 	jobHandler          *JobHandler
+	jobBatchHandler     *JobBatchHandler
 	jobContextHandler   *JobContextHandler
 	executorHandler     *ExecutorHandler
+	artifactHandler     *ArtifactHandler
+	executionLogHandler *ExecutionLogHandler
+	eventHandler        *EventHandler
+	webhookHandler      *WebhookHandler
+	apiKeyHandler       *APIKeyHandler
+	roleBindingHandler  *RoleBindingHandler
+	projectHandler      *ProjectHandler
+	retentionHandler    *RetentionHandler
+	logLevelHandler     *LogLevelHandler
 }
 
 // NewRouter 创建路由器
@@ -21,167 +33,45 @@ func NewRouter(
 	service service.WorkflowService,
 	jobContextRepo repository.JobContextRepository,
 	taskExecutorService *service.TaskExecutorService,
+	artifactService *service.ArtifactService,
+	executionLogService *service.ExecutionLogService,
+	eventBus *events.Bus,
+	webhookService *service.WebhookService,
+	apiKeyService *service.APIKeyService,
+	authorizer *service.Authorizer,
+	projectService *service.ProjectService,
+	retentionService *service.RetentionService,
 ) *Router {
 	return &Router{
-		taskHandler:       NewTaskHandler(service),
-		flowHandler:       NewFlowHandler(service),
-		jobHandler:        NewJobHandler(service),
-		jobContextHandler: NewJobContextHandler(jobContextRepo),
-		executorHandler:   NewExecutorHandler(taskExecutorService),
+		taskHandler:         NewTaskHandler(service),
+		flowHandler:         NewFlowHandler(service),
+		jobHandler:          NewJobHandler(service),
+		jobBatchHandler:     NewJobBatchHandler(service, taskExecutorService),
+		jobContextHandler:   NewJobContextHandler(jobContextRepo),
+		executorHandler:     NewExecutorHandler(taskExecutorService),
+		artifactHandler:     NewArtifactHandler(artifactService),
+		executionLogHandler: NewExecutionLogHandler(executionLogService),
+		eventHandler:        NewEventHandler(eventBus),
+		webhookHandler:      NewWebhookHandler(webhookService),
+		apiKeyHandler:       NewAPIKeyHandler(apiKeyService),
+		roleBindingHandler:  NewRoleBindingHandler(authorizer),
+		projectHandler:      NewProjectHandler(projectService),
+		retentionHandler:    NewRetentionHandler(retentionService),
+		logLevelHandler:     NewLogLevelHandler(authorizer),
 	}
 }
 
-// Setup 设置路由
+// OpenAPIPath v1 接口的 OpenAPI 文档地址，无需认证
+const OpenAPIPath = "/api/openapi.json"
+
+// Setup 设置路由：/api/v1 为资源化接口，其余 /api 路由保留为已弃用的旧接口
 func (router *Router) Setup() *http.ServeMux {
 	mux := http.NewServeMux()
 
-	// Task 路由
-	mux.HandleFunc("/api/tasks", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			router.taskHandler.CreateTask(w, r)
-		case http.MethodGet:
-			if r.URL.Query().Get("id") != "" {
-				router.taskHandler.GetTask(w, r)
-			} else {
-				router.taskHandler.ListTasks(w, r)
-			}
-		case http.MethodPut:
-			router.taskHandler.UpdateTask(w, r)
-		case http.MethodDelete:
-			router.taskHandler.DeleteTask(w, r)
-		default:
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Flow 路由
-	mux.HandleFunc("/api/flows", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			router.flowHandler.CreateFlow(w, r)
-		case http.MethodGet:
-			if r.URL.Query().Get("id") != "" {
-				router.flowHandler.GetFlow(w, r)
-			} else {
-				router.flowHandler.ListFlows(w, r)
-			}
-		case http.MethodPut:
-			router.flowHandler.UpdateFlow(w, r)
-		case http.MethodDelete:
-			router.flowHandler.DeleteFlow(w, r)
-		default:
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Job 路由
-	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			router.jobHandler.CreateJob(w, r)
-		case http.MethodGet:
-			if r.URL.Query().Get("id") != "" {
-				router.jobHandler.GetJob(w, r)
-			} else {
-				router.jobHandler.ListJobs(w, r)
-			}
-		default:
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	mux.HandleFunc("/api/jobs/start", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.StartJob(w, r)
-	})
-
-	// 自动执行路由（新增）
-	mux.HandleFunc("/api/jobs/auto-execute", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.executorHandler.AutoExecuteJob(w, r)
-	})
-
-	mux.HandleFunc("/api/jobs/next-task", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.GetNextTask(w, r)
-	})
-
-	// Job Context 路由
-	mux.HandleFunc("/api/jobs/", func(w http.ResponseWriter, r *http.Request) {
-		// 匹配 /api/jobs/{id}/context
-		if len(r.URL.Path) > len("/api/jobs/") && r.URL.Path[len(r.URL.Path)-8:] == "/context" {
-			switch r.Method {
-			case http.MethodGet:
-				router.jobContextHandler.GetJobContext(w, r)
-			case http.MethodPut:
-				router.jobContextHandler.UpdateJobContext(w, r)
-			default:
-				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			}
-		} else {
-			http.NotFound(w, r)
-		}
-	})
-
-	// JobTask 路由
-	mux.HandleFunc("/api/tasks/start", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.StartTask(w, r)
-	})
-
-	mux.HandleFunc("/api/tasks/complete", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.CompleteTask(w, r)
-	})
-
-	mux.HandleFunc("/api/tasks/fail", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.FailTask(w, r)
-	})
-
-	mux.HandleFunc("/api/tasks/skip", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.SkipTask(w, r)
-	})
-
-	mux.HandleFunc("/api/tasks/rollback", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.jobHandler.RollbackTask(w, r)
-	})
-
-	// 任务执行路由（新增）
-	mux.HandleFunc("/api/tasks/execute", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		router.executorHandler.ExecuteTask(w, r)
-	})
+	v1, spec := router.v1()
+	mux.Handle("/api/v1/", v1)
+	mux.Handle(OpenAPIPath, spec)
+	router.setupLegacy(mux)
 
 	// 健康检查
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -199,3 +89,21 @@ func (router *Router) Setup() *http.ServeMux {
 
 	return mux
 }
+
+// jobInProject 校验作业属于当前项目，否则返回 404
+func (router *Router) jobInProject(w http.ResponseWriter, r *http.Request, jobID int64) bool {
+	if _, err := router.jobHandler.service.WithContext(r.Context()).GetJob(jobID); err != nil {
+		response.NotFound(w, "job not found")
+		return false
+	}
+	return true
+}
+
+// jobTaskInProject 校验作业任务属于当前项目，否则返回 404
+func (router *Router) jobTaskInProject(w http.ResponseWriter, r *http.Request, jobTaskID int64) bool {
+	if _, err := router.jobHandler.service.WithContext(r.Context()).GetJobTask(jobTaskID); err != nil {
+		response.NotFound(w, "job task not found")
+		return false
+	}
+	return true
+}
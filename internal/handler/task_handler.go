@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/service"
@@ -29,8 +28,8 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	task.IsActive = true
-	if err := h.service.CreateTask(&task); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).CreateTask(&task); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -38,14 +37,8 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetTask 获取任务
-func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
-	if err != nil {
-		response.BadRequest(w, "invalid task id")
-		return
-	}
-
-	task, err := h.service.GetTask(id)
+func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request, id int64) {
+	task, err := h.service.WithContext(r.Context()).GetTask(id)
 	if err != nil {
 		response.NotFound(w, "task not found")
 		return
@@ -56,32 +49,32 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
 
 // ListTasks 获取任务列表
 func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 {
-		limit = 20
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	tasks, err := h.service.ListTasks(limit, offset)
+	tasks, page, err := h.service.WithContext(r.Context()).ListTasks(filter)
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		writeServiceError(w, err)
 		return
 	}
 
-	response.Success(w, tasks)
+	response.Paged(w, tasks, page)
 }
 
-// UpdateTask 更新任务
-func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+// UpdateTask 更新任务，id 为 0 时取请求体中的 id
+func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request, id int64) {
 	var task models.Task
 	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
+	task.ID = pathOrBodyID(id, task.ID)
 
-	if err := h.service.UpdateTask(&task); err != nil {
-		response.InternalServerError(w, err.Error())
+	if err := h.service.WithContext(r.Context()).UpdateTask(&task); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -89,15 +82,9 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteTask 删除任务
-func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
-	if err != nil {
-		response.BadRequest(w, "invalid task id")
-		return
-	}
-
-	if err := h.service.DeleteTask(id); err != nil {
-		response.InternalServerError(w, err.Error())
+func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := h.service.WithContext(r.Context()).DeleteTask(id); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/httprouter"
+	"github.com/cfrs2005/GoWorkFlow/pkg/openapi"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// APIV1Prefix v1 接口路径前缀
+const APIV1Prefix = "/api/v1"
+
+// idHandler 以路径中的资源 ID 为参数的处理函数
+type idHandler func(http.ResponseWriter, *http.Request, int64)
+
+// withID 解析路径参数 {name} 为整数 ID 后调用处理函数
+func withID(name, label string, handle idHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := httprouter.Int64Param(r, name)
+		if err != nil {
+			response.BadRequest(w, "invalid "+label+" id")
+			return
+		}
+		handle(w, r, id)
+	}
+}
+
+// withJob 解析 {id} 并校验作业属于当前项目
+func (router *Router) withJob(handle idHandler) http.HandlerFunc {
+	return withID("id", "job", func(w http.ResponseWriter, r *http.Request, jobID int64) {
+		if router.jobInProject(w, r, jobID) {
+			handle(w, r, jobID)
+		}
+	})
+}
+
+// withJobTask 解析 {id}/{taskId} 并校验作业任务属于该作业与当前项目，处理函数收到作业任务 ID
+func (router *Router) withJobTask(handle idHandler) http.HandlerFunc {
+	return withID("id", "job", func(w http.ResponseWriter, r *http.Request, jobID int64) {
+		withID("taskId", "job task", func(w http.ResponseWriter, r *http.Request, jobTaskID int64) {
+			jobTask, err := router.jobHandler.service.WithContext(r.Context()).GetJobTask(jobTaskID)
+			if err != nil || jobTask.JobID != jobID {
+				response.NotFound(w, "job task not found")
+				return
+			}
+			handle(w, r, jobTaskID)
+		})(w, r)
+	})
+}
+
+// 常用查询参数
+var (
+	pageParams = []openapi.Parameter{
+		openapi.QueryInt("limit", "每页数量，默认 20"),
+		openapi.QueryInt("offset", "偏移量"),
+	}
+	contextScopeParam = openapi.QueryString("scope", "上下文作用域：shared 或 task:<任务名称>")
+)
+
+// listParams 任务、流程与作业列表的查询参数：分页、排序、游标、名称搜索与时间范围，extra 为资源特有的过滤条件
+func listParams(sortFields string, extra ...openapi.Parameter) []openapi.Parameter {
+	params := append([]openapi.Parameter{}, pageParams...)
+	params = append(params,
+		openapi.QueryString("sort", "排序字段（"+sortFields+"），前缀 - 表示降序，默认 -id"),
+		openapi.QueryString("cursor", "上一页返回的 page.next_cursor，按键集分页，指定时忽略 offset"),
+		openapi.QueryString("name", "名称包含"),
+		openapi.QueryString("created_after", "创建时间不早于（RFC 3339 或 YYYY-MM-DD）"),
+		openapi.QueryString("created_before", "创建时间早于"),
+		openapi.QueryString("updated_after", "更新时间不早于"),
+		openapi.QueryString("updated_before", "更新时间早于"),
+	)
+	return append(params, extra...)
+}
+
+// message 只返回 message 的响应 data
+var message = map[string]string{}
+
+// v1 资源化接口：路径参数标识资源，非 CRUD 操作使用 {id}:action 形式；同时生成 OpenAPI 文档，保证文档与路由一致
+func (router *Router) v1() (*httprouter.Router, *openapi.Document) {
+	rt := httprouter.New()
+	doc := openapi.New(APIV1Prefix, "GoWorkFlow API", "v1", "工作流任务管理系统 API。响应统一包装为 {code, message, data}。")
+	projectParam := openapi.Parameter{Name: ProjectHeader, In: "header", Description: "项目 ID 或标识，缺省为默认项目", Schema: &openapi.Schema{Type: "string"}}
+	v := func(method, pattern string, handler http.HandlerFunc, op openapi.Op) {
+		rt.HandleFunc(method, APIV1Prefix+pattern, handler)
+		if isProjectScoped(APIV1Prefix + pattern) {
+			op.Query = append(op.Query, projectParam)
+		}
+		doc.Add(method, pattern, op)
+	}
+	action := func(tag, summary string, body interface{}) openapi.Op {
+		return openapi.Op{Tag: tag, Summary: summary, Body: body, BodyOptional: true, Result: message}
+	}
+
+	// 任务定义
+	v(http.MethodGet, "/tasks", router.taskHandler.ListTasks,
+		openapi.Op{Tag: "tasks", Summary: "获取任务列表", Result: []models.Task{}, Page: models.Page{},
+			Query: listParams("id, name, created_at, updated_at",
+				openapi.QueryString("task_type", "任务类型：manual、automated 或 approval"),
+				openapi.QueryString("is_active", "true、false 或 all，默认不限"),
+			)})
+	v(http.MethodPost, "/tasks", router.taskHandler.CreateTask,
+		openapi.Op{Tag: "tasks", Summary: "创建任务", Body: models.Task{}, Result: models.Task{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/tasks/{id}", withID("id", "task", router.taskHandler.GetTask),
+		openapi.Op{Tag: "tasks", Summary: "获取任务", Result: models.Task{}})
+	v(http.MethodPut, "/tasks/{id}", withID("id", "task", router.taskHandler.UpdateTask),
+		openapi.Op{Tag: "tasks", Summary: "更新任务", Body: models.Task{}, Result: models.Task{}})
+	v(http.MethodDelete, "/tasks/{id}", withID("id", "task", router.taskHandler.DeleteTask),
+		openapi.Op{Tag: "tasks", Summary: "删除任务", Result: message})
+
+	// 流程
+	v(http.MethodGet, "/flows", router.flowHandler.ListFlows,
+		openapi.Op{Tag: "flows", Summary: "获取流程列表", Result: []models.Flow{}, Page: models.Page{},
+			Query: listParams("id, name, created_at, updated_at",
+				openapi.QueryInt("created_by", "创建人 ID"),
+				openapi.QueryString("is_active", "true、false 或 all，默认只返回启用的流程"),
+			)})
+	v(http.MethodPost, "/flows", router.flowHandler.CreateFlow,
+		openapi.Op{Tag: "flows", Summary: "创建流程", Description: "tasks 与 task_ids 二选一，tasks 可指定流程级配置覆盖",
+			Body: CreateFlowRequest{}, Result: models.Flow{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/flows/{id}", withID("id", "flow", router.flowHandler.GetFlow),
+		openapi.Op{Tag: "flows", Summary: "获取流程详情（包含任务编排）", Result: FlowDetail{}})
+	v(http.MethodPut, "/flows/{id}", withID("id", "flow", router.flowHandler.UpdateFlow),
+		openapi.Op{Tag: "flows", Summary: "更新流程", Body: models.Flow{}, Result: models.Flow{}})
+	v(http.MethodDelete, "/flows/{id}", withID("id", "flow", router.flowHandler.DeleteFlow),
+		openapi.Op{Tag: "flows", Summary: "删除流程", Result: message})
+	v(http.MethodGet, "/flows/{id}/tasks", withID("id", "flow", router.flowHandler.ListFlowTasks),
+		openapi.Op{Tag: "flows", Summary: "获取流程的任务编排", Result: []models.FlowTask{}})
+
+	// 作业
+	v(http.MethodGet, "/jobs", router.jobHandler.ListJobs,
+		openapi.Op{Tag: "jobs", Summary: "获取作业列表", Result: []models.Job{}, Page: models.Page{},
+			Query: listParams("id, name, status, created_at, updated_at",
+				openapi.QueryString("status", "作业状态，逗号分隔表示任一状态"),
+				openapi.QueryInt("flow_id", "流程 ID"),
+				openapi.QueryInt("batch_id", "批次 ID"),
+				openapi.QueryInt("rerun_of", "重新运行的来源作业 ID"),
+				openapi.QueryInt("created_by", "创建人 ID"),
+			)})
+	v(http.MethodPost, "/jobs", router.jobHandler.CreateJob,
+		openapi.Op{Tag: "jobs", Summary: "创建作业", Body: CreateJobRequest{}, Result: models.Job{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/jobs/{id}", withID("id", "job", router.jobHandler.GetJob),
+		openapi.Op{Tag: "jobs", Summary: "获取作业详情（包含所有作业任务）", Result: JobDetail{}})
+	v(http.MethodPost, "/jobs/{id}:start", router.withJob(router.jobHandler.StartJob),
+		action("jobs", "启动作业", nil))
+	v(http.MethodPost, "/jobs/{id}:cancel", router.withJob(router.jobHandler.CancelJob),
+		action("jobs", "取消作业", CancelJobRequest{}))
+	v(http.MethodPost, "/jobs/{id}:rerun", router.withJob(router.jobHandler.RerunJob),
+		openapi.Op{Tag: "jobs", Summary: "重新运行作业",
+			Description: "以来源作业的输入创建同一流程（latest_version 为 true 时为同名流程的最新启用版本）的新作业，" +
+				"新作业的 rerun_of 为来源作业；inputs 覆盖同名输入。reuse_outputs 为 true 时从第一个任务起连续复用" +
+				"来源作业中已完成、定义与配置未变化且不读取被覆盖输入的自动化任务输出，最后一个任务总会重新执行",
+			Body: RerunJobRequest{}, BodyOptional: true, Result: models.JobRerun{}, Status: http.StatusCreated})
+	v(http.MethodPost, "/jobs/{id}:auto-execute", router.withJob(router.executorHandler.AutoExecuteJob),
+		openapi.Op{Tag: "jobs", Summary: "后台自动执行作业的所有任务", Result: map[string]interface{}{}})
+	v(http.MethodGet, "/jobs/{id}/next-task", router.withJob(router.jobHandler.GetNextTask),
+		openapi.Op{Tag: "jobs", Summary: "获取下一个待执行的任务", Description: "没有待执行任务时 data 为 {\"message\": \"no more tasks\"}", Result: models.JobTask{}})
+	v(http.MethodGet, "/jobs/{id}/context", router.withJob(router.jobContextHandler.GetJobContext),
+		openapi.Op{Tag: "jobs", Summary: "获取作业上下文", Description: "未指定 scope 时返回 {shared, tasks}",
+			Query: []openapi.Parameter{contextScopeParam}, Result: map[string]interface{}{}})
+	v(http.MethodPut, "/jobs/{id}/context", router.withJob(router.jobContextHandler.UpdateJobContext),
+		openapi.Op{Tag: "jobs", Summary: "更新作业上下文", Query: []openapi.Parameter{contextScopeParam},
+			Body: map[string]interface{}{}, Result: message})
+	v(http.MethodGet, "/jobs/{id}/logs", router.withJob(router.jobHandler.ListJobLogs),
+		openapi.Op{Tag: "jobs", Summary: "获取作业的审计日志", Result: []models.JobTaskLog{}})
+	v(http.MethodGet, "/jobs/{id}/artifacts", router.withJob(router.artifactHandler.ListArtifacts),
+		openapi.Op{Tag: "jobs", Summary: "获取作业的制品列表", Result: []models.Artifact{}})
+	v(http.MethodGet, "/jobs/{id}/artifacts/{artifactId}", router.withJob(func(w http.ResponseWriter, r *http.Request, jobID int64) {
+		router.artifactHandler.DownloadArtifact(w, r, jobID, httprouter.Param(r, "artifactId"))
+	}), openapi.Op{Tag: "jobs", Summary: "下载制品", Binary: true})
+
+	// 作业批次
+	v(http.MethodPost, "/job-batches", router.jobBatchHandler.CreateJobBatch,
+		openapi.Op{Tag: "job-batches", Summary: "按输入行批量创建作业",
+			Description: "请求体为 JSON 对象；或为输入行的 JSON 数组、CSV（Content-Type: text/csv，首行为列名），" +
+				"此时其余字段经查询参数传入。每行创建一个作业，job_name 列为作业名称，其余列为流程输入；" +
+				"所有作业在同一事务中创建，auto_execute 为 true 时按 concurrency 并发自动执行",
+			Query: []openapi.Parameter{
+				openapi.QueryInt("flow_id", "流程 ID（JSON 数组或 CSV 请求体）"),
+				openapi.QueryString("name", "批次名称"),
+				openapi.QueryString("auto_execute", "true 表示创建后自动执行"),
+				openapi.QueryInt("concurrency", "自动执行的最大并发作业数，默认 4"),
+			},
+			Body: CreateJobBatchRequest{}, Result: JobBatchDetail{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/job-batches/{id}", withID("id", "job batch", router.jobBatchHandler.GetJobBatch),
+		openapi.Op{Tag: "job-batches", Summary: "获取批次及其作业状态统计", Result: models.JobBatchSummary{}})
+	v(http.MethodGet, "/job-batches/{id}/jobs", withID("id", "job batch", router.jobBatchHandler.ListBatchJobs),
+		openapi.Op{Tag: "job-batches", Summary: "获取批次中的作业", Result: []models.Job{}, Page: models.Page{},
+			Query: listParams("id, name, status, created_at, updated_at",
+				openapi.QueryString("status", "作业状态，逗号分隔表示任一状态"),
+			)})
+	v(http.MethodPost, "/job-batches/{id}:cancel", withID("id", "job batch", router.jobBatchHandler.CancelJobBatch),
+		openapi.Op{Tag: "job-batches", Summary: "取消批次中所有待执行与执行中的作业",
+			Body: CancelJobBatchRequest{}, BodyOptional: true, Result: models.JobBatchResult{}})
+	v(http.MethodPost, "/job-batches/{id}:retry-failed", withID("id", "job batch", router.jobBatchHandler.RetryFailedJobBatch),
+		openapi.Op{Tag: "job-batches", Summary: "重试批次中所有失败的作业", Description: "auto_execute 默认沿用批次设置",
+			Body: RetryJobBatchRequest{}, BodyOptional: true, Result: models.JobBatchResult{}})
+
+	// 作业任务
+	v(http.MethodGet, "/jobs/{id}/tasks", router.withJob(router.jobHandler.ListJobTasks),
+		openapi.Op{Tag: "job-tasks", Summary: "获取作业的任务列表", Result: []models.JobTask{}})
+	v(http.MethodGet, "/jobs/{id}/tasks/{taskId}", router.withJobTask(router.jobHandler.GetJobTask),
+		openapi.Op{Tag: "job-tasks", Summary: "获取作业任务", Result: models.JobTask{}})
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:start", router.withJobTask(router.jobHandler.StartTask),
+		action("job-tasks", "开始执行任务", StartTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:complete", router.withJobTask(router.jobHandler.CompleteTask),
+		action("job-tasks", "完成任务", CompleteTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:fail", router.withJobTask(router.jobHandler.FailTask),
+		action("job-tasks", "标记任务失败", FailTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:skip", router.withJobTask(router.jobHandler.SkipTask),
+		action("job-tasks", "跳过任务", SkipTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:rollback", router.withJobTask(router.jobHandler.RollbackTask),
+		action("job-tasks", "打回任务", RollbackTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:retry", router.withJobTask(router.jobHandler.RetryTask),
+		action("job-tasks", "重试失败的任务", RetryTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:reassign", router.withJobTask(router.jobHandler.ReassignTask),
+		action("job-tasks", "转派任务", ReassignTaskRequest{}))
+	v(http.MethodPost, "/jobs/{id}/tasks/{taskId}:execute", router.withJobTask(router.executorHandler.ExecuteTask),
+		openapi.Op{Tag: "job-tasks", Summary: "后台执行单个任务", Result: map[string]interface{}{}})
+	v(http.MethodGet, "/jobs/{id}/tasks/{taskId}/logs", router.withJobTask(router.jobHandler.ListJobTaskLogs),
+		openapi.Op{Tag: "job-tasks", Summary: "获取作业任务的审计日志", Result: []models.JobTaskLog{}})
+	v(http.MethodGet, "/jobs/{id}/tasks/{taskId}/execution-logs", router.withJobTask(router.executionLogHandler.ListExecutionLogs),
+		openapi.Op{Tag: "job-tasks", Summary: "获取作业任务的执行日志", Result: []models.ExecutionLog{},
+			Query: []openapi.Parameter{openapi.QueryInt("after_id", "只返回该 ID 之后的日志"), openapi.QueryInt("limit", "返回条数")}})
+	v(http.MethodGet, "/jobs/{id}/tasks/{taskId}/execution-logs:stream", router.withJobTask(router.executionLogHandler.StreamExecutionLogs),
+		openapi.Op{Tag: "job-tasks", Summary: "实时跟踪执行日志", Stream: true,
+			Query: []openapi.Parameter{openapi.QueryInt("after_id", "从该 ID 之后开始推送")}})
+
+	// 事件与 Webhook
+	v(http.MethodGet, "/events", router.eventHandler.StreamEvents,
+		openapi.Op{Tag: "events", Summary: "订阅作业与任务事件", Stream: true, Query: []openapi.Parameter{
+			openapi.QueryInt("job_id", "按作业过滤"),
+			openapi.QueryInt("flow_id", "按流程过滤"),
+			openapi.QueryString("types", "按事件类型过滤，逗号分隔"),
+		}})
+	v(http.MethodGet, "/webhooks", router.webhookHandler.ListWebhooks,
+		openapi.Op{Tag: "webhooks", Summary: "获取 Webhook 订阅列表", Query: pageParams, Result: []models.WebhookSubscription{}})
+	v(http.MethodPost, "/webhooks", router.webhookHandler.CreateWebhook,
+		openapi.Op{Tag: "webhooks", Summary: "创建 Webhook 订阅", Body: webhookRequest{}, Result: models.WebhookSubscription{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/webhooks/{id}", withID("id", "webhook", router.webhookHandler.GetWebhook),
+		openapi.Op{Tag: "webhooks", Summary: "获取 Webhook 订阅", Result: models.WebhookSubscription{}})
+	v(http.MethodPut, "/webhooks/{id}", withID("id", "webhook", router.webhookHandler.UpdateWebhook),
+		openapi.Op{Tag: "webhooks", Summary: "更新 Webhook 订阅", Description: "未提供 secret 时保留原密钥",
+			Body: webhookRequest{}, Result: models.WebhookSubscription{}})
+	v(http.MethodDelete, "/webhooks/{id}", withID("id", "webhook", router.webhookHandler.DeleteWebhook),
+		openapi.Op{Tag: "webhooks", Summary: "删除 Webhook 订阅", Result: message})
+	v(http.MethodGet, "/webhooks/{id}/deliveries", withID("id", "webhook", router.webhookHandler.ListDeliveries),
+		openapi.Op{Tag: "webhooks", Summary: "获取订阅的投递记录", Query: pageParams, Result: []models.WebhookDelivery{}})
+	v(http.MethodGet, "/webhook-deliveries/{id}", withID("id", "delivery", router.webhookHandler.GetDelivery),
+		openapi.Op{Tag: "webhooks", Summary: "获取投递详情", Result: models.WebhookDelivery{}})
+	v(http.MethodPost, "/webhook-deliveries/{id}:redeliver", withID("id", "delivery", router.webhookHandler.Redeliver),
+		openapi.Op{Tag: "webhooks", Summary: "重新投递", Result: models.WebhookDelivery{}, Status: http.StatusCreated})
+
+	// 执行器与运维
+	v(http.MethodGet, "/executors", router.executorHandler.ListExecutors,
+		openapi.Op{Tag: "system", Summary: "列出执行器及其输入/输出契约", Result: []executor.Descriptor{}})
+	v(http.MethodGet, "/log-level", router.logLevelHandler.GetLogLevel,
+		openapi.Op{Tag: "system", Summary: "获取日志级别", Result: LogLevelRequest{}})
+	v(http.MethodPut, "/log-level", router.logLevelHandler.SetLogLevel,
+		openapi.Op{Tag: "system", Summary: "调整日志级别", Description: "须拥有全局 project.admin 权限", Body: LogLevelRequest{}, Result: LogLevelRequest{}})
+
+	// 作业保留与清理
+	v(http.MethodGet, "/retention-policies", router.retentionHandler.ListRetentionPolicies,
+		openapi.Op{Tag: "retention", Summary: "获取作业保留策略", Result: []models.RetentionPolicy{}})
+	v(http.MethodPut, "/retention-policies", router.retentionHandler.SetRetentionPolicy,
+		openapi.Op{Tag: "retention", Summary: "设置流程或默认保留策略", Description: "按 flow_id 覆盖已有策略，flow_id 为空时设置默认策略",
+			Body: RetentionPolicyRequest{}, Result: models.RetentionPolicy{}})
+	v(http.MethodDelete, "/retention-policies/{id}", withID("id", "retention policy", router.retentionHandler.DeleteRetentionPolicy),
+		openapi.Op{Tag: "retention", Summary: "删除保留策略", Result: message})
+	v(http.MethodGet, "/retention-runs", router.retentionHandler.ListRetentionRuns,
+		openapi.Op{Tag: "retention", Summary: "获取清理记录", Query: pageParams, Result: []models.RetentionRun{}})
+	v(http.MethodPost, "/retention-runs", router.retentionHandler.RunRetention,
+		openapi.Op{Tag: "retention", Summary: "立即按当前策略清理", Description: "dry_run 为 true 时只统计将被清理的作业",
+			Body: RetentionRunRequest{}, Result: models.RetentionRun{}, Status: http.StatusCreated})
+
+	// 认证与权限
+	v(http.MethodGet, "/auth/me", WhoAmI,
+		openapi.Op{Tag: "auth", Summary: "当前认证主体", Result: auth.Principal{}})
+	v(http.MethodGet, "/api-keys", router.apiKeyHandler.ListAPIKeys,
+		openapi.Op{Tag: "auth", Summary: "获取当前用户的 API Key", Result: []models.APIKey{}})
+	v(http.MethodPost, "/api-keys", router.apiKeyHandler.CreateAPIKey,
+		openapi.Op{Tag: "auth", Summary: "创建 API Key", Description: "响应中的 key 只返回一次",
+			Body: CreateAPIKeyRequest{}, Result: service.CreatedAPIKey{}, Status: http.StatusCreated})
+	v(http.MethodDelete, "/api-keys/{id}", withID("id", "api key", router.apiKeyHandler.RevokeAPIKey),
+		openapi.Op{Tag: "auth", Summary: "吊销 API Key", Result: message})
+	v(http.MethodGet, "/roles", ListRoles,
+		openapi.Op{Tag: "auth", Summary: "列出内置角色及其权限", Result: map[string][]service.Permission{}})
+	v(http.MethodGet, "/role-bindings", router.roleBindingHandler.ListRoleBindings,
+		openapi.Op{Tag: "auth", Summary: "按用户或流程查询角色绑定", Result: []models.RoleBinding{}, Query: []openapi.Parameter{
+			openapi.QueryInt("user_id", "用户 ID"),
+			openapi.QueryInt("flow_id", "流程 ID，0 表示全局"),
+		}})
+	v(http.MethodPost, "/role-bindings", router.roleBindingHandler.CreateRoleBinding,
+		openapi.Op{Tag: "auth", Summary: "授予角色", Body: CreateRoleBindingRequest{}, Result: models.RoleBinding{}, Status: http.StatusCreated})
+	v(http.MethodDelete, "/role-bindings/{id}", withID("id", "role binding", router.roleBindingHandler.DeleteRoleBinding),
+		openapi.Op{Tag: "auth", Summary: "撤销角色", Result: message})
+
+	// 项目
+	v(http.MethodGet, "/projects", router.projectHandler.ListProjects,
+		openapi.Op{Tag: "projects", Summary: "获取项目列表", Query: pageParams, Result: []models.Project{}})
+	v(http.MethodPost, "/projects", router.projectHandler.CreateProject,
+		openapi.Op{Tag: "projects", Summary: "创建项目", Body: ProjectRequest{}, Result: models.Project{}, Status: http.StatusCreated})
+	v(http.MethodGet, "/projects/{id}", withID("id", "project", router.projectHandler.GetProject),
+		openapi.Op{Tag: "projects", Summary: "获取项目", Result: models.Project{}})
+	v(http.MethodPut, "/projects/{id}", withID("id", "project", router.projectHandler.UpdateProject),
+		openapi.Op{Tag: "projects", Summary: "更新项目", Body: ProjectRequest{}, Result: models.Project{}})
+	v(http.MethodGet, "/projects/{id}/members", withID("id", "project", router.projectHandler.ListMembers),
+		openapi.Op{Tag: "projects", Summary: "获取项目成员", Result: []models.ProjectMember{}})
+	v(http.MethodPost, "/projects/{id}/members", withID("id", "project", router.projectHandler.AddMember),
+		openapi.Op{Tag: "projects", Summary: "添加成员或修改成员角色", Body: models.ProjectMember{}, Result: models.ProjectMember{}, Status: http.StatusCreated})
+	v(http.MethodDelete, "/projects/{id}/members/{userId}", withID("id", "project", func(w http.ResponseWriter, r *http.Request, projectID int64) {
+		withID("userId", "user", func(w http.ResponseWriter, r *http.Request, userID int64) {
+			router.projectHandler.RemoveMember(w, r, projectID, userID)
+		})(w, r)
+	}), openapi.Op{Tag: "projects", Summary: "移除成员", Result: message})
+	v(http.MethodGet, "/projects/{id}/secrets", withID("id", "project", router.projectHandler.ListSecrets),
+		openapi.Op{Tag: "projects", Summary: "获取项目密钥名称", Result: []models.ProjectSecret{}})
+	v(http.MethodPut, "/projects/{id}/secrets", withID("id", "project", router.projectHandler.SetSecret),
+		openapi.Op{Tag: "projects", Summary: "写入项目密钥", Body: SetSecretRequest{}, Result: message})
+	v(http.MethodDelete, "/projects/{id}/secrets/{name}", withID("id", "project", func(w http.ResponseWriter, r *http.Request, projectID int64) {
+		router.projectHandler.DeleteSecret(w, r, projectID, httprouter.Param(r, "name"))
+	}), openapi.Op{Tag: "projects", Summary: "删除项目密钥", Result: message})
+
+	return rt, doc
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/response"
+)
+
+// WebhookHandler Webhook 处理器
+type WebhookHandler struct {
+	service *service.WebhookService
+}
+
+// NewWebhookHandler 创建 Webhook 处理器
+func NewWebhookHandler(service *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// webhookRequest 创建/更新订阅请求；Secret 不会在响应中返回
+type webhookRequest struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	FlowID     *int64   `json:"flow_id"`
+	Secret     *string  `json:"secret"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// apply 将请求字段写入订阅
+func (req *webhookRequest) apply(sub *models.WebhookSubscription) {
+	sub.Name = req.Name
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	sub.FlowID = sql.NullInt64{}
+	if req.FlowID != nil {
+		sub.FlowID = sql.NullInt64{Int64: *req.FlowID, Valid: true}
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+}
+
+// CreateWebhook 创建订阅
+// POST /api/webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	sub := models.WebhookSubscription{IsActive: true}
+	req.apply(&sub)
+	if err := h.service.CreateSubscription(r.Context(), &sub); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Created(w, sub)
+}
+
+// GetWebhook 获取订阅
+// GET /api/v1/webhooks/{id}（旧接口 GET /api/webhooks?id=）
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request, id int64) {
+	sub, err := h.service.GetSubscription(r.Context(), id)
+	if err != nil {
+		response.NotFound(w, "webhook not found")
+		return
+	}
+
+	response.Success(w, sub)
+}
+
+// ListWebhooks 获取订阅列表
+// GET /api/webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	subs, err := h.service.ListSubscriptions(r.Context(), limit, offset)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.Success(w, subs)
+}
+
+// UpdateWebhook 更新订阅，未提供 secret 时保留原密钥；id 为 0 时取请求体中的 id
+// PUT /api/v1/webhooks/{id}（旧接口 PUT /api/webhooks）
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request, id int64) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	sub, err := h.service.GetSubscription(r.Context(), pathOrBodyID(id, req.ID))
+	if err != nil {
+		response.NotFound(w, "webhook not found")
+		return
+	}
+
+	req.apply(sub)
+	if err := h.service.UpdateSubscription(r.Context(), sub); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, sub)
+}
+
+// DeleteWebhook 删除订阅
+// DELETE /api/v1/webhooks/{id}（旧接口 DELETE /api/webhooks?id=）
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, err := h.service.GetSubscription(r.Context(), id); err != nil {
+		response.NotFound(w, "webhook not found")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "webhook deleted successfully"})
+}
+
+// ListDeliveries 获取订阅的投递记录
+// GET /api/webhooks/{id}/deliveries
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request, subscriptionID int64) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), subscriptionID, limit, offset)
+	if err != nil {
+		response.NotFound(w, "webhook not found")
+		return
+	}
+
+	response.Success(w, deliveries)
+}
+
+// GetDelivery 获取投递记录及尝试历史
+// GET /api/webhook-deliveries/{id}
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request, deliveryID int64) {
+	delivery, err := h.service.GetDelivery(r.Context(), deliveryID)
+	if err != nil {
+		response.NotFound(w, "delivery not found")
+		return
+	}
+
+	response.Success(w, delivery)
+}
+
+// Redeliver 重新投递
+// POST /api/webhook-deliveries/{id}/redeliver
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request, deliveryID int64) {
+	delivery, err := h.service.Redeliver(r.Context(), deliveryID)
+	if err != nil {
+		var forbiddenErr *service.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			writeServiceError(w, err)
+			return
+		}
+		response.NotFound(w, "delivery not found")
+		return
+	}
+
+	response.Created(w, delivery)
+}
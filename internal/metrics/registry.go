@@ -0,0 +1,303 @@
+// Package metrics 提供轻量的 Prometheus 指标（计数器、仪表、直方图）及文本格式输出
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector 可输出 Prometheus 文本格式的指标
+type Collector interface {
+	writeTo(b *bytes.Buffer)
+}
+
+// Registry 指标注册表
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry 创建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// MustRegister 注册指标
+func (r *Registry) MustRegister(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Handler 返回以 Prometheus 文本格式输出所有指标的 HTTP 处理器
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var b bytes.Buffer
+		r.mu.RLock()
+		for _, c := range r.collectors {
+			c.writeTo(&b)
+		}
+		r.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(b.Bytes())
+	})
+}
+
+// desc 指标元数据
+type desc struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (d *desc) writeHeader(b *bytes.Buffer, metricType string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", d.name, metricType)
+}
+
+// key 将标签值编码为 map 键
+func (d *desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// labels 格式化标签，extra 为附加的标签对（如直方图的 le）
+func (d *desc) labels(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labelNames) > 0 {
+		values := strings.Split(key, "\xff")
+		for i, name := range d.labelNames {
+			pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec 创建计数器
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{desc: desc{name: name, help: help, labelNames: labelNames}, values: make(map[string]float64)}
+}
+
+// Inc 计数加 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 计数增加 v（v 不能为负）
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	k := c.key(labelValues)
+	c.mu.Lock()
+	c.values[k] += v
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) writeTo(b *bytes.Buffer) {
+	c.writeHeader(b, "counter")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range sortedKeys(c.values) {
+		fmt.Fprintf(b, "%s%s %s\n", c.name, c.labels(k), formatFloat(c.values[k]))
+	}
+}
+
+// GaugeVec 带标签的仪表
+type GaugeVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewGaugeVec 创建仪表
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{desc: desc{name: name, help: help, labelNames: labelNames}, values: make(map[string]float64)}
+}
+
+// Set 设置数值
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	g.values[k] = v
+	g.mu.Unlock()
+}
+
+// Add 数值增加 v（可为负）
+func (g *GaugeVec) Add(v float64, labelValues ...string) {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	g.values[k] += v
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) writeTo(b *bytes.Buffer) {
+	g.writeHeader(b, "gauge")
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, k := range sortedKeys(g.values) {
+		fmt.Fprintf(b, "%s%s %s\n", g.name, g.labels(k), formatFloat(g.values[k]))
+	}
+}
+
+// GaugeFunc 在采集时通过回调取值的仪表，回调返回错误时本次不输出样本
+type GaugeFunc struct {
+	desc
+	fn func() (float64, error)
+}
+
+// NewGaugeFunc 创建回调仪表
+func NewGaugeFunc(name, help string, fn func() (float64, error)) *GaugeFunc {
+	return &GaugeFunc{desc: desc{name: name, help: help}, fn: fn}
+}
+
+func (g *GaugeFunc) writeTo(b *bytes.Buffer) {
+	v, err := g.fn()
+	if err != nil {
+		return
+	}
+	g.writeHeader(b, "gauge")
+	fmt.Fprintf(b, "%s %s\n", g.name, formatFloat(v))
+}
+
+// CollectorFunc 在采集时生成一组指标的回调，用于一次取值输出多个指标（如连接池统计）
+type CollectorFunc func(b *bytes.Buffer)
+
+func (f CollectorFunc) writeTo(b *bytes.Buffer) {
+	f(b)
+}
+
+// DefBuckets 默认直方图分桶（秒）
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // 各分桶的非累计计数
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec 创建直方图，buckets 为升序上界
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{
+		desc:    desc{name: name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		series:  make(map[string]*histogram),
+	}
+}
+
+// Observe 记录一次观测值
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *HistogramVec) writeTo(b *bytes.Buffer) {
+	h.writeHeader(b, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := h.series[k]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, h.labels(k, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, h.labels(k, "le", "+Inf"), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", h.name, h.labels(k), formatFloat(s.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", h.name, h.labels(k), s.count)
+	}
+}
+
+// WriteGauge 在 CollectorFunc 中输出单个无标签仪表
+func WriteGauge(b *bytes.Buffer, name, help string, v float64) {
+	writeSingle(b, name, help, "gauge", v)
+}
+
+// WriteCounter 在 CollectorFunc 中输出单个无标签计数器
+func WriteCounter(b *bytes.Buffer, name, help string, v float64) {
+	writeSingle(b, name, help, "counter", v)
+}
+
+func writeSingle(b *bytes.Buffer, name, help, metricType string, v float64) {
+	d := desc{name: name, help: help}
+	d.writeHeader(b, metricType)
+	fmt.Fprintf(b, "%s %s\n", name, formatFloat(v))
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+)
+
+// 执行器错误类型
+const (
+	ErrorTypeInput     = "input"     // 输入不符合契约
+	ErrorTypeOutput    = "output"    // 输出不符合契约
+	ErrorTypeNotFound  = "not_found" // 执行器未注册
+	ErrorTypeForbidden = "forbidden" // 执行器不在项目白名单中
+	ErrorTypeTimeout   = "timeout"   // 执行超时
+	ErrorTypeCanceled  = "canceled"  // 执行被取消
+	ErrorTypeExecution = "execution" // 执行器返回错误
+)
+
+// taskDurationBuckets 作业任务耗时分桶（秒），执行器通常需要数秒到数分钟
+var taskDurationBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+// Metrics 工作流服务指标
+type Metrics struct {
+	registry *Registry
+
+	jobsCreated     *CounterVec
+	jobsCompleted   *CounterVec
+	jobsFailed      *CounterVec
+	jobTaskDuration *HistogramVec
+	executorErrors  *CounterVec
+	httpDuration    *HistogramVec
+}
+
+// New 创建工作流服务指标
+func New() *Metrics {
+	m := &Metrics{
+		registry:        NewRegistry(),
+		jobsCreated:     NewCounterVec("workflow_jobs_created_total", "Jobs created, by flow.", "flow_id"),
+		jobsCompleted:   NewCounterVec("workflow_jobs_completed_total", "Jobs completed, by flow.", "flow_id"),
+		jobsFailed:      NewCounterVec("workflow_jobs_failed_total", "Jobs failed, by flow.", "flow_id"),
+		jobTaskDuration: NewHistogramVec("workflow_job_task_duration_seconds", "Automated job task execution time, by executor and outcome.", taskDurationBuckets, "executor", "status"),
+		executorErrors:  NewCounterVec("workflow_executor_errors_total", "Executor errors, by executor and error type.", "executor", "type"),
+		httpDuration:    NewHistogramVec("workflow_http_request_duration_seconds", "HTTP request latency, by method, route and status code.", DefBuckets, "method", "route", "code"),
+	}
+	m.registry.MustRegister(
+		m.jobsCreated,
+		m.jobsCompleted,
+		m.jobsFailed,
+		m.jobTaskDuration,
+		m.executorErrors,
+		m.httpDuration,
+	)
+	return m
+}
+
+// Handler 返回 /metrics 处理器
+func (m *Metrics) Handler() http.Handler {
+	return m.registry.Handler()
+}
+
+// Register 注册额外的指标
+func (m *Metrics) Register(collectors ...Collector) {
+	m.registry.MustRegister(collectors...)
+}
+
+// ConsumeEvents 订阅事件总线统计作业创建、完成与失败数量，ctx 取消后停止
+func (m *Metrics) ConsumeEvents(ctx context.Context, bus *events.Bus) {
+	sub := bus.SubscribeBuffered(events.Filter{
+		Types: []events.Type{events.JobCreated, events.JobCompleted, events.JobFailed},
+	}, 1024)
+
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				flowID := strconv.FormatInt(event.FlowID, 10)
+				switch event.Type {
+				case events.JobCreated:
+					m.jobsCreated.Inc(flowID)
+				case events.JobCompleted:
+					m.jobsCompleted.Inc(flowID)
+				case events.JobFailed:
+					m.jobsFailed.Inc(flowID)
+				}
+			}
+		}
+	}()
+}
+
+// ObserveJobTask 记录一次作业任务执行耗时，status 为 completed 或 failed
+func (m *Metrics) ObserveJobTask(executorName, status string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.jobTaskDuration.Observe(d.Seconds(), executorName, status)
+}
+
+// IncExecutorError 记录一次执行器错误
+func (m *Metrics) IncExecutorError(executorName, errorType string) {
+	if m == nil {
+		return
+	}
+	m.executorErrors.Inc(executorName, errorType)
+}
+
+// RegisterDBStats 注册数据库连接池指标
+func (m *Metrics) RegisterDBStats(db *sql.DB) {
+	m.Register(CollectorFunc(func(b *bytes.Buffer) {
+		stats := db.Stats()
+		WriteGauge(b, "workflow_db_max_open_connections", "Maximum number of open connections to the database.", float64(stats.MaxOpenConnections))
+		WriteGauge(b, "workflow_db_open_connections", "Number of established connections, in use and idle.", float64(stats.OpenConnections))
+		WriteGauge(b, "workflow_db_in_use_connections", "Number of connections currently in use.", float64(stats.InUse))
+		WriteGauge(b, "workflow_db_idle_connections", "Number of idle connections.", float64(stats.Idle))
+		WriteCounter(b, "workflow_db_wait_count_total", "Total number of connections waited for.", float64(stats.WaitCount))
+		WriteCounter(b, "workflow_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", stats.WaitDuration.Seconds())
+		WriteCounter(b, "workflow_db_max_idle_closed_total", "Total connections closed due to SetMaxIdleConns.", float64(stats.MaxIdleClosed))
+		WriteCounter(b, "workflow_db_max_lifetime_closed_total", "Total connections closed due to SetConnMaxLifetime.", float64(stats.MaxLifetimeClosed))
+	}))
+}
+
+// RegisterTaskGauges 注册队列深度（运行中作业的待执行任务数）与运行中任务数，采集时实时查询
+func (m *Metrics) RegisterTaskGauges(queueDepth, running func() (float64, error)) {
+	m.Register(
+		NewGaugeFunc("workflow_queue_depth", "Pending job tasks in running jobs.", queueDepth),
+		NewGaugeFunc("workflow_running_tasks", "Job tasks currently running.", running),
+	)
+}
+
+// Middleware 记录 HTTP 请求耗时，route 取自 ServeMux 匹配的路由模式以避免标签基数过高
+func (m *Metrics) Middleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, route := mux.Handler(r)
+		if sub, ok := h.(routeMatcher); ok {
+			if _, pattern := sub.Handler(r); pattern != "" {
+				route = pattern
+			}
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		mux.ServeHTTP(rec, r)
+		m.httpDuration.Observe(time.Since(start).Seconds(), r.Method, route, strconv.Itoa(rec.status))
+	})
+}
+
+// routeMatcher 挂载在 ServeMux 下的子路由（如 /api/v1），用于取得更细的路由模式
+type routeMatcher interface {
+	Handler(r *http.Request) (http.Handler, string)
+}
+
+// statusRecorder 记录响应状态码，并保留 Flush 以支持 SSE
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
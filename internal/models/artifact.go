@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Artifact 作业制品模型（转录文本、分析结果、报告文件等）
+type Artifact struct {
+	ID          int64         `json:"id"`
+	JobID       int64         `json:"job_id"`
+	JobTaskID   sql.NullInt64 `json:"job_task_id"`
+	Name        string        `json:"name"`
+	ContentType string        `json:"content_type"`
+	Size        int64         `json:"size"`
+	Checksum    string        `json:"checksum"`
+	Backend     string        `json:"backend"`
+	StorageKey  string        `json:"-"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// TableName 返回表名
+func (Artifact) TableName() string {
+	return "artifacts"
+}
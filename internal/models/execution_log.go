@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ExecutionLog 执行器输出的日志行
+type ExecutionLog struct {
+	ID        int64     `json:"id"`
+	JobTaskID int64     `json:"job_task_id"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 返回表名
+func (ExecutionLog) TableName() string {
+	return "execution_logs"
+}
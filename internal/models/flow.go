@@ -1,19 +1,47 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 )
 
+// FlowInputs 流程输入键（创建作业时通过作业上下文提供）
+type FlowInputs []string
+
+// Value 实现 driver.Valuer 接口
+func (fi FlowInputs) Value() (driver.Value, error) {
+	if fi == nil {
+		return nil, nil
+	}
+	return json.Marshal(fi)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (fi *FlowInputs) Scan(value interface{}) error {
+	if value == nil {
+		*fi = nil
+		return nil
+	}
+	bytes, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, fi)
+}
+
 // Flow 流程定义模型
 type Flow struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Version     string    `json:"version"`
-	IsActive    bool      `json:"is_active"`
-	CreatedBy   int64     `json:"created_by"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64      `json:"id"`
+	ProjectID   int64      `json:"project_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Version     string     `json:"version"`
+	Inputs      FlowInputs `json:"inputs"`
+	IsActive    bool       `json:"is_active"`
+	CreatedBy   int64      `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // TableName 返回表名
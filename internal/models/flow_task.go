@@ -23,7 +23,7 @@ func (cc *ConditionConfig) Scan(value interface{}) error {
 		*cc = nil
 		return nil
 	}
-	bytes, ok := value.([]byte)
+	bytes, ok := jsonBytes(value)
 	if !ok {
 		return nil
 	}
@@ -39,6 +39,7 @@ type FlowTask struct {
 	IsOptional      bool            `json:"is_optional"`
 	AllowRollback   bool            `json:"allow_rollback"`
 	ConditionConfig ConditionConfig `json:"condition_config"`
+	ConfigOverrides TaskConfig      `json:"config_overrides"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 
@@ -50,3 +51,17 @@ type FlowTask struct {
 func (FlowTask) TableName() string {
 	return "flow_tasks"
 }
+
+// EffectiveConfig 返回合并流程级覆盖后的任务配置（覆盖项优先）
+func (ft *FlowTask) EffectiveConfig() TaskConfig {
+	config := make(TaskConfig)
+	if ft.Task != nil {
+		for k, v := range ft.Task.Config {
+			config[k] = v
+		}
+	}
+	for k, v := range ft.ConfigOverrides {
+		config[k] = v
+	}
+	return config
+}
@@ -19,7 +19,10 @@ const (
 // Job 作业实例模型
 type Job struct {
 	ID             int64        `json:"id"`
+	ProjectID      int64        `json:"project_id"`
 	FlowID         int64        `json:"flow_id"`
+	BatchID        sql.NullInt64 `json:"batch_id"`
+	RerunOf        sql.NullInt64 `json:"rerun_of"` // 重新运行的来源作业
 	JobName        string       `json:"job_name"`
 	Status         JobStatus    `json:"status"`
 	CurrentTaskSeq sql.NullInt64 `json:"current_task_seq"`
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// JobBatch 作业批次：按一组输入行批量创建的同一流程的作业
+type JobBatch struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	FlowID      int64     `json:"flow_id"`
+	Name        string    `json:"name"`
+	TotalJobs   int       `json:"total_jobs"`
+	AutoExecute bool      `json:"auto_execute"`
+	Concurrency int       `json:"concurrency"` // 自动执行的最大并发作业数
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 返回表名
+func (JobBatch) TableName() string {
+	return "job_batches"
+}
+
+// JobBatchItem 批次中一个作业的名称与流程输入（写入作业共享上下文）
+type JobBatchItem struct {
+	JobName string
+	Inputs  map[string]interface{}
+}
+
+// JobBatchSummary 批次及其作业按状态的统计
+type JobBatchSummary struct {
+	Batch    *JobBatch         `json:"batch"`
+	Counts   map[JobStatus]int `json:"counts"`
+	Finished bool              `json:"finished"` // 所有作业均已完成、失败或取消
+}
+
+// JobBatchResult 批次级操作（取消、重试）的结果
+type JobBatchResult struct {
+	JobIDs []int64          `json:"job_ids"`          // 已处理的作业
+	Errors map[int64]string `json:"errors,omitempty"` // 处理失败的作业及原因
+}
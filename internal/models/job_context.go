@@ -1,17 +1,40 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+const (
+	// ContextScopeShared 共享作用域：作业输入及显式写入的共享数据
+	ContextScopeShared = "shared"
+
+	// contextScopeTaskPrefix 任务输出命名空间前缀
+	contextScopeTaskPrefix = "task:"
+)
+
+// TaskContextScope 返回任务输出命名空间
+func TaskContextScope(taskName string) string {
+	return contextScopeTaskPrefix + taskName
+}
+
+// ParseTaskContextScope 解析任务输出命名空间，返回任务名称
+func ParseTaskContextScope(scope string) (string, bool) {
+	if !strings.HasPrefix(scope, contextScopeTaskPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(scope, contextScopeTaskPrefix), true
+}
+
 // JobContext 作业上下文模型
 type JobContext struct {
-	ID           int64     `json:"id"`
-	JobID        int64     `json:"job_id"`
-	ContextKey   string    `json:"context_key"`
-	ContextValue string    `json:"context_value"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int64       `json:"id"`
+	JobID        int64       `json:"job_id"`
+	Scope        string      `json:"scope"`
+	ContextKey   string      `json:"context_key"`
+	ContextValue interface{} `json:"context_value"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
 // TableName 返回表名
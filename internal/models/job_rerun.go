@@ -0,0 +1,26 @@
+package models
+
+// JobRerunOptions 重新运行作业的选项
+type JobRerunOptions struct {
+	JobName       string                 // 新作业名称，默认为来源作业名称加 (rerun)
+	LatestVersion bool                   // 使用同名流程的最新启用版本，默认使用来源作业的流程
+	Inputs        map[string]interface{} // 覆盖来源作业的输入
+	ReuseOutputs  bool                   // 复用未变化的上游任务输出
+	CreatedBy     int64
+}
+
+// TaskReuse 重新运行时复用来源作业输出的任务：新作业中的该任务直接标记为已完成，
+// 结果与任务输出命名空间从来源作业复制
+type TaskReuse struct {
+	Sequence        int        `json:"sequence"`
+	TaskName        string     `json:"task_name"`
+	SourceJobTaskID int64      `json:"source_job_task_id"`
+	JobTaskID       int64      `json:"job_task_id"` // 新作业中的作业任务
+	Result          TaskResult `json:"-"`
+}
+
+// JobRerun 重新运行创建的作业及复用输出的任务
+type JobRerun struct {
+	Job         *Job        `json:"job"`
+	ReusedTasks []TaskReuse `json:"reused_tasks"`
+}
@@ -36,7 +36,7 @@ func (tr *TaskResult) Scan(value interface{}) error {
 		*tr = nil
 		return nil
 	}
-	bytes, ok := value.([]byte)
+	bytes, ok := jsonBytes(value)
 	if !ok {
 		return nil
 	}
@@ -16,6 +16,10 @@ const (
 	LogActionSkip     LogAction = "skip"     // 跳过
 	LogActionRollback LogAction = "rollback" // 打回
 	LogActionFail     LogAction = "fail"     // 失败
+	LogActionRetry    LogAction = "retry"    // 重试
+	LogActionReassign LogAction = "reassign" // 转派
+	LogActionCancel   LogAction = "cancel"   // 取消作业
+	LogActionReuse    LogAction = "reuse"    // 重新运行时复用来源作业的输出
 )
 
 // LogMetadata 日志元数据
@@ -35,7 +39,7 @@ func (lm *LogMetadata) Scan(value interface{}) error {
 		*lm = nil
 		return nil
 	}
-	bytes, ok := value.([]byte)
+	bytes, ok := jsonBytes(value)
 	if !ok {
 		return nil
 	}
@@ -44,13 +48,13 @@ func (lm *LogMetadata) Scan(value interface{}) error {
 
 // JobTaskLog 作业任务日志模型
 type JobTaskLog struct {
-	ID         int64          `json:"id"`
-	JobTaskID  int64          `json:"job_task_id"`
-	Action     LogAction      `json:"action"`
-	OperatorID sql.NullInt64  `json:"operator_id"`
-	Message    string         `json:"message"`
-	Metadata   LogMetadata    `json:"metadata"`
-	CreatedAt  time.Time      `json:"created_at"`
+	ID         int64         `json:"id"`
+	JobTaskID  int64         `json:"job_task_id"`
+	Action     LogAction     `json:"action"`
+	OperatorID sql.NullInt64 `json:"operator_id"`
+	Message    string        `json:"message"`
+	Metadata   LogMetadata   `json:"metadata"`
+	CreatedAt  time.Time     `json:"created_at"`
 }
 
 // TableName 返回表名
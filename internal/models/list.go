@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// ListQuery 列表查询的通用参数：分页、排序、名称搜索与时间范围
+type ListQuery struct {
+	Limit  int    // 每页数量
+	Offset int    // 偏移量，指定 Cursor 时忽略
+	Sort   string // 排序字段，前缀 - 表示降序，默认 -id
+	Cursor string // 上一页返回的 next_cursor，用于键集分页
+
+	Name          string    // 名称包含该字符串
+	CreatedAfter  time.Time // 创建时间 >= CreatedAfter
+	CreatedBefore time.Time // 创建时间 < CreatedBefore
+	UpdatedAfter  time.Time // 更新时间 >= UpdatedAfter
+	UpdatedBefore time.Time // 更新时间 < UpdatedBefore
+}
+
+// TaskFilter 任务列表查询条件
+type TaskFilter struct {
+	ListQuery
+	TaskType TaskType
+	IsActive *bool // nil 表示不限
+}
+
+// FlowFilter 流程列表查询条件
+type FlowFilter struct {
+	ListQuery
+	CreatedBy int64
+	IsActive  *bool // nil 表示不限
+}
+
+// JobFilter 作业列表查询条件
+type JobFilter struct {
+	ListQuery
+	Statuses  []JobStatus // 任一状态
+	FlowID    int64
+	BatchID   int64
+	RerunOf   int64 // 重新运行的来源作业
+	CreatedBy int64
+}
+
+// Page 列表分页信息
+type Page struct {
+	Total      int64  `json:"total"`                 // 符合条件的总数
+	Limit      int    `json:"limit"`                 // 每页数量
+	Offset     int    `json:"offset"`                // 偏移量
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标，没有更多数据时为空
+}
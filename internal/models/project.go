@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// DefaultProjectID 默认项目ID，未指定项目的请求与历史数据归属该项目
+const DefaultProjectID int64 = 1
+
+// 项目成员角色
+const (
+	ProjectRoleOwner  = "owner"  // 可管理成员、密钥与执行器白名单
+	ProjectRoleMember = "member" // 可访问项目内的任务、流程与作业
+)
+
+// ExecutorList 执行器名称列表
+type ExecutorList []string
+
+// Value 实现 driver.Valuer 接口
+func (el ExecutorList) Value() (driver.Value, error) {
+	if el == nil {
+		return nil, nil
+	}
+	return json.Marshal(el)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (el *ExecutorList) Scan(value interface{}) error {
+	if value == nil {
+		*el = nil
+		return nil
+	}
+	bytes, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, el)
+}
+
+// Project 项目（命名空间）模型
+type Project struct {
+	ID               int64        `json:"id"`
+	Name             string       `json:"name"`
+	Slug             string       `json:"slug"`
+	Description      string       `json:"description"`
+	AllowedExecutors ExecutorList `json:"allowed_executors"`
+	CreatedBy        int64        `json:"created_by"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// TableName 返回表名
+func (Project) TableName() string {
+	return "projects"
+}
+
+// AllowsExecutor 判断项目是否允许使用执行器，白名单为空时不限制
+func (p *Project) AllowsExecutor(name string) bool {
+	if len(p.AllowedExecutors) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedExecutors {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectMember 项目成员
+type ProjectMember struct {
+	ProjectID int64     `json:"project_id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 返回表名
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// ProjectSecret 项目密钥，值不通过接口返回
+type ProjectSecret struct {
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"project_id"`
+	Name      string    `json:"name"`
+	Value     string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 返回表名
+func (ProjectSecret) TableName() string {
+	return "project_secrets"
+}
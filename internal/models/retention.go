@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// RetentionAction 过期作业的清理方式
+type RetentionAction string
+
+const (
+	RetentionActionDelete       RetentionAction = "delete"        // 直接删除
+	RetentionActionArchiveFile  RetentionAction = "archive_file"  // 写入压缩 JSON 文件后删除
+	RetentionActionArchiveTable RetentionAction = "archive_table" // 写入 job_archives 表后删除
+)
+
+// RetentionPolicy 作业保留策略。只清理已结束的作业：已完成、已取消的作业超过 KeepDays 天，
+// 或不在流程最近 KeepJobs 个之内时清理；失败作业超过 FailedKeepDays 天时清理
+type RetentionPolicy struct {
+	ID             int64           `json:"id"`
+	FlowID         sql.NullInt64   `json:"flow_id"` // 为空表示默认策略
+	KeepDays       int             `json:"keep_days"`
+	KeepJobs       int             `json:"keep_jobs"`
+	FailedKeepDays int             `json:"failed_keep_days"` // 0 表示沿用 KeepDays
+	Action         RetentionAction `json:"action"`
+	IsActive       bool            `json:"is_active"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// TableName 返回表名
+func (RetentionPolicy) TableName() string {
+	return "retention_policies"
+}
+
+// RetentionCutoff 由保留策略换算出的清理界限，零值表示不按该条件清理
+type RetentionCutoff struct {
+	FinishedBefore time.Time // 已完成、已取消的作业结束早于该时间时清理
+	FailedBefore   time.Time // 失败的作业结束早于该时间时清理
+	KeepLatest     int       // 保留流程最近的已完成、已取消作业数
+}
+
+// JobSnapshot 归档的作业数据
+type JobSnapshot struct {
+	Job       Job                               `json:"job"`
+	Tasks     []JobTask                         `json:"tasks"`
+	Logs      []JobTaskLog                      `json:"logs"`
+	Context   map[string]map[string]interface{} `json:"context"`
+	Artifacts []Artifact                        `json:"artifacts"`
+}
+
+// JobArchive 归档表中的作业记录
+type JobArchive struct {
+	ID           int64           `json:"id"`
+	JobID        int64           `json:"job_id"`
+	ProjectID    int64           `json:"project_id"`
+	FlowID       int64           `json:"flow_id"`
+	JobName      string          `json:"job_name"`
+	Status       JobStatus       `json:"status"`
+	Data         json.RawMessage `json:"data"`
+	JobCreatedAt time.Time       `json:"job_created_at"`
+	ArchivedAt   time.Time       `json:"archived_at"`
+}
+
+// TableName 返回表名
+func (JobArchive) TableName() string {
+	return "job_archives"
+}
+
+// RetentionFlowResult 单个流程的清理结果
+type RetentionFlowResult struct {
+	FlowID       int64           `json:"flow_id"`
+	PolicyID     int64           `json:"policy_id"`
+	Action       RetentionAction `json:"action"`
+	JobIDs       []int64         `json:"job_ids"`                 // 已清理（试运行时为将清理）的作业
+	ArchiveFiles []string        `json:"archive_files,omitempty"` // archive_file 方式写入的归档文件
+	FilesRemoved []string        `json:"files_removed,omitempty"` // 删除的报告文件
+	Artifacts    int             `json:"artifacts"`               // 删除的制品对象数
+}
+
+// RetentionDetails 按流程统计的清理明细
+type RetentionDetails []RetentionFlowResult
+
+// Value 实现 driver.Valuer 接口
+func (d RetentionDetails) Value() (driver.Value, error) {
+	if d == nil {
+		return json.Marshal([]RetentionFlowResult{})
+	}
+	return json.Marshal([]RetentionFlowResult(d))
+}
+
+// Scan 实现 sql.Scanner 接口
+func (d *RetentionDetails) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+	bytes, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// RetentionRun 一次清理的结果
+type RetentionRun struct {
+	ID               int64            `json:"id"`
+	DryRun           bool             `json:"dry_run"`
+	JobsDeleted      int              `json:"jobs_deleted"`
+	JobsArchived     int              `json:"jobs_archived"`
+	FilesRemoved     int              `json:"files_removed"`
+	ArtifactsRemoved int              `json:"artifacts_removed"`
+	Details          RetentionDetails `json:"details"`
+	Error            string           `json:"error,omitempty"`
+	StartedAt        time.Time        `json:"started_at"`
+	FinishedAt       sql.NullTime     `json:"finished_at"`
+}
+
+// TableName 返回表名
+func (RetentionRun) TableName() string {
+	return "retention_runs"
+}
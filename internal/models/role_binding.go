@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RoleBinding 角色绑定：FlowID 为 0 表示全局角色，否则只在该流程上生效
+type RoleBinding struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	FlowID    int64     `json:"flow_id"`
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 返回表名
+func (RoleBinding) TableName() string {
+	return "role_bindings"
+}
@@ -32,16 +32,28 @@ func (tc *TaskConfig) Scan(value interface{}) error {
 		*tc = nil
 		return nil
 	}
-	bytes, ok := value.([]byte)
+	bytes, ok := jsonBytes(value)
 	if !ok {
 		return nil
 	}
 	return json.Unmarshal(bytes, tc)
 }
 
+// jsonBytes 返回 JSON 列的原始数据：MySQL、PostgreSQL 驱动返回 []byte，SQLite 的 TEXT 列返回 string
+func jsonBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
 // Task 任务定义模型
 type Task struct {
 	ID          int64      `json:"id"`
+	ProjectID   int64      `json:"project_id"`
 	Name        string     `json:"name"`
 	Description string     `json:"description"`
 	TaskType    TaskType   `json:"task_type"`
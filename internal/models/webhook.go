@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// WebhookDeliveryStatus 投递状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // 等待投递或重试
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // 投递成功
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // 重试次数耗尽
+)
+
+// WebhookEventTypes 订阅的事件类型，为空表示订阅全部事件
+type WebhookEventTypes []string
+
+// Value 实现 driver.Valuer 接口
+func (et WebhookEventTypes) Value() (driver.Value, error) {
+	if et == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal(et)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (et *WebhookEventTypes) Scan(value interface{}) error {
+	if value == nil {
+		*et = nil
+		return nil
+	}
+	bytes, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, et)
+}
+
+// Includes 判断是否订阅了指定事件类型
+func (et WebhookEventTypes) Includes(eventType string) bool {
+	if len(et) == 0 {
+		return true
+	}
+	for _, t := range et {
+		if t == eventType || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription Webhook 订阅配置
+type WebhookSubscription struct {
+	ID         int64             `json:"id"`
+	ProjectID  int64             `json:"project_id"`
+	Name       string            `json:"name"`
+	URL        string            `json:"url"`
+	EventTypes WebhookEventTypes `json:"event_types"`
+	FlowID     sql.NullInt64     `json:"flow_id"`
+	Secret     string            `json:"-"`
+	IsActive   bool              `json:"is_active"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// TableName 返回表名
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery Webhook 投递记录（一个事件对一个订阅的投递）
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventType      string                `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode sql.NullInt64         `json:"last_status_code"`
+	LastError      string                `json:"last_error"`
+	NextAttemptAt  sql.NullTime          `json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime          `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+
+	// 关联数据
+	AttemptLogs []WebhookDeliveryAttempt `json:"attempt_logs,omitempty"`
+}
+
+// TableName 返回表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt 单次投递尝试
+type WebhookDeliveryAttempt struct {
+	ID           int64         `json:"id"`
+	DeliveryID   int64         `json:"delivery_id"`
+	Attempt      int           `json:"attempt"`
+	StatusCode   sql.NullInt64 `json:"status_code"`
+	Error        string        `json:"error"`
+	ResponseBody string        `json:"response_body"`
+	DurationMs   int64         `json:"duration_ms"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// TableName 返回表名
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// APIKeyRepository API Key 仓储接口
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id int64) (*models.APIKey, error)
+	GetByHash(keyHash string) (*models.APIKey, error)
+	ListByUserID(userID int64) ([]models.APIKey, error)
+	Revoke(id int64) error
+	TouchLastUsed(id int64, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db DBTX
+}
+
+// NewAPIKeyRepository 创建 API Key 仓储
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: newDB(db)}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, user_id, expires_at, last_used_at, revoked_at, created_at`
+
+// Create 创建 API Key
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, user_id, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query, key.Name, key.Prefix, key.KeyHash, key.UserID, key.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	key.ID = id
+	key.CreatedAt = time.Now()
+	return nil
+}
+
+// GetByID 根据ID获取 API Key
+func (r *apiKeyRepository) GetByID(id int64) (*models.APIKey, error) {
+	return r.get(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id)
+}
+
+// GetByHash 根据密钥哈希获取 API Key
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	return r.get(`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = ?`, keyHash)
+}
+
+func (r *apiKeyRepository) get(query string, arg interface{}) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := r.db.QueryRow(query, arg).Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.UserID,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// ListByUserID 获取用户的 API Key 列表
+func (r *apiKeyRepository) ListByUserID(userID int64) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = ? ORDER BY id DESC`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		var key models.APIKey
+		if err := rows.Scan(
+			&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.UserID,
+			&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke 吊销 API Key
+func (r *apiKeyRepository) Revoke(id int64) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// TouchLastUsed 更新最近使用时间
+func (r *apiKeyRepository) TouchLastUsed(id int64, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	return nil
+}
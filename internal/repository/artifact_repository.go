@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// ArtifactRepository 制品仓储接口
+type ArtifactRepository interface {
+	Create(artifact *models.Artifact) error
+	GetByID(id int64) (*models.Artifact, error)
+	ListByJobID(jobID int64) ([]models.Artifact, error)
+	Delete(id int64) error
+}
+
+type artifactRepository struct {
+	db DBTX
+}
+
+// NewArtifactRepository 创建制品仓储
+func NewArtifactRepository(db *sql.DB) ArtifactRepository {
+	return &artifactRepository{db: newDB(db)}
+}
+
+// Create 创建制品记录
+func (r *artifactRepository) Create(artifact *models.Artifact) error {
+	query := `
+		INSERT INTO artifacts (job_id, job_task_id, name, content_type, size, checksum, backend, storage_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query,
+		artifact.JobID, artifact.JobTaskID, artifact.Name, artifact.ContentType,
+		artifact.Size, artifact.Checksum, artifact.Backend, artifact.StorageKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact: %w", err)
+	}
+
+	artifact.ID = id
+	return nil
+}
+
+// GetByID 根据ID获取制品
+func (r *artifactRepository) GetByID(id int64) (*models.Artifact, error) {
+	query := `
+		SELECT id, job_id, job_task_id, name, content_type, size, checksum, backend, storage_key, created_at
+		FROM artifacts
+		WHERE id = ?
+	`
+	artifact := &models.Artifact{}
+	err := r.db.QueryRow(query, id).Scan(
+		&artifact.ID, &artifact.JobID, &artifact.JobTaskID, &artifact.Name, &artifact.ContentType,
+		&artifact.Size, &artifact.Checksum, &artifact.Backend, &artifact.StorageKey, &artifact.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("artifact not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+
+	return artifact, nil
+}
+
+// ListByJobID 获取作业的所有制品
+func (r *artifactRepository) ListByJobID(jobID int64) ([]models.Artifact, error) {
+	query := `
+		SELECT id, job_id, job_task_id, name, content_type, size, checksum, backend, storage_key, created_at
+		FROM artifacts
+		WHERE job_id = ?
+		ORDER BY id ASC
+	`
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	var artifacts []models.Artifact
+	for rows.Next() {
+		var artifact models.Artifact
+		if err := rows.Scan(
+			&artifact.ID, &artifact.JobID, &artifact.JobTaskID, &artifact.Name, &artifact.ContentType,
+			&artifact.Size, &artifact.Checksum, &artifact.Backend, &artifact.StorageKey, &artifact.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact: %w", err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+// Delete 删除制品记录
+func (r *artifactRepository) Delete(id int64) error {
+	query := `DELETE FROM artifacts WHERE id = ?`
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+
+	return nil
+}
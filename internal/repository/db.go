@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
+	"github.com/cfrs2005/GoWorkFlow/pkg/database"
+)
+
+// DBTX 仓储使用的数据库句柄，*sql.DB 与 *sql.Tx 均实现该接口
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Prepare(query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// newDB 返回仓储使用的数据库句柄：MySQL 直接使用连接，其他方言的语句与参数经 dialectDB 改写
+func newDB(db *sql.DB) DBTX {
+	return withDialect(db, database.DialectOf(db))
+}
+
+// withDialect 为数据库句柄绑定方言
+func withDialect(db DBTX, dialect database.Dialect) DBTX {
+	if dialect == database.MySQL {
+		return db
+	}
+	return &dialectDB{DBTX: db, dialect: dialect}
+}
+
+// withTx 返回在事务中执行、方言与 db 相同的数据库句柄
+func withTx(db DBTX, tx *sql.Tx) DBTX {
+	return withDialect(tx, dialectOf(db))
+}
+
+// dialectOf 返回数据库句柄的方言
+func dialectOf(db DBTX) database.Dialect {
+	if traced, ok := db.(*tracedDB); ok {
+		db = traced.DBTX
+	}
+	if d, ok := db.(*dialectDB); ok {
+		return d.dialect
+	}
+	return database.MySQL
+}
+
+// insertID 执行插入语句并返回自增 ID；PostgreSQL 不支持 LastInsertId，通过 RETURNING id 取回
+func insertID(db DBTX, query string, args ...interface{}) (int64, error) {
+	if dialect := dialectOf(db); dialect == database.Postgres {
+		var id int64
+		err := db.QueryRow(query+dialect.Returning("id"), args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// dialectDB 按方言改写占位符与参数的数据库句柄
+type dialectDB struct {
+	DBTX
+	dialect database.Dialect
+}
+
+func (d *dialectDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.DBTX.Exec(d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.DBTX.Query(d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.DBTX.QueryRow(d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) Prepare(query string) (*sql.Stmt, error) {
+	return d.DBTX.Prepare(d.dialect.Rebind(query))
+}
+
+func (d *dialectDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.DBTX.ExecContext(ctx, d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return d.DBTX.QueryContext(ctx, d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return d.DBTX.QueryRowContext(ctx, d.dialect.Rebind(query), d.dialect.ConvertArgs(args)...)
+}
+
+func (d *dialectDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.DBTX.PrepareContext(ctx, d.dialect.Rebind(query))
+}
+
+// withContext 返回绑定 ctx 的数据库句柄：查询使用 ctx 执行，并在 ctx 中存在追踪时为每条语句记录 SQL Span
+func withContext(ctx context.Context, db DBTX) DBTX {
+	if traced, ok := db.(*tracedDB); ok {
+		db = traced.DBTX
+	}
+	return &tracedDB{DBTX: db, ctx: ctx}
+}
+
+// unwrapDB 返回被 withContext、withDialect 包装前的数据库句柄
+func unwrapDB(db DBTX) DBTX {
+	if traced, ok := db.(*tracedDB); ok {
+		db = traced.DBTX
+	}
+	if d, ok := db.(*dialectDB); ok {
+		db = d.DBTX
+	}
+	return db
+}
+
+// tracedDB 绑定上下文的数据库句柄
+type tracedDB struct {
+	DBTX
+	ctx context.Context
+}
+
+func (t *tracedDB) span(query string) (context.Context, *tracing.Span) {
+	statement := strings.Join(strings.Fields(query), " ")
+	operation := statement
+	if i := strings.IndexByte(statement, ' '); i > 0 {
+		operation = statement[:i]
+	}
+	if len(statement) > 1000 {
+		statement = statement[:1000]
+	}
+	return tracing.StartChild(t.ctx, "db."+strings.ToLower(operation),
+		tracing.WithKind(tracing.SpanKindClient),
+		tracing.WithAttributes(
+			tracing.String("db.system", string(dialectOf(t.DBTX))),
+			tracing.String("db.operation", strings.ToUpper(operation)),
+			tracing.String("db.statement", statement),
+		),
+	)
+}
+
+func (t *tracedDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := t.span(query)
+	defer span.End()
+	result, err := t.DBTX.ExecContext(ctx, query, args...)
+	span.RecordError(err)
+	return result, err
+}
+
+func (t *tracedDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := t.span(query)
+	defer span.End()
+	rows, err := t.DBTX.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+	return rows, err
+}
+
+func (t *tracedDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, span := t.span(query)
+	defer span.End()
+	row := t.DBTX.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != sql.ErrNoRows {
+		span.RecordError(err)
+	}
+	return row
+}
+
+func (t *tracedDB) Prepare(query string) (*sql.Stmt, error) {
+	return t.DBTX.PrepareContext(t.ctx, query)
+}
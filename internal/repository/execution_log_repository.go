@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// ExecutionLogRepository 执行日志仓储接口
+type ExecutionLogRepository interface {
+	Create(log *models.ExecutionLog) error
+	// ListByJobTaskID 获取作业任务 ID 大于 afterID 的日志行，limit <= 0 表示不限制
+	ListByJobTaskID(jobTaskID, afterID int64, limit int) ([]models.ExecutionLog, error)
+}
+
+type executionLogRepository struct {
+	db DBTX
+}
+
+// NewExecutionLogRepository 创建执行日志仓储
+func NewExecutionLogRepository(db *sql.DB) ExecutionLogRepository {
+	return &executionLogRepository{db: newDB(db)}
+}
+
+// Create 写入日志行
+func (r *executionLogRepository) Create(log *models.ExecutionLog) error {
+	query := `
+		INSERT INTO execution_logs (job_task_id, level, message, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query, log.JobTaskID, log.Level, log.Message, log.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create execution log: %w", err)
+	}
+
+	log.ID = id
+	return nil
+}
+
+// ListByJobTaskID 获取作业任务的日志行
+func (r *executionLogRepository) ListByJobTaskID(jobTaskID, afterID int64, limit int) ([]models.ExecutionLog, error) {
+	query := `
+		SELECT id, job_task_id, level, message, created_at
+		FROM execution_logs
+		WHERE job_task_id = ? AND id > ?
+		ORDER BY id ASC
+	`
+	args := []interface{}{jobTaskID, afterID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list execution logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.ExecutionLog{}
+	for rows.Next() {
+		var log models.ExecutionLog
+		if err := rows.Scan(&log.ID, &log.JobTaskID, &log.Level, &log.Message, &log.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan execution log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
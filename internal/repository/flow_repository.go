@@ -11,37 +11,50 @@ import (
 type FlowRepository interface {
 	Create(flow *models.Flow) error
 	GetByID(id int64) (*models.Flow, error)
-	List(limit, offset int) ([]models.Flow, error)
+	List(filter models.FlowFilter) ([]models.Flow, *models.Page, error)
 	Update(flow *models.Flow) error
 	Delete(id int64) error
 	GetFlowWithTasks(flowID int64) (*models.Flow, []models.FlowTask, error)
+	// ListVersions 获取同名流程的所有版本，按 ID 升序
+	ListVersions(name string) ([]models.Flow, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) FlowRepository
+	// WithProject 返回只读写指定项目数据的仓储，projectID 为 0 表示不限项目
+	WithProject(projectID int64) FlowRepository
 }
 
 type flowRepository struct {
-	db *sql.DB
+	db        DBTX
+	projectID int64
 }
 
 // NewFlowRepository 创建流程仓储
 func NewFlowRepository(db *sql.DB) FlowRepository {
-	return &flowRepository{db: db}
+	return &flowRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *flowRepository) WithTx(tx *sql.Tx) FlowRepository {
+	return &flowRepository{db: withTx(r.db, tx), projectID: r.projectID}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *flowRepository) WithProject(projectID int64) FlowRepository {
+	return &flowRepository{db: r.db, projectID: projectID}
 }
 
 // Create 创建流程
 func (r *flowRepository) Create(flow *models.Flow) error {
 	query := `
-		INSERT INTO flows (name, description, version, is_active, created_by)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO flows (project_id, name, description, version, input_keys, is_active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, flow.Name, flow.Description, flow.Version, flow.IsActive, flow.CreatedBy)
+	flow.ProjectID = projectForCreate(flow.ProjectID, r.projectID)
+	id, err := insertID(r.db, query, flow.ProjectID, flow.Name, flow.Description, flow.Version, flow.Inputs, flow.IsActive, flow.CreatedBy)
 	if err != nil {
 		return fmt.Errorf("failed to create flow: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	flow.ID = id
 	return nil
 }
@@ -49,13 +62,13 @@ func (r *flowRepository) Create(flow *models.Flow) error {
 // GetByID 根据ID获取流程
 func (r *flowRepository) GetByID(id int64) (*models.Flow, error) {
 	query := `
-		SELECT id, name, description, version, is_active, created_by, created_at, updated_at
+		SELECT id, project_id, name, description, version, input_keys, is_active, created_by, created_at, updated_at
 		FROM flows
-		WHERE id = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
 	flow := &models.Flow{}
-	err := r.db.QueryRow(query, id).Scan(
-		&flow.ID, &flow.Name, &flow.Description, &flow.Version,
+	err := r.db.QueryRow(query, projectArgs(r.projectID, id)...).Scan(
+		&flow.ID, &flow.ProjectID, &flow.Name, &flow.Description, &flow.Version, &flow.Inputs,
 		&flow.IsActive, &flow.CreatedBy, &flow.CreatedAt, &flow.UpdatedAt,
 	)
 	if err != nil {
@@ -68,18 +81,46 @@ func (r *flowRepository) GetByID(id int64) (*models.Flow, error) {
 	return flow, nil
 }
 
-// List 获取流程列表
-func (r *flowRepository) List(limit, offset int) ([]models.Flow, error) {
+// flowSortColumns 流程列表允许的排序字段
+var flowSortColumns = map[string]sortColumn{
+	"id":         {"id", kindInt},
+	"name":       {"name", kindString},
+	"created_at": {"created_at", kindTime},
+	"updated_at": {"updated_at", kindTime},
+}
+
+// List 按条件分页查询流程列表
+func (r *flowRepository) List(filter models.FlowFilter) ([]models.Flow, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, flowSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := newListBuilder(r.db, r.projectID)
+	b.common("name", filter.ListQuery)
+	if filter.CreatedBy > 0 {
+		b.add("created_by = ?", filter.CreatedBy)
+	}
+	if filter.IsActive != nil {
+		b.add("is_active = ?", *filter.IsActive)
+	}
+	total, err := b.count(r.db, "flows")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.keyset(spec, filter.Cursor); err != nil {
+		return nil, nil, err
+	}
+
+	page, limit, offset := pageArgs(filter.ListQuery)
 	query := `
-		SELECT id, name, description, version, is_active, created_by, created_at, updated_at
-		FROM flows
-		WHERE is_active = 1
-		ORDER BY id DESC
+		SELECT id, project_id, name, description, version, input_keys, is_active, created_by, created_at, updated_at
+		FROM flows` + b.where() + spec.orderBy() + `
 		LIMIT ? OFFSET ?
 	`
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.Query(query, append(b.args, limit, offset)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list flows: %w", err)
+		return nil, nil, fmt.Errorf("failed to list flows: %w", err)
 	}
 	defer rows.Close()
 
@@ -87,25 +128,34 @@ func (r *flowRepository) List(limit, offset int) ([]models.Flow, error) {
 	for rows.Next() {
 		var flow models.Flow
 		if err := rows.Scan(
-			&flow.ID, &flow.Name, &flow.Description, &flow.Version,
+			&flow.ID, &flow.ProjectID, &flow.Name, &flow.Description, &flow.Version, &flow.Inputs,
 			&flow.IsActive, &flow.CreatedBy, &flow.CreatedAt, &flow.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan flow: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan flow: %w", err)
 		}
 		flows = append(flows, flow)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list flows: %w", err)
+	}
 
-	return flows, nil
+	page.Total = total
+	if len(flows) > page.Limit {
+		flows = flows[:page.Limit]
+		last := flows[len(flows)-1]
+		page.NextCursor = encodeCursor(spec, nameOrTimeSortValue(spec.field, last.ID, last.Name, last.CreatedAt, last.UpdatedAt), last.ID)
+	}
+	return flows, &page, nil
 }
 
 // Update 更新流程
 func (r *flowRepository) Update(flow *models.Flow) error {
 	query := `
 		UPDATE flows
-		SET name = ?, description = ?, version = ?, is_active = ?
-		WHERE id = ?
+		SET name = ?, description = ?, version = ?, input_keys = ?, is_active = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
-	_, err := r.db.Exec(query, flow.Name, flow.Description, flow.Version, flow.IsActive, flow.ID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, flow.Name, flow.Description, flow.Version, flow.Inputs, flow.IsActive, flow.ID)...)
 	if err != nil {
 		return fmt.Errorf("failed to update flow: %w", err)
 	}
@@ -115,8 +165,8 @@ func (r *flowRepository) Update(flow *models.Flow) error {
 
 // Delete 删除流程
 func (r *flowRepository) Delete(id int64) error {
-	query := `DELETE FROM flows WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+	query := `DELETE FROM flows WHERE id = ?` + projectClause(r.projectID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, id)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete flow: %w", err)
 	}
@@ -124,6 +174,38 @@ func (r *flowRepository) Delete(id int64) error {
 	return nil
 }
 
+// ListVersions 获取同名流程的所有版本
+func (r *flowRepository) ListVersions(name string) ([]models.Flow, error) {
+	query := `
+		SELECT id, project_id, name, description, version, input_keys, is_active, created_by, created_at, updated_at
+		FROM flows
+		WHERE name = ?` + projectClause(r.projectID) + `
+		ORDER BY id ASC
+	`
+	rows, err := r.db.Query(query, projectArgs(r.projectID, name)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flow versions: %w", err)
+	}
+	defer rows.Close()
+
+	var flows []models.Flow
+	for rows.Next() {
+		var flow models.Flow
+		if err := rows.Scan(
+			&flow.ID, &flow.ProjectID, &flow.Name, &flow.Description, &flow.Version, &flow.Inputs,
+			&flow.IsActive, &flow.CreatedBy, &flow.CreatedAt, &flow.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan flow: %w", err)
+		}
+		flows = append(flows, flow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list flow versions: %w", err)
+	}
+
+	return flows, nil
+}
+
 // GetFlowWithTasks 获取流程及其关联的任务
 func (r *flowRepository) GetFlowWithTasks(flowID int64) (*models.Flow, []models.FlowTask, error) {
 	// 获取流程
@@ -135,8 +217,8 @@ func (r *flowRepository) GetFlowWithTasks(flowID int64) (*models.Flow, []models.
 	// 获取流程任务
 	query := `
 		SELECT ft.id, ft.flow_id, ft.task_id, ft.sequence, ft.is_optional,
-		       ft.allow_rollback, ft.condition_config, ft.created_at, ft.updated_at,
-		       t.id, t.name, t.description, t.task_type, t.config, t.is_active,
+		       ft.allow_rollback, ft.condition_config, ft.config_overrides, ft.created_at, ft.updated_at,
+		       t.id, t.project_id, t.name, t.description, t.task_type, t.config, t.is_active,
 		       t.created_at, t.updated_at
 		FROM flow_tasks ft
 		INNER JOIN tasks t ON ft.task_id = t.id
@@ -156,8 +238,8 @@ func (r *flowRepository) GetFlowWithTasks(flowID int64) (*models.Flow, []models.
 		if err := rows.Scan(
 			&flowTask.ID, &flowTask.FlowID, &flowTask.TaskID, &flowTask.Sequence,
 			&flowTask.IsOptional, &flowTask.AllowRollback, &flowTask.ConditionConfig,
-			&flowTask.CreatedAt, &flowTask.UpdatedAt,
-			&task.ID, &task.Name, &task.Description, &task.TaskType,
+			&flowTask.ConfigOverrides, &flowTask.CreatedAt, &flowTask.UpdatedAt,
+			&task.ID, &task.ProjectID, &task.Name, &task.Description, &task.TaskType,
 			&task.Config, &task.IsActive, &task.CreatedAt, &task.UpdatedAt,
 		); err != nil {
 			return nil, nil, fmt.Errorf("failed to scan flow task: %w", err)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,36 +16,46 @@ type FlowTaskRepository interface {
 	Update(flowTask *models.FlowTask) error
 	Delete(id int64) error
 	DeleteByFlowID(flowID int64) error
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) FlowTaskRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) FlowTaskRepository
 }
 
 type flowTaskRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewFlowTaskRepository 创建流程任务仓储
 func NewFlowTaskRepository(db *sql.DB) FlowTaskRepository {
-	return &flowTaskRepository{db: db}
+	return &flowTaskRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *flowTaskRepository) WithTx(tx *sql.Tx) FlowTaskRepository {
+	return &flowTaskRepository{db: withTx(r.db, tx)}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *flowTaskRepository) WithContext(ctx context.Context) FlowTaskRepository {
+	return &flowTaskRepository{db: withContext(ctx, r.db)}
 }
 
 // Create 创建流程任务
 func (r *flowTaskRepository) Create(flowTask *models.FlowTask) error {
 	query := `
-		INSERT INTO flow_tasks (flow_id, task_id, sequence, is_optional, allow_rollback, condition_config)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO flow_tasks (flow_id, task_id, sequence, is_optional, allow_rollback, condition_config, config_overrides)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query,
+	id, err := insertID(r.db, query,
 		flowTask.FlowID, flowTask.TaskID, flowTask.Sequence,
 		flowTask.IsOptional, flowTask.AllowRollback, flowTask.ConditionConfig,
+		flowTask.ConfigOverrides,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create flow task: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	flowTask.ID = id
 	return nil
 }
@@ -52,7 +63,8 @@ func (r *flowTaskRepository) Create(flowTask *models.FlowTask) error {
 // GetByID 根据ID获取流程任务
 func (r *flowTaskRepository) GetByID(id int64) (*models.FlowTask, error) {
 	query := `
-		SELECT id, flow_id, task_id, sequence, is_optional, allow_rollback, condition_config, created_at, updated_at
+		SELECT id, flow_id, task_id, sequence, is_optional, allow_rollback, condition_config, config_overrides,
+		       created_at, updated_at
 		FROM flow_tasks
 		WHERE id = ?
 	`
@@ -60,7 +72,7 @@ func (r *flowTaskRepository) GetByID(id int64) (*models.FlowTask, error) {
 	err := r.db.QueryRow(query, id).Scan(
 		&flowTask.ID, &flowTask.FlowID, &flowTask.TaskID, &flowTask.Sequence,
 		&flowTask.IsOptional, &flowTask.AllowRollback, &flowTask.ConditionConfig,
-		&flowTask.CreatedAt, &flowTask.UpdatedAt,
+		&flowTask.ConfigOverrides, &flowTask.CreatedAt, &flowTask.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,7 +87,8 @@ func (r *flowTaskRepository) GetByID(id int64) (*models.FlowTask, error) {
 // GetByFlowID 根据流程ID获取所有流程任务
 func (r *flowTaskRepository) GetByFlowID(flowID int64) ([]models.FlowTask, error) {
 	query := `
-		SELECT id, flow_id, task_id, sequence, is_optional, allow_rollback, condition_config, created_at, updated_at
+		SELECT id, flow_id, task_id, sequence, is_optional, allow_rollback, condition_config, config_overrides,
+		       created_at, updated_at
 		FROM flow_tasks
 		WHERE flow_id = ?
 		ORDER BY sequence ASC
@@ -92,7 +105,7 @@ func (r *flowTaskRepository) GetByFlowID(flowID int64) ([]models.FlowTask, error
 		if err := rows.Scan(
 			&flowTask.ID, &flowTask.FlowID, &flowTask.TaskID, &flowTask.Sequence,
 			&flowTask.IsOptional, &flowTask.AllowRollback, &flowTask.ConditionConfig,
-			&flowTask.CreatedAt, &flowTask.UpdatedAt,
+			&flowTask.ConfigOverrides, &flowTask.CreatedAt, &flowTask.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan flow task: %w", err)
 		}
@@ -106,12 +119,13 @@ func (r *flowTaskRepository) GetByFlowID(flowID int64) ([]models.FlowTask, error
 func (r *flowTaskRepository) Update(flowTask *models.FlowTask) error {
 	query := `
 		UPDATE flow_tasks
-		SET task_id = ?, sequence = ?, is_optional = ?, allow_rollback = ?, condition_config = ?
+		SET task_id = ?, sequence = ?, is_optional = ?, allow_rollback = ?, condition_config = ?,
+		    config_overrides = ?
 		WHERE id = ?
 	`
 	_, err := r.db.Exec(query,
 		flowTask.TaskID, flowTask.Sequence, flowTask.IsOptional,
-		flowTask.AllowRollback, flowTask.ConditionConfig, flowTask.ID,
+		flowTask.AllowRollback, flowTask.ConditionConfig, flowTask.ConfigOverrides, flowTask.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update flow task: %w", err)
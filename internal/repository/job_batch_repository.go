@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// JobBatchRepository 作业批次仓储接口
+type JobBatchRepository interface {
+	Create(batch *models.JobBatch) error
+	GetByID(id int64) (*models.JobBatch, error)
+	// CountJobsByStatus 统计批次中各状态的作业数量
+	CountJobsByStatus(batchID int64) (map[models.JobStatus]int, error)
+	// ListJobIDs 返回批次中处于任一指定状态的作业 ID（按 ID 升序），未指定状态时返回全部
+	ListJobIDs(batchID int64, statuses ...models.JobStatus) ([]int64, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) JobBatchRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) JobBatchRepository
+	// WithProject 返回只读写指定项目数据的仓储，projectID 为 0 表示不限项目
+	WithProject(projectID int64) JobBatchRepository
+}
+
+type jobBatchRepository struct {
+	db        DBTX
+	projectID int64
+}
+
+// NewJobBatchRepository 创建作业批次仓储
+func NewJobBatchRepository(db *sql.DB) JobBatchRepository {
+	return &jobBatchRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *jobBatchRepository) WithTx(tx *sql.Tx) JobBatchRepository {
+	return &jobBatchRepository{db: withTx(r.db, tx), projectID: r.projectID}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *jobBatchRepository) WithContext(ctx context.Context) JobBatchRepository {
+	return &jobBatchRepository{db: withContext(ctx, r.db), projectID: r.projectID}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *jobBatchRepository) WithProject(projectID int64) JobBatchRepository {
+	return &jobBatchRepository{db: r.db, projectID: projectID}
+}
+
+// Create 创建作业批次
+func (r *jobBatchRepository) Create(batch *models.JobBatch) error {
+	query := `
+		INSERT INTO job_batches (project_id, flow_id, name, total_jobs, auto_execute, concurrency, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	batch.ProjectID = projectForCreate(batch.ProjectID, r.projectID)
+	id, err := insertID(r.db, query, batch.ProjectID, batch.FlowID, batch.Name, batch.TotalJobs,
+		batch.AutoExecute, batch.Concurrency, batch.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create job batch: %w", err)
+	}
+
+	batch.ID = id
+	return nil
+}
+
+// GetByID 根据ID获取作业批次
+func (r *jobBatchRepository) GetByID(id int64) (*models.JobBatch, error) {
+	query := `
+		SELECT id, project_id, flow_id, name, total_jobs, auto_execute, concurrency,
+		       created_by, created_at, updated_at
+		FROM job_batches
+		WHERE id = ?` + projectClause(r.projectID) + `
+	`
+	batch := &models.JobBatch{}
+	err := r.db.QueryRow(query, projectArgs(r.projectID, id)...).Scan(
+		&batch.ID, &batch.ProjectID, &batch.FlowID, &batch.Name, &batch.TotalJobs, &batch.AutoExecute,
+		&batch.Concurrency, &batch.CreatedBy, &batch.CreatedAt, &batch.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("job batch not found")
+		}
+		return nil, fmt.Errorf("failed to get job batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+// CountJobsByStatus 统计批次中各状态的作业数量
+func (r *jobBatchRepository) CountJobsByStatus(batchID int64) (map[models.JobStatus]int, error) {
+	query := `SELECT status, COUNT(*) FROM jobs WHERE batch_id = ?` + projectClause(r.projectID) + ` GROUP BY status`
+	rows, err := r.db.Query(query, projectArgs(r.projectID, batchID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch jobs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[models.JobStatus]int)
+	for rows.Next() {
+		var status models.JobStatus
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan batch job count: %w", err)
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// ListJobIDs 返回批次中处于任一指定状态的作业 ID
+func (r *jobBatchRepository) ListJobIDs(batchID int64, statuses ...models.JobStatus) ([]int64, error) {
+	b := newListBuilder(r.db, r.projectID)
+	b.add("batch_id = ?", batchID)
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	b.in("status", values)
+
+	rows, err := r.db.Query("SELECT id FROM jobs"+b.where()+" ORDER BY id ASC", b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan batch job: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
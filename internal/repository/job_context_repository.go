@@ -1,33 +1,55 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 )
 
 // JobContextRepository 作业上下文仓储接口
 type JobContextRepository interface {
-	GetByJobID(jobID int64) (map[string]string, error)
-	Set(jobID int64, key, value string) error
-	Get(jobID int64, key string) (string, error)
-	Delete(jobID int64, key string) error
+	// GetByJobID 获取作业的所有上下文数据（作用域 -> 键 -> 值）
+	GetByJobID(jobID int64) (map[string]map[string]interface{}, error)
+	GetScope(jobID int64, scope string) (map[string]interface{}, error)
+	Set(jobID int64, scope, key string, value interface{}) error
+	Get(jobID int64, scope, key string) (interface{}, error)
+	Delete(jobID int64, scope, key string) error
+	// DeleteScope 删除单个作用域的所有上下文数据
+	DeleteScope(jobID int64, scope string) error
 	DeleteByJobID(jobID int64) error
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) JobContextRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) JobContextRepository
 }
 
 type jobContextRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewJobContextRepository 创建作业上下文仓储
 func NewJobContextRepository(db *sql.DB) JobContextRepository {
-	return &jobContextRepository{db: db}
+	return &jobContextRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *jobContextRepository) WithTx(tx *sql.Tx) JobContextRepository {
+	return &jobContextRepository{db: withTx(r.db, tx)}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *jobContextRepository) WithContext(ctx context.Context) JobContextRepository {
+	return &jobContextRepository{db: withContext(ctx, r.db)}
 }
 
 // GetByJobID 获取作业的所有上下文数据
-func (r *jobContextRepository) GetByJobID(jobID int64) (map[string]string, error) {
+func (r *jobContextRepository) GetByJobID(jobID int64) (map[string]map[string]interface{}, error) {
 	query := `
-		SELECT context_key, context_value
+		SELECT scope, context_key, context_value
 		FROM job_context
 		WHERE job_id = ?
+		ORDER BY id ASC
 	`
 
 	rows, err := r.db.Query(query, jobID)
@@ -36,12 +58,51 @@ func (r *jobContextRepository) GetByJobID(jobID int64) (map[string]string, error
 	}
 	defer rows.Close()
 
-	context := make(map[string]string)
+	context := make(map[string]map[string]interface{})
 	for rows.Next() {
-		var key, value string
-		if err := rows.Scan(&key, &value); err != nil {
+		var scope, key string
+		var raw []byte
+		if err := rows.Scan(&scope, &key, &raw); err != nil {
 			return nil, err
 		}
+		value, err := decodeContextValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode context %s.%s: %w", scope, key, err)
+		}
+		if context[scope] == nil {
+			context[scope] = make(map[string]interface{})
+		}
+		context[scope][key] = value
+	}
+
+	return context, rows.Err()
+}
+
+// GetScope 获取单个作用域的上下文数据
+func (r *jobContextRepository) GetScope(jobID int64, scope string) (map[string]interface{}, error) {
+	query := `
+		SELECT context_key, context_value
+		FROM job_context
+		WHERE job_id = ? AND scope = ?
+	`
+
+	rows, err := r.db.Query(query, jobID, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	context := make(map[string]interface{})
+	for rows.Next() {
+		var key string
+		var raw []byte
+		if err := rows.Scan(&key, &raw); err != nil {
+			return nil, err
+		}
+		value, err := decodeContextValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode context %s.%s: %w", scope, key, err)
+		}
 		context[key] = value
 	}
 
@@ -49,38 +110,52 @@ func (r *jobContextRepository) GetByJobID(jobID int64) (map[string]string, error
 }
 
 // Set 设置上下文数据
-func (r *jobContextRepository) Set(jobID int64, key, value string) error {
+func (r *jobContextRepository) Set(jobID int64, scope, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode context %s.%s: %w", scope, key, err)
+	}
+
 	query := `
-		INSERT INTO job_context (job_id, context_key, context_value)
-		VALUES (?, ?, ?)
-		ON DUPLICATE KEY UPDATE context_value = VALUES(context_value)
-	`
+		INSERT INTO job_context (job_id, scope, context_key, context_value)
+		VALUES (?, ?, ?, ?)
+	` + dialectOf(r.db).Upsert([]string{"job_id", "scope", "context_key"}, "context_value")
 
-	_, err := r.db.Exec(query, jobID, key, value)
+	_, err = r.db.Exec(query, jobID, scope, key, raw)
 	return err
 }
 
 // Get 获取单个上下文值
-func (r *jobContextRepository) Get(jobID int64, key string) (string, error) {
+func (r *jobContextRepository) Get(jobID int64, scope, key string) (interface{}, error) {
 	query := `
 		SELECT context_value
 		FROM job_context
-		WHERE job_id = ? AND context_key = ?
+		WHERE job_id = ? AND scope = ? AND context_key = ?
 	`
 
-	var value string
-	err := r.db.QueryRow(query, jobID, key).Scan(&value)
+	var raw []byte
+	err := r.db.QueryRow(query, jobID, scope, key).Scan(&raw)
 	if err == sql.ErrNoRows {
-		return "", nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return value, err
+	return decodeContextValue(raw)
 }
 
 // Delete 删除单个上下文键
-func (r *jobContextRepository) Delete(jobID int64, key string) error {
-	query := `DELETE FROM job_context WHERE job_id = ? AND context_key = ?`
-	_, err := r.db.Exec(query, jobID, key)
+func (r *jobContextRepository) Delete(jobID int64, scope, key string) error {
+	query := `DELETE FROM job_context WHERE job_id = ? AND scope = ? AND context_key = ?`
+	_, err := r.db.Exec(query, jobID, scope, key)
+	return err
+}
+
+// DeleteScope 删除单个作用域的所有上下文数据
+func (r *jobContextRepository) DeleteScope(jobID int64, scope string) error {
+	query := `DELETE FROM job_context WHERE job_id = ? AND scope = ?`
+	_, err := r.db.Exec(query, jobID, scope)
 	return err
 }
 
@@ -90,3 +165,15 @@ func (r *jobContextRepository) DeleteByJobID(jobID int64) error {
 	_, err := r.db.Exec(query, jobID)
 	return err
 }
+
+// decodeContextValue 解析 JSON 上下文值
+func decodeContextValue(raw []byte) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
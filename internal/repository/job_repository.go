@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -11,37 +12,55 @@ import (
 type JobRepository interface {
 	Create(job *models.Job) error
 	GetByID(id int64) (*models.Job, error)
-	List(limit, offset int) ([]models.Job, error)
+	List(filter models.JobFilter) ([]models.Job, *models.Page, error)
 	Update(job *models.Job) error
 	UpdateStatus(jobID int64, status models.JobStatus) error
 	GetJobWithTasks(jobID int64) (*models.Job, []models.JobTask, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) JobRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) JobRepository
+	// WithProject 返回只读写指定项目数据的仓储，projectID 为 0 表示不限项目
+	WithProject(projectID int64) JobRepository
 }
 
 type jobRepository struct {
-	db *sql.DB
+	db        DBTX
+	projectID int64
 }
 
 // NewJobRepository 创建作业仓储
 func NewJobRepository(db *sql.DB) JobRepository {
-	return &jobRepository{db: db}
+	return &jobRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *jobRepository) WithTx(tx *sql.Tx) JobRepository {
+	return &jobRepository{db: withTx(r.db, tx), projectID: r.projectID}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *jobRepository) WithContext(ctx context.Context) JobRepository {
+	return &jobRepository{db: withContext(ctx, r.db), projectID: r.projectID}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *jobRepository) WithProject(projectID int64) JobRepository {
+	return &jobRepository{db: r.db, projectID: projectID}
 }
 
 // Create 创建作业
 func (r *jobRepository) Create(job *models.Job) error {
 	query := `
-		INSERT INTO jobs (flow_id, job_name, status, current_task_seq, created_by)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO jobs (project_id, flow_id, batch_id, rerun_of, job_name, status, current_task_seq, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, job.FlowID, job.JobName, job.Status, job.CurrentTaskSeq, job.CreatedBy)
+	job.ProjectID = projectForCreate(job.ProjectID, r.projectID)
+	id, err := insertID(r.db, query, job.ProjectID, job.FlowID, job.BatchID, job.RerunOf, job.JobName, job.Status, job.CurrentTaskSeq, job.CreatedBy)
 	if err != nil {
 		return fmt.Errorf("failed to create job: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	job.ID = id
 	return nil
 }
@@ -49,14 +68,14 @@ func (r *jobRepository) Create(job *models.Job) error {
 // GetByID 根据ID获取作业
 func (r *jobRepository) GetByID(id int64) (*models.Job, error) {
 	query := `
-		SELECT id, flow_id, job_name, status, current_task_seq, started_at, completed_at,
+		SELECT id, project_id, flow_id, batch_id, rerun_of, job_name, status, current_task_seq, started_at, completed_at,
 		       created_by, created_at, updated_at
 		FROM jobs
-		WHERE id = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
 	job := &models.Job{}
-	err := r.db.QueryRow(query, id).Scan(
-		&job.ID, &job.FlowID, &job.JobName, &job.Status, &job.CurrentTaskSeq,
+	err := r.db.QueryRow(query, projectArgs(r.projectID, id)...).Scan(
+		&job.ID, &job.ProjectID, &job.FlowID, &job.BatchID, &job.RerunOf, &job.JobName, &job.Status, &job.CurrentTaskSeq,
 		&job.StartedAt, &job.CompletedAt, &job.CreatedBy, &job.CreatedAt, &job.UpdatedAt,
 	)
 	if err != nil {
@@ -69,18 +88,59 @@ func (r *jobRepository) GetByID(id int64) (*models.Job, error) {
 	return job, nil
 }
 
-// List 获取作业列表
-func (r *jobRepository) List(limit, offset int) ([]models.Job, error) {
+// jobSortColumns 作业列表允许的排序字段
+var jobSortColumns = map[string]sortColumn{
+	"id":         {"id", kindInt},
+	"name":       {"job_name", kindString},
+	"status":     {"status", kindString},
+	"created_at": {"created_at", kindTime},
+	"updated_at": {"updated_at", kindTime},
+}
+
+// List 按条件分页查询作业列表
+func (r *jobRepository) List(filter models.JobFilter) ([]models.Job, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, jobSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := newListBuilder(r.db, r.projectID)
+	b.common("job_name", filter.ListQuery)
+	statuses := make([]string, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		statuses[i] = string(status)
+	}
+	b.in("status", statuses)
+	if filter.FlowID > 0 {
+		b.add("flow_id = ?", filter.FlowID)
+	}
+	if filter.BatchID > 0 {
+		b.add("batch_id = ?", filter.BatchID)
+	}
+	if filter.RerunOf > 0 {
+		b.add("rerun_of = ?", filter.RerunOf)
+	}
+	if filter.CreatedBy > 0 {
+		b.add("created_by = ?", filter.CreatedBy)
+	}
+	total, err := b.count(r.db, "jobs")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.keyset(spec, filter.Cursor); err != nil {
+		return nil, nil, err
+	}
+
+	page, limit, offset := pageArgs(filter.ListQuery)
 	query := `
-		SELECT id, flow_id, job_name, status, current_task_seq, started_at, completed_at,
+		SELECT id, project_id, flow_id, batch_id, rerun_of, job_name, status, current_task_seq, started_at, completed_at,
 		       created_by, created_at, updated_at
-		FROM jobs
-		ORDER BY id DESC
+		FROM jobs` + b.where() + spec.orderBy() + `
 		LIMIT ? OFFSET ?
 	`
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.Query(query, append(b.args, limit, offset)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list jobs: %w", err)
+		return nil, nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
 	defer rows.Close()
 
@@ -88,15 +148,39 @@ func (r *jobRepository) List(limit, offset int) ([]models.Job, error) {
 	for rows.Next() {
 		var job models.Job
 		if err := rows.Scan(
-			&job.ID, &job.FlowID, &job.JobName, &job.Status, &job.CurrentTaskSeq,
+			&job.ID, &job.ProjectID, &job.FlowID, &job.BatchID, &job.RerunOf, &job.JobName, &job.Status, &job.CurrentTaskSeq,
 			&job.StartedAt, &job.CompletedAt, &job.CreatedBy, &job.CreatedAt, &job.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan job: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 		jobs = append(jobs, job)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
 
-	return jobs, nil
+	page.Total = total
+	if len(jobs) > page.Limit {
+		jobs = jobs[:page.Limit]
+		last := jobs[len(jobs)-1]
+		page.NextCursor = encodeCursor(spec, jobSortValue(&last, spec.field), last.ID)
+	}
+	return jobs, &page, nil
+}
+
+// jobSortValue 返回作业在排序字段上的值，用于生成游标
+func jobSortValue(job *models.Job, field string) interface{} {
+	switch field {
+	case "name":
+		return job.JobName
+	case "status":
+		return string(job.Status)
+	case "created_at":
+		return job.CreatedAt
+	case "updated_at":
+		return job.UpdatedAt
+	}
+	return job.ID
 }
 
 // Update 更新作业
@@ -104,9 +188,9 @@ func (r *jobRepository) Update(job *models.Job) error {
 	query := `
 		UPDATE jobs
 		SET status = ?, current_task_seq = ?, started_at = ?, completed_at = ?
-		WHERE id = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
-	_, err := r.db.Exec(query, job.Status, job.CurrentTaskSeq, job.StartedAt, job.CompletedAt, job.ID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, job.Status, job.CurrentTaskSeq, job.StartedAt, job.CompletedAt, job.ID)...)
 	if err != nil {
 		return fmt.Errorf("failed to update job: %w", err)
 	}
@@ -116,8 +200,8 @@ func (r *jobRepository) Update(job *models.Job) error {
 
 // UpdateStatus 更新作业状态
 func (r *jobRepository) UpdateStatus(jobID int64, status models.JobStatus) error {
-	query := `UPDATE jobs SET status = ? WHERE id = ?`
-	_, err := r.db.Exec(query, status, jobID)
+	query := `UPDATE jobs SET status = ? WHERE id = ?` + projectClause(r.projectID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, status, jobID)...)
 	if err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
@@ -136,9 +220,9 @@ func (r *jobRepository) GetJobWithTasks(jobID int64) (*models.Job, []models.JobT
 	// 获取作业任务
 	query := `
 		SELECT jt.id, jt.job_id, jt.flow_task_id, jt.task_id, jt.sequence, jt.status,
-		       jt.is_skipped, jt.executor_id, jt.result, jt.error_message,
+		       jt.is_skipped, jt.executor_id, jt.result, COALESCE(jt.error_message, ''),
 		       jt.started_at, jt.completed_at, jt.created_at, jt.updated_at,
-		       t.id, t.name, t.description, t.task_type, t.config, t.is_active,
+		       t.id, t.project_id, t.name, t.description, t.task_type, t.config, t.is_active,
 		       t.created_at, t.updated_at
 		FROM job_tasks jt
 		INNER JOIN tasks t ON jt.task_id = t.id
@@ -160,7 +244,7 @@ func (r *jobRepository) GetJobWithTasks(jobID int64) (*models.Job, []models.JobT
 			&jobTask.Sequence, &jobTask.Status, &jobTask.IsSkipped, &jobTask.ExecutorID,
 			&jobTask.Result, &jobTask.ErrorMessage, &jobTask.StartedAt, &jobTask.CompletedAt,
 			&jobTask.CreatedAt, &jobTask.UpdatedAt,
-			&task.ID, &task.Name, &task.Description, &task.TaskType,
+			&task.ID, &task.ProjectID, &task.Name, &task.Description, &task.TaskType,
 			&task.Config, &task.IsActive, &task.CreatedAt, &task.UpdatedAt,
 		); err != nil {
 			return nil, nil, fmt.Errorf("failed to scan job task: %w", err)
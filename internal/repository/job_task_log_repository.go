@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// JobTaskLogRepository 作业任务日志仓储接口
+type JobTaskLogRepository interface {
+	Create(log *models.JobTaskLog) error
+	ListByJobTaskID(jobTaskID int64) ([]models.JobTaskLog, error)
+	ListByJobID(jobID int64) ([]models.JobTaskLog, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) JobTaskLogRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) JobTaskLogRepository
+}
+
+type jobTaskLogRepository struct {
+	db DBTX
+}
+
+// NewJobTaskLogRepository 创建作业任务日志仓储
+func NewJobTaskLogRepository(db *sql.DB) JobTaskLogRepository {
+	return &jobTaskLogRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *jobTaskLogRepository) WithTx(tx *sql.Tx) JobTaskLogRepository {
+	return &jobTaskLogRepository{db: withTx(r.db, tx)}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *jobTaskLogRepository) WithContext(ctx context.Context) JobTaskLogRepository {
+	return &jobTaskLogRepository{db: withContext(ctx, r.db)}
+}
+
+// Create 写入作业任务日志
+func (r *jobTaskLogRepository) Create(log *models.JobTaskLog) error {
+	query := `
+		INSERT INTO job_task_logs (job_task_id, action, operator_id, message, metadata)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query, log.JobTaskID, log.Action, log.OperatorID, log.Message, log.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to create job task log: %w", err)
+	}
+
+	log.ID = id
+	return nil
+}
+
+// ListByJobTaskID 获取作业任务的日志（按时间顺序）
+func (r *jobTaskLogRepository) ListByJobTaskID(jobTaskID int64) ([]models.JobTaskLog, error) {
+	query := `
+		SELECT id, job_task_id, action, operator_id, message, metadata, created_at
+		FROM job_task_logs
+		WHERE job_task_id = ?
+		ORDER BY id ASC
+	`
+	return r.list(query, jobTaskID)
+}
+
+// ListByJobID 获取作业下所有任务的日志（按时间顺序）
+func (r *jobTaskLogRepository) ListByJobID(jobID int64) ([]models.JobTaskLog, error) {
+	query := `
+		SELECT l.id, l.job_task_id, l.action, l.operator_id, l.message, l.metadata, l.created_at
+		FROM job_task_logs l
+		INNER JOIN job_tasks jt ON l.job_task_id = jt.id
+		WHERE jt.job_id = ?
+		ORDER BY l.id ASC
+	`
+	return r.list(query, jobID)
+}
+
+func (r *jobTaskLogRepository) list(query string, args ...interface{}) ([]models.JobTaskLog, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job task logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.JobTaskLog{}
+	for rows.Next() {
+		var log models.JobTaskLog
+		var message sql.NullString
+		if err := rows.Scan(
+			&log.ID, &log.JobTaskID, &log.Action, &log.OperatorID,
+			&message, &log.Metadata, &log.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job task log: %w", err)
+		}
+		log.Message = message.String
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -16,15 +17,31 @@ type JobTaskRepository interface {
 	Update(jobTask *models.JobTask) error
 	UpdateStatus(id int64, status models.JobTaskStatus) error
 	BatchCreate(jobTasks []models.JobTask) error
+	// CountByStatus 统计处于指定作业状态下、指定状态的作业任务数量
+	CountByStatus(jobStatus models.JobStatus, status models.JobTaskStatus) (int64, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) JobTaskRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) JobTaskRepository
 }
 
 type jobTaskRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewJobTaskRepository 创建作业任务仓储
 func NewJobTaskRepository(db *sql.DB) JobTaskRepository {
-	return &jobTaskRepository{db: db}
+	return &jobTaskRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *jobTaskRepository) WithTx(tx *sql.Tx) JobTaskRepository {
+	return &jobTaskRepository{db: withTx(r.db, tx)}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *jobTaskRepository) WithContext(ctx context.Context) JobTaskRepository {
+	return &jobTaskRepository{db: withContext(ctx, r.db)}
 }
 
 // Create 创建作业任务
@@ -33,7 +50,7 @@ func (r *jobTaskRepository) Create(jobTask *models.JobTask) error {
 		INSERT INTO job_tasks (job_id, flow_task_id, task_id, sequence, status, is_skipped)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query,
+	id, err := insertID(r.db, query,
 		jobTask.JobID, jobTask.FlowTaskID, jobTask.TaskID,
 		jobTask.Sequence, jobTask.Status, jobTask.IsSkipped,
 	)
@@ -41,11 +58,6 @@ func (r *jobTaskRepository) Create(jobTask *models.JobTask) error {
 		return fmt.Errorf("failed to create job task: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	jobTask.ID = id
 	return nil
 }
@@ -54,7 +66,7 @@ func (r *jobTaskRepository) Create(jobTask *models.JobTask) error {
 func (r *jobTaskRepository) GetByID(id int64) (*models.JobTask, error) {
 	query := `
 		SELECT id, job_id, flow_task_id, task_id, sequence, status, is_skipped,
-		       executor_id, result, error_message, started_at, completed_at,
+		       executor_id, result, COALESCE(error_message, ''), started_at, completed_at,
 		       created_at, updated_at
 		FROM job_tasks
 		WHERE id = ?
@@ -80,7 +92,7 @@ func (r *jobTaskRepository) GetByID(id int64) (*models.JobTask, error) {
 func (r *jobTaskRepository) GetByJobID(jobID int64) ([]models.JobTask, error) {
 	query := `
 		SELECT id, job_id, flow_task_id, task_id, sequence, status, is_skipped,
-		       executor_id, result, error_message, started_at, completed_at,
+		       executor_id, result, COALESCE(error_message, ''), started_at, completed_at,
 		       created_at, updated_at
 		FROM job_tasks
 		WHERE job_id = ?
@@ -113,7 +125,7 @@ func (r *jobTaskRepository) GetByJobID(jobID int64) ([]models.JobTask, error) {
 func (r *jobTaskRepository) GetBySequence(jobID int64, sequence int) (*models.JobTask, error) {
 	query := `
 		SELECT id, job_id, flow_task_id, task_id, sequence, status, is_skipped,
-		       executor_id, result, error_message, started_at, completed_at,
+		       executor_id, result, COALESCE(error_message, ''), started_at, completed_at,
 		       created_at, updated_at
 		FROM job_tasks
 		WHERE job_id = ? AND sequence = ?
@@ -171,41 +183,54 @@ func (r *jobTaskRepository) BatchCreate(jobTasks []models.JobTask) error {
 		return nil
 	}
 
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	// 未处于外部事务中时自行开启事务
+	db := r.db
+	var tx *sql.Tx
+	if sqlDB, ok := unwrapDB(r.db).(*sql.DB); ok {
+		var err error
+		tx, err = sqlDB.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		db = withTx(r.db, tx)
 	}
-	defer tx.Rollback()
 
 	query := `
 		INSERT INTO job_tasks (job_id, flow_task_id, task_id, sequence, status, is_skipped)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer stmt.Close()
-
 	for i := range jobTasks {
-		result, err := stmt.Exec(
+		id, err := insertID(db, query,
 			jobTasks[i].JobID, jobTasks[i].FlowTaskID, jobTasks[i].TaskID,
 			jobTasks[i].Sequence, jobTasks[i].Status, jobTasks[i].IsSkipped,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert job task: %w", err)
 		}
-
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get last insert id: %w", err)
-		}
 		jobTasks[i].ID = id
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
 	}
 
 	return nil
 }
+
+// CountByStatus 统计处于指定作业状态下、指定状态的作业任务数量
+func (r *jobTaskRepository) CountByStatus(jobStatus models.JobStatus, status models.JobTaskStatus) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM job_tasks jt
+		INNER JOIN jobs j ON jt.job_id = j.id
+		WHERE j.status = ? AND jt.status = ?
+	`
+	var count int64
+	if err := r.db.QueryRow(query, jobStatus, status).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count job tasks: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/pkg/database"
+)
+
+// ErrInvalidListQuery 列表查询参数（排序字段、游标）无效
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// valueKind 排序列的值类型，用于游标编解码
+type valueKind int
+
+const (
+	kindInt valueKind = iota
+	kindString
+	kindTime
+)
+
+// sortColumn 允许排序的列
+type sortColumn struct {
+	column string
+	kind   valueKind
+}
+
+// sortSpec 解析后的排序：field 为接口字段名，desc 表示降序，相同值按 id 同向排序
+type sortSpec struct {
+	field string
+	sortColumn
+	desc bool
+}
+
+// parseSort 解析 sort 参数，如 created_at、-created_at，空值为 -id
+func parseSort(s string, columns map[string]sortColumn) (sortSpec, error) {
+	if s == "" {
+		s = "-id"
+	}
+	spec := sortSpec{field: strings.TrimPrefix(s, "-"), desc: strings.HasPrefix(s, "-")}
+	col, ok := columns[spec.field]
+	if !ok {
+		fields := make([]string, 0, len(columns))
+		for f := range columns {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		return spec, fmt.Errorf("%w: unsupported sort field %q (allowed: %s)", ErrInvalidListQuery, spec.field, strings.Join(fields, ", "))
+	}
+	spec.sortColumn = col
+	return spec, nil
+}
+
+func (s sortSpec) String() string {
+	if s.desc {
+		return "-" + s.field
+	}
+	return s.field
+}
+
+// orderBy 返回 ORDER BY 子句
+func (s sortSpec) orderBy() string {
+	dir := " ASC"
+	if s.desc {
+		dir = " DESC"
+	}
+	if s.column == "id" {
+		return " ORDER BY id" + dir
+	}
+	return " ORDER BY " + s.column + dir + ", id" + dir
+}
+
+// cursor 键集分页游标：上一页最后一条记录的排序值与 ID
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	ID    int64  `json:"id"`
+}
+
+// encodeCursor 根据最后一条记录生成下一页游标
+func encodeCursor(spec sortSpec, value interface{}, id int64) string {
+	c := cursor{Sort: spec.String(), ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	case string:
+		c.Value = v
+	case int64:
+		c.Value = strconv.FormatInt(v, 10)
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标为排序值与 ID，游标须由相同排序生成
+func decodeCursor(spec sortSpec, s string) (interface{}, int64, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", ErrInvalidListQuery)
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, 0, invalid
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, 0, invalid
+	}
+	if c.Sort != spec.String() {
+		return nil, 0, fmt.Errorf("%w: cursor was issued for sort %q, not %q", ErrInvalidListQuery, c.Sort, spec.String())
+	}
+
+	switch spec.kind {
+	case kindTime:
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, 0, invalid
+		}
+		return t, c.ID, nil
+	case kindInt:
+		if spec.column == "id" {
+			return nil, c.ID, nil
+		}
+		v, err := strconv.ParseInt(c.Value, 10, 64)
+		if err != nil {
+			return nil, 0, invalid
+		}
+		return v, c.ID, nil
+	}
+	return c.Value, c.ID, nil
+}
+
+// nameOrTimeSortValue 返回按 id、name、created_at、updated_at 排序的记录在排序字段上的值
+func nameOrTimeSortValue(field string, id int64, name string, createdAt, updatedAt time.Time) interface{} {
+	switch field {
+	case "name":
+		return name
+	case "created_at":
+		return createdAt
+	case "updated_at":
+		return updatedAt
+	}
+	return id
+}
+
+// listBuilder 组装列表查询的过滤条件
+type listBuilder struct {
+	conds   []string
+	args    []interface{}
+	dialect database.Dialect
+}
+
+// newListBuilder 创建在 db 上执行的查询条件，projectID 非 0 时只查询该项目
+func newListBuilder(db DBTX, projectID int64) *listBuilder {
+	b := &listBuilder{dialect: dialectOf(db)}
+	if projectID != 0 {
+		b.add("project_id = ?", projectID)
+	}
+	return b
+}
+
+func (b *listBuilder) add(cond string, args ...interface{}) {
+	b.conds = append(b.conds, cond)
+	b.args = append(b.args, args...)
+}
+
+// common 添加名称搜索与创建、更新时间范围条件
+func (b *listBuilder) common(nameColumn string, q models.ListQuery) {
+	if q.Name != "" {
+		b.add(nameColumn+" "+b.dialect.Like()+" ? ESCAPE '!'", "%"+escapeLike(q.Name)+"%")
+	}
+	b.timeRange("created_at", q.CreatedAfter, q.CreatedBefore)
+	b.timeRange("updated_at", q.UpdatedAfter, q.UpdatedBefore)
+}
+
+func (b *listBuilder) timeRange(column string, after, before time.Time) {
+	if !after.IsZero() {
+		b.add(column+" >= ?", after)
+	}
+	if !before.IsZero() {
+		b.add(column+" < ?", before)
+	}
+}
+
+// in 添加 column IN (...) 条件，values 为空时不过滤
+func (b *listBuilder) in(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	b.add(column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")", args...)
+}
+
+// keyset 添加游标之后的键集条件
+func (b *listBuilder) keyset(spec sortSpec, token string) error {
+	if token == "" {
+		return nil
+	}
+	value, id, err := decodeCursor(spec, token)
+	if err != nil {
+		return err
+	}
+	op := " > "
+	if spec.desc {
+		op = " < "
+	}
+	if spec.column == "id" {
+		b.add("id"+op+"?", id)
+		return nil
+	}
+	b.add("("+spec.column+op+"? OR ("+spec.column+" = ? AND id"+op+"?))", value, value, id)
+	return nil
+}
+
+// where 返回 WHERE 子句
+func (b *listBuilder) where() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+// count 统计符合条件的记录数（须在 keyset 之前调用，总数不受游标影响）
+func (b *listBuilder) count(db DBTX, table string) (int64, error) {
+	var total int64
+	if err := db.QueryRow("SELECT COUNT(*) FROM "+table+b.where(), b.args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", table, err)
+	}
+	return total, nil
+}
+
+// pageArgs 规范分页参数：指定游标时忽略偏移量，多查询一条用于判断是否有下一页
+func pageArgs(q models.ListQuery) (page models.Page, limit, offset int) {
+	page = models.Page{Limit: q.Limit, Offset: q.Offset}
+	if q.Cursor != "" {
+		page.Offset = 0
+	}
+	return page, page.Limit + 1, page.Offset
+}
+
+// escapeLike 转义 LIKE 通配符（转义字符为 !）
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
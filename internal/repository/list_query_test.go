@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/pkg/database"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		want    string
+		orderBy string
+		wantErr bool
+	}{
+		{sort: "", want: "-id", orderBy: " ORDER BY id DESC"},
+		{sort: "id", want: "id", orderBy: " ORDER BY id ASC"},
+		{sort: "-name", want: "-name", orderBy: " ORDER BY job_name DESC, id DESC"},
+		{sort: "created_at", want: "created_at", orderBy: " ORDER BY created_at ASC, id ASC"},
+		{sort: "flow_id", wantErr: true},
+		{sort: "job_name", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			spec, err := parseSort(tt.sort, jobSortColumns)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidListQuery) {
+					t.Fatalf("parseSort(%q) error = %v, want ErrInvalidListQuery", tt.sort, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSort(%q) error = %v", tt.sort, err)
+			}
+			if spec.String() != tt.want || spec.orderBy() != tt.orderBy {
+				t.Fatalf("parseSort(%q) = %s %q, want %s %q", tt.sort, spec, spec.orderBy(), tt.want, tt.orderBy)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		sort      string
+		value     interface{}
+		id        int64
+		wantValue interface{}
+	}{
+		{sort: "-id", value: int64(42), id: 42, wantValue: nil},
+		{sort: "id", value: int64(7), id: 7, wantValue: nil},
+		{sort: "name", value: "nightly, run", id: 3, wantValue: "nightly, run"},
+		{sort: "-status", value: "", id: 9, wantValue: ""},
+		{sort: "-created_at", value: created, id: 5, wantValue: created.UTC()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			spec, err := parseSort(tt.sort, jobSortColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token := encodeCursor(spec, tt.value, tt.id)
+			value, id, err := decodeCursor(spec, token)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if id != tt.id || !reflect.DeepEqual(value, tt.wantValue) {
+				t.Fatalf("decodeCursor() = %#v, %d, want %#v, %d", value, id, tt.wantValue, tt.id)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	byName, _ := parseSort("name", jobSortColumns)
+	byTime, _ := parseSort("created_at", jobSortColumns)
+	descName, _ := parseSort("-name", jobSortColumns)
+	nameToken := encodeCursor(byName, "a", 1)
+
+	tests := []struct {
+		name  string
+		spec  sortSpec
+		token string
+	}{
+		{name: "not base64", spec: byName, token: "%%%"},
+		{name: "not json", spec: byName, token: "bm90IGpzb24"},
+		{name: "missing id", spec: byName, token: encodeCursor(byName, "a", 0)},
+		{name: "different sort field", spec: byTime, token: nameToken},
+		{name: "different direction", spec: descName, token: nameToken},
+		{name: "bad time value", spec: byTime, token: encodeCursor(byTime, "yesterday", 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeCursor(tt.spec, tt.token); !errors.Is(err, ErrInvalidListQuery) {
+				t.Fatalf("decodeCursor() error = %v, want ErrInvalidListQuery", err)
+			}
+		})
+	}
+}
+
+func TestListBuilder(t *testing.T) {
+	spec, _ := parseSort("-name", jobSortColumns)
+	b := &listBuilder{dialect: database.MySQL}
+	b.add("project_id = ?", int64(2))
+	b.common("job_name", models.ListQuery{Name: "50%_off!"})
+	b.in("status", []string{"failed", "cancelled"})
+	b.in("flow_id", nil)
+	if err := b.keyset(spec, encodeCursor(spec, "m", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	wantWhere := " WHERE project_id = ? AND job_name LIKE ? ESCAPE '!' AND status IN (?, ?) AND (job_name < ? OR (job_name = ? AND id < ?))"
+	if b.where() != wantWhere {
+		t.Fatalf("where() = %q, want %q", b.where(), wantWhere)
+	}
+	wantArgs := []interface{}{int64(2), "%50!%!_off!!%", "failed", "cancelled", "m", "m", int64(10)}
+	if !reflect.DeepEqual(b.args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", b.args, wantArgs)
+	}
+}
+
+func TestPageArgs(t *testing.T) {
+	page, limit, offset := pageArgs(models.ListQuery{Limit: 20, Offset: 40})
+	if page.Limit != 20 || page.Offset != 40 || limit != 21 || offset != 40 {
+		t.Fatalf("pageArgs() = %+v, %d, %d", page, limit, offset)
+	}
+	page, _, offset = pageArgs(models.ListQuery{Limit: 20, Offset: 40, Cursor: "x"})
+	if page.Offset != 0 || offset != 0 {
+		t.Fatalf("pageArgs() with cursor should ignore offset, got %+v, %d", page, offset)
+	}
+}
@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+type memoryFlowRepository struct {
+	store     *MemoryStore
+	projectID int64
+}
+
+// NewMemoryFlowRepository 创建基于内存数据的流程仓储
+func NewMemoryFlowRepository(store *MemoryStore) FlowRepository {
+	return &memoryFlowRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryFlowRepository) WithTx(tx *sql.Tx) FlowRepository {
+	return r
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *memoryFlowRepository) WithProject(projectID int64) FlowRepository {
+	return &memoryFlowRepository{store: r.store, projectID: projectID}
+}
+
+// Create 创建流程
+func (r *memoryFlowRepository) Create(flow *models.Flow) error {
+	stored, err := cloneFlow(*flow)
+	if err != nil {
+		return fmt.Errorf("failed to create flow: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored.ProjectID = projectForCreate(flow.ProjectID, r.projectID)
+	stored.ID = r.store.nextID("flows")
+	stored.CreatedAt = memoryNow()
+	stored.UpdatedAt = stored.CreatedAt
+	r.store.flows[stored.ID] = stored
+
+	flow.ID = stored.ID
+	flow.ProjectID = stored.ProjectID
+	return nil
+}
+
+// GetByID 根据ID获取流程
+func (r *memoryFlowRepository) GetByID(id int64) (*models.Flow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.get(id)
+}
+
+// get 获取流程，调用方须持有锁
+func (r *memoryFlowRepository) get(id int64) (*models.Flow, error) {
+	flow, ok := r.store.flows[id]
+	if !ok || !inProject(r.projectID, flow.ProjectID) {
+		return nil, fmt.Errorf("flow not found")
+	}
+	flow, err := cloneFlow(flow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow: %w", err)
+	}
+	return &flow, nil
+}
+
+// List 按条件分页查询流程列表
+func (r *memoryFlowRepository) List(filter models.FlowFilter) ([]models.Flow, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, flowSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []models.Flow
+	var ids []int64
+	var values []interface{}
+	for _, flow := range r.store.flows {
+		if !inProject(r.projectID, flow.ProjectID) || !matchesListQuery(filter.ListQuery, flow.Name, flow.CreatedAt, flow.UpdatedAt) {
+			continue
+		}
+		if filter.CreatedBy > 0 && flow.CreatedBy != filter.CreatedBy {
+			continue
+		}
+		if filter.IsActive != nil && flow.IsActive != *filter.IsActive {
+			continue
+		}
+		matched = append(matched, flow)
+		ids = append(ids, flow.ID)
+		values = append(values, nameOrTimeSortValue(spec.field, flow.ID, flow.Name, flow.CreatedAt, flow.UpdatedAt))
+	}
+
+	order, page, err := memoryPage(spec, filter.ListQuery, ids, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	var flows []models.Flow
+	for _, i := range order {
+		flow, err := cloneFlow(matched[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list flows: %w", err)
+		}
+		flows = append(flows, flow)
+	}
+	return flows, page, nil
+}
+
+// Update 更新流程
+func (r *memoryFlowRepository) Update(flow *models.Flow) error {
+	updated, err := cloneFlow(*flow)
+	if err != nil {
+		return fmt.Errorf("failed to update flow: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.flows[flow.ID]
+	if !ok || !inProject(r.projectID, stored.ProjectID) {
+		return nil
+	}
+	stored.Name = updated.Name
+	stored.Description = updated.Description
+	stored.Version = updated.Version
+	stored.Inputs = updated.Inputs
+	stored.IsActive = updated.IsActive
+	stored.UpdatedAt = memoryNow()
+	r.store.flows[flow.ID] = stored
+	return nil
+}
+
+// Delete 删除流程及其流程任务；已创建作业的流程不能删除
+func (r *memoryFlowRepository) Delete(id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	flow, ok := r.store.flows[id]
+	if !ok || !inProject(r.projectID, flow.ProjectID) {
+		return nil
+	}
+	for _, job := range r.store.jobs {
+		if job.FlowID == id {
+			return fmt.Errorf("failed to delete flow: flow %d is referenced by job %d", id, job.ID)
+		}
+	}
+	for ftID, flowTask := range r.store.flowTasks {
+		if flowTask.FlowID == id {
+			delete(r.store.flowTasks, ftID)
+		}
+	}
+	delete(r.store.flows, id)
+	return nil
+}
+
+// ListVersions 获取同名流程的所有版本
+func (r *memoryFlowRepository) ListVersions(name string) ([]models.Flow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var flows []models.Flow
+	for _, flow := range r.store.flows {
+		if flow.Name != name || !inProject(r.projectID, flow.ProjectID) {
+			continue
+		}
+		flow, err := cloneFlow(flow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list flow versions: %w", err)
+		}
+		flows = append(flows, flow)
+	}
+	sort.Slice(flows, func(i, j int) bool { return flows[i].ID < flows[j].ID })
+	return flows, nil
+}
+
+// GetFlowWithTasks 获取流程及其关联的任务
+func (r *memoryFlowRepository) GetFlowWithTasks(flowID int64) (*models.Flow, []models.FlowTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	flow, err := r.get(flowID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flowTasks, err := r.store.flowTasksOf(flowID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get flow tasks: %w", err)
+	}
+	var withTasks []models.FlowTask
+	for _, flowTask := range flowTasks {
+		task, ok := r.store.tasks[flowTask.TaskID]
+		if !ok {
+			continue
+		}
+		if task, err = cloneTask(task); err != nil {
+			return nil, nil, fmt.Errorf("failed to get flow tasks: %w", err)
+		}
+		flowTask.Task = &task
+		withTasks = append(withTasks, flowTask)
+	}
+	return flow, withTasks, nil
+}
+
+type memoryFlowTaskRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryFlowTaskRepository 创建基于内存数据的流程任务仓储
+func NewMemoryFlowTaskRepository(store *MemoryStore) FlowTaskRepository {
+	return &memoryFlowTaskRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryFlowTaskRepository) WithTx(tx *sql.Tx) FlowTaskRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）
+func (r *memoryFlowTaskRepository) WithContext(ctx context.Context) FlowTaskRepository {
+	return &memoryFlowTaskRepository{store: r.store.in(ctx)}
+}
+
+// Create 创建流程任务；流程与任务须已存在，同一流程中的序号不能重复
+func (r *memoryFlowTaskRepository) Create(flowTask *models.FlowTask) error {
+	stored, err := cloneFlowTask(*flowTask)
+	if err != nil {
+		return fmt.Errorf("failed to create flow task: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkFlowTask(&stored); err != nil {
+		return fmt.Errorf("failed to create flow task: %w", err)
+	}
+	stored.ID = r.store.nextID("flow_tasks")
+	stored.CreatedAt = memoryNow()
+	stored.UpdatedAt = stored.CreatedAt
+	r.store.flowTasks[stored.ID] = stored
+
+	flowTask.ID = stored.ID
+	return nil
+}
+
+// GetByID 根据ID获取流程任务
+func (r *memoryFlowTaskRepository) GetByID(id int64) (*models.FlowTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	flowTask, ok := r.store.flowTasks[id]
+	if !ok {
+		return nil, fmt.Errorf("flow task not found")
+	}
+	flowTask, err := cloneFlowTask(flowTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow task: %w", err)
+	}
+	return &flowTask, nil
+}
+
+// GetByFlowID 根据流程ID获取所有流程任务
+func (r *memoryFlowTaskRepository) GetByFlowID(flowID int64) ([]models.FlowTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	flowTasks, err := r.store.flowTasksOf(flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow tasks: %w", err)
+	}
+	return flowTasks, nil
+}
+
+// Update 更新流程任务
+func (r *memoryFlowTaskRepository) Update(flowTask *models.FlowTask) error {
+	updated, err := cloneFlowTask(*flowTask)
+	if err != nil {
+		return fmt.Errorf("failed to update flow task: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.flowTasks[flowTask.ID]
+	if !ok {
+		return nil
+	}
+	stored.TaskID = updated.TaskID
+	stored.Sequence = updated.Sequence
+	stored.IsOptional = updated.IsOptional
+	stored.AllowRollback = updated.AllowRollback
+	stored.ConditionConfig = updated.ConditionConfig
+	stored.ConfigOverrides = updated.ConfigOverrides
+	if err := r.store.checkFlowTask(&stored); err != nil {
+		return fmt.Errorf("failed to update flow task: %w", err)
+	}
+	stored.UpdatedAt = memoryNow()
+	r.store.flowTasks[flowTask.ID] = stored
+	return nil
+}
+
+// Delete 删除流程任务
+func (r *memoryFlowTaskRepository) Delete(id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.flowTasks, id)
+	return nil
+}
+
+// DeleteByFlowID 删除流程的所有任务
+func (r *memoryFlowTaskRepository) DeleteByFlowID(flowID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, flowTask := range r.store.flowTasks {
+		if flowTask.FlowID == flowID {
+			delete(r.store.flowTasks, id)
+		}
+	}
+	return nil
+}
+
+// checkFlowTask 校验流程任务引用的流程与任务存在且序号未被占用，调用方须持有锁
+func (s *MemoryStore) checkFlowTask(flowTask *models.FlowTask) error {
+	if _, ok := s.flows[flowTask.FlowID]; !ok {
+		return fmt.Errorf("flow %d does not exist", flowTask.FlowID)
+	}
+	if _, ok := s.tasks[flowTask.TaskID]; !ok {
+		return fmt.Errorf("task %d does not exist", flowTask.TaskID)
+	}
+	for _, other := range s.flowTasks {
+		if other.ID != flowTask.ID && other.FlowID == flowTask.FlowID && other.Sequence == flowTask.Sequence {
+			return fmt.Errorf("duplicate sequence %d in flow %d", flowTask.Sequence, flowTask.FlowID)
+		}
+	}
+	return nil
+}
+
+// flowTasksOf 返回流程的所有流程任务（按序号升序），调用方须持有锁
+func (s *MemoryStore) flowTasksOf(flowID int64) ([]models.FlowTask, error) {
+	var flowTasks []models.FlowTask
+	for _, flowTask := range s.flowTasks {
+		if flowTask.FlowID != flowID {
+			continue
+		}
+		flowTask, err := cloneFlowTask(flowTask)
+		if err != nil {
+			return nil, err
+		}
+		flowTasks = append(flowTasks, flowTask)
+	}
+	sort.Slice(flowTasks, func(i, j int) bool { return flowTasks[i].Sequence < flowTasks[j].Sequence })
+	return flowTasks, nil
+}
+
+// cloneFlow 复制流程，输入键不与原记录共享
+func cloneFlow(flow models.Flow) (models.Flow, error) {
+	var inputs models.FlowInputs
+	err := copyJSON(&inputs, flow.Inputs)
+	flow.Inputs = inputs
+	return flow, err
+}
+
+// cloneFlowTask 复制流程任务，执行条件与配置覆盖不与原记录共享；关联的任务定义不复制
+func cloneFlowTask(flowTask models.FlowTask) (models.FlowTask, error) {
+	var condition models.ConditionConfig
+	if err := copyJSON(&condition, flowTask.ConditionConfig); err != nil {
+		return flowTask, err
+	}
+	var overrides models.TaskConfig
+	if err := copyJSON(&overrides, flowTask.ConfigOverrides); err != nil {
+		return flowTask, err
+	}
+	flowTask.ConditionConfig = condition
+	flowTask.ConfigOverrides = overrides
+	flowTask.Task = nil
+	return flowTask, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+type memoryJobContextRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryJobContextRepository 创建基于内存数据的作业上下文仓储
+func NewMemoryJobContextRepository(store *MemoryStore) JobContextRepository {
+	return &memoryJobContextRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryJobContextRepository) WithTx(tx *sql.Tx) JobContextRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）
+func (r *memoryJobContextRepository) WithContext(ctx context.Context) JobContextRepository {
+	return &memoryJobContextRepository{store: r.store.in(ctx)}
+}
+
+// GetByJobID 获取作业的所有上下文数据
+func (r *memoryJobContextRepository) GetByJobID(jobID int64) (map[string]map[string]interface{}, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	context := make(map[string]map[string]interface{})
+	for _, entry := range r.store.contexts[jobID] {
+		value, err := decodeContextValue(entry.raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode context %s.%s: %w", entry.scope, entry.key, err)
+		}
+		if context[entry.scope] == nil {
+			context[entry.scope] = make(map[string]interface{})
+		}
+		context[entry.scope][entry.key] = value
+	}
+	return context, nil
+}
+
+// GetScope 获取单个作用域的上下文数据
+func (r *memoryJobContextRepository) GetScope(jobID int64, scope string) (map[string]interface{}, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	context := make(map[string]interface{})
+	for _, entry := range r.store.contexts[jobID] {
+		if entry.scope != scope {
+			continue
+		}
+		value, err := decodeContextValue(entry.raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode context %s.%s: %w", scope, entry.key, err)
+		}
+		context[entry.key] = value
+	}
+	return context, nil
+}
+
+// Set 设置上下文数据，已存在的键原位更新
+func (r *memoryJobContextRepository) Set(jobID int64, scope, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode context %s.%s: %w", scope, key, err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.jobs[jobID]; !ok {
+		return fmt.Errorf("job %d does not exist", jobID)
+	}
+	entries := r.store.contexts[jobID]
+	for i := range entries {
+		if entries[i].scope == scope && entries[i].key == key {
+			entries[i].raw = raw
+			return nil
+		}
+	}
+	r.store.contexts[jobID] = append(entries, memoryContextEntry{scope: scope, key: key, raw: raw})
+	return nil
+}
+
+// Get 获取单个上下文值，不存在时返回 nil
+func (r *memoryJobContextRepository) Get(jobID int64, scope, key string) (interface{}, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, entry := range r.store.contexts[jobID] {
+		if entry.scope == scope && entry.key == key {
+			return decodeContextValue(entry.raw)
+		}
+	}
+	return nil, nil
+}
+
+// Delete 删除单个上下文键
+func (r *memoryJobContextRepository) Delete(jobID int64, scope, key string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entries := r.store.contexts[jobID]
+	for i := range entries {
+		if entries[i].scope == scope && entries[i].key == key {
+			r.store.contexts[jobID] = append(entries[:i:i], entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteScope 删除单个作用域的所有上下文数据
+func (r *memoryJobContextRepository) DeleteScope(jobID int64, scope string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var kept []memoryContextEntry
+	for _, entry := range r.store.contexts[jobID] {
+		if entry.scope != scope {
+			kept = append(kept, entry)
+		}
+	}
+	r.store.contexts[jobID] = kept
+	return nil
+}
+
+// DeleteByJobID 删除作业的所有上下文数据
+func (r *memoryJobContextRepository) DeleteByJobID(jobID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.contexts, jobID)
+	return nil
+}
@@ -0,0 +1,558 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+type memoryJobRepository struct {
+	store     *MemoryStore
+	projectID int64
+}
+
+// NewMemoryJobRepository 创建基于内存数据的作业仓储
+func NewMemoryJobRepository(store *MemoryStore) JobRepository {
+	return &memoryJobRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryJobRepository) WithTx(tx *sql.Tx) JobRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）；内存仓储不记录 SQL 追踪
+func (r *memoryJobRepository) WithContext(ctx context.Context) JobRepository {
+	return &memoryJobRepository{store: r.store.in(ctx), projectID: r.projectID}
+}
+
+// Atomically 见 MemoryStore.Atomically
+func (r *memoryJobRepository) Atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.store.Atomically(ctx, fn)
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *memoryJobRepository) WithProject(projectID int64) JobRepository {
+	return &memoryJobRepository{store: r.store, projectID: projectID}
+}
+
+// Create 创建作业
+func (r *memoryJobRepository) Create(job *models.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.flows[job.FlowID]; !ok {
+		return fmt.Errorf("failed to create job: flow %d does not exist", job.FlowID)
+	}
+	job.ProjectID = projectForCreate(job.ProjectID, r.projectID)
+	stored := models.Job{
+		ID:             r.store.nextID("jobs"),
+		ProjectID:      job.ProjectID,
+		FlowID:         job.FlowID,
+		BatchID:        job.BatchID,
+		RerunOf:        job.RerunOf,
+		JobName:        job.JobName,
+		Status:         job.Status,
+		CurrentTaskSeq: job.CurrentTaskSeq,
+		CreatedBy:      job.CreatedBy,
+		CreatedAt:      memoryNow(),
+	}
+	stored.UpdatedAt = stored.CreatedAt
+	r.store.jobs[stored.ID] = stored
+
+	job.ID = stored.ID
+	return nil
+}
+
+// GetByID 根据ID获取作业
+func (r *memoryJobRepository) GetByID(id int64) (*models.Job, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok || !inProject(r.projectID, job.ProjectID) {
+		return nil, fmt.Errorf("job not found")
+	}
+	return &job, nil
+}
+
+// List 按条件分页查询作业列表
+func (r *memoryJobRepository) List(filter models.JobFilter) ([]models.Job, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, jobSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statuses := make(map[models.JobStatus]bool)
+	for _, status := range filter.Statuses {
+		statuses[status] = true
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []models.Job
+	var ids []int64
+	var values []interface{}
+	for _, job := range r.store.jobs {
+		if !inProject(r.projectID, job.ProjectID) || !matchesListQuery(filter.ListQuery, job.JobName, job.CreatedAt, job.UpdatedAt) {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[job.Status] {
+			continue
+		}
+		if (filter.FlowID > 0 && job.FlowID != filter.FlowID) ||
+			(filter.BatchID > 0 && job.BatchID.Int64 != filter.BatchID) ||
+			(filter.RerunOf > 0 && job.RerunOf.Int64 != filter.RerunOf) ||
+			(filter.CreatedBy > 0 && job.CreatedBy != filter.CreatedBy) {
+			continue
+		}
+		matched = append(matched, job)
+		ids = append(ids, job.ID)
+		values = append(values, jobSortValue(&job, spec.field))
+	}
+
+	order, page, err := memoryPage(spec, filter.ListQuery, ids, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	var jobs []models.Job
+	for _, i := range order {
+		jobs = append(jobs, matched[i])
+	}
+	return jobs, page, nil
+}
+
+// Update 更新作业
+func (r *memoryJobRepository) Update(job *models.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.jobs[job.ID]
+	if !ok || !inProject(r.projectID, stored.ProjectID) {
+		return nil
+	}
+	stored.Status = job.Status
+	stored.CurrentTaskSeq = job.CurrentTaskSeq
+	stored.StartedAt = job.StartedAt
+	stored.CompletedAt = job.CompletedAt
+	stored.UpdatedAt = memoryNow()
+	r.store.jobs[job.ID] = stored
+	return nil
+}
+
+// UpdateStatus 更新作业状态
+func (r *memoryJobRepository) UpdateStatus(jobID int64, status models.JobStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.jobs[jobID]
+	if !ok || !inProject(r.projectID, stored.ProjectID) {
+		return nil
+	}
+	stored.Status = status
+	stored.UpdatedAt = memoryNow()
+	r.store.jobs[jobID] = stored
+	return nil
+}
+
+// GetJobWithTasks 获取作业及其任务
+func (r *memoryJobRepository) GetJobWithTasks(jobID int64) (*models.Job, []models.JobTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok || !inProject(r.projectID, job.ProjectID) {
+		return nil, nil, fmt.Errorf("job not found")
+	}
+
+	jobTasks, err := r.store.jobTasksOf(jobID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get job tasks: %w", err)
+	}
+	var withTasks []models.JobTask
+	for _, jobTask := range jobTasks {
+		task, ok := r.store.tasks[jobTask.TaskID]
+		if !ok {
+			continue
+		}
+		if task, err = cloneTask(task); err != nil {
+			return nil, nil, fmt.Errorf("failed to get job tasks: %w", err)
+		}
+		jobTask.Task = &task
+		withTasks = append(withTasks, jobTask)
+	}
+	return &job, withTasks, nil
+}
+
+type memoryJobTaskRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryJobTaskRepository 创建基于内存数据的作业任务仓储
+func NewMemoryJobTaskRepository(store *MemoryStore) JobTaskRepository {
+	return &memoryJobTaskRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryJobTaskRepository) WithTx(tx *sql.Tx) JobTaskRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）；内存仓储不记录 SQL 追踪
+func (r *memoryJobTaskRepository) WithContext(ctx context.Context) JobTaskRepository {
+	return &memoryJobTaskRepository{store: r.store.in(ctx)}
+}
+
+// Create 创建作业任务
+func (r *memoryJobTaskRepository) Create(jobTask *models.JobTask) error {
+	jobTasks := []models.JobTask{*jobTask}
+	if err := r.BatchCreate(jobTasks); err != nil {
+		return err
+	}
+
+	jobTask.ID = jobTasks[0].ID
+	return nil
+}
+
+// GetByID 根据ID获取作业任务
+func (r *memoryJobTaskRepository) GetByID(id int64) (*models.JobTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	jobTask, ok := r.store.jobTasks[id]
+	if !ok {
+		return nil, fmt.Errorf("job task not found")
+	}
+	jobTask, err := cloneJobTask(jobTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job task: %w", err)
+	}
+	return &jobTask, nil
+}
+
+// GetByJobID 根据作业ID获取所有作业任务
+func (r *memoryJobTaskRepository) GetByJobID(jobID int64) ([]models.JobTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	jobTasks, err := r.store.jobTasksOf(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job tasks: %w", err)
+	}
+	return jobTasks, nil
+}
+
+// GetBySequence 根据作业ID和序号获取作业任务
+func (r *memoryJobTaskRepository) GetBySequence(jobID int64, sequence int) (*models.JobTask, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, jobTask := range r.store.jobTasks {
+		if jobTask.JobID != jobID || jobTask.Sequence != sequence {
+			continue
+		}
+		jobTask, err := cloneJobTask(jobTask)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job task: %w", err)
+		}
+		return &jobTask, nil
+	}
+	return nil, fmt.Errorf("job task not found")
+}
+
+// Update 更新作业任务
+func (r *memoryJobTaskRepository) Update(jobTask *models.JobTask) error {
+	updated, err := cloneJobTask(*jobTask)
+	if err != nil {
+		return fmt.Errorf("failed to update job task: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.jobTasks[jobTask.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = updated.Status
+	stored.IsSkipped = updated.IsSkipped
+	stored.ExecutorID = updated.ExecutorID
+	stored.Result = updated.Result
+	stored.ErrorMessage = updated.ErrorMessage
+	stored.StartedAt = updated.StartedAt
+	stored.CompletedAt = updated.CompletedAt
+	stored.UpdatedAt = memoryNow()
+	r.store.jobTasks[jobTask.ID] = stored
+	return nil
+}
+
+// UpdateStatus 更新作业任务状态
+func (r *memoryJobTaskRepository) UpdateStatus(id int64, status models.JobTaskStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.jobTasks[id]
+	if !ok {
+		return nil
+	}
+	stored.Status = status
+	stored.UpdatedAt = memoryNow()
+	r.store.jobTasks[id] = stored
+	return nil
+}
+
+// BatchCreate 批量创建作业任务，任一记录无效时不写入任何记录
+func (r *memoryJobTaskRepository) BatchCreate(jobTasks []models.JobTask) error {
+	if len(jobTasks) == 0 {
+		return nil
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, jobTask := range jobTasks {
+		if _, ok := r.store.jobs[jobTask.JobID]; !ok {
+			return fmt.Errorf("failed to insert job task: job %d does not exist", jobTask.JobID)
+		}
+		if _, ok := r.store.flowTasks[jobTask.FlowTaskID]; !ok {
+			return fmt.Errorf("failed to insert job task: flow task %d does not exist", jobTask.FlowTaskID)
+		}
+		if _, ok := r.store.tasks[jobTask.TaskID]; !ok {
+			return fmt.Errorf("failed to insert job task: task %d does not exist", jobTask.TaskID)
+		}
+	}
+
+	now := memoryNow()
+	for i := range jobTasks {
+		stored := models.JobTask{
+			ID:         r.store.nextID("job_tasks"),
+			JobID:      jobTasks[i].JobID,
+			FlowTaskID: jobTasks[i].FlowTaskID,
+			TaskID:     jobTasks[i].TaskID,
+			Sequence:   jobTasks[i].Sequence,
+			Status:     jobTasks[i].Status,
+			IsSkipped:  jobTasks[i].IsSkipped,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		r.store.jobTasks[stored.ID] = stored
+		jobTasks[i].ID = stored.ID
+	}
+	return nil
+}
+
+// CountByStatus 统计处于指定作业状态下、指定状态的作业任务数量
+func (r *memoryJobTaskRepository) CountByStatus(jobStatus models.JobStatus, status models.JobTaskStatus) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, jobTask := range r.store.jobTasks {
+		if jobTask.Status == status && r.store.jobs[jobTask.JobID].Status == jobStatus {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// jobTasksOf 返回作业的所有作业任务（按序号升序），调用方须持有锁
+func (s *MemoryStore) jobTasksOf(jobID int64) ([]models.JobTask, error) {
+	var jobTasks []models.JobTask
+	for _, jobTask := range s.jobTasks {
+		if jobTask.JobID != jobID {
+			continue
+		}
+		jobTask, err := cloneJobTask(jobTask)
+		if err != nil {
+			return nil, err
+		}
+		jobTasks = append(jobTasks, jobTask)
+	}
+	sort.Slice(jobTasks, func(i, j int) bool { return jobTasks[i].Sequence < jobTasks[j].Sequence })
+	return jobTasks, nil
+}
+
+// cloneJobTask 复制作业任务，执行结果不与原记录共享；关联数据不复制
+func cloneJobTask(jobTask models.JobTask) (models.JobTask, error) {
+	var result models.TaskResult
+	err := copyJSON(&result, jobTask.Result)
+	jobTask.Result = result
+	jobTask.Task = nil
+	jobTask.FlowTask = nil
+	return jobTask, err
+}
+
+type memoryJobTaskLogRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryJobTaskLogRepository 创建基于内存数据的作业任务日志仓储
+func NewMemoryJobTaskLogRepository(store *MemoryStore) JobTaskLogRepository {
+	return &memoryJobTaskLogRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryJobTaskLogRepository) WithTx(tx *sql.Tx) JobTaskLogRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）；内存仓储不记录 SQL 追踪
+func (r *memoryJobTaskLogRepository) WithContext(ctx context.Context) JobTaskLogRepository {
+	return &memoryJobTaskLogRepository{store: r.store.in(ctx)}
+}
+
+// Create 写入作业任务日志
+func (r *memoryJobTaskLogRepository) Create(log *models.JobTaskLog) error {
+	stored, err := cloneJobTaskLog(*log)
+	if err != nil {
+		return fmt.Errorf("failed to create job task log: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.jobTasks[log.JobTaskID]; !ok {
+		return fmt.Errorf("failed to create job task log: job task %d does not exist", log.JobTaskID)
+	}
+	stored.ID = r.store.nextID("job_task_logs")
+	stored.CreatedAt = memoryNow()
+	r.store.logs[stored.ID] = stored
+
+	log.ID = stored.ID
+	return nil
+}
+
+// ListByJobTaskID 获取作业任务的日志（按时间顺序）
+func (r *memoryJobTaskLogRepository) ListByJobTaskID(jobTaskID int64) ([]models.JobTaskLog, error) {
+	return r.list(func(log models.JobTaskLog) bool { return log.JobTaskID == jobTaskID })
+}
+
+// ListByJobID 获取作业下所有任务的日志（按时间顺序）
+func (r *memoryJobTaskLogRepository) ListByJobID(jobID int64) ([]models.JobTaskLog, error) {
+	return r.list(func(log models.JobTaskLog) bool {
+		jobTask, ok := r.store.jobTasks[log.JobTaskID]
+		return ok && jobTask.JobID == jobID
+	})
+}
+
+func (r *memoryJobTaskLogRepository) list(match func(log models.JobTaskLog) bool) ([]models.JobTaskLog, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	logs := []models.JobTaskLog{}
+	for _, log := range r.store.logs {
+		if !match(log) {
+			continue
+		}
+		log, err := cloneJobTaskLog(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list job task logs: %w", err)
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ID < logs[j].ID })
+	return logs, nil
+}
+
+// cloneJobTaskLog 复制作业任务日志，元数据不与原记录共享
+func cloneJobTaskLog(log models.JobTaskLog) (models.JobTaskLog, error) {
+	var metadata models.LogMetadata
+	err := copyJSON(&metadata, log.Metadata)
+	log.Metadata = metadata
+	return log, err
+}
+
+type memoryJobBatchRepository struct {
+	store     *MemoryStore
+	projectID int64
+}
+
+// NewMemoryJobBatchRepository 创建基于内存数据的作业批次仓储
+func NewMemoryJobBatchRepository(store *MemoryStore) JobBatchRepository {
+	return &memoryJobBatchRepository{store: store}
+}
+
+// WithTx 内存仓储不使用事务，返回自身
+func (r *memoryJobBatchRepository) WithTx(tx *sql.Tx) JobBatchRepository {
+	return r
+}
+
+// WithContext 返回读写 ctx 中数据视图的仓储（见 MemoryStore.Atomically）；内存仓储不记录 SQL 追踪
+func (r *memoryJobBatchRepository) WithContext(ctx context.Context) JobBatchRepository {
+	return &memoryJobBatchRepository{store: r.store.in(ctx), projectID: r.projectID}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *memoryJobBatchRepository) WithProject(projectID int64) JobBatchRepository {
+	return &memoryJobBatchRepository{store: r.store, projectID: projectID}
+}
+
+// Create 创建作业批次
+func (r *memoryJobBatchRepository) Create(batch *models.JobBatch) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	batch.ProjectID = projectForCreate(batch.ProjectID, r.projectID)
+	stored := *batch
+	stored.ID = r.store.nextID("job_batches")
+	stored.CreatedAt = memoryNow()
+	stored.UpdatedAt = stored.CreatedAt
+	r.store.batches[stored.ID] = stored
+
+	batch.ID = stored.ID
+	return nil
+}
+
+// GetByID 根据ID获取作业批次
+func (r *memoryJobBatchRepository) GetByID(id int64) (*models.JobBatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	batch, ok := r.store.batches[id]
+	if !ok || !inProject(r.projectID, batch.ProjectID) {
+		return nil, fmt.Errorf("job batch not found")
+	}
+	return &batch, nil
+}
+
+// CountJobsByStatus 统计批次中各状态的作业数量
+func (r *memoryJobBatchRepository) CountJobsByStatus(batchID int64) (map[models.JobStatus]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[models.JobStatus]int)
+	for _, job := range r.store.jobs {
+		if job.BatchID.Valid && job.BatchID.Int64 == batchID && inProject(r.projectID, job.ProjectID) {
+			counts[job.Status]++
+		}
+	}
+	return counts, nil
+}
+
+// ListJobIDs 返回批次中处于任一指定状态的作业 ID
+func (r *memoryJobBatchRepository) ListJobIDs(batchID int64, statuses ...models.JobStatus) ([]int64, error) {
+	wanted := make(map[models.JobStatus]bool)
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var ids []int64
+	for _, job := range r.store.jobs {
+		if !job.BatchID.Valid || job.BatchID.Int64 != batchID || !inProject(r.projectID, job.ProjectID) {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[job.Status] {
+			continue
+		}
+		ids = append(ids, job.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// MemoryStore 内存仓储共享的数据。基于同一 MemoryStore 创建的内存仓储可以关联查询（如作业任务关联任务定义），
+// 用于嵌入式运行与单元测试：数据不持久化，WithTx 传入的事务被忽略，每次读写单独加锁；
+// 需要多次写入同时生效或撤销时使用 Atomically。
+type MemoryStore struct {
+	mu        *sync.RWMutex
+	lastID    map[string]int64
+	tasks     map[int64]models.Task
+	flows     map[int64]models.Flow
+	flowTasks map[int64]models.FlowTask
+	jobs      map[int64]models.Job
+	jobTasks  map[int64]models.JobTask
+	logs      map[int64]models.JobTaskLog
+	batches   map[int64]models.JobBatch
+	contexts  map[int64][]memoryContextEntry
+}
+
+// memoryContextEntry 一条作业上下文，按写入顺序保存
+type memoryContextEntry struct {
+	scope string
+	key   string
+	raw   []byte
+}
+
+// NewMemoryStore 创建空的内存数据
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu:        &sync.RWMutex{},
+		lastID:    make(map[string]int64),
+		tasks:     make(map[int64]models.Task),
+		flows:     make(map[int64]models.Flow),
+		flowTasks: make(map[int64]models.FlowTask),
+		jobs:      make(map[int64]models.Job),
+		jobTasks:  make(map[int64]models.JobTask),
+		logs:      make(map[int64]models.JobTaskLog),
+		batches:   make(map[int64]models.JobBatch),
+		contexts:  make(map[int64][]memoryContextEntry),
+	}
+}
+
+// memoryTxKey 在 context 中保存 Atomically 执行期间 store 的数据视图
+type memoryTxKey struct {
+	store *MemoryStore
+}
+
+// Atomically 独占内存数据执行 fn：执行期间其他读写等待，fn 返回错误时撤销 fn 的全部写入。
+// fn 中须使用 WithContext(ctx) 返回的仓储读写，直接使用其他仓储会因等待锁而死锁
+func (s *MemoryStore) Atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.clone()
+	view := *s
+	view.mu = &sync.RWMutex{}
+	if err := fn(context.WithValue(ctx, memoryTxKey{store: s}, &view)); err != nil {
+		snapshot.mu = s.mu
+		*s = *snapshot
+		return err
+	}
+	return nil
+}
+
+// in 返回 ctx 中 Atomically 执行期间的数据视图，不在 Atomically 中时返回 s
+func (s *MemoryStore) in(ctx context.Context) *MemoryStore {
+	if view, ok := ctx.Value(memoryTxKey{store: s}).(*MemoryStore); ok {
+		return view
+	}
+	return s
+}
+
+// clone 复制全部数据（不含锁），调用方须持有锁
+func (s *MemoryStore) clone() *MemoryStore {
+	contexts := make(map[int64][]memoryContextEntry, len(s.contexts))
+	for jobID, entries := range s.contexts {
+		contexts[jobID] = slices.Clone(entries)
+	}
+	return &MemoryStore{
+		lastID:    maps.Clone(s.lastID),
+		tasks:     maps.Clone(s.tasks),
+		flows:     maps.Clone(s.flows),
+		flowTasks: maps.Clone(s.flowTasks),
+		jobs:      maps.Clone(s.jobs),
+		jobTasks:  maps.Clone(s.jobTasks),
+		logs:      maps.Clone(s.logs),
+		batches:   maps.Clone(s.batches),
+		contexts:  contexts,
+	}
+}
+
+// nextID 分配表的自增ID，调用方须持有写锁
+func (s *MemoryStore) nextID(table string) int64 {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// memoryNow 返回写入记录的时间（去除单调时钟读数，与数据库读出的时间可直接比较）
+func memoryNow() time.Time {
+	return time.Now().Round(0)
+}
+
+// copyJSON 经 JSON 编解码复制 JSON 列，使内存数据与数据库读写后的值类型一致（如数字为 float64），且不与调用方共享；dst 须为零值
+func copyJSON(dst sql.Scanner, src driver.Valuer) error {
+	value, err := src.Value()
+	if err != nil {
+		return err
+	}
+	return dst.Scan(value)
+}
+
+// inProject 判断记录是否属于仓储作用域内的项目，scope 为 0 表示不限项目
+func inProject(scope, projectID int64) bool {
+	return scope == 0 || scope == projectID
+}
+
+// matchesListQuery 判断记录是否满足名称搜索与创建、更新时间范围条件（名称搜索不区分大小写）
+func matchesListQuery(q models.ListQuery, name string, createdAt, updatedAt time.Time) bool {
+	if q.Name != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(q.Name)) {
+		return false
+	}
+	return inTimeRange(createdAt, q.CreatedAfter, q.CreatedBefore) && inTimeRange(updatedAt, q.UpdatedAfter, q.UpdatedBefore)
+}
+
+func inTimeRange(t, after, before time.Time) bool {
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && !t.Before(before) {
+		return false
+	}
+	return true
+}
+
+// memoryPage 按排序、游标与分页参数选取内存记录，与 SQL 仓储的列表语义一致。
+// ids 与 values 为已过滤记录的 ID 及其在排序字段上的值，返回当前页记录在 ids 中的下标
+func memoryPage(spec sortSpec, q models.ListQuery, ids []int64, values []interface{}) ([]int, *models.Page, error) {
+	page, limit, offset := pageArgs(q)
+	page.Total = int64(len(ids))
+
+	less := func(i, j int) bool {
+		c := compareSortValues(values[i], values[j])
+		if c == 0 {
+			c = compareSortValues(ids[i], ids[j])
+		}
+		if spec.desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	order := make([]int, len(ids))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return less(order[a], order[b]) })
+
+	if q.Cursor != "" {
+		value, id, err := decodeCursor(spec, q.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if spec.column == "id" {
+			value = id
+		}
+		after := order[:0]
+		for _, i := range order {
+			c := compareSortValues(values[i], value)
+			if c == 0 {
+				c = compareSortValues(ids[i], id)
+			}
+			if (spec.desc && c < 0) || (!spec.desc && c > 0) {
+				after = append(after, i)
+			}
+		}
+		order = after
+	}
+
+	if offset > len(order) {
+		offset = len(order)
+	}
+	order = order[offset:]
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	if len(order) > page.Limit {
+		order = order[:page.Limit]
+		last := order[len(order)-1]
+		page.NextCursor = encodeCursor(spec, values[last], ids[last])
+	}
+	return order, &page, nil
+}
+
+// compareSortValues 比较排序字段的值（int64、string 或 time.Time）
+func compareSortValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		y := b.(int64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+func TestMemoryPage(t *testing.T) {
+	ids := []int64{1, 2, 3, 4, 5}
+	names := []interface{}{"b", "a", "b", "c", "a"}
+
+	tests := []struct {
+		sort   string
+		limit  int
+		offset int
+		want   [][]int64
+	}{
+		{sort: "-id", limit: 2, want: [][]int64{{5, 4}, {3, 2}, {1}}},
+		{sort: "id", limit: 5, want: [][]int64{{1, 2, 3, 4, 5}}},
+		{sort: "id", limit: 2, offset: 3, want: [][]int64{{4, 5}}},
+		{sort: "name", limit: 2, want: [][]int64{{2, 5}, {1, 3}, {4}}},
+		{sort: "-name", limit: 3, want: [][]int64{{4, 3, 1}, {5, 2}}},
+		{sort: "name", limit: 2, offset: 1, want: [][]int64{{5, 1}, {3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			spec, err := parseSort(tt.sort, jobSortColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := names
+			if spec.column == "id" {
+				values = make([]interface{}, len(ids))
+				for i, id := range ids {
+					values[i] = id
+				}
+			}
+
+			q := models.ListQuery{Limit: tt.limit, Offset: tt.offset}
+			var got [][]int64
+			for {
+				order, page, err := memoryPage(spec, q, ids, values)
+				if err != nil {
+					t.Fatalf("memoryPage() error = %v", err)
+				}
+				if page.Total != int64(len(ids)) {
+					t.Fatalf("page.Total = %d, want %d", page.Total, len(ids))
+				}
+				pageIDs := make([]int64, len(order))
+				for i, idx := range order {
+					pageIDs[i] = ids[idx]
+				}
+				got = append(got, pageIDs)
+				if page.NextCursor == "" || len(got) > len(tt.want) {
+					break
+				}
+				q.Cursor = page.NextCursor
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	spec, _ := parseSort("name", jobSortColumns)
+	other, _ := parseSort("-id", jobSortColumns)
+	q := models.ListQuery{Limit: 2, Cursor: encodeCursor(other, nil, 3)}
+	if _, _, err := memoryPage(spec, q, ids, names); !errors.Is(err, ErrInvalidListQuery) {
+		t.Fatalf("memoryPage() with cursor of another sort error = %v, want ErrInvalidListQuery", err)
+	}
+}
+
+func TestMemoryCopyJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(input, got *models.Task)
+	}{
+		{name: "caller input", mutate: func(input, got *models.Task) {
+			input.Config["retries"] = 9
+			input.Config["env"].(map[string]interface{})["region"] = "us"
+		}},
+		{name: "returned value", mutate: func(input, got *models.Task) {
+			got.Config["retries"] = 9
+			got.Config["env"].(map[string]interface{})["region"] = "us"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewMemoryTaskRepository(NewMemoryStore())
+			input := &models.Task{
+				Name:     "deploy",
+				TaskType: models.TaskTypeAutomated,
+				Config:   models.TaskConfig{"retries": 3, "env": map[string]interface{}{"region": "cn"}},
+			}
+			if err := repo.Create(input); err != nil {
+				t.Fatal(err)
+			}
+			got, err := repo.GetByID(input.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.mutate(input, got)
+
+			stored, err := repo.GetByID(input.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := models.TaskConfig{"retries": float64(3), "env": map[string]interface{}{"region": "cn"}}
+			if !reflect.DeepEqual(stored.Config, want) {
+				t.Fatalf("stored config = %#v, want %#v", stored.Config, want)
+			}
+		})
+	}
+}
+
+func TestMemoryWithProject(t *testing.T) {
+	store := NewMemoryStore()
+	flows := NewMemoryFlowRepository(store)
+	alpha := &models.Flow{Name: "alpha", Version: "1.0"}
+	if err := flows.WithProject(1).Create(alpha); err != nil {
+		t.Fatal(err)
+	}
+	beta := &models.Flow{Name: "beta", Version: "1.0"}
+	if err := flows.WithProject(2).Create(beta); err != nil {
+		t.Fatal(err)
+	}
+	if alpha.ProjectID != 1 || beta.ProjectID != 2 {
+		t.Fatalf("created project ids = %d, %d, want 1, 2", alpha.ProjectID, beta.ProjectID)
+	}
+
+	tests := []struct {
+		name    string
+		scope   int64
+		wantIDs []int64
+	}{
+		{name: "unscoped", scope: 0, wantIDs: []int64{alpha.ID, beta.ID}},
+		{name: "project 1", scope: 1, wantIDs: []int64{alpha.ID}},
+		{name: "project 2", scope: 2, wantIDs: []int64{beta.ID}},
+		{name: "empty project", scope: 3, wantIDs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := flows.WithProject(tt.scope)
+			list, page, err := repo.List(models.FlowFilter{ListQuery: models.ListQuery{Limit: 10, Sort: "id"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotIDs []int64
+			for _, flow := range list {
+				gotIDs = append(gotIDs, flow.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) || page.Total != int64(len(tt.wantIDs)) {
+				t.Fatalf("List() = %v (total %d), want %v", gotIDs, page.Total, tt.wantIDs)
+			}
+
+			for _, id := range []int64{alpha.ID, beta.ID} {
+				_, err := repo.GetByID(id)
+				visible := false
+				for _, want := range tt.wantIDs {
+					visible = visible || want == id
+				}
+				if visible != (err == nil) {
+					t.Fatalf("GetByID(%d) error = %v, visible = %v", id, err, visible)
+				}
+			}
+		})
+	}
+
+	if err := flows.WithProject(1).Delete(beta.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := flows.GetByID(beta.ID); err != nil {
+		t.Fatalf("Delete() from another project removed flow %d: %v", beta.ID, err)
+	}
+}
+
+func TestMemoryStoreAtomically(t *testing.T) {
+	store := NewMemoryStore()
+	flow := &models.Flow{Name: "nightly", Version: "1.0"}
+	if err := NewMemoryFlowRepository(store).Create(flow); err != nil {
+		t.Fatal(err)
+	}
+	jobs := NewMemoryJobRepository(store)
+	contexts := NewMemoryJobContextRepository(store)
+	kept := &models.Job{FlowID: flow.ID, JobName: "kept", Status: models.JobStatusPending}
+	if err := jobs.Create(kept); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("boom")
+	tests := []struct {
+		name    string
+		err     error
+		wantJob bool
+	}{
+		{name: "rolled back", err: failure, wantJob: false},
+		{name: "applied", err: nil, wantJob: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created models.Job
+			err := store.Atomically(context.Background(), func(ctx context.Context) error {
+				created = models.Job{FlowID: flow.ID, JobName: tt.name, Status: models.JobStatusPending}
+				if err := jobs.WithContext(ctx).Create(&created); err != nil {
+					return err
+				}
+				if err := contexts.WithContext(ctx).Set(kept.ID, models.ContextScopeShared, tt.name, 1); err != nil {
+					return err
+				}
+				kept.Status = models.JobStatusRunning
+				if err := jobs.WithContext(ctx).Update(kept); err != nil {
+					return err
+				}
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Atomically() error = %v, want %v", err, tt.err)
+			}
+
+			_, getErr := jobs.GetByID(created.ID)
+			if (getErr == nil) != tt.wantJob {
+				t.Fatalf("GetByID(%d) error = %v, want job %v", created.ID, getErr, tt.wantJob)
+			}
+			scope, err := contexts.GetScope(kept.ID, models.ContextScopeShared)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := scope[tt.name]; ok != tt.wantJob {
+				t.Fatalf("context key %q present = %v, want %v", tt.name, ok, tt.wantJob)
+			}
+			stored, err := jobs.GetByID(kept.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (stored.Status == models.JobStatusRunning) != tt.wantJob {
+				t.Fatalf("kept job status = %s after %s", stored.Status, tt.name)
+			}
+		})
+	}
+}
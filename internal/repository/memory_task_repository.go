@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+type memoryTaskRepository struct {
+	store     *MemoryStore
+	projectID int64
+}
+
+// NewMemoryTaskRepository 创建基于内存数据的任务仓储
+func NewMemoryTaskRepository(store *MemoryStore) TaskRepository {
+	return &memoryTaskRepository{store: store}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *memoryTaskRepository) WithProject(projectID int64) TaskRepository {
+	return &memoryTaskRepository{store: r.store, projectID: projectID}
+}
+
+// Create 创建任务
+func (r *memoryTaskRepository) Create(task *models.Task) error {
+	stored, err := cloneTask(*task)
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored.ProjectID = projectForCreate(task.ProjectID, r.projectID)
+	stored.ID = r.store.nextID("tasks")
+	stored.CreatedAt = memoryNow()
+	stored.UpdatedAt = stored.CreatedAt
+	r.store.tasks[stored.ID] = stored
+
+	task.ID = stored.ID
+	task.ProjectID = stored.ProjectID
+	return nil
+}
+
+// GetByID 根据ID获取任务
+func (r *memoryTaskRepository) GetByID(id int64) (*models.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || !inProject(r.projectID, task.ProjectID) {
+		return nil, fmt.Errorf("task not found")
+	}
+	task, err := cloneTask(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	return &task, nil
+}
+
+// List 按条件分页查询任务列表
+func (r *memoryTaskRepository) List(filter models.TaskFilter) ([]models.Task, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, taskSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []models.Task
+	var ids []int64
+	var values []interface{}
+	for _, task := range r.store.tasks {
+		if !inProject(r.projectID, task.ProjectID) || !matchesListQuery(filter.ListQuery, task.Name, task.CreatedAt, task.UpdatedAt) {
+			continue
+		}
+		if filter.TaskType != "" && task.TaskType != filter.TaskType {
+			continue
+		}
+		if filter.IsActive != nil && task.IsActive != *filter.IsActive {
+			continue
+		}
+		matched = append(matched, task)
+		ids = append(ids, task.ID)
+		values = append(values, nameOrTimeSortValue(spec.field, task.ID, task.Name, task.CreatedAt, task.UpdatedAt))
+	}
+
+	order, page, err := memoryPage(spec, filter.ListQuery, ids, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tasks []models.Task
+	for _, i := range order {
+		task, err := cloneTask(matched[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, page, nil
+}
+
+// Update 更新任务
+func (r *memoryTaskRepository) Update(task *models.Task) error {
+	updated, err := cloneTask(*task)
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.tasks[task.ID]
+	if !ok || !inProject(r.projectID, stored.ProjectID) {
+		return nil
+	}
+	stored.Name = updated.Name
+	stored.Description = updated.Description
+	stored.TaskType = updated.TaskType
+	stored.Config = updated.Config
+	stored.IsActive = updated.IsActive
+	stored.UpdatedAt = memoryNow()
+	r.store.tasks[task.ID] = stored
+	return nil
+}
+
+// Delete 删除任务，同时删除引用该任务的流程任务；已被作业引用的任务不能删除
+func (r *memoryTaskRepository) Delete(id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || !inProject(r.projectID, task.ProjectID) {
+		return nil
+	}
+	for _, jobTask := range r.store.jobTasks {
+		if jobTask.TaskID == id {
+			return fmt.Errorf("failed to delete task: task %d is referenced by job task %d", id, jobTask.ID)
+		}
+	}
+	for ftID, flowTask := range r.store.flowTasks {
+		if flowTask.TaskID == id {
+			delete(r.store.flowTasks, ftID)
+		}
+	}
+	delete(r.store.tasks, id)
+	return nil
+}
+
+// GetByIDs 根据ID列表获取任务（按 ID 升序）
+func (r *memoryTaskRepository) GetByIDs(ids []int64) ([]models.Task, error) {
+	if len(ids) == 0 {
+		return []models.Task{}, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[int64]bool)
+	var tasks []models.Task
+	for _, id := range ids {
+		task, ok := r.store.tasks[id]
+		if !ok || seen[id] || !inProject(r.projectID, task.ProjectID) {
+			continue
+		}
+		seen[id] = true
+		task, err := cloneTask(task)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks by ids: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// cloneTask 复制任务，配置不与原记录共享
+func cloneTask(task models.Task) (models.Task, error) {
+	var config models.TaskConfig
+	err := copyJSON(&config, task.Config)
+	task.Config = config
+	return task, err
+}
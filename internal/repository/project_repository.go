@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// ProjectRepository 项目仓储接口（含项目成员）
+type ProjectRepository interface {
+	Create(project *models.Project) error
+	GetByID(id int64) (*models.Project, error)
+	GetBySlug(slug string) (*models.Project, error)
+	List(limit, offset int) ([]models.Project, error)
+	// ListByUserID 获取用户所属的项目
+	ListByUserID(userID int64) ([]models.Project, error)
+	Update(project *models.Project) error
+
+	// AddMember 添加成员，已是成员时更新其角色
+	AddMember(member *models.ProjectMember) error
+	GetMember(projectID, userID int64) (*models.ProjectMember, error)
+	ListMembers(projectID int64) ([]models.ProjectMember, error)
+	RemoveMember(projectID, userID int64) error
+}
+
+type projectRepository struct {
+	db DBTX
+}
+
+// NewProjectRepository 创建项目仓储
+func NewProjectRepository(db *sql.DB) ProjectRepository {
+	return &projectRepository{db: newDB(db)}
+}
+
+const projectColumns = `id, name, slug, COALESCE(description, ''), allowed_executors, COALESCE(created_by, 0), created_at, updated_at`
+
+// Create 创建项目
+func (r *projectRepository) Create(project *models.Project) error {
+	query := `
+		INSERT INTO projects (name, slug, description, allowed_executors, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query, project.Name, project.Slug, project.Description, project.AllowedExecutors, project.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+
+	project.ID = id
+	project.CreatedAt = time.Now()
+	project.UpdatedAt = project.CreatedAt
+	return nil
+}
+
+// GetByID 根据ID获取项目
+func (r *projectRepository) GetByID(id int64) (*models.Project, error) {
+	return r.get(`SELECT `+projectColumns+` FROM projects WHERE id = ?`, id)
+}
+
+// GetBySlug 根据标识获取项目
+func (r *projectRepository) GetBySlug(slug string) (*models.Project, error) {
+	return r.get(`SELECT `+projectColumns+` FROM projects WHERE slug = ?`, slug)
+}
+
+func (r *projectRepository) get(query string, arg interface{}) (*models.Project, error) {
+	project := &models.Project{}
+	err := r.db.QueryRow(query, arg).Scan(
+		&project.ID, &project.Name, &project.Slug, &project.Description,
+		&project.AllowedExecutors, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+// List 获取项目列表
+func (r *projectRepository) List(limit, offset int) ([]models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects ORDER BY id LIMIT ? OFFSET ?`
+	return r.list(query, limit, offset)
+}
+
+// ListByUserID 获取用户所属的项目
+func (r *projectRepository) ListByUserID(userID int64) ([]models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE id IN (SELECT project_id FROM project_members WHERE user_id = ?)
+		ORDER BY id
+	`
+	return r.list(query, userID)
+}
+
+func (r *projectRepository) list(query string, args ...interface{}) ([]models.Project, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var project models.Project
+		if err := rows.Scan(
+			&project.ID, &project.Name, &project.Slug, &project.Description,
+			&project.AllowedExecutors, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// Update 更新项目名称、描述与执行器白名单
+func (r *projectRepository) Update(project *models.Project) error {
+	query := `UPDATE projects SET name = ?, description = ?, allowed_executors = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, project.Name, project.Description, project.AllowedExecutors, project.ID); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	return nil
+}
+
+// AddMember 添加成员，已是成员时更新其角色
+func (r *projectRepository) AddMember(member *models.ProjectMember) error {
+	query := `
+		INSERT INTO project_members (project_id, user_id, role)
+		VALUES (?, ?, ?)
+	` + dialectOf(r.db).Upsert([]string{"project_id", "user_id"}, "role")
+	if _, err := r.db.Exec(query, member.ProjectID, member.UserID, member.Role); err != nil {
+		return fmt.Errorf("failed to add project member: %w", err)
+	}
+	member.CreatedAt = time.Now()
+	return nil
+}
+
+// GetMember 获取项目成员
+func (r *projectRepository) GetMember(projectID, userID int64) (*models.ProjectMember, error) {
+	query := `SELECT project_id, user_id, role, created_at FROM project_members WHERE project_id = ? AND user_id = ?`
+	member := &models.ProjectMember{}
+	err := r.db.QueryRow(query, projectID, userID).Scan(&member.ProjectID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("project member not found")
+		}
+		return nil, fmt.Errorf("failed to get project member: %w", err)
+	}
+	return member, nil
+}
+
+// ListMembers 获取项目成员列表
+func (r *projectRepository) ListMembers(projectID int64) ([]models.ProjectMember, error) {
+	query := `SELECT project_id, user_id, role, created_at FROM project_members WHERE project_id = ? ORDER BY user_id`
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []models.ProjectMember
+	for rows.Next() {
+		var member models.ProjectMember
+		if err := rows.Scan(&member.ProjectID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// RemoveMember 移除项目成员
+func (r *projectRepository) RemoveMember(projectID, userID int64) error {
+	if _, err := r.db.Exec(`DELETE FROM project_members WHERE project_id = ? AND user_id = ?`, projectID, userID); err != nil {
+		return fmt.Errorf("failed to remove project member: %w", err)
+	}
+	return nil
+}
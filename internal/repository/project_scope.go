@@ -0,0 +1,30 @@
+package repository
+
+import "github.com/cfrs2005/GoWorkFlow/internal/models"
+
+// projectClause 返回按项目过滤的 SQL 条件（以 AND 连接），projectID 为 0 表示不限项目
+func projectClause(projectID int64) string {
+	if projectID == 0 {
+		return ""
+	}
+	return " AND project_id = ?"
+}
+
+// projectArgs 在查询参数后追加项目ID（与 projectClause 配套）
+func projectArgs(projectID int64, args ...interface{}) []interface{} {
+	if projectID == 0 {
+		return args
+	}
+	return append(args, projectID)
+}
+
+// projectForCreate 确定新记录所属项目：记录自身指定优先，其次为仓储作用域，最后为默认项目
+func projectForCreate(recordProjectID, scopeProjectID int64) int64 {
+	if recordProjectID != 0 {
+		return recordProjectID
+	}
+	if scopeProjectID != 0 {
+		return scopeProjectID
+	}
+	return models.DefaultProjectID
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// ProjectSecretRepository 项目密钥仓储接口
+type ProjectSecretRepository interface {
+	// Set 写入密钥，同名密钥已存在时覆盖其值
+	Set(secret *models.ProjectSecret) error
+	// List 获取项目的密钥（含值，仅供执行时解析模板使用）
+	List(projectID int64) ([]models.ProjectSecret, error)
+	Delete(projectID int64, name string) error
+}
+
+type projectSecretRepository struct {
+	db DBTX
+}
+
+// NewProjectSecretRepository 创建项目密钥仓储
+func NewProjectSecretRepository(db *sql.DB) ProjectSecretRepository {
+	return &projectSecretRepository{db: newDB(db)}
+}
+
+// Set 写入密钥，同名密钥已存在时覆盖其值
+func (r *projectSecretRepository) Set(secret *models.ProjectSecret) error {
+	query := `
+		INSERT INTO project_secrets (project_id, name, value)
+		VALUES (?, ?, ?)
+	` + dialectOf(r.db).Upsert([]string{"project_id", "name"}, "value")
+	if _, err := r.db.Exec(query, secret.ProjectID, secret.Name, secret.Value); err != nil {
+		return fmt.Errorf("failed to set project secret: %w", err)
+	}
+	return nil
+}
+
+// List 获取项目的密钥
+func (r *projectSecretRepository) List(projectID int64) ([]models.ProjectSecret, error) {
+	query := `
+		SELECT id, project_id, name, value, created_at, updated_at
+		FROM project_secrets
+		WHERE project_id = ?
+		ORDER BY name
+	`
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project secrets: %w", err)
+	}
+	defer rows.Close()
+
+	var secrets []models.ProjectSecret
+	for rows.Next() {
+		var secret models.ProjectSecret
+		if err := rows.Scan(
+			&secret.ID, &secret.ProjectID, &secret.Name, &secret.Value, &secret.CreatedAt, &secret.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan project secret: %w", err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, rows.Err()
+}
+
+// Delete 删除密钥
+func (r *projectSecretRepository) Delete(projectID int64, name string) error {
+	result, err := r.db.Exec(`DELETE FROM project_secrets WHERE project_id = ? AND name = ?`, projectID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete project secret: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("project secret not found")
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// RetentionRepository 作业保留策略、归档与清理记录仓储接口
+type RetentionRepository interface {
+	CreatePolicy(policy *models.RetentionPolicy) error
+	GetPolicy(id int64) (*models.RetentionPolicy, error)
+	// GetPolicyByFlowID 获取流程的策略，flowID 为 0 时获取默认策略；不存在时返回 nil
+	GetPolicyByFlowID(flowID int64) (*models.RetentionPolicy, error)
+	ListPolicies() ([]models.RetentionPolicy, error)
+	UpdatePolicy(policy *models.RetentionPolicy) error
+	DeletePolicy(id int64) error
+	// ListFlowIDs 返回存在已结束作业的流程
+	ListFlowIDs() ([]int64, error)
+	// ListExpiredJobIDs 返回流程中超出保留界限、ID 大于 afterID 的已结束作业（按 ID 升序，最多 limit 个）
+	ListExpiredJobIDs(flowID int64, cutoff models.RetentionCutoff, afterID int64, limit int) ([]int64, error)
+	// LockFinishedJobs 在事务中锁定仍处于结束状态的作业，返回锁定的作业 ID
+	LockFinishedJobs(ids []int64) ([]int64, error)
+	// DeleteJobs 删除已结束的作业，作业任务、日志、上下文与制品记录随之级联删除；返回删除的作业数
+	DeleteJobs(ids []int64) (int64, error)
+	CreateArchive(archive *models.JobArchive) error
+	CreateRun(run *models.RetentionRun) error
+	FinishRun(run *models.RetentionRun) error
+	ListRuns(limit, offset int) ([]models.RetentionRun, error)
+	// WithTx 返回在指定事务中执行的仓储
+	WithTx(tx *sql.Tx) RetentionRepository
+	// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+	WithContext(ctx context.Context) RetentionRepository
+}
+
+type retentionRepository struct {
+	db DBTX
+}
+
+// NewRetentionRepository 创建作业保留仓储
+func NewRetentionRepository(db *sql.DB) RetentionRepository {
+	return &retentionRepository{db: newDB(db)}
+}
+
+// WithTx 返回在指定事务中执行的仓储
+func (r *retentionRepository) WithTx(tx *sql.Tx) RetentionRepository {
+	return &retentionRepository{db: withTx(r.db, tx)}
+}
+
+// WithContext 返回使用 ctx 执行查询并记录 SQL 追踪的仓储
+func (r *retentionRepository) WithContext(ctx context.Context) RetentionRepository {
+	return &retentionRepository{db: withContext(ctx, r.db)}
+}
+
+const retentionPolicyColumns = `id, flow_id, keep_days, keep_jobs, failed_keep_days, action, is_active, created_at, updated_at`
+
+// finishedJobStatuses 可被清理的作业状态
+const finishedJobStatuses = `('completed', 'cancelled', 'failed')`
+
+// jobFinishedAt 作业的结束时间：失败的作业没有 completed_at，取最后更新时间
+const jobFinishedAt = `COALESCE(completed_at, updated_at)`
+
+// CreatePolicy 创建保留策略
+func (r *retentionRepository) CreatePolicy(policy *models.RetentionPolicy) error {
+	query := `
+		INSERT INTO retention_policies (flow_id, keep_days, keep_jobs, failed_keep_days, action, is_active)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query, policy.FlowID, policy.KeepDays, policy.KeepJobs, policy.FailedKeepDays,
+		policy.Action, policy.IsActive)
+	if err != nil {
+		return fmt.Errorf("failed to create retention policy: %w", err)
+	}
+
+	policy.ID = id
+	return nil
+}
+
+// GetPolicy 根据ID获取保留策略
+func (r *retentionRepository) GetPolicy(id int64) (*models.RetentionPolicy, error) {
+	policies, err := r.listPolicies(`SELECT `+retentionPolicyColumns+` FROM retention_policies WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("retention policy not found")
+	}
+	return &policies[0], nil
+}
+
+// GetPolicyByFlowID 获取流程的策略，flowID 为 0 时获取默认策略
+func (r *retentionRepository) GetPolicyByFlowID(flowID int64) (*models.RetentionPolicy, error) {
+	query := `SELECT ` + retentionPolicyColumns + ` FROM retention_policies WHERE flow_id IS NULL ORDER BY id ASC LIMIT 1`
+	args := []interface{}{}
+	if flowID != 0 {
+		query = `SELECT ` + retentionPolicyColumns + ` FROM retention_policies WHERE flow_id = ? ORDER BY id ASC LIMIT 1`
+		args = append(args, flowID)
+	}
+	policies, err := r.listPolicies(query, args...)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	return &policies[0], nil
+}
+
+// ListPolicies 获取全部保留策略，默认策略在前
+func (r *retentionRepository) ListPolicies() ([]models.RetentionPolicy, error) {
+	return r.listPolicies(`SELECT ` + retentionPolicyColumns + ` FROM retention_policies ORDER BY flow_id IS NOT NULL, flow_id ASC, id ASC`)
+}
+
+func (r *retentionRepository) listPolicies(query string, args ...interface{}) ([]models.RetentionPolicy, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []models.RetentionPolicy{}
+	for rows.Next() {
+		var p models.RetentionPolicy
+		if err := rows.Scan(
+			&p.ID, &p.FlowID, &p.KeepDays, &p.KeepJobs, &p.FailedKeepDays,
+			&p.Action, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan retention policy: %w", err)
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, rows.Err()
+}
+
+// UpdatePolicy 更新保留策略
+func (r *retentionRepository) UpdatePolicy(policy *models.RetentionPolicy) error {
+	query := `
+		UPDATE retention_policies
+		SET keep_days = ?, keep_jobs = ?, failed_keep_days = ?, action = ?, is_active = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, policy.KeepDays, policy.KeepJobs, policy.FailedKeepDays, policy.Action,
+		policy.IsActive, policy.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update retention policy: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePolicy 删除保留策略
+func (r *retentionRepository) DeletePolicy(id int64) error {
+	_, err := r.db.Exec(`DELETE FROM retention_policies WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete retention policy: %w", err)
+	}
+
+	return nil
+}
+
+// ListFlowIDs 返回存在已结束作业的流程
+func (r *retentionRepository) ListFlowIDs() ([]int64, error) {
+	query := `SELECT DISTINCT flow_id FROM jobs WHERE status IN ` + finishedJobStatuses + ` ORDER BY flow_id ASC`
+	return r.listIDs(query)
+}
+
+// ListExpiredJobIDs 返回流程中超出保留界限的已结束作业：已完成、已取消的作业按结束时间与最近保留数判断，
+// 失败的作业只按结束时间判断
+func (r *retentionRepository) ListExpiredJobIDs(flowID int64, cutoff models.RetentionCutoff, afterID int64, limit int) ([]int64, error) {
+	var conds []string
+	var args []interface{}
+
+	var done []string
+	if !cutoff.FinishedBefore.IsZero() {
+		done = append(done, jobFinishedAt+" < ?")
+		args = append(args, cutoff.FinishedBefore)
+	}
+	if cutoff.KeepLatest > 0 {
+		// 最近 KeepLatest 个之外的作业 ID 不大于第 KeepLatest+1 个作业的 ID
+		var maxID int64
+		err := r.db.QueryRow(`
+			SELECT id FROM jobs
+			WHERE flow_id = ? AND status IN ('completed', 'cancelled')
+			ORDER BY id DESC LIMIT 1 OFFSET ?`, flowID, cutoff.KeepLatest).Scan(&maxID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to find retention boundary: %w", err)
+		}
+		if err == nil {
+			done = append(done, "id <= ?")
+			args = append(args, maxID)
+		}
+	}
+	if len(done) > 0 {
+		conds = append(conds, "(status IN ('completed', 'cancelled') AND ("+strings.Join(done, " OR ")+"))")
+	}
+	if !cutoff.FailedBefore.IsZero() {
+		conds = append(conds, "(status = 'failed' AND "+jobFinishedAt+" < ?)")
+		args = append(args, cutoff.FailedBefore)
+	}
+	if len(conds) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id FROM jobs WHERE flow_id = ? AND id > ? AND (` + strings.Join(conds, " OR ") + `) ORDER BY id ASC LIMIT ?`
+	args = append([]interface{}{flowID, afterID}, args...)
+	return r.listIDs(query, append(args, limit)...)
+}
+
+func (r *retentionRepository) listIDs(query string, args ...interface{}) ([]int64, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// LockFinishedJobs 锁定仍处于结束状态的作业，期间被重试恢复的作业不会被清理
+func (r *retentionRepository) LockFinishedJobs(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query := `SELECT id FROM jobs WHERE id IN ` + inPlaceholders(len(ids)) + ` AND status IN ` + finishedJobStatuses + ` ORDER BY id ASC` + dialectOf(r.db).ForUpdate()
+	return r.listIDs(query, idArgs(ids)...)
+}
+
+// DeleteJobs 删除已结束的作业，执行中或已被重试恢复的作业不会被删除
+func (r *retentionRepository) DeleteJobs(ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query := `DELETE FROM jobs WHERE id IN ` + inPlaceholders(len(ids)) + ` AND status IN ` + finishedJobStatuses
+	result, err := r.db.Exec(query, idArgs(ids)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// inPlaceholders 返回 n 个参数的 IN 列表占位符
+func inPlaceholders(n int) string {
+	return "(?" + strings.Repeat(", ?", n-1) + ")"
+}
+
+func idArgs(ids []int64) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
+
+// CreateArchive 写入作业归档，同一作业重复归档时覆盖
+func (r *retentionRepository) CreateArchive(archive *models.JobArchive) error {
+	query := `
+		INSERT INTO job_archives (job_id, project_id, flow_id, job_name, status, data, job_created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	` + dialectOf(r.db).Upsert([]string{"job_id"}, "data", "status") + `, archived_at = CURRENT_TIMESTAMP`
+	id, err := insertID(r.db, query, archive.JobID, archive.ProjectID, archive.FlowID, archive.JobName,
+		archive.Status, []byte(archive.Data), archive.JobCreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to archive job %d: %w", archive.JobID, err)
+	}
+
+	archive.ID = id
+	return nil
+}
+
+// CreateRun 记录清理开始
+func (r *retentionRepository) CreateRun(run *models.RetentionRun) error {
+	query := `INSERT INTO retention_runs (dry_run, details, started_at) VALUES (?, ?, ?)`
+	id, err := insertID(r.db, query, run.DryRun, run.Details, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create retention run: %w", err)
+	}
+
+	run.ID = id
+	return nil
+}
+
+// FinishRun 记录清理结果
+func (r *retentionRepository) FinishRun(run *models.RetentionRun) error {
+	query := `
+		UPDATE retention_runs
+		SET jobs_deleted = ?, jobs_archived = ?, files_removed = ?, artifacts_removed = ?, details = ?, error = ?, finished_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, run.JobsDeleted, run.JobsArchived, run.FilesRemoved, run.ArtifactsRemoved,
+		run.Details, run.Error, run.FinishedAt, run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update retention run: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuns 获取清理记录，最近的在前
+func (r *retentionRepository) ListRuns(limit, offset int) ([]models.RetentionRun, error) {
+	query := `
+		SELECT id, dry_run, jobs_deleted, jobs_archived, files_removed, artifacts_removed, details,
+		       COALESCE(error, ''), started_at, finished_at
+		FROM retention_runs
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.RetentionRun{}
+	for rows.Next() {
+		var run models.RetentionRun
+		if err := rows.Scan(
+			&run.ID, &run.DryRun, &run.JobsDeleted, &run.JobsArchived, &run.FilesRemoved, &run.ArtifactsRemoved,
+			&run.Details, &run.Error, &run.StartedAt, &run.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan retention run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
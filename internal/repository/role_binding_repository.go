@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// RoleBindingRepository 角色绑定仓储接口
+type RoleBindingRepository interface {
+	Create(binding *models.RoleBinding) error
+	GetByID(id int64) (*models.RoleBinding, error)
+	Delete(id int64) error
+	// ListByUserID 获取用户在指定流程上生效的角色绑定（含全局绑定），flowID 为 0 时只返回全局绑定
+	ListByUserID(userID, flowID int64) ([]models.RoleBinding, error)
+	// ListByFlowID 获取指定流程上的角色绑定，flowID 为 0 时返回全局绑定
+	ListByFlowID(flowID int64) ([]models.RoleBinding, error)
+}
+
+type roleBindingRepository struct {
+	db DBTX
+}
+
+// NewRoleBindingRepository 创建角色绑定仓储
+func NewRoleBindingRepository(db *sql.DB) RoleBindingRepository {
+	return &roleBindingRepository{db: newDB(db)}
+}
+
+const roleBindingColumns = `id, user_id, role, flow_id, COALESCE(created_by, 0), created_at`
+
+// Create 创建角色绑定
+func (r *roleBindingRepository) Create(binding *models.RoleBinding) error {
+	query := `INSERT INTO role_bindings (user_id, role, flow_id, created_by) VALUES (?, ?, ?, ?)`
+	id, err := insertID(r.db, query, binding.UserID, binding.Role, binding.FlowID, binding.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create role binding: %w", err)
+	}
+
+	binding.ID = id
+	binding.CreatedAt = time.Now()
+	return nil
+}
+
+// GetByID 根据ID获取角色绑定
+func (r *roleBindingRepository) GetByID(id int64) (*models.RoleBinding, error) {
+	binding := &models.RoleBinding{}
+	err := r.db.QueryRow(`SELECT `+roleBindingColumns+` FROM role_bindings WHERE id = ?`, id).Scan(
+		&binding.ID, &binding.UserID, &binding.Role, &binding.FlowID, &binding.CreatedBy, &binding.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("role binding not found")
+		}
+		return nil, fmt.Errorf("failed to get role binding: %w", err)
+	}
+	return binding, nil
+}
+
+// Delete 删除角色绑定
+func (r *roleBindingRepository) Delete(id int64) error {
+	if _, err := r.db.Exec(`DELETE FROM role_bindings WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete role binding: %w", err)
+	}
+	return nil
+}
+
+// ListByUserID 获取用户在指定流程上生效的角色绑定（含全局绑定）
+func (r *roleBindingRepository) ListByUserID(userID, flowID int64) ([]models.RoleBinding, error) {
+	query := `SELECT ` + roleBindingColumns + ` FROM role_bindings WHERE user_id = ? AND flow_id IN (0, ?) ORDER BY id`
+	return r.list(query, userID, flowID)
+}
+
+// ListByFlowID 获取指定流程上的角色绑定
+func (r *roleBindingRepository) ListByFlowID(flowID int64) ([]models.RoleBinding, error) {
+	query := `SELECT ` + roleBindingColumns + ` FROM role_bindings WHERE flow_id = ? ORDER BY id`
+	return r.list(query, flowID)
+}
+
+func (r *roleBindingRepository) list(query string, args ...interface{}) ([]models.RoleBinding, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	defer rows.Close()
+
+	var bindings []models.RoleBinding
+	for rows.Next() {
+		var binding models.RoleBinding
+		if err := rows.Scan(
+			&binding.ID, &binding.UserID, &binding.Role, &binding.FlowID, &binding.CreatedBy, &binding.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role binding: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+
+	return bindings, rows.Err()
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/migrations"
+	"github.com/cfrs2005/GoWorkFlow/pkg/database"
+)
+
+// newSQLiteDB 打开已执行内置迁移的内存 SQLite 数据库；内存库按连接隔离，须限制为单连接
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.NewDB(database.Config{
+		Driver:       "sqlite",
+		DSN:          ":memory:?_pragma=foreign_keys(1)",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	applied, err := db.Migrate(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) < 2 {
+		t.Fatalf("applied migrations %v, want at least 001 and 002", applied)
+	}
+	return db.DB
+}
+
+func TestSQLiteRepositories(t *testing.T) {
+	db := newSQLiteDB(t)
+	tasks := NewTaskRepository(db)
+	flows := NewFlowRepository(db)
+	flowTasks := NewFlowTaskRepository(db)
+	jobs := NewJobRepository(db)
+	contexts := NewJobContextRepository(db)
+
+	var taskIDs []int64
+	for _, name := range []string{"build", "deploy"} {
+		task := &models.Task{Name: name, TaskType: models.TaskTypeAutomated, Config: models.TaskConfig{"retries": 3}, IsActive: true}
+		if err := tasks.Create(task); err != nil {
+			t.Fatalf("Create(task %s) error = %v", name, err)
+		}
+		taskIDs = append(taskIDs, task.ID)
+	}
+	if taskIDs[0] <= 0 || taskIDs[1] != taskIDs[0]+1 {
+		t.Fatalf("task ids = %v, want consecutive insert ids", taskIDs)
+	}
+	taskList, page, err := tasks.List(models.TaskFilter{ListQuery: models.ListQuery{Limit: 10, Sort: "id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(taskList) != 2 || page.Total != 2 || taskList[1].Config["retries"] != float64(3) {
+		t.Fatalf("List() tasks = %+v (total %d)", taskList, page.Total)
+	}
+
+	flow := &models.Flow{Name: "release", Version: "1.0", IsActive: true}
+	if err := flows.WithProject(models.DefaultProjectID).Create(flow); err != nil {
+		t.Fatal(err)
+	}
+	for i, taskID := range taskIDs {
+		if err := flowTasks.Create(&models.FlowTask{FlowID: flow.ID, TaskID: taskID, Sequence: (i + 1) * 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, steps, err := flows.GetFlowWithTasks(flow.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Sequence != 10 || steps[1].Sequence != 20 {
+		t.Fatalf("GetFlowWithTasks() steps = %+v, want sequences 10, 20", steps)
+	}
+	flowList, page, err := flows.WithProject(models.DefaultProjectID).List(models.FlowFilter{ListQuery: models.ListQuery{Limit: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flowList) != 1 || page.Total != 1 || flowList[0].ID != flow.ID {
+		t.Fatalf("List() flows = %+v (total %d)", flowList, page.Total)
+	}
+
+	job := &models.Job{FlowID: flow.ID, JobName: "release-1", Status: models.JobStatusPending}
+	if err := jobs.Create(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.UpdateStatus(job.ID, models.JobStatusRunning); err != nil {
+		t.Fatal(err)
+	}
+	jobList, page, err := jobs.List(models.JobFilter{ListQuery: models.ListQuery{Limit: 10}, FlowID: flow.ID, Statuses: []models.JobStatus{models.JobStatusRunning}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobList) != 1 || page.Total != 1 || jobList[0].ID != job.ID {
+		t.Fatalf("List() running jobs = %+v (total %d)", jobList, page.Total)
+	}
+
+	// 同一键写两次走 Upsert 更新
+	for _, value := range []interface{}{"cn", map[string]interface{}{"region": "us"}} {
+		if err := contexts.Set(job.ID, models.ContextScopeShared, "target", value); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	got, err := contexts.Get(job.ID, models.ContextScopeShared, "target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"region": "us"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get() = %#v, want %#v", got, want)
+	}
+	all, err := contexts.GetByJobID(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all[models.ContextScopeShared]) != 1 {
+		t.Fatalf("GetByJobID() = %v, want a single shared key", all)
+	}
+
+	// 迁移 002：订阅归属项目
+	webhooks := NewWebhookRepository(db)
+	sub := &models.WebhookSubscription{
+		Name:     "hook",
+		URL:      "https://example.com/hook",
+		FlowID:   sql.NullInt64{Int64: flow.ID, Valid: true},
+		IsActive: true,
+	}
+	if err := webhooks.WithProject(models.DefaultProjectID).Create(sub); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := webhooks.GetByID(sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.ProjectID != models.DefaultProjectID {
+		t.Fatalf("subscription project_id = %d, want %d", stored.ProjectID, models.DefaultProjectID)
+	}
+	if list, err := webhooks.WithProject(models.DefaultProjectID+1).List(10, 0); err != nil || len(list) != 0 {
+		t.Fatalf("List() in another project = %v, %v, want none", list, err)
+	}
+}
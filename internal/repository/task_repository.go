@@ -11,37 +11,41 @@ import (
 type TaskRepository interface {
 	Create(task *models.Task) error
 	GetByID(id int64) (*models.Task, error)
-	List(limit, offset int) ([]models.Task, error)
+	List(filter models.TaskFilter) ([]models.Task, *models.Page, error)
 	Update(task *models.Task) error
 	Delete(id int64) error
 	GetByIDs(ids []int64) ([]models.Task, error)
+	// WithProject 返回只读写指定项目数据的仓储，projectID 为 0 表示不限项目
+	WithProject(projectID int64) TaskRepository
 }
 
 type taskRepository struct {
-	db *sql.DB
+	db        DBTX
+	projectID int64
 }
 
 // NewTaskRepository 创建任务仓储
 func NewTaskRepository(db *sql.DB) TaskRepository {
-	return &taskRepository{db: db}
+	return &taskRepository{db: newDB(db)}
+}
+
+// WithProject 返回只读写指定项目数据的仓储
+func (r *taskRepository) WithProject(projectID int64) TaskRepository {
+	return &taskRepository{db: r.db, projectID: projectID}
 }
 
 // Create 创建任务
 func (r *taskRepository) Create(task *models.Task) error {
 	query := `
-		INSERT INTO tasks (name, description, task_type, config, is_active)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO tasks (project_id, name, description, task_type, config, is_active)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, task.Name, task.Description, task.TaskType, task.Config, task.IsActive)
+	task.ProjectID = projectForCreate(task.ProjectID, r.projectID)
+	id, err := insertID(r.db, query, task.ProjectID, task.Name, task.Description, task.TaskType, task.Config, task.IsActive)
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	task.ID = id
 	return nil
 }
@@ -49,13 +53,13 @@ func (r *taskRepository) Create(task *models.Task) error {
 // GetByID 根据ID获取任务
 func (r *taskRepository) GetByID(id int64) (*models.Task, error) {
 	query := `
-		SELECT id, name, description, task_type, config, is_active, created_at, updated_at
+		SELECT id, project_id, name, description, task_type, config, is_active, created_at, updated_at
 		FROM tasks
-		WHERE id = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
 	task := &models.Task{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID, &task.Name, &task.Description, &task.TaskType,
+	err := r.db.QueryRow(query, projectArgs(r.projectID, id)...).Scan(
+		&task.ID, &task.ProjectID, &task.Name, &task.Description, &task.TaskType,
 		&task.Config, &task.IsActive, &task.CreatedAt, &task.UpdatedAt,
 	)
 	if err != nil {
@@ -68,17 +72,46 @@ func (r *taskRepository) GetByID(id int64) (*models.Task, error) {
 	return task, nil
 }
 
-// List 获取任务列表
-func (r *taskRepository) List(limit, offset int) ([]models.Task, error) {
+// taskSortColumns 任务列表允许的排序字段
+var taskSortColumns = map[string]sortColumn{
+	"id":         {"id", kindInt},
+	"name":       {"name", kindString},
+	"created_at": {"created_at", kindTime},
+	"updated_at": {"updated_at", kindTime},
+}
+
+// List 按条件分页查询任务列表
+func (r *taskRepository) List(filter models.TaskFilter) ([]models.Task, *models.Page, error) {
+	spec, err := parseSort(filter.Sort, taskSortColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := newListBuilder(r.db, r.projectID)
+	b.common("name", filter.ListQuery)
+	if filter.TaskType != "" {
+		b.add("task_type = ?", filter.TaskType)
+	}
+	if filter.IsActive != nil {
+		b.add("is_active = ?", *filter.IsActive)
+	}
+	total, err := b.count(r.db, "tasks")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.keyset(spec, filter.Cursor); err != nil {
+		return nil, nil, err
+	}
+
+	page, limit, offset := pageArgs(filter.ListQuery)
 	query := `
-		SELECT id, name, description, task_type, config, is_active, created_at, updated_at
-		FROM tasks
-		ORDER BY id DESC
+		SELECT id, project_id, name, description, task_type, config, is_active, created_at, updated_at
+		FROM tasks` + b.where() + spec.orderBy() + `
 		LIMIT ? OFFSET ?
 	`
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.Query(query, append(b.args, limit, offset)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tasks: %w", err)
+		return nil, nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
 	defer rows.Close()
 
@@ -86,15 +119,24 @@ func (r *taskRepository) List(limit, offset int) ([]models.Task, error) {
 	for rows.Next() {
 		var task models.Task
 		if err := rows.Scan(
-			&task.ID, &task.Name, &task.Description, &task.TaskType,
+			&task.ID, &task.ProjectID, &task.Name, &task.Description, &task.TaskType,
 			&task.Config, &task.IsActive, &task.CreatedAt, &task.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
 
-	return tasks, nil
+	page.Total = total
+	if len(tasks) > page.Limit {
+		tasks = tasks[:page.Limit]
+		last := tasks[len(tasks)-1]
+		page.NextCursor = encodeCursor(spec, nameOrTimeSortValue(spec.field, last.ID, last.Name, last.CreatedAt, last.UpdatedAt), last.ID)
+	}
+	return tasks, &page, nil
 }
 
 // Update 更新任务
@@ -102,9 +144,9 @@ func (r *taskRepository) Update(task *models.Task) error {
 	query := `
 		UPDATE tasks
 		SET name = ?, description = ?, task_type = ?, config = ?, is_active = ?
-		WHERE id = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
 	`
-	_, err := r.db.Exec(query, task.Name, task.Description, task.TaskType, task.Config, task.IsActive, task.ID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, task.Name, task.Description, task.TaskType, task.Config, task.IsActive, task.ID)...)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -114,8 +156,8 @@ func (r *taskRepository) Update(task *models.Task) error {
 
 // Delete 删除任务
 func (r *taskRepository) Delete(id int64) error {
-	query := `DELETE FROM tasks WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+	query := `DELETE FROM tasks WHERE id = ?` + projectClause(r.projectID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, id)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -130,15 +172,16 @@ func (r *taskRepository) GetByIDs(ids []int64) ([]models.Task, error) {
 	}
 
 	query := `
-		SELECT id, name, description, task_type, config, is_active, created_at, updated_at
+		SELECT id, project_id, name, description, task_type, config, is_active, created_at, updated_at
 		FROM tasks
-		WHERE id IN (?` + repeatPlaceholder(len(ids)-1) + `)
+		WHERE id IN (?` + repeatPlaceholder(len(ids)-1) + `)` + projectClause(r.projectID) + `
 	`
 
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
 		args[i] = id
 	}
+	args = projectArgs(r.projectID, args...)
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -150,7 +193,7 @@ func (r *taskRepository) GetByIDs(ids []int64) ([]models.Task, error) {
 	for rows.Next() {
 		var task models.Task
 		if err := rows.Scan(
-			&task.ID, &task.Name, &task.Description, &task.TaskType,
+			&task.ID, &task.ProjectID, &task.Name, &task.Description, &task.TaskType,
 			&task.Config, &task.IsActive, &task.CreatedAt, &task.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
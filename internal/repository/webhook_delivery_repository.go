@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// WebhookDeliveryRepository Webhook 投递记录仓储接口
+type WebhookDeliveryRepository interface {
+	Create(delivery *models.WebhookDelivery) error
+	GetByID(id int64) (*models.WebhookDelivery, error)
+	ListBySubscriptionID(subscriptionID int64, limit, offset int) ([]models.WebhookDelivery, error)
+	// ListDue 获取到期待投递的记录
+	ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+	CreateAttempt(attempt *models.WebhookDeliveryAttempt) error
+	ListAttempts(deliveryID int64) ([]models.WebhookDeliveryAttempt, error)
+}
+
+type webhookDeliveryRepository struct {
+	db DBTX
+}
+
+// NewWebhookDeliveryRepository 创建 Webhook 投递记录仓储
+func NewWebhookDeliveryRepository(db *sql.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: newDB(db)}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_type, payload, status, attempts, last_status_code,
+		       last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// Create 创建投递记录
+func (r *webhookDeliveryRepository) Create(delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload, status, attempts, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query,
+		delivery.SubscriptionID, delivery.EventType, []byte(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	delivery.ID = id
+	return nil
+}
+
+// GetByID 根据ID获取投递记录
+func (r *webhookDeliveryRepository) GetByID(id int64) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = ?`
+	delivery, err := scanWebhookDelivery(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook delivery not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListBySubscriptionID 获取订阅的投递记录（最新在前）
+func (r *webhookDeliveryRepository) ListBySubscriptionID(subscriptionID int64, limit, offset int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	return r.list(query, subscriptionID, limit, offset)
+}
+
+// ListDue 获取到期待投递的记录
+func (r *webhookDeliveryRepository) ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC
+		LIMIT ?
+	`
+	return r.list(query, models.WebhookDeliveryPending, now, limit)
+}
+
+func (r *webhookDeliveryRepository) list(query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Update 更新投递状态
+func (r *webhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_status_code = ?, last_error = ?,
+		    next_attempt_at = ?, delivered_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query,
+		delivery.Status, delivery.Attempts, delivery.LastStatusCode, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAttempt 记录一次投递尝试
+func (r *webhookDeliveryRepository) CreateAttempt(attempt *models.WebhookDeliveryAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, response_body, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	id, err := insertID(r.db, query,
+		attempt.DeliveryID, attempt.Attempt, attempt.StatusCode,
+		attempt.Error, attempt.ResponseBody, attempt.DurationMs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery attempt: %w", err)
+	}
+
+	attempt.ID = id
+	return nil
+}
+
+// ListAttempts 获取投递记录的所有尝试
+func (r *webhookDeliveryRepository) ListAttempts(deliveryID int64) ([]models.WebhookDeliveryAttempt, error) {
+	query := `
+		SELECT id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = ?
+		ORDER BY id ASC
+	`
+	rows, err := r.db.Query(query, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []models.WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var attempt models.WebhookDeliveryAttempt
+		var errMsg, body sql.NullString
+		if err := rows.Scan(
+			&attempt.ID, &attempt.DeliveryID, &attempt.Attempt, &attempt.StatusCode,
+			&errMsg, &body, &attempt.DurationMs, &attempt.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery attempt: %w", err)
+		}
+		attempt.Error = errMsg.String
+		attempt.ResponseBody = body.String
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// rowScanner *sql.Row 与 *sql.Rows 的公共扫描接口
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var payload []byte
+	var lastError sql.NullString
+	if err := row.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastStatusCode, &lastError,
+		&delivery.NextAttemptAt, &delivery.DeliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	delivery.LastError = lastError.String
+	return delivery, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// WebhookRepository Webhook 订阅仓储接口
+type WebhookRepository interface {
+	Create(sub *models.WebhookSubscription) error
+	GetByID(id int64) (*models.WebhookSubscription, error)
+	List(limit, offset int) ([]models.WebhookSubscription, error)
+	ListActive() ([]models.WebhookSubscription, error)
+	Update(sub *models.WebhookSubscription) error
+	Delete(id int64) error
+	// WithProject 返回只读写指定项目订阅的仓储，projectID 为 0 表示不限项目
+	WithProject(projectID int64) WebhookRepository
+}
+
+type webhookRepository struct {
+	db        DBTX
+	projectID int64
+}
+
+// NewWebhookRepository 创建 Webhook 订阅仓储
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: newDB(db)}
+}
+
+// WithProject 返回只读写指定项目订阅的仓储
+func (r *webhookRepository) WithProject(projectID int64) WebhookRepository {
+	return &webhookRepository{db: r.db, projectID: projectID}
+}
+
+const webhookColumns = `id, project_id, name, url, event_types, flow_id, secret, is_active, created_at, updated_at`
+
+// Create 创建订阅
+func (r *webhookRepository) Create(sub *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (project_id, name, url, event_types, flow_id, secret, is_active)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	sub.ProjectID = projectForCreate(sub.ProjectID, r.projectID)
+	id, err := insertID(r.db, query, sub.ProjectID, sub.Name, sub.URL, sub.EventTypes, sub.FlowID, sub.Secret, sub.IsActive)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	sub.ID = id
+	return nil
+}
+
+// GetByID 根据ID获取订阅
+func (r *webhookRepository) GetByID(id int64) (*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE id = ?` + projectClause(r.projectID)
+	sub := &models.WebhookSubscription{}
+	err := r.db.QueryRow(query, projectArgs(r.projectID, id)...).Scan(
+		&sub.ID, &sub.ProjectID, &sub.Name, &sub.URL, &sub.EventTypes, &sub.FlowID,
+		&sub.Secret, &sub.IsActive, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return sub, nil
+}
+
+// List 获取订阅列表
+func (r *webhookRepository) List(limit, offset int) ([]models.WebhookSubscription, error) {
+	b := newListBuilder(r.db, r.projectID)
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions` + b.where() + ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	return r.list(query, append(b.args, limit, offset)...)
+}
+
+// ListActive 获取所有启用的订阅
+func (r *webhookRepository) ListActive() ([]models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE is_active = TRUE` + projectClause(r.projectID) + ` ORDER BY id ASC`
+	return r.list(query, projectArgs(r.projectID)...)
+}
+
+func (r *webhookRepository) list(query string, args ...interface{}) ([]models.WebhookSubscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		var sub models.WebhookSubscription
+		if err := rows.Scan(
+			&sub.ID, &sub.ProjectID, &sub.Name, &sub.URL, &sub.EventTypes, &sub.FlowID,
+			&sub.Secret, &sub.IsActive, &sub.CreatedAt, &sub.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// Update 更新订阅
+func (r *webhookRepository) Update(sub *models.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET name = ?, url = ?, event_types = ?, flow_id = ?, secret = ?, is_active = ?
+		WHERE id = ?` + projectClause(r.projectID) + `
+	`
+	_, err := r.db.Exec(query, projectArgs(r.projectID, sub.Name, sub.URL, sub.EventTypes, sub.FlowID, sub.Secret, sub.IsActive, sub.ID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Delete 删除订阅
+func (r *webhookRepository) Delete(id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = ?` + projectClause(r.projectID)
+	_, err := r.db.Exec(query, projectArgs(r.projectID, id)...)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// expressionPattern 匹配 ${...} 模板表达式
+var expressionPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// Scope 模板表达式的取值范围
+type Scope struct {
+	// Context 作业上下文，对应 ${context.<key>}
+	Context map[string]interface{}
+	// Tasks 已完成任务的执行结果（按任务名称），对应 ${tasks.<name>.result.<key>}
+	Tasks map[string]map[string]interface{}
+	// Pending 尚未产生结果的任务名称及其状态，用于给出更明确的错误信息
+	Pending map[string]string
+	// Secrets 作业所属项目的密钥，对应 ${secrets.<name>}
+	Secrets map[string]string
+}
+
+// Reference 模板表达式引用
+type Reference struct {
+	// Expression 原始表达式（不含 ${}）
+	Expression string
+	// Source 引用来源：context、tasks 或 secrets
+	Source string
+	// Task 引用的任务名称（Source 为 tasks 时有效）
+	Task string
+	// Path 取值路径（context 键、result 下的键路径或密钥名称）
+	Path []string
+}
+
+// ReferenceError 模板引用解析错误
+type ReferenceError struct {
+	Expression string
+	Reason     string
+}
+
+// Error 实现 error 接口
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("unresolved reference ${%s}: %s", e.Expression, e.Reason)
+}
+
+// HasExpression 判断字符串是否包含模板表达式
+func HasExpression(s string) bool {
+	return expressionPattern.MatchString(s)
+}
+
+// Parse 解析单个表达式
+func Parse(expression string) (Reference, error) {
+	expr := strings.TrimSpace(expression)
+	ref := Reference{Expression: expr}
+
+	switch {
+	case strings.HasPrefix(expr, "context."):
+		ref.Source = "context"
+		ref.Path = splitPath(strings.TrimPrefix(expr, "context."))
+	case strings.HasPrefix(expr, "tasks."):
+		rest := strings.TrimPrefix(expr, "tasks.")
+		idx := strings.Index(rest, ".result")
+		if idx <= 0 {
+			return ref, &ReferenceError{Expression: expr, Reason: "expected tasks.<name>.result[.<key>...]"}
+		}
+		ref.Source = "tasks"
+		ref.Task = rest[:idx]
+		tail := strings.TrimPrefix(rest[idx:], ".result")
+		if tail != "" && !strings.HasPrefix(tail, ".") {
+			return ref, &ReferenceError{Expression: expr, Reason: "expected tasks.<name>.result[.<key>...]"}
+		}
+		ref.Path = splitPath(strings.TrimPrefix(tail, "."))
+	case strings.HasPrefix(expr, "secrets."):
+		ref.Source = "secrets"
+		ref.Path = []string{strings.TrimPrefix(expr, "secrets.")}
+	default:
+		return ref, &ReferenceError{Expression: expr, Reason: "unknown source, expected context.<key>, tasks.<name>.result.<key> or secrets.<name>"}
+	}
+
+	for _, segment := range ref.Path {
+		if segment == "" {
+			return ref, &ReferenceError{Expression: expr, Reason: "empty path segment"}
+		}
+	}
+	if ref.Source == "context" && len(ref.Path) == 0 {
+		return ref, &ReferenceError{Expression: expr, Reason: "missing context key"}
+	}
+
+	return ref, nil
+}
+
+// References 收集配置中出现的所有模板引用
+func References(config map[string]interface{}) ([]Reference, error) {
+	var refs []Reference
+	var firstErr error
+	walk(config, func(s string) {
+		for _, m := range expressionPattern.FindAllStringSubmatch(s, -1) {
+			ref, err := Parse(m[1])
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	})
+	return refs, firstErr
+}
+
+// Resolve 解析配置中的所有模板表达式，返回新的配置
+// 整个字符串仅为一个表达式时保留被引用值的原始类型，否则按字符串插值
+func Resolve(config map[string]interface{}, scope Scope) (map[string]interface{}, error) {
+	resolved, err := resolveValue(config, scope)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil {
+		return nil, nil
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+func resolveValue(value interface{}, scope Scope) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r, err := resolveValue(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := resolveValue(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		return resolveString(v, scope)
+	}
+	return value, nil
+}
+
+func resolveString(s string, scope Scope) (interface{}, error) {
+	matches := expressionPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	// 整个字符串就是一个表达式：保留原始类型
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return lookup(s[matches[0][2]:matches[0][3]], scope)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(s[last:m[0]])
+		value, err := lookup(s[m[2]:m[3]], scope)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(stringify(value))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+func lookup(expression string, scope Scope) (interface{}, error) {
+	ref, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	path := ref.Path
+	switch ref.Source {
+	case "context":
+		value, ok := scope.Context[path[0]]
+		if !ok {
+			return nil, &ReferenceError{Expression: ref.Expression, Reason: fmt.Sprintf("key %q not found in job context", path[0])}
+		}
+		root = value
+		path = path[1:]
+	case "tasks":
+		result, ok := scope.Tasks[ref.Task]
+		if !ok {
+			if status, pending := scope.Pending[ref.Task]; pending {
+				return nil, &ReferenceError{Expression: ref.Expression, Reason: fmt.Sprintf("task %q has no result yet (status: %s)", ref.Task, status)}
+			}
+			return nil, &ReferenceError{Expression: ref.Expression, Reason: fmt.Sprintf("task %q not found in job", ref.Task)}
+		}
+		root = result
+	case "secrets":
+		value, ok := scope.Secrets[path[0]]
+		if !ok {
+			return nil, &ReferenceError{Expression: ref.Expression, Reason: fmt.Sprintf("secret %q not found in project", path[0])}
+		}
+		return value, nil
+	}
+
+	// 错误信息中的键路径包含 context 的顶层键
+	offset := len(ref.Path) - len(path)
+	value := root
+	for i, segment := range path {
+		value = descend(value, segment)
+		if value == nil {
+			return nil, &ReferenceError{Expression: ref.Expression, Reason: fmt.Sprintf("key %q not found", strings.Join(ref.Path[:offset+i+1], "."))}
+		}
+	}
+	return value, nil
+}
+
+// descend 进入下一层取值，字符串形式的 JSON 会先被解析
+func descend(value interface{}, key string) interface{} {
+	if s, ok := value.(string); ok {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			return nil
+		}
+		value = decoded
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v[key]
+	case []interface{}:
+		var idx int
+		if _, err := fmt.Sscanf(key, "%d", &idx); err != nil || idx < 0 || idx >= len(v) {
+			return nil
+		}
+		return v[idx]
+	}
+	return nil
+}
+
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+func walk(value interface{}, visit func(string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			walk(item, visit)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walk(item, visit)
+		}
+	case string:
+		visit(v)
+	}
+}
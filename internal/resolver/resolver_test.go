@@ -0,0 +1,146 @@
+package resolver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Reference
+		wantErr string
+	}{
+		{expr: "context.video_url", want: Reference{Expression: "context.video_url", Source: "context", Path: []string{"video_url"}}},
+		{expr: " context.a.b ", want: Reference{Expression: "context.a.b", Source: "context", Path: []string{"a", "b"}}},
+		{expr: "tasks.asr.result", want: Reference{Expression: "tasks.asr.result", Source: "tasks", Task: "asr"}},
+		{expr: "tasks.asr.result.text", want: Reference{Expression: "tasks.asr.result.text", Source: "tasks", Task: "asr", Path: []string{"text"}}},
+		{expr: "tasks.my.task.result.a.0", want: Reference{Expression: "tasks.my.task.result.a.0", Source: "tasks", Task: "my.task", Path: []string{"a", "0"}}},
+		{expr: "secrets.API_KEY", want: Reference{Expression: "secrets.API_KEY", Source: "secrets", Path: []string{"API_KEY"}}},
+		{expr: "tasks.asr.value", wantErr: "expected tasks.<name>.result"},
+		{expr: "tasks..result", wantErr: "expected tasks.<name>.result"},
+		{expr: "tasks.asr.resultx", wantErr: "expected tasks.<name>.result"},
+		{expr: "context.", wantErr: "missing context key"},
+		{expr: "context.a..b", wantErr: "empty path segment"},
+		{expr: "env.HOME", wantErr: "unknown source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Parse(tt.expr)
+			if tt.wantErr != "" {
+				var refErr *ReferenceError
+				if !errors.As(err, &refErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	refs, err := References(map[string]interface{}{
+		"url":   "${context.url}",
+		"items": []interface{}{"plain", map[string]interface{}{"text": "${tasks.asr.result.text} ${secrets.KEY}"}},
+		"n":     3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Expression)
+	}
+	for _, want := range []string{"context.url", "tasks.asr.result.text", "secrets.KEY"} {
+		if !strings.Contains(strings.Join(got, ","), want) {
+			t.Errorf("References() = %v, missing %s", got, want)
+		}
+	}
+
+	if _, err := References(map[string]interface{}{"bad": "${foo.bar}", "ok": "${context.a}"}); err == nil {
+		t.Error("References() with invalid expression should return error")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	scope := Scope{
+		Context: map[string]interface{}{
+			"count":  float64(3),
+			"flag":   true,
+			"name":   "demo",
+			"nested": map[string]interface{}{"list": []interface{}{"a", "b"}},
+			"json":   `{"k":"v"}`,
+		},
+		Tasks: map[string]map[string]interface{}{
+			"asr": {"text": "hello", "segments": []interface{}{map[string]interface{}{"start": float64(1)}}},
+		},
+		Pending: map[string]string{"summary": "pending"},
+		Secrets: map[string]string{"KEY": "s3cr3t"},
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "whole string keeps number", value: "${context.count}", want: float64(3)},
+		{name: "whole string keeps bool", value: "${context.flag}", want: true},
+		{name: "whole string keeps map", value: "${context.nested}", want: map[string]interface{}{"list": []interface{}{"a", "b"}}},
+		{name: "whole task result", value: "${tasks.asr.result}", want: map[string]interface{}{"text": "hello", "segments": []interface{}{map[string]interface{}{"start": float64(1)}}}},
+		{name: "interpolation stringifies", value: "n=${context.count} ok=${context.flag}", want: "n=3 ok=true"},
+		{name: "interpolation marshals json", value: "list: ${context.nested.list}", want: `list: ["a","b"]`},
+		{name: "array index", value: "${context.nested.list.1}", want: "b"},
+		{name: "task path with index", value: "${tasks.asr.result.segments.0.start}", want: float64(1)},
+		{name: "json string descends", value: "${context.json.k}", want: "v"},
+		{name: "secret", value: "Bearer ${secrets.KEY}", want: "Bearer s3cr3t"},
+		{name: "no expression", value: "plain", want: "plain"},
+		{name: "non string untouched", value: float64(7), want: float64(7)},
+		{name: "nested containers", value: []interface{}{"${context.name}", map[string]interface{}{"x": "${context.count}"}}, want: []interface{}{"demo", map[string]interface{}{"x": float64(3)}}},
+		{name: "missing context key", value: "${context.missing}", wantErr: `key "missing" not found in job context`},
+		{name: "missing nested key", value: "${context.nested.other}", wantErr: `key "nested.other" not found`},
+		{name: "index out of range", value: "${context.nested.list.5}", wantErr: "not found"},
+		{name: "pending task", value: "${tasks.summary.result.text}", wantErr: `task "summary" has no result yet (status: pending)`},
+		{name: "unknown task", value: "${tasks.other.result}", wantErr: `task "other" not found in job`},
+		{name: "missing secret", value: "${secrets.NOPE}", wantErr: `secret "NOPE" not found in project`},
+		{name: "error inside interpolation", value: "x ${context.missing}", wantErr: "not found in job context"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(map[string]interface{}{"v": tt.value}, scope)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(got["v"], tt.want) {
+				t.Fatalf("Resolve() = %#v, want %#v", got["v"], tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDoesNotModifyInput(t *testing.T) {
+	config := map[string]interface{}{"a": "${context.x}", "b": []interface{}{"${context.x}"}}
+	if _, err := Resolve(config, Scope{Context: map[string]interface{}{"x": "y"}}); err != nil {
+		t.Fatal(err)
+	}
+	if config["a"] != "${context.x}" || config["b"].([]interface{})[0] != "${context.x}" {
+		t.Fatalf("Resolve() modified its input: %v", config)
+	}
+	if got, err := Resolve(nil, Scope{}); got != nil || err != nil {
+		t.Fatalf("Resolve(nil) = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+)
+
+// APIKeyService API Key 管理服务
+type APIKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyService 创建 API Key 管理服务
+func NewAPIKeyService(repo repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{repo: repo}
+}
+
+// CreatedAPIKey 新建的 API Key，Key 为明文，只在创建时返回一次
+type CreatedAPIKey struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// Create 为用户创建 API Key，ttl 为 0 表示不过期
+func (s *APIKeyService) Create(userID int64, name string, ttl time.Duration) (*CreatedAPIKey, error) {
+	if userID <= 0 {
+		return nil, validationErrorf("user_id is required")
+	}
+	if name == "" {
+		return nil, validationErrorf("name is required")
+	}
+	if ttl < 0 {
+		return nil, validationErrorf("expires_in must not be negative")
+	}
+
+	plaintext, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := models.APIKey{
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: auth.HashAPIKey(plaintext),
+		UserID:  userID,
+	}
+	if ttl > 0 {
+		key.ExpiresAt = sql.NullTime{Time: time.Now().Add(ttl), Valid: true}
+	}
+	if err := s.repo.Create(&key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+// List 获取用户的 API Key 列表（不含明文）
+func (s *APIKeyService) List(userID int64) ([]models.APIKey, error) {
+	return s.repo.ListByUserID(userID)
+}
+
+// Revoke 吊销用户自己的 API Key
+func (s *APIKeyService) Revoke(userID, id int64) error {
+	key, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if key.UserID != userID {
+		return fmt.Errorf("api key not found")
+	}
+	return s.repo.Revoke(id)
+}
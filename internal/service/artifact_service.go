@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/artifact"
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+)
+
+// ArtifactService 制品服务：写入存储后端并在数据库中关联作业/作业任务
+type ArtifactService struct {
+	store artifact.Store
+	repo  repository.ArtifactRepository
+}
+
+// NewArtifactService 创建制品服务
+func NewArtifactService(store artifact.Store, repo repository.ArtifactRepository) *ArtifactService {
+	return &ArtifactService{
+		store: store,
+		repo:  repo,
+	}
+}
+
+// Save 保存制品
+func (s *ArtifactService) Save(ctx context.Context, jobID int64, jobTaskID sql.NullInt64, name, contentType string, data []byte) (*models.Artifact, error) {
+	name = sanitizeArtifactName(name)
+	if name == "" {
+		return nil, fmt.Errorf("artifact name is required")
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	sum := sha256.Sum256(data)
+	record := &models.Artifact{
+		JobID:       jobID,
+		JobTaskID:   jobTaskID,
+		Name:        name,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		Backend:     s.store.Backend(),
+		StorageKey:  fmt.Sprintf("jobs/%d/%d_%s", jobID, time.Now().UnixNano(), name),
+	}
+	if jobTaskID.Valid {
+		record.StorageKey = fmt.Sprintf("jobs/%d/tasks/%d/%d_%s", jobID, jobTaskID.Int64, time.Now().UnixNano(), name)
+	}
+
+	if err := s.store.Put(ctx, record.StorageKey, bytes.NewReader(data), record.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	if err := s.repo.Create(record); err != nil {
+		// 记录写入失败时清理已上传的对象
+		s.store.Delete(ctx, record.StorageKey)
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// ListByJob 获取作业的所有制品
+func (s *ArtifactService) ListByJob(jobID int64) ([]models.Artifact, error) {
+	return s.repo.ListByJobID(jobID)
+}
+
+// DeleteObjects 删除制品在存储后端中的对象，制品记录随作业级联删除；返回删除的对象数与首个错误
+func (s *ArtifactService) DeleteObjects(ctx context.Context, artifacts []models.Artifact) (int, error) {
+	var firstErr error
+	deleted := 0
+	for i := range artifacts {
+		if err := s.store.Delete(ctx, artifacts[i].StorageKey); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete artifact %d: %w", artifacts[i].ID, err)
+			}
+			continue
+		}
+		deleted++
+	}
+	return deleted, firstErr
+}
+
+// Open 打开作业的制品内容，调用方负责关闭；制品记录或内容不存在时返回 artifact.ErrNotFound
+func (s *ArtifactService) Open(ctx context.Context, jobID, artifactID int64) (*models.Artifact, io.ReadCloser, error) {
+	record, err := s.repo.GetByID(artifactID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, artifact.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.JobID != jobID {
+		return nil, nil, artifact.ErrNotFound
+	}
+
+	body, err := s.store.Get(ctx, record.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open artifact %d content: %w", artifactID, err)
+	}
+	return record, body, nil
+}
+
+// Writer 返回绑定到作业任务的制品写入器
+func (s *ArtifactService) Writer(jobID, jobTaskID int64) executor.ArtifactWriter {
+	return &jobTaskArtifactWriter{service: s, jobID: jobID, jobTaskID: jobTaskID}
+}
+
+type jobTaskArtifactWriter struct {
+	service   *ArtifactService
+	jobID     int64
+	jobTaskID int64
+}
+
+func (w *jobTaskArtifactWriter) WriteArtifact(ctx context.Context, name, contentType string, data []byte) (*executor.ArtifactRef, error) {
+	record, err := w.service.Save(ctx, w.jobID, sql.NullInt64{Int64: w.jobTaskID, Valid: true}, name, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	return &executor.ArtifactRef{
+		ID:          record.ID,
+		Name:        record.Name,
+		ContentType: record.ContentType,
+		Size:        record.Size,
+		Checksum:    record.Checksum,
+	}, nil
+}
+
+// sanitizeArtifactName 仅保留文件名部分
+func sanitizeArtifactName(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/artifact"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+)
+
+// failingArtifactRepository 模拟数据库不可用的制品仓储
+type failingArtifactRepository struct {
+	repository.ArtifactRepository
+	err error
+}
+
+func (r failingArtifactRepository) GetByID(id int64) (*models.Artifact, error) {
+	return nil, r.err
+}
+
+func TestArtifactServiceOpen(t *testing.T) {
+	db := newTestDB(t)
+	flow := &models.Flow{Name: "a", Version: "1.0", IsActive: true}
+	if err := repository.NewFlowRepository(db).Create(flow); err != nil {
+		t.Fatal(err)
+	}
+	job := &models.Job{FlowID: flow.ID, JobName: "a", Status: models.JobStatusPending}
+	if err := repository.NewJobRepository(db).Create(job); err != nil {
+		t.Fatal(err)
+	}
+	store, err := artifact.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewArtifactService(store, repository.NewArtifactRepository(db))
+	record, err := svc.Save(context.Background(), job.ID, sql.NullInt64{}, "report.txt", "text/plain", []byte("ok"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outage := errors.New("database is locked")
+	tests := []struct {
+		name         string
+		svc          *ArtifactService
+		jobID        int64
+		artifactID   int64
+		wantNotFound bool
+		wantErr      error
+	}{
+		{name: "found", svc: svc, jobID: job.ID, artifactID: record.ID},
+		{name: "missing record", svc: svc, jobID: job.ID, artifactID: record.ID + 1, wantNotFound: true},
+		{name: "other job", svc: svc, jobID: job.ID + 1, artifactID: record.ID, wantNotFound: true},
+		{name: "database error", svc: NewArtifactService(store, failingArtifactRepository{err: outage}), jobID: job.ID, artifactID: record.ID, wantErr: outage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, err := tt.svc.Open(context.Background(), tt.jobID, tt.artifactID)
+			if body != nil {
+				body.Close()
+			}
+			if errors.Is(err, artifact.ErrNotFound) != tt.wantNotFound {
+				t.Fatalf("Open() error = %v, want ErrNotFound %v", err, tt.wantNotFound)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+			if !tt.wantNotFound && tt.wantErr == nil && err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+		})
+	}
+}
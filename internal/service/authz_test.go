@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+// fakeRoleBindingRepository 内存中的角色绑定仓储
+type fakeRoleBindingRepository struct {
+	bindings []models.RoleBinding
+	nextID   int64
+}
+
+func (r *fakeRoleBindingRepository) Create(binding *models.RoleBinding) error {
+	r.nextID++
+	binding.ID = r.nextID
+	r.bindings = append(r.bindings, *binding)
+	return nil
+}
+
+func (r *fakeRoleBindingRepository) GetByID(id int64) (*models.RoleBinding, error) {
+	for i := range r.bindings {
+		if r.bindings[i].ID == id {
+			binding := r.bindings[i]
+			return &binding, nil
+		}
+	}
+	return nil, fmt.Errorf("role binding not found")
+}
+
+func (r *fakeRoleBindingRepository) Delete(id int64) error {
+	for i := range r.bindings {
+		if r.bindings[i].ID == id {
+			r.bindings = append(r.bindings[:i], r.bindings[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("role binding not found")
+}
+
+func (r *fakeRoleBindingRepository) ListByUserID(userID, flowID int64) ([]models.RoleBinding, error) {
+	var list []models.RoleBinding
+	for _, b := range r.bindings {
+		if b.UserID == userID && (b.FlowID == 0 || b.FlowID == flowID) {
+			list = append(list, b)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeRoleBindingRepository) ListByFlowID(flowID int64) ([]models.RoleBinding, error) {
+	var list []models.RoleBinding
+	for _, b := range r.bindings {
+		if b.FlowID == flowID {
+			list = append(list, b)
+		}
+	}
+	return list, nil
+}
+
+// 测试用户：1 全局 admin，2 全局 flow_editor，3 流程 10 上的 job_operator，
+// 4 全局 approver，5 流程 10 上的 "Tech Lead"，6 流程 10 上的 admin，7 没有角色
+const (
+	userAdmin int64 = iota + 1
+	userEditor
+	userFlowOperator
+	userApprover
+	userTechLead
+	userFlowAdmin
+	userNobody
+)
+
+func newTestAuthorizer() (*Authorizer, *fakeRoleBindingRepository) {
+	repo := &fakeRoleBindingRepository{}
+	for _, b := range []models.RoleBinding{
+		{UserID: userAdmin, Role: RoleAdmin},
+		{UserID: userEditor, Role: RoleFlowEditor},
+		{UserID: userFlowOperator, Role: RoleJobOperator, FlowID: 10},
+		{UserID: userApprover, Role: RoleApprover},
+		{UserID: userTechLead, Role: "Tech Lead", FlowID: 10},
+		{UserID: userFlowAdmin, Role: RoleAdmin, FlowID: 10},
+	} {
+		repo.Create(&b)
+	}
+	return NewAuthorizer(repo), repo
+}
+
+func asUser(userID int64) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID})
+}
+
+func TestAuthorizerCheck(t *testing.T) {
+	authz, _ := newTestAuthorizer()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		authz  *Authorizer
+		perm   Permission
+		flowID int64
+		want   bool
+	}{
+		{name: "no principal", ctx: context.Background(), authz: authz, perm: PermRoleManage, want: true},
+		{name: "nil authorizer", ctx: asUser(userNobody), perm: PermRoleManage, want: true},
+		{name: "global admin", ctx: asUser(userAdmin), authz: authz, perm: PermProjectAdmin, want: true},
+		{name: "global binding applies per flow", ctx: asUser(userEditor), authz: authz, perm: PermFlowEdit, flowID: 10, want: true},
+		{name: "global binding at project level", ctx: asUser(userEditor), authz: authz, perm: PermFlowEdit, want: true},
+		{name: "role lacks permission", ctx: asUser(userEditor), authz: authz, perm: PermJobOperate, flowID: 10},
+		{name: "flow binding on its flow", ctx: asUser(userFlowOperator), authz: authz, perm: PermTaskSkip, flowID: 10, want: true},
+		{name: "flow binding on another flow", ctx: asUser(userFlowOperator), authz: authz, perm: PermTaskSkip, flowID: 11},
+		{name: "flow binding at project level", ctx: asUser(userFlowOperator), authz: authz, perm: PermTaskSkip},
+		{name: "flow admin is not project admin", ctx: asUser(userFlowAdmin), authz: authz, perm: PermProjectAdmin},
+		{name: "custom role has no permissions", ctx: asUser(userTechLead), authz: authz, perm: PermApprove, flowID: 10},
+		{name: "no bindings", ctx: asUser(userNobody), authz: authz, perm: PermFlowEdit, flowID: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.authz.Check(tt.ctx, tt.perm, tt.flowID)
+			checkAllowed(t, "Check()", err, tt.want)
+		})
+	}
+}
+
+func TestAuthorizerCheckApproval(t *testing.T) {
+	authz, _ := newTestAuthorizer()
+	techLead := []string{"Tech Lead"}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		flowID    int64
+		approvers []string
+		want      bool
+	}{
+		{name: "no principal", ctx: context.Background(), flowID: 10, approvers: techLead, want: true},
+		{name: "Tech Lead on its flow", ctx: asUser(userTechLead), flowID: 10, approvers: techLead, want: true},
+		{name: "Tech Lead on another flow", ctx: asUser(userTechLead), flowID: 11, approvers: techLead},
+		{name: "approver role is not a named approver", ctx: asUser(userApprover), flowID: 10, approvers: techLead},
+		{name: "one of several approvers", ctx: asUser(userApprover), flowID: 10, approvers: []string{"Tech Lead", RoleApprover}, want: true},
+		{name: "global admin always allowed", ctx: asUser(userAdmin), flowID: 10, approvers: techLead, want: true},
+		{name: "flow admin always allowed", ctx: asUser(userFlowAdmin), flowID: 10, approvers: techLead, want: true},
+		{name: "no approvers needs approval permission", ctx: asUser(userApprover), flowID: 10, want: true},
+		{name: "no approvers and custom role", ctx: asUser(userTechLead), flowID: 10},
+		{name: "no approvers and editor", ctx: asUser(userEditor), flowID: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authz.CheckApproval(tt.ctx, tt.flowID, tt.approvers)
+			checkAllowed(t, "CheckApproval()", err, tt.want)
+		})
+	}
+}
+
+func TestAuthorizerGrantRevoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		binding     models.RoleBinding
+		wantInvalid bool
+		want        bool
+	}{
+		{name: "global admin grants global role", ctx: asUser(userAdmin), binding: models.RoleBinding{UserID: userNobody, Role: RoleViewer}, want: true},
+		{name: "flow admin grants on its flow", ctx: asUser(userFlowAdmin), binding: models.RoleBinding{UserID: userNobody, Role: "Tech Lead", FlowID: 10}, want: true},
+		{name: "flow admin grants on another flow", ctx: asUser(userFlowAdmin), binding: models.RoleBinding{UserID: userNobody, Role: RoleApprover, FlowID: 11}},
+		{name: "flow admin grants global role", ctx: asUser(userFlowAdmin), binding: models.RoleBinding{UserID: userNobody, Role: RoleAdmin}},
+		{name: "editor cannot grant", ctx: asUser(userEditor), binding: models.RoleBinding{UserID: userNobody, Role: RoleFlowEditor, FlowID: 10}},
+		{name: "missing user", ctx: asUser(userAdmin), binding: models.RoleBinding{Role: RoleViewer}, wantInvalid: true},
+		{name: "blank role", ctx: asUser(userAdmin), binding: models.RoleBinding{UserID: userNobody, Role: "  "}, wantInvalid: true},
+		{name: "negative flow", ctx: asUser(userAdmin), binding: models.RoleBinding{UserID: userNobody, Role: RoleViewer, FlowID: -1}, wantInvalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authz, repo := newTestAuthorizer()
+			binding := tt.binding
+			err := authz.Grant(tt.ctx, &binding)
+			if tt.wantInvalid {
+				var invalid *ValidationError
+				if !errors.As(err, &invalid) {
+					t.Fatalf("Grant() error = %v, want ValidationError", err)
+				}
+				return
+			}
+			checkAllowed(t, "Grant()", err, tt.want)
+			if !tt.want {
+				return
+			}
+			if p, _ := auth.PrincipalFromContext(tt.ctx); binding.CreatedBy != p.UserID {
+				t.Fatalf("CreatedBy = %d, want %d", binding.CreatedBy, p.UserID)
+			}
+
+			// 被授权者不能撤销自己的绑定，授权者可以
+			err = authz.Revoke(asUser(userNobody), binding.ID)
+			checkAllowed(t, "Revoke() by grantee", err, false)
+			if err := authz.Revoke(tt.ctx, binding.ID); err != nil {
+				t.Fatalf("Revoke() error = %v", err)
+			}
+			if _, err := repo.GetByID(binding.ID); err == nil {
+				t.Fatalf("binding %d still exists after Revoke()", binding.ID)
+			}
+		})
+	}
+}
+
+// checkAllowed 校验权限结果：允许时无错误，拒绝时返回 ForbiddenError
+func checkAllowed(t *testing.T, call string, err error, want bool) {
+	t.Helper()
+	if want {
+		if err != nil {
+			t.Fatalf("%s error = %v, want allowed", call, err)
+		}
+		return
+	}
+	var forbidden *ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("%s error = %v, want ForbiddenError", call, err)
+	}
+}
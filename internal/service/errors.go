@@ -0,0 +1,33 @@
+package service
+
+import "fmt"
+
+// ValidationError 请求参数校验错误
+type ValidationError struct {
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// validationErrorf 创建参数校验错误
+func validationErrorf(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ForbiddenError 权限不足
+type ForbiddenError struct {
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// forbiddenErrorf 创建权限不足错误
+func forbiddenErrorf(format string, args ...interface{}) error {
+	return &ForbiddenError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// executionLogBuffer 订阅通道的缓冲大小，订阅者跟不上时丢弃的日志行可通过 afterID 补读
+const executionLogBuffer = 256
+
+// ExecutionLogService 执行日志服务：持久化执行器输出的日志行，并推送给正在跟踪的订阅者
+type ExecutionLogService struct {
+	repo        repository.ExecutionLogRepository
+	jobTaskRepo repository.JobTaskRepository
+
+	mu          sync.Mutex
+	subscribers map[int64]map[chan models.ExecutionLog]struct{}
+}
+
+// NewExecutionLogService 创建执行日志服务
+func NewExecutionLogService(repo repository.ExecutionLogRepository, jobTaskRepo repository.JobTaskRepository) *ExecutionLogService {
+	return &ExecutionLogService{
+		repo:        repo,
+		jobTaskRepo: jobTaskRepo,
+		subscribers: make(map[int64]map[chan models.ExecutionLog]struct{}),
+	}
+}
+
+// Append 写入一行日志并推送给订阅者
+func (s *ExecutionLogService) Append(jobTaskID int64, level executor.LogLevel, message string) (*models.ExecutionLog, error) {
+	line := &models.ExecutionLog{
+		JobTaskID: jobTaskID,
+		Level:     string(level),
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(line); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for ch := range s.subscribers[jobTaskID] {
+		select {
+		case ch <- *line:
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	return line, nil
+}
+
+// List 获取作业任务 ID 大于 afterID 的日志行
+func (s *ExecutionLogService) List(jobTaskID, afterID int64, limit int) ([]models.ExecutionLog, error) {
+	return s.repo.ListByJobTaskID(jobTaskID, afterID, limit)
+}
+
+// JobTaskStatus 获取作业任务当前状态
+func (s *ExecutionLogService) JobTaskStatus(jobTaskID int64) (models.JobTaskStatus, error) {
+	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
+	if err != nil {
+		return "", err
+	}
+	return jobTask.Status, nil
+}
+
+// Subscribe 订阅作业任务的新日志行，执行结束时通道被关闭；返回的函数用于取消订阅
+func (s *ExecutionLogService) Subscribe(jobTaskID int64) (<-chan models.ExecutionLog, func()) {
+	ch := make(chan models.ExecutionLog, executionLogBuffer)
+
+	s.mu.Lock()
+	if s.subscribers[jobTaskID] == nil {
+		s.subscribers[jobTaskID] = make(map[chan models.ExecutionLog]struct{})
+	}
+	s.subscribers[jobTaskID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[jobTaskID][ch]; ok {
+			delete(s.subscribers[jobTaskID], ch)
+			close(ch)
+		}
+		if len(s.subscribers[jobTaskID]) == 0 {
+			delete(s.subscribers, jobTaskID)
+		}
+	}
+	return ch, cancel
+}
+
+// Finish 结束作业任务的本次执行，关闭所有订阅通道
+func (s *ExecutionLogService) Finish(jobTaskID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[jobTaskID] {
+		close(ch)
+	}
+	delete(s.subscribers, jobTaskID)
+}
+
+// Sink 返回写入指定作业任务的日志接收器
+func (s *ExecutionLogService) Sink(jobTaskID int64) executor.LogSink {
+	return &executionLogSink{service: s, jobTaskID: jobTaskID}
+}
+
+// executionLogSink 将执行器日志写入执行日志服务（全局日志由 executor.TaskLogger 携带上下文字段输出）
+type executionLogSink struct {
+	service   *ExecutionLogService
+	jobTaskID int64
+}
+
+// WriteLog 实现 executor.LogSink 接口
+func (k *executionLogSink) WriteLog(level executor.LogLevel, message string) {
+	if _, err := k.service.Append(k.jobTaskID, level, message); err != nil {
+		logger.Errorf("Failed to persist execution log for job task %d: %v", k.jobTaskID, err)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/resolver"
+)
+
+// validateTaskConfig 校验自动化任务的执行器是否存在以及配置参数类型
+func validateTaskConfig(task *models.Task) error {
+	if _, err := resolver.References(task.Config); err != nil {
+		return &executor.ContractError{Stage: "config", Violations: []string{err.Error()}}
+	}
+
+	if task.TaskType != models.TaskTypeAutomated {
+		return nil
+	}
+
+	executorName, _ := task.Config["executor"].(string)
+	if executorName == "" {
+		return nil
+	}
+
+	if _, err := executor.GetExecutor(executorName); err != nil {
+		return &executor.ContractError{
+			Executor:   executorName,
+			Stage:      "config",
+			Violations: []string{"executor is not registered"},
+		}
+	}
+
+	descriptor, ok := executor.GetDescriptor(executorName)
+	if !ok {
+		return nil
+	}
+	return descriptor.ValidateConfig(task.Config)
+}
+
+// validateFlowContracts 按执行顺序检查每个任务的必填参数是否由任务配置、流程输入或上游任务输出提供，
+// 并检查模板表达式引用的任务位于上游、任务名称不重复。flowTasks 需按 sequence 升序排列且已关联任务定义；
+// 上游存在未声明契约的任务时，其输出未知，不再判定上下文键缺失
+func validateFlowContracts(inputs models.FlowInputs, flowTasks []models.FlowTask) error {
+	available := make(map[string]bool, len(inputs))
+	for _, key := range inputs {
+		available[key] = true
+	}
+	upstream := make(map[string]bool, len(flowTasks))
+	open := false
+
+	var violations []string
+	for i := range flowTasks {
+		if flowTasks[i].Task == nil {
+			continue
+		}
+		task := flowTasks[i].Task
+		sequence := flowTasks[i].Sequence
+		config := flowTasks[i].EffectiveConfig()
+
+		// 任务输出按任务名称保存在上下文中，同名任务会互相覆盖
+		if upstream[task.Name] {
+			violations = append(violations, fmt.Sprintf(
+				"task %q (step %d): task already appears at an earlier step, a flow cannot contain the same task name twice",
+				task.Name, sequence,
+			))
+		}
+
+		refs, err := resolver.References(config)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("task %q (step %d): %v", task.Name, sequence, err))
+		}
+		for _, ref := range refs {
+			switch {
+			case ref.Source == "tasks" && !upstream[ref.Task]:
+				violations = append(violations, fmt.Sprintf(
+					"task %q (step %d): ${%s} references task %q which is not an upstream task",
+					task.Name, sequence, ref.Expression, ref.Task,
+				))
+			case ref.Source == "context" && !available[ref.Path[0]] && !open:
+				violations = append(violations, fmt.Sprintf(
+					"task %q (step %d): ${%s} is not produced by an upstream task or flow input",
+					task.Name, sequence, ref.Expression,
+				))
+			}
+		}
+		upstream[task.Name] = true
+
+		executorName, _ := config["executor"].(string)
+		descriptor, ok := executor.GetDescriptor(executorName)
+		if task.TaskType != models.TaskTypeAutomated || !ok {
+			open = true
+			continue
+		}
+
+		for _, p := range descriptor.Params {
+			if !p.Required || p.Default != nil {
+				continue
+			}
+			if _, inConfig := config[p.Name]; inConfig || available[p.Name] || open {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf(
+				"task %q (step %d): required parameter %s is not produced by an upstream task or flow input",
+				task.Name, sequence, p.Name,
+			))
+		}
+
+		for _, name := range descriptor.OutputNames() {
+			available[name] = true
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &executor.ContractError{Stage: "flow", Violations: violations}
+}
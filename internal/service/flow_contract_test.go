@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+func TestValidateFlowContracts(t *testing.T) {
+	step := func(sequence int, name string, config models.TaskConfig) models.FlowTask {
+		return models.FlowTask{Sequence: sequence, Task: &models.Task{Name: name, TaskType: models.TaskTypeManual, Config: config}}
+	}
+
+	tests := []struct {
+		name      string
+		inputs    models.FlowInputs
+		flowTasks []models.FlowTask
+		want      []string
+	}{
+		{
+			name:      "valid",
+			inputs:    models.FlowInputs{"topic"},
+			flowTasks: []models.FlowTask{step(1, "draft", models.TaskConfig{"x": "${context.topic}"}), step(2, "review", models.TaskConfig{"y": "${tasks.draft.result.out}"})},
+		},
+		{
+			name:      "duplicate task name",
+			flowTasks: []models.FlowTask{step(1, "review", nil), step(2, "draft", nil), step(3, "review", nil)},
+			want:      []string{`task "review" (step 3): task already appears at an earlier step`},
+		},
+		{
+			name:      "gap sequences",
+			flowTasks: []models.FlowTask{step(10, "draft", models.TaskConfig{"x": "${context.topic}"}), step(20, "review", models.TaskConfig{"y": "${tasks.publish.result.out}"})},
+			want: []string{
+				`task "draft" (step 10): ${context.topic} is not produced by an upstream task or flow input`,
+				`task "review" (step 20): ${tasks.publish.result.out} references task "publish" which is not an upstream task`,
+			},
+		},
+		{
+			name:      "duplicate task name at gap sequences",
+			flowTasks: []models.FlowTask{step(10, "review", nil), step(20, "review", nil)},
+			want:      []string{`task "review" (step 20): task already appears at an earlier step`},
+		},
+		{
+			name:      "downstream reference",
+			flowTasks: []models.FlowTask{step(1, "draft", models.TaskConfig{"y": "${tasks.review.result.out}"}), step(2, "review", nil)},
+			want:      []string{`task "draft" (step 1): ${tasks.review.result.out} references task "review" which is not an upstream task`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFlowContracts(tt.inputs, tt.flowTasks)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("validateFlowContracts() error = %v", err)
+				}
+				return
+			}
+			var contractErr *executor.ContractError
+			if !errors.As(err, &contractErr) {
+				t.Fatalf("validateFlowContracts() error = %v, want ContractError", err)
+			}
+			if len(contractErr.Violations) != len(tt.want) {
+				t.Fatalf("violations = %q, want %q", contractErr.Violations, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(contractErr.Violations[i], want) {
+					t.Fatalf("violation %d = %q, want prefix %q", i, contractErr.Violations[i], want)
+				}
+			}
+		})
+	}
+}
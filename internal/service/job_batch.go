@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+)
+
+const (
+	// MaxBatchJobs 单个批次最多创建的作业数
+	MaxBatchJobs = 500
+	// DefaultBatchConcurrency 批次自动执行的默认并发作业数
+	DefaultBatchConcurrency = 4
+	// MaxBatchConcurrency 批次自动执行的最大并发作业数
+	MaxBatchConcurrency = 32
+)
+
+// CreateJobBatch 校验输入行后在同一事务中创建批次及其作业：每行须提供流程声明的全部输入，
+// 未指定作业名称时按批次名称与行号命名
+func (s *workflowService) CreateJobBatch(batch *models.JobBatch, items []models.JobBatchItem) ([]models.Job, error) {
+	if len(items) == 0 {
+		return nil, validationErrorf("at least one row is required")
+	}
+	if len(items) > MaxBatchJobs {
+		return nil, validationErrorf("too many rows: %d (at most %d per batch)", len(items), MaxBatchJobs)
+	}
+	switch {
+	case batch.Concurrency == 0:
+		batch.Concurrency = DefaultBatchConcurrency
+	case batch.Concurrency < 0 || batch.Concurrency > MaxBatchConcurrency:
+		return nil, validationErrorf("concurrency must be between 1 and %d", MaxBatchConcurrency)
+	}
+
+	flow, err := s.flowRepo.GetByID(batch.FlowID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authz.Check(s.ctx, PermJobOperate, flow.ID); err != nil {
+		return nil, err
+	}
+
+	if batch.Name == "" {
+		batch.Name = fmt.Sprintf("%s %s", flow.Name, time.Now().Format("2006-01-02 15:04:05"))
+	}
+	for i := range items {
+		for _, key := range flow.Inputs {
+			if value, ok := items[i].Inputs[key]; !ok || value == nil || value == "" {
+				return nil, validationErrorf("row %d: missing flow input %q", i+1, key)
+			}
+		}
+		if items[i].JobName == "" {
+			items[i].JobName = fmt.Sprintf("%s #%d", batch.Name, i+1)
+		}
+	}
+
+	return s.engine.CreateJobBatch(batch, items)
+}
+
+// GetJobBatch 获取批次及其作业按状态的统计
+func (s *workflowService) GetJobBatch(id int64) (*models.JobBatchSummary, error) {
+	batch, err := s.batchRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.batchRepo.CountJobsByStatus(id)
+	if err != nil {
+		return nil, err
+	}
+
+	active := counts[models.JobStatusPending] + counts[models.JobStatusRunning]
+	return &models.JobBatchSummary{Batch: batch, Counts: counts, Finished: active == 0}, nil
+}
+
+// CancelJobBatch 取消批次中所有待执行与执行中的作业，单个作业取消失败不影响其余作业
+func (s *workflowService) CancelJobBatch(id int64, operatorID int64, reason string) (*models.JobBatchResult, error) {
+	batch, err := s.checkJobBatch(id)
+	if err != nil {
+		return nil, err
+	}
+	jobIDs, err := s.batchRepo.ListJobIDs(batch.ID, models.JobStatusPending, models.JobStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	return batchAction(jobIDs, func(jobID int64) error {
+		return s.engine.CancelJob(jobID, operatorID, reason)
+	}), nil
+}
+
+// RetryFailedJobBatch 重试批次中所有失败作业的失败任务，作业恢复运行
+func (s *workflowService) RetryFailedJobBatch(id int64, operatorID int64) (*models.JobBatchResult, error) {
+	batch, err := s.checkJobBatch(id)
+	if err != nil {
+		return nil, err
+	}
+	jobIDs, err := s.batchRepo.ListJobIDs(batch.ID, models.JobStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return batchAction(jobIDs, func(jobID int64) error {
+		jobTasks, err := s.jobTaskRepo.GetByJobID(jobID)
+		if err != nil {
+			return err
+		}
+		for i := range jobTasks {
+			if jobTasks[i].Status == models.JobTaskStatusFailed {
+				return s.engine.RetryTask(jobTasks[i].ID, operatorID)
+			}
+		}
+		return fmt.Errorf("no failed task")
+	}), nil
+}
+
+// checkJobBatch 获取批次并校验当前用户在其流程上的作业操作权限
+func (s *workflowService) checkJobBatch(id int64) (*models.JobBatch, error) {
+	batch, err := s.batchRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authz.Check(s.ctx, PermJobOperate, batch.FlowID); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// batchAction 对每个作业执行操作，汇总成功的作业与失败原因
+func batchAction(jobIDs []int64, fn func(jobID int64) error) *models.JobBatchResult {
+	result := &models.JobBatchResult{JobIDs: []int64{}}
+	for _, jobID := range jobIDs {
+		if err := fn(jobID); err != nil {
+			if result.Errors == nil {
+				result.Errors = make(map[int64]string)
+			}
+			result.Errors[jobID] = err.Error()
+			continue
+		}
+		result.JobIDs = append(result.JobIDs, jobID)
+	}
+	return result
+}
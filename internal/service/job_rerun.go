@@ -0,0 +1,220 @@
+package service
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/resolver"
+)
+
+// RerunJob 以来源作业的输入（共享上下文中流程声明的输入键）创建同一流程的新作业，opts.Inputs 覆盖同名输入。
+// 开启 ReuseOutputs 时，从第一个任务起连续复用来源作业中未变化的已完成任务输出，最后一个任务总会重新执行
+func (s *workflowService) RerunJob(jobID int64, opts models.JobRerunOptions) (*models.JobRerun, error) {
+	source, err := s.jobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authz.Check(s.ctx, PermJobOperate, source.FlowID); err != nil {
+		return nil, err
+	}
+
+	flow, err := s.flowRepo.GetByID(source.FlowID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.LatestVersion {
+		if flow, err = s.latestFlowVersion(flow); err != nil {
+			return nil, err
+		}
+		if flow.ID != source.FlowID {
+			if err := s.authz.Check(s.ctx, PermJobOperate, flow.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 只沿用流程声明的输入，执行中写入共享上下文的其他值不带入新作业；叠加覆盖值，记录值发生变化的键
+	shared, err := s.contextRepo.GetScope(source.ID, models.ContextScopeShared)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make(map[string]interface{})
+	for _, key := range flow.Inputs {
+		if value, ok := shared[key]; ok {
+			inputs[key] = value
+		}
+	}
+	for key, value := range opts.Inputs {
+		inputs[key] = value
+	}
+	changed := make(map[string]bool)
+	for key, previous := range shared {
+		if value, ok := inputs[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changed[key] = true
+		}
+	}
+	for key := range inputs {
+		if _, ok := shared[key]; !ok {
+			changed[key] = true
+		}
+	}
+	for _, key := range flow.Inputs {
+		if value, ok := inputs[key]; !ok || value == nil || value == "" {
+			return nil, validationErrorf("missing flow input %q", key)
+		}
+	}
+
+	job := &models.Job{
+		FlowID:    flow.ID,
+		RerunOf:   sql.NullInt64{Int64: source.ID, Valid: true},
+		JobName:   opts.JobName,
+		CreatedBy: opts.CreatedBy,
+	}
+	if job.JobName == "" {
+		job.JobName = source.JobName + " (rerun)"
+	}
+
+	reuse := []models.TaskReuse{}
+	if opts.ReuseOutputs {
+		if reuse, err = s.reusableTasks(source.ID, flow.ID, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.engine.RerunJob(job, inputs, reuse); err != nil {
+		return nil, err
+	}
+	return &models.JobRerun{Job: job, ReusedTasks: reuse}, nil
+}
+
+// latestFlowVersion 返回与 flow 同项目同名的最新启用版本，版本号相同时取后创建的
+func (s *workflowService) latestFlowVersion(flow *models.Flow) (*models.Flow, error) {
+	versions, err := s.flowRepo.ListVersions(flow.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *models.Flow
+	for i := range versions {
+		v := &versions[i]
+		if !v.IsActive || v.ProjectID != flow.ProjectID {
+			continue
+		}
+		if latest == nil || compareVersions(v.Version, latest.Version) >= 0 {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return nil, validationErrorf("flow %q has no active version", flow.Name)
+	}
+	return latest, nil
+}
+
+// compareVersions 按点分段比较版本号，数字段按数值比较，其余按字符串比较
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// reusableTasks 按顺序对比来源作业的任务与目标流程的任务，返回可复用输出的前缀任务；
+// 遇到第一个不可复用的任务即停止，其后的任务都可能读取到变化的上游输出
+func (s *workflowService) reusableTasks(sourceJobID, flowID int64, changed map[string]bool) ([]models.TaskReuse, error) {
+	_, sourceTasks, err := s.jobRepo.GetJobWithTasks(sourceJobID)
+	if err != nil {
+		return nil, err
+	}
+	_, flowTasks, err := s.flowRepo.GetFlowWithTasks(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	reuse := []models.TaskReuse{}
+	for i := 0; i < len(flowTasks)-1 && i < len(sourceTasks); i++ {
+		ok, err := s.canReuse(&sourceTasks[i], &flowTasks[i], changed)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		reuse = append(reuse, models.TaskReuse{
+			Sequence:        flowTasks[i].Sequence,
+			TaskName:        flowTasks[i].Task.Name,
+			SourceJobTaskID: sourceTasks[i].ID,
+			Result:          sourceTasks[i].Result,
+		})
+	}
+	return reuse, nil
+}
+
+// canReuse 判断来源作业任务的输出能否用于目标流程任务：须为同一任务定义的已完成（未跳过）自动化任务，
+// 任务定义与流程级配置覆盖在其执行后未修改、与目标流程任务的有效配置一致，且不读取值变化的输入
+func (s *workflowService) canReuse(source *models.JobTask, flowTask *models.FlowTask, changed map[string]bool) (bool, error) {
+	if source.Status != models.JobTaskStatusCompleted || source.IsSkipped || !source.StartedAt.Valid ||
+		source.TaskID != flowTask.TaskID || flowTask.Task.TaskType != models.TaskTypeAutomated {
+		return false, nil
+	}
+	if flowTask.Task.UpdatedAt.After(source.StartedAt.Time) {
+		return false, nil
+	}
+
+	sourceFlowTask, err := s.flowTaskRepo.GetByID(source.FlowTaskID)
+	if err != nil {
+		return false, err
+	}
+	if sourceFlowTask.UpdatedAt.After(source.StartedAt.Time) {
+		return false, nil
+	}
+	sourceFlowTask.Task = flowTask.Task
+	config := flowTask.EffectiveConfig()
+	if !reflect.DeepEqual(sourceFlowTask.EffectiveConfig(), config) {
+		return false, nil
+	}
+
+	return !readsInputs(config, changed), nil
+}
+
+// readsInputs 判断任务是否读取 keys 中的输入：配置中的 ${context.*} 引用或执行器契约声明的同名参数。
+// 执行器未声明契约时可能读取任意输入，视为读取
+func readsInputs(config models.TaskConfig, keys map[string]bool) bool {
+	if len(keys) == 0 {
+		return false
+	}
+
+	refs, _ := resolver.References(config)
+	for _, ref := range refs {
+		if ref.Source == "context" && keys[ref.Path[0]] {
+			return true
+		}
+	}
+
+	name, _ := config["executor"].(string)
+	descriptor, ok := executor.GetDescriptor(name)
+	if !ok {
+		return true
+	}
+	for _, param := range descriptor.Params {
+		if keys[param.Name] {
+			return true
+		}
+	}
+	return false
+}
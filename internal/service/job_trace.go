@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// TraceJobs 订阅作业完成、失败与取消事件，补记覆盖作业整个生命周期的根 Span，
+// 各任务执行的 Span 均挂在该根 Span 下。ctx 取消后停止
+func TraceJobs(ctx context.Context, bus *events.Bus, jobRepo repository.JobRepository) {
+	if !tracing.Enabled() {
+		return
+	}
+
+	sub := bus.SubscribeBuffered(events.Filter{Types: []events.Type{events.JobCompleted, events.JobFailed, events.JobCancelled}}, 256)
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				job, err := jobRepo.GetByID(event.JobID)
+				if err != nil {
+					logger.Errorf("Failed to load job %d for tracing: %v", event.JobID, err)
+					continue
+				}
+
+				end := event.OccurredAt
+				if job.CompletedAt.Valid {
+					end = job.CompletedAt.Time
+				}
+				var jobErr error
+				switch event.Type {
+				case events.JobFailed:
+					jobErr = errors.New("job failed")
+				case events.JobCancelled:
+					jobErr = errors.New("job cancelled")
+				}
+				tracing.RecordSpan(tracing.JobSpanContext(job.ID, job.CreatedAt), "job", job.CreatedAt, end, jobErr,
+					tracing.Int64("job.id", job.ID),
+					tracing.String("job.name", job.JobName),
+					tracing.Int64("flow.id", job.FlowID),
+					tracing.String("job.status", string(job.Status)),
+				)
+			}
+		}
+	}()
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+)
+
+type projectKey struct{}
+
+// WithProjectID 返回携带当前项目的 ctx，WorkflowService.WithContext 据此隔离数据
+func WithProjectID(ctx context.Context, projectID int64) context.Context {
+	return context.WithValue(ctx, projectKey{}, projectID)
+}
+
+// ProjectIDFromContext 获取 ctx 中的当前项目，未指定时返回 0（不限项目，用于内部调用）
+func ProjectIDFromContext(ctx context.Context) int64 {
+	id, _ := ctx.Value(projectKey{}).(int64)
+	return id
+}
+
+// slugPattern 项目标识：小写字母、数字与连字符，不能为纯数字（纯数字按项目ID解析）
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// secretNamePattern 密钥名称，需可用于 ${secrets.<name>} 表达式
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,99}$`)
+
+// ProjectService 项目服务：项目、成员、执行器白名单与密钥
+type ProjectService struct {
+	repo    repository.ProjectRepository
+	secrets repository.ProjectSecretRepository
+	authz   *Authorizer
+}
+
+// NewProjectService 创建项目服务
+func NewProjectService(repo repository.ProjectRepository, secrets repository.ProjectSecretRepository, authz *Authorizer) *ProjectService {
+	return &ProjectService{repo: repo, secrets: secrets, authz: authz}
+}
+
+// Resolve 按ID或标识查找项目，ref 为空时返回默认项目
+func (s *ProjectService) Resolve(ref string) (*models.Project, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return s.repo.GetByID(models.DefaultProjectID)
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return s.repo.GetByID(id)
+	}
+	return s.repo.GetBySlug(ref)
+}
+
+// Authorize 校验当前用户能否访问项目：须为项目成员或全局管理员；未启用认证时不限制
+func (s *ProjectService) Authorize(ctx context.Context, projectID int64) error {
+	_, err := s.membership(ctx, projectID, false)
+	return err
+}
+
+// membership 返回当前用户在项目中的成员记录，全局管理员与未认证的内部调用返回 nil
+func (s *ProjectService) membership(ctx context.Context, projectID int64, requireOwner bool) (*models.ProjectMember, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	if s.authz.Check(ctx, PermProjectAdmin, 0) == nil {
+		return nil, nil
+	}
+
+	member, err := s.repo.GetMember(projectID, p.UserID)
+	if err != nil {
+		return nil, forbiddenErrorf("user %d is not a member of project %d", p.UserID, projectID)
+	}
+	if requireOwner && member.Role != models.ProjectRoleOwner {
+		return nil, forbiddenErrorf("user %d is not an owner of project %d", p.UserID, projectID)
+	}
+	return member, nil
+}
+
+// validateProject 校验项目名称、标识与执行器白名单
+func validateProject(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return validationErrorf("name is required")
+	}
+	if !slugPattern.MatchString(project.Slug) {
+		return validationErrorf("slug must match %s", slugPattern)
+	}
+	if _, err := strconv.ParseInt(project.Slug, 10, 64); err == nil {
+		return validationErrorf("slug must not be numeric")
+	}
+	for _, name := range project.AllowedExecutors {
+		if _, err := executor.GetExecutor(name); err != nil {
+			return validationErrorf("unknown executor in allowed_executors: %s", name)
+		}
+	}
+	return nil
+}
+
+// Create 创建项目，创建人成为项目 owner
+func (s *ProjectService) Create(ctx context.Context, project *models.Project) error {
+	if err := validateProject(project); err != nil {
+		return err
+	}
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		project.CreatedBy = p.UserID
+	}
+	if err := s.repo.Create(project); err != nil {
+		return err
+	}
+
+	if project.CreatedBy == 0 {
+		return nil
+	}
+	return s.repo.AddMember(&models.ProjectMember{
+		ProjectID: project.ID,
+		UserID:    project.CreatedBy,
+		Role:      models.ProjectRoleOwner,
+	})
+}
+
+// List 获取当前用户可访问的项目；全局管理员与未启用认证时返回全部项目
+func (s *ProjectService) List(ctx context.Context, limit, offset int) ([]models.Project, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if p, ok := auth.PrincipalFromContext(ctx); ok && s.authz.Check(ctx, PermProjectAdmin, 0) != nil {
+		return s.repo.ListByUserID(p.UserID)
+	}
+	return s.repo.List(limit, offset)
+}
+
+// Get 获取项目，须有访问权限
+func (s *ProjectService) Get(ctx context.Context, projectID int64) (*models.Project, error) {
+	project, err := s.repo.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Authorize(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// Update 更新项目名称、描述与执行器白名单，须为项目 owner
+func (s *ProjectService) Update(ctx context.Context, project *models.Project) error {
+	existing, err := s.repo.GetByID(project.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.membership(ctx, project.ID, true); err != nil {
+		return err
+	}
+	project.Slug = existing.Slug
+	if err := validateProject(project); err != nil {
+		return err
+	}
+	return s.repo.Update(project)
+}
+
+// ListMembers 获取项目成员，须有访问权限
+func (s *ProjectService) ListMembers(ctx context.Context, projectID int64) ([]models.ProjectMember, error) {
+	if err := s.Authorize(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(projectID)
+}
+
+// AddMember 添加成员或修改成员角色，须为项目 owner
+func (s *ProjectService) AddMember(ctx context.Context, member *models.ProjectMember) error {
+	if member.UserID <= 0 {
+		return validationErrorf("user_id is required")
+	}
+	if member.Role == "" {
+		member.Role = models.ProjectRoleMember
+	}
+	if member.Role != models.ProjectRoleOwner && member.Role != models.ProjectRoleMember {
+		return validationErrorf("role must be %s or %s", models.ProjectRoleOwner, models.ProjectRoleMember)
+	}
+	if _, err := s.repo.GetByID(member.ProjectID); err != nil {
+		return err
+	}
+	if _, err := s.membership(ctx, member.ProjectID, true); err != nil {
+		return err
+	}
+	return s.repo.AddMember(member)
+}
+
+// RemoveMember 移除成员，须为项目 owner
+func (s *ProjectService) RemoveMember(ctx context.Context, projectID, userID int64) error {
+	if _, err := s.membership(ctx, projectID, true); err != nil {
+		return err
+	}
+	return s.repo.RemoveMember(projectID, userID)
+}
+
+// ListSecrets 获取项目密钥（不含值），须为项目 owner
+func (s *ProjectService) ListSecrets(ctx context.Context, projectID int64) ([]models.ProjectSecret, error) {
+	if _, err := s.membership(ctx, projectID, true); err != nil {
+		return nil, err
+	}
+	return s.secrets.List(projectID)
+}
+
+// SetSecret 写入项目密钥，须为项目 owner
+func (s *ProjectService) SetSecret(ctx context.Context, secret *models.ProjectSecret) error {
+	if !secretNamePattern.MatchString(secret.Name) {
+		return validationErrorf("secret name must match %s", secretNamePattern)
+	}
+	if _, err := s.repo.GetByID(secret.ProjectID); err != nil {
+		return err
+	}
+	if _, err := s.membership(ctx, secret.ProjectID, true); err != nil {
+		return err
+	}
+	return s.secrets.Set(secret)
+}
+
+// DeleteSecret 删除项目密钥，须为项目 owner
+func (s *ProjectService) DeleteSecret(ctx context.Context, projectID int64, name string) error {
+	if _, err := s.membership(ctx, projectID, true); err != nil {
+		return err
+	}
+	return s.secrets.Delete(projectID, name)
+}
+
+// Secrets 返回项目密钥的名称到值映射，供执行时解析 ${secrets.<name>}
+func (s *ProjectService) Secrets(projectID int64) (map[string]string, error) {
+	secrets, err := s.secrets.List(projectID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		values[secret.Name] = secret.Value
+	}
+	return values, nil
+}
+
+// Project 获取项目（内部使用，不校验权限）
+func (s *ProjectService) Project(projectID int64) (*models.Project, error) {
+	return s.repo.GetByID(projectID)
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+const (
+	// defaultRetentionBatchSize 每个事务清理的作业数
+	defaultRetentionBatchSize = 100
+	// reportPathKey HTML 报告执行器输出中报告文件路径的键
+	reportPathKey = "report_path"
+)
+
+// RetentionService 作业保留策略与清理：按流程策略删除或归档已结束的旧作业，
+// 同时清理作业的制品对象与报告文件，并记录每次清理的结果
+type RetentionService struct {
+	db             *sql.DB
+	repo           repository.RetentionRepository
+	flowRepo       repository.FlowRepository
+	jobRepo        repository.JobRepository
+	jobTaskLogRepo repository.JobTaskLogRepository
+	jobContextRepo repository.JobContextRepository
+	artifacts      *ArtifactService
+	authz          *Authorizer
+	interval       time.Duration
+	batchSize      int
+	archiveDir     string
+	reportsDir     string
+
+	running sync.Mutex // 同一时间只进行一次清理
+}
+
+// NewRetentionService 创建作业保留服务；interval 为后台清理间隔，0 表示只在手动触发时清理
+func NewRetentionService(
+	db *sql.DB,
+	repo repository.RetentionRepository,
+	flowRepo repository.FlowRepository,
+	jobRepo repository.JobRepository,
+	jobTaskLogRepo repository.JobTaskLogRepository,
+	jobContextRepo repository.JobContextRepository,
+	artifacts *ArtifactService,
+	authz *Authorizer,
+	interval time.Duration,
+	batchSize int,
+	archiveDir string,
+	reportsDir string,
+) *RetentionService {
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+	return &RetentionService{
+		db:             db,
+		repo:           repo,
+		flowRepo:       flowRepo,
+		jobRepo:        jobRepo,
+		jobTaskLogRepo: jobTaskLogRepo,
+		jobContextRepo: jobContextRepo,
+		artifacts:      artifacts,
+		authz:          authz,
+		interval:       interval,
+		batchSize:      batchSize,
+		archiveDir:     archiveDir,
+		reportsDir:     reportsDir,
+	}
+}
+
+// Start 启动后台清理，ctx 取消后停止
+func (s *RetentionService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !s.running.TryLock() {
+				continue
+			}
+			run, err := s.purge(ctx, false)
+			s.running.Unlock()
+			if err != nil {
+				logger.Ctx(ctx).Errorf("Retention run failed: %v", err)
+				continue
+			}
+			if run.JobsDeleted > 0 {
+				logger.Ctx(ctx).With("retention_run_id", run.ID).Infof("Retention removed %d jobs (%d archived), %d report files and %d artifacts",
+					run.JobsDeleted, run.JobsArchived, run.FilesRemoved, run.ArtifactsRemoved)
+			}
+		}
+	}()
+}
+
+// ListPolicies 获取默认策略及当前项目流程的保留策略
+func (s *RetentionService) ListPolicies(ctx context.Context) ([]models.RetentionPolicy, error) {
+	policies, err := s.repo.WithContext(ctx).ListPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	visible := policies[:0]
+	for _, policy := range policies {
+		if s.inProject(ctx, policy.FlowID) {
+			visible = append(visible, policy)
+		}
+	}
+	return visible, nil
+}
+
+// GetPolicy 获取默认策略或当前项目流程的保留策略
+func (s *RetentionService) GetPolicy(ctx context.Context, id int64) (*models.RetentionPolicy, error) {
+	policy, err := s.repo.WithContext(ctx).GetPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.inProject(ctx, policy.FlowID) {
+		return nil, fmt.Errorf("retention policy not found")
+	}
+	return policy, nil
+}
+
+// inProject 判断策略所属流程是否在当前项目中，默认策略对所有项目可见
+func (s *RetentionService) inProject(ctx context.Context, flowID sql.NullInt64) bool {
+	if !flowID.Valid {
+		return true
+	}
+	_, err := s.flowRepo.WithProject(ProjectIDFromContext(ctx)).GetByID(flowID.Int64)
+	return err == nil
+}
+
+// SetPolicy 设置流程的保留策略（flow_id 为空时设置默认策略，须拥有全局项目管理权限），已存在时覆盖
+func (s *RetentionService) SetPolicy(ctx context.Context, policy *models.RetentionPolicy) error {
+	if policy.KeepDays < 0 || policy.KeepJobs < 0 || policy.FailedKeepDays < 0 {
+		return validationErrorf("keep_days, keep_jobs and failed_keep_days must not be negative")
+	}
+	switch policy.Action {
+	case "":
+		policy.Action = models.RetentionActionDelete
+	case models.RetentionActionDelete, models.RetentionActionArchiveFile, models.RetentionActionArchiveTable:
+	default:
+		return validationErrorf("invalid action %q, expected delete, archive_file or archive_table", policy.Action)
+	}
+
+	repo := s.repo.WithContext(ctx)
+	if !s.inProject(ctx, policy.FlowID) {
+		return validationErrorf("flow %d not found", policy.FlowID.Int64)
+	}
+	if err := s.checkEdit(ctx, policy.FlowID); err != nil {
+		return err
+	}
+
+	existing, err := repo.GetPolicyByFlowID(policy.FlowID.Int64)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if err := repo.CreatePolicy(policy); err != nil {
+			return err
+		}
+	} else {
+		policy.ID = existing.ID
+		if err := repo.UpdatePolicy(policy); err != nil {
+			return err
+		}
+	}
+
+	saved, err := repo.GetPolicy(policy.ID)
+	if err != nil {
+		return err
+	}
+	*policy = *saved
+	return nil
+}
+
+// DeletePolicy 删除保留策略，流程随之改用默认策略
+func (s *RetentionService) DeletePolicy(ctx context.Context, id int64) error {
+	policy, err := s.GetPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEdit(ctx, policy.FlowID); err != nil {
+		return err
+	}
+	return s.repo.WithContext(ctx).DeletePolicy(id)
+}
+
+// checkEdit 校验能否修改策略：流程策略须拥有该流程的编辑权限；默认策略作用于所有项目的流程，须拥有全局项目管理权限
+func (s *RetentionService) checkEdit(ctx context.Context, flowID sql.NullInt64) error {
+	if !flowID.Valid {
+		return s.authz.Check(ctx, PermProjectAdmin, 0)
+	}
+	return s.authz.Check(ctx, PermFlowEdit, flowID.Int64)
+}
+
+// ListRuns 获取清理记录，最近的在前；清理记录包含所有项目的作业，须拥有全局项目管理权限
+func (s *RetentionService) ListRuns(ctx context.Context, limit, offset int) ([]models.RetentionRun, error) {
+	if err := s.authz.Check(ctx, PermProjectAdmin, 0); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.repo.WithContext(ctx).ListRuns(limit, offset)
+}
+
+// Run 立即按当前策略清理一次，dryRun 时只统计将被清理的作业；须拥有全局项目管理权限
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) (*models.RetentionRun, error) {
+	if err := s.authz.Check(ctx, PermProjectAdmin, 0); err != nil {
+		return nil, err
+	}
+	if !s.running.TryLock() {
+		return nil, validationErrorf("a retention run is already in progress")
+	}
+	defer s.running.Unlock()
+
+	return s.purge(ctx, dryRun)
+}
+
+// purge 清理所有流程的过期作业并记录结果；清理中断时已处理的作业仍计入结果
+func (s *RetentionService) purge(ctx context.Context, dryRun bool) (*models.RetentionRun, error) {
+	repo := s.repo.WithContext(ctx)
+	run := &models.RetentionRun{DryRun: dryRun, StartedAt: time.Now(), Details: models.RetentionDetails{}}
+	if err := repo.CreateRun(run); err != nil {
+		return nil, err
+	}
+
+	err := s.purgeFlows(ctx, run)
+	run.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if ferr := repo.FinishRun(run); ferr != nil && err == nil {
+		err = ferr
+	}
+	return run, err
+}
+
+// purgeFlows 按流程的保留策略（没有或未启用时使用默认策略）分批清理过期作业
+func (s *RetentionService) purgeFlows(ctx context.Context, run *models.RetentionRun) error {
+	repo := s.repo.WithContext(ctx)
+	defaultPolicy, err := repo.GetPolicyByFlowID(0)
+	if err != nil {
+		return err
+	}
+	flowIDs, err := repo.ListFlowIDs()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, flowID := range flowIDs {
+		policy, err := repo.GetPolicyByFlowID(flowID)
+		if err != nil {
+			return err
+		}
+		if policy == nil || !policy.IsActive {
+			policy = defaultPolicy
+		}
+		if policy == nil || !policy.IsActive {
+			continue
+		}
+
+		result := models.RetentionFlowResult{FlowID: flowID, PolicyID: policy.ID, Action: policy.Action, JobIDs: []int64{}}
+		err = s.purgeFlow(ctx, run, policy, retentionCutoff(policy, now), &result)
+		if len(result.JobIDs) > 0 {
+			run.Details = append(run.Details, result)
+		}
+		if err != nil {
+			return fmt.Errorf("flow %d: %w", flowID, err)
+		}
+	}
+	return nil
+}
+
+// purgeFlow 分批清理单个流程的过期作业
+func (s *RetentionService) purgeFlow(ctx context.Context, run *models.RetentionRun, policy *models.RetentionPolicy,
+	cutoff models.RetentionCutoff, result *models.RetentionFlowResult) error {
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ids, err := s.repo.WithContext(ctx).ListExpiredJobIDs(result.FlowID, cutoff, afterID, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+
+		if run.DryRun {
+			result.JobIDs = append(result.JobIDs, ids...)
+			run.JobsDeleted += len(ids)
+			if policy.Action != models.RetentionActionDelete {
+				run.JobsArchived += len(ids)
+			}
+		} else if err := s.purgeJobs(ctx, run, policy, ids, result); err != nil {
+			return err
+		}
+
+		if len(ids) < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// purgeJobs 在一个事务中锁定、归档并删除一批作业，提交后删除其制品对象与报告文件
+func (s *RetentionService) purgeJobs(ctx context.Context, run *models.RetentionRun, policy *models.RetentionPolicy,
+	ids []int64, result *models.RetentionFlowResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	repo := s.repo.WithTx(tx).WithContext(ctx)
+	ids, err = repo.LockFinishedJobs(ids)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	snapshots := make([]models.JobSnapshot, len(ids))
+	for i, id := range ids {
+		if err := s.snapshot(ctx, tx, id, &snapshots[i]); err != nil {
+			return err
+		}
+	}
+
+	var archiveFile string
+	switch policy.Action {
+	case models.RetentionActionArchiveFile:
+		if archiveFile, err = s.writeArchiveFile(result.FlowID, snapshots); err != nil {
+			return err
+		}
+	case models.RetentionActionArchiveTable:
+		for i := range snapshots {
+			if err := repo.CreateArchive(newJobArchive(&snapshots[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	deleted, err := repo.DeleteJobs(ids)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	result.JobIDs = append(result.JobIDs, ids...)
+	run.JobsDeleted += int(deleted)
+	if policy.Action != models.RetentionActionDelete {
+		run.JobsArchived += len(ids)
+	}
+	if archiveFile != "" {
+		result.ArchiveFiles = append(result.ArchiveFiles, archiveFile)
+	}
+
+	// 作业记录已删除，文件清理失败只记录日志，不中断清理
+	for i := range snapshots {
+		n, err := s.artifacts.DeleteObjects(ctx, snapshots[i].Artifacts)
+		if err != nil {
+			logger.Ctx(ctx).With("job_id", snapshots[i].Job.ID).Warnf("Retention failed to delete artifacts: %v", err)
+		}
+		result.Artifacts += n
+		run.ArtifactsRemoved += n
+
+		for _, path := range s.reportFiles(&snapshots[i]) {
+			if err := os.Remove(path); err != nil {
+				if !os.IsNotExist(err) {
+					logger.Ctx(ctx).With("job_id", snapshots[i].Job.ID).Warnf("Retention failed to remove report %s: %v", path, err)
+				}
+				continue
+			}
+			result.FilesRemoved = append(result.FilesRemoved, path)
+			run.FilesRemoved++
+		}
+	}
+	return nil
+}
+
+// snapshot 在事务中读取作业及其任务、审计日志、上下文与制品信息
+func (s *RetentionService) snapshot(ctx context.Context, tx *sql.Tx, jobID int64, snap *models.JobSnapshot) error {
+	job, tasks, err := s.jobRepo.WithTx(tx).WithContext(ctx).GetJobWithTasks(jobID)
+	if err != nil {
+		return err
+	}
+	logs, err := s.jobTaskLogRepo.WithTx(tx).WithContext(ctx).ListByJobID(jobID)
+	if err != nil {
+		return err
+	}
+	jobContext, err := s.jobContextRepo.WithTx(tx).GetByJobID(jobID)
+	if err != nil {
+		return err
+	}
+	artifacts, err := s.artifacts.ListByJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	*snap = models.JobSnapshot{Job: *job, Tasks: tasks, Logs: logs, Context: jobContext, Artifacts: artifacts}
+	return nil
+}
+
+// reportFiles 返回作业任务输出中位于报告目录内的报告文件
+func (s *RetentionService) reportFiles(snap *models.JobSnapshot) []string {
+	if s.reportsDir == "" {
+		return nil
+	}
+	root, err := filepath.Abs(s.reportsDir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for i := range snap.Tasks {
+		path, ok := snap.Tasks[i].Result[reportPathKey].(string)
+		if !ok || path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil || !strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+// writeArchiveFile 将一批作业写入 <归档目录>/flow-<流程ID>/jobs-<首个作业ID>-<末个作业ID>.json.gz
+func (s *RetentionService) writeArchiveFile(flowID int64, snapshots []models.JobSnapshot) (string, error) {
+	dir := filepath.Join(s.archiveDir, fmt.Sprintf("flow-%d", flowID))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("jobs-%d-%d.json.gz", snapshots[0].Job.ID, snapshots[len(snapshots)-1].Job.ID))
+
+	// 先写临时文件再重命名，避免中断时留下不完整的归档
+	tmp, err := os.CreateTemp(dir, ".archive-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(zw).Encode(snapshots); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return path, nil
+}
+
+// newJobArchive 将作业数据转换为归档表记录
+func newJobArchive(snap *models.JobSnapshot) *models.JobArchive {
+	data, _ := json.Marshal(snap)
+	return &models.JobArchive{
+		JobID:        snap.Job.ID,
+		ProjectID:    snap.Job.ProjectID,
+		FlowID:       snap.Job.FlowID,
+		JobName:      snap.Job.JobName,
+		Status:       snap.Job.Status,
+		Data:         data,
+		JobCreatedAt: snap.Job.CreatedAt,
+	}
+}
+
+// retentionCutoff 按策略换算清理界限：失败作业未单独设置保留天数时沿用 keep_days
+func retentionCutoff(policy *models.RetentionPolicy, now time.Time) models.RetentionCutoff {
+	cutoff := models.RetentionCutoff{KeepLatest: policy.KeepJobs}
+	if policy.KeepDays > 0 {
+		cutoff.FinishedBefore = now.AddDate(0, 0, -policy.KeepDays)
+	}
+	failedKeepDays := policy.FailedKeepDays
+	if failedKeepDays == 0 {
+		failedKeepDays = policy.KeepDays
+	}
+	if failedKeepDays > 0 {
+		cutoff.FailedBefore = now.AddDate(0, 0, -failedKeepDays)
+	}
+	return cutoff
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/artifact"
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/migrations"
+	"github.com/cfrs2005/GoWorkFlow/pkg/database"
+)
+
+// newTestDB 创建已执行内置迁移的 SQLite 数据库
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.NewDB(database.Config{
+		Driver: "sqlite",
+		DSN:    filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatal(err)
+	}
+	return db.DB
+}
+
+func TestRetentionPolicyProjectIsolation(t *testing.T) {
+	db := newTestDB(t)
+	flows := repository.NewFlowRepository(db)
+	jobs := repository.NewJobRepository(db)
+	roles := repository.NewRoleBindingRepository(db)
+
+	projectB := &models.Project{Name: "B", Slug: "b"}
+	if err := repository.NewProjectRepository(db).Create(projectB); err != nil {
+		t.Fatal(err)
+	}
+	flowA := &models.Flow{Name: "a", Version: "1.0", IsActive: true}
+	if err := flows.WithProject(models.DefaultProjectID).Create(flowA); err != nil {
+		t.Fatal(err)
+	}
+	flowB := &models.Flow{Name: "b", Version: "1.0", IsActive: true}
+	if err := flows.WithProject(projectB.ID).Create(flowB); err != nil {
+		t.Fatal(err)
+	}
+	jobA := &models.Job{FlowID: flowA.ID, JobName: "a", Status: models.JobStatusCompleted}
+	jobB := &models.Job{FlowID: flowB.ID, JobName: "b", Status: models.JobStatusCompleted}
+	for _, job := range []*models.Job{jobA, jobB} {
+		if err := jobs.Create(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE jobs SET status = 'completed', completed_at = '2020-01-01 00:00:00'`); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户 2 是全局 flow_editor，用户 1 是全局 admin
+	for _, binding := range []models.RoleBinding{{UserID: 1, Role: RoleAdmin}, {UserID: 2, Role: RoleFlowEditor}} {
+		if err := roles.Create(&binding); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := artifact.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewRetentionService(db, repository.NewRetentionRepository(db), flows, jobs,
+		repository.NewJobTaskLogRepository(db), repository.NewJobContextRepository(db),
+		NewArtifactService(store, repository.NewArtifactRepository(db)), NewAuthorizer(roles),
+		0, 0, t.TempDir(), t.TempDir())
+
+	inProjectA := func(userID int64) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID})
+		return WithProjectID(ctx, models.DefaultProjectID)
+	}
+	editor, admin := inProjectA(2), inProjectA(1)
+
+	var forbidden *ForbiddenError
+	var invalid *ValidationError
+	err = svc.SetPolicy(editor, &models.RetentionPolicy{KeepDays: 1, IsActive: true})
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("flow_editor SetPolicy(default) error = %v, want ForbiddenError", err)
+	}
+	err = svc.SetPolicy(editor, &models.RetentionPolicy{FlowID: sql.NullInt64{Int64: flowB.ID, Valid: true}, KeepDays: 1, IsActive: true})
+	if !errors.As(err, &invalid) {
+		t.Fatalf("SetPolicy(flow of project B) error = %v, want ValidationError", err)
+	}
+	if err := svc.SetPolicy(editor, &models.RetentionPolicy{FlowID: sql.NullInt64{Int64: flowA.ID, Valid: true}, KeepDays: 1, IsActive: true}); err != nil {
+		t.Fatalf("SetPolicy(flow of project A) error = %v", err)
+	}
+
+	defaultPolicy := &models.RetentionPolicy{KeepDays: 3650, IsActive: true}
+	if err := svc.SetPolicy(admin, defaultPolicy); err != nil {
+		t.Fatalf("admin SetPolicy(default) error = %v", err)
+	}
+	if err := svc.DeletePolicy(editor, defaultPolicy.ID); !errors.As(err, &forbidden) {
+		t.Fatalf("flow_editor DeletePolicy(default) error = %v, want ForbiddenError", err)
+	}
+
+	run, err := svc.Run(admin, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.JobsDeleted != 1 {
+		t.Fatalf("run deleted %d jobs, want 1", run.JobsDeleted)
+	}
+	if _, err := jobs.GetByID(jobA.ID); err == nil {
+		t.Fatalf("job %d of project A was not purged", jobA.ID)
+	}
+	if _, err := jobs.GetByID(jobB.ID); err != nil {
+		t.Fatalf("job %d of project B was purged: %v", jobB.ID, err)
+	}
+}
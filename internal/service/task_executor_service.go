@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cfrs2005/GoWorkFlow/internal/engine"
 	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/metrics"
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
 	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/internal/resolver"
+	"github.com/cfrs2005/GoWorkFlow/internal/tracing"
 	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
 )
 
@@ -19,7 +23,13 @@ type TaskExecutorService struct {
 	jobTaskRepo    repository.JobTaskRepository
 	jobContextRepo repository.JobContextRepository
 	taskRepo       repository.TaskRepository
+	flowTaskRepo   repository.FlowTaskRepository
 	engine         engine.WorkflowEngine
+	artifacts      *ArtifactService
+	executionLogs  *ExecutionLogService
+	metrics        *metrics.Metrics
+	authz          *Authorizer
+	projects       *ProjectService
 }
 
 // NewTaskExecutorService 创建任务执行服务
@@ -28,138 +38,370 @@ func NewTaskExecutorService(
 	jobTaskRepo repository.JobTaskRepository,
 	jobContextRepo repository.JobContextRepository,
 	taskRepo repository.TaskRepository,
+	flowTaskRepo repository.FlowTaskRepository,
 	workflowEngine engine.WorkflowEngine,
+	artifactService *ArtifactService,
+	executionLogService *ExecutionLogService,
+	metrics *metrics.Metrics,
+	authz *Authorizer,
+	projects *ProjectService,
 ) *TaskExecutorService {
 	return &TaskExecutorService{
 		jobRepo:        jobRepo,
 		jobTaskRepo:    jobTaskRepo,
 		jobContextRepo: jobContextRepo,
 		taskRepo:       taskRepo,
+		flowTaskRepo:   flowTaskRepo,
 		engine:         workflowEngine,
+		artifacts:      artifactService,
+		executionLogs:  executionLogService,
+		metrics:        metrics,
+		authz:          authz,
+		projects:       projects,
 	}
 }
 
+// AuthorizeJob 校验当前用户能否自动执行作业；执行在后台进行，调用方应在启动前同步校验
+func (s *TaskExecutorService) AuthorizeJob(ctx context.Context, jobID int64) error {
+	job, err := s.jobRepo.WithProject(ProjectIDFromContext(ctx)).GetByID(jobID)
+	if err != nil {
+		return err
+	}
+	return s.authz.Check(ctx, PermJobOperate, job.FlowID)
+}
+
+// AuthorizeJobTask 校验当前用户能否自动执行作业任务
+func (s *TaskExecutorService) AuthorizeJobTask(ctx context.Context, jobTaskID int64) error {
+	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
+	if err != nil {
+		return err
+	}
+	return s.AuthorizeJob(ctx, jobTask.JobID)
+}
+
 // ExecuteTask 自动执行任务
-func (s *TaskExecutorService) ExecuteTask(ctx context.Context, jobTaskID int64) error {
+func (s *TaskExecutorService) ExecuteTask(ctx context.Context, jobTaskID int64) (err error) {
 	// 获取任务信息
 	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
 	if err != nil {
 		return fmt.Errorf("failed to get job task: %w", err)
 	}
 
+	// 任务执行记录为作业根 Span 的子 Span，同一作业的所有任务归属同一条追踪
+	job, err := s.jobRepo.GetByID(jobTask.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	ctx = tracing.ContextWithRemoteParent(ctx, tracing.JobSpanContext(job.ID, job.CreatedAt))
+	ctx, span := tracing.Start(ctx, "job_task.execute", tracing.WithAttributes(
+		tracing.Int64("job.id", job.ID),
+		tracing.Int64("flow.id", job.FlowID),
+		tracing.Int64("job_task.id", jobTaskID),
+		tracing.Int("job_task.sequence", jobTask.Sequence),
+	))
+
+	// 本次执行的日志（含引擎与执行器）均携带作业、任务与追踪关联字段
+	ctx = logger.WithFields(ctx, "job_id", job.ID, "job_task_id", jobTaskID, "flow_id", job.FlowID)
+	if span != nil {
+		ctx = logger.WithFields(ctx, "trace_id", span.SpanContext().TraceID.String())
+	}
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// 引擎状态变更与数据库读取沿用追踪但不随执行超时或取消而中断，保证失败状态能够写回
+	dbCtx := context.WithoutCancel(ctx)
+	eng := s.engine.WithContext(dbCtx)
+
 	// 获取任务定义
 	task, err := s.taskRepo.GetByID(jobTask.TaskID)
 	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
+	span.SetAttributes(tracing.String("task.name", task.Name))
 
 	// 只自动执行 automated 类型的任务
 	if task.TaskType != models.TaskTypeAutomated {
-		logger.Infof("Task %d is not automated (type: %s), skipping auto execution", task.ID, task.TaskType)
+		logger.Ctx(ctx).Infof("Task %d is not automated (type: %s), skipping auto execution", task.ID, task.TaskType)
 		return nil
 	}
 
 	// 标记任务开始
-	if err := s.engine.StartTask(jobTaskID, 0); err != nil {
+	if err := eng.StartTask(jobTaskID, 0); err != nil {
 		return fmt.Errorf("failed to start task: %w", err)
 	}
 
-	logger.Infof("Starting automated execution for task %d (job task %d)", task.ID, jobTaskID)
+	logger.Ctx(ctx).Infof("Starting automated execution for task %d (job task %d)", task.ID, jobTaskID)
+
+	// 为本次执行提供日志接收器，执行结束（完成或失败）后关闭日志订阅
+	if s.executionLogs != nil {
+		ctx = executor.WithLogSink(ctx, s.executionLogs.Sink(jobTaskID))
+		defer s.executionLogs.Finish(jobTaskID)
+	}
+	taskLogger := executor.Logger(ctx)
+	if span != nil {
+		taskLogger.Infof("Trace ID: %s", span.SpanContext().TraceID)
+	}
 
-	// 获取 Job Context
-	jobContext, err := s.jobContextRepo.GetByJobID(jobTask.JobID)
+	// 获取 Job Context 及作业中各任务的状态
+	_, jobTasks, err := s.jobRepo.WithContext(dbCtx).GetJobWithTasks(jobTask.JobID)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Failed to get job context: %v", err))
-		jobContext = make(map[string]string)
+		eng.FailTask(jobTaskID, err.Error())
+		return fmt.Errorf("failed to get job tasks: %w", err)
+	}
+	jobContext, err := s.loadJobContext(jobTask.JobID, jobTasks)
+	if err != nil {
+		logger.Ctx(ctx).Warnf("Failed to get job context: %v", err)
+		jobContext = &executor.JobContext{Shared: make(map[string]interface{})}
 	}
 
-	// 从 Job Context 构建输入参数
-	input := make(map[string]interface{})
-	for k, v := range jobContext {
-		input[k] = v
+	// 合并流程级配置覆盖并解析模板表达式
+	config, templated, err := s.resolveTaskConfig(job.ProjectID, jobTask, task, jobTasks, jobContext)
+	if err != nil {
+		taskLogger.Errorf("Failed to resolve task config: %v", err)
+		eng.FailTask(jobTaskID, err.Error())
+		return fmt.Errorf("failed to resolve task config: %w", err)
 	}
 
-	// 合并任务配置到输入
-	if task.Config != nil {
-		for k, v := range task.Config {
-			if _, exists := input[k]; !exists {
-				input[k] = v
-			}
+	// 从 Job Context 构建输入参数
+	input := jobContext.Flatten()
+
+	// 合并任务配置到输入（模板表达式显式指定的参数优先于上下文）
+	for k, v := range config {
+		if _, exists := input[k]; !exists || templated[k] {
+			input[k] = v
 		}
 	}
 
 	// 获取执行器名称
-	executorName, ok := task.Config["executor"].(string)
+	executorName, ok := config["executor"].(string)
 	if !ok || executorName == "" {
+		eng.FailTask(jobTaskID, "task config missing 'executor' field")
 		return fmt.Errorf("task config missing 'executor' field")
 	}
 
+	// 校验项目的执行器白名单
+	if s.projects != nil {
+		project, err := s.projects.Project(job.ProjectID)
+		if err != nil {
+			eng.FailTask(jobTaskID, err.Error())
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		if !project.AllowsExecutor(executorName) {
+			msg := fmt.Sprintf("executor %s is not allowed in project %s", executorName, project.Slug)
+			s.metrics.IncExecutorError(executorName, metrics.ErrorTypeForbidden)
+			eng.FailTask(jobTaskID, msg)
+			return fmt.Errorf("%s", msg)
+		}
+	}
+
 	// 获取执行器
 	exec, err := executor.GetExecutor(executorName)
 	if err != nil {
-		s.engine.FailTask(jobTaskID, fmt.Sprintf("Executor not found: %s", executorName))
+		s.metrics.IncExecutorError(executorName, metrics.ErrorTypeNotFound)
+		eng.FailTask(jobTaskID, fmt.Sprintf("Executor not found: %s", executorName))
 		return fmt.Errorf("failed to get executor: %w", err)
 	}
 
+	// 按执行器契约补全默认值并校验输入
+	descriptor, hasDescriptor := executor.GetDescriptor(executorName)
+	if hasDescriptor {
+		input, err = descriptor.PrepareInput(input)
+		if err != nil {
+			taskLogger.Errorf("Invalid task input: %v", err)
+			s.metrics.IncExecutorError(executorName, metrics.ErrorTypeInput)
+			eng.FailTask(jobTaskID, err.Error())
+			return fmt.Errorf("invalid task input: %w", err)
+		}
+	}
+
+	// 为执行器提供制品写入器
+	if s.artifacts != nil {
+		ctx = executor.WithArtifactWriter(ctx, s.artifacts.Writer(jobTask.JobID, jobTaskID))
+	}
+
 	// 执行任务
-	logger.Infof("Executing task with executor: %s", executorName)
-	result, err := exec.Execute(ctx, input, jobContext)
+	ctx = logger.WithFields(ctx, "executor", executorName)
+	taskLogger = executor.Logger(ctx)
+	taskLogger.Infof("Executing task with executor: %s", executorName)
+	span.SetAttributes(tracing.String("executor", executorName))
+	execCtx, execSpan := tracing.Start(ctx, "executor."+executorName)
+	start := time.Now()
+	result, err := exec.Execute(execCtx, input, jobContext)
+	execSpan.RecordError(err)
+	execSpan.End()
 	if err != nil {
-		logger.Errorf("Task execution failed: %v", err)
-		s.engine.FailTask(jobTaskID, err.Error())
+		s.metrics.ObserveJobTask(executorName, string(models.JobTaskStatusFailed), time.Since(start))
+		s.metrics.IncExecutorError(executorName, executionErrorType(ctx, err))
+		taskLogger.Errorf("Task execution failed: %v", err)
+		eng.FailTask(jobTaskID, err.Error())
 		return fmt.Errorf("execution failed: %w", err)
 	}
 
-	// 保存结果到 Job Context
-	if err := s.saveResultToContext(jobTask.JobID, result); err != nil {
-		logger.Info(fmt.Sprintf("Failed to save result to context: %v", err))
+	// 校验执行结果是否符合契约
+	if hasDescriptor {
+		if err := descriptor.ValidateOutput(result); err != nil {
+			s.metrics.ObserveJobTask(executorName, string(models.JobTaskStatusFailed), time.Since(start))
+			s.metrics.IncExecutorError(executorName, metrics.ErrorTypeOutput)
+			taskLogger.Errorf("Task output validation failed: %v", err)
+			eng.FailTask(jobTaskID, err.Error())
+			return fmt.Errorf("invalid task output: %w", err)
+		}
+	}
+
+	s.metrics.ObserveJobTask(executorName, string(models.JobTaskStatusCompleted), time.Since(start))
+
+	// 保存结果到任务输出命名空间
+	if err := s.saveResultToContext(jobTask.JobID, models.TaskContextScope(task.Name), result); err != nil {
+		logger.Ctx(ctx).Warnf("Failed to save result to context: %v", err)
 	}
 
 	// 标记任务完成
 	taskResult := models.TaskResult(result)
-	if err := s.engine.CompleteTask(jobTaskID, taskResult); err != nil {
+	if err := eng.CompleteTask(jobTaskID, taskResult); err != nil {
 		return fmt.Errorf("failed to complete task: %w", err)
 	}
 
-	logger.Infof("Task %d (job task %d) completed successfully", task.ID, jobTaskID)
+	taskLogger.Infof("Task %d (job task %d) completed successfully", task.ID, jobTaskID)
 
 	return nil
 }
 
-// saveResultToContext 将执行结果保存到 Job Context
-func (s *TaskExecutorService) saveResultToContext(jobID int64, result map[string]interface{}) error {
-	for key, value := range result {
-		// 将值转换为字符串
-		var valueStr string
-		switch v := value.(type) {
-		case string:
-			valueStr = v
-		case int, int64, float64, bool:
-			valueStr = fmt.Sprintf("%v", v)
-		default:
-			// 复杂类型转 JSON
-			jsonBytes, err := json.Marshal(v)
-			if err != nil {
-				logger.Info(fmt.Sprintf("Failed to marshal value for key %s: %v", key, err))
-				continue
-			}
-			valueStr = string(jsonBytes)
+// executionErrorType 区分执行超时、取消与执行器自身的错误
+func executionErrorType(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return metrics.ErrorTypeTimeout
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return metrics.ErrorTypeCanceled
+	default:
+		return metrics.ErrorTypeExecution
+	}
+}
+
+// loadJobContext 加载类型化作业上下文，任务输出命名空间按作业任务执行顺序排列
+func (s *TaskExecutorService) loadJobContext(jobID int64, jobTasks []models.JobTask) (*executor.JobContext, error) {
+	scopes, err := s.jobContextRepo.GetByJobID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobContext := &executor.JobContext{Shared: scopes[models.ContextScopeShared]}
+	if jobContext.Shared == nil {
+		jobContext.Shared = make(map[string]interface{})
+	}
+
+	seen := make(map[string]bool)
+	for _, jt := range jobTasks {
+		if jt.Task == nil || seen[jt.Task.Name] {
+			continue
+		}
+		seen[jt.Task.Name] = true
+		if values, ok := scopes[models.TaskContextScope(jt.Task.Name)]; ok {
+			jobContext.Tasks = append(jobContext.Tasks, executor.TaskOutputs{Name: jt.Task.Name, Values: values})
 		}
+	}
+
+	return jobContext, nil
+}
+
+// resolveTaskConfig 合并任务配置与流程级覆盖，并解析 ${context.*} / ${tasks.*} / ${secrets.*} 表达式
+// 返回解析后的配置以及包含表达式的顶层键
+func (s *TaskExecutorService) resolveTaskConfig(
+	projectID int64,
+	jobTask *models.JobTask,
+	task *models.Task,
+	jobTasks []models.JobTask,
+	jobContext *executor.JobContext,
+) (models.TaskConfig, map[string]bool, error) {
+	flowTask, err := s.flowTaskRepo.GetByID(jobTask.FlowTaskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get flow task: %w", err)
+	}
+	flowTask.Task = task
+	config := flowTask.EffectiveConfig()
+
+	templated := make(map[string]bool)
+	for k, v := range config {
+		if refs, _ := resolver.References(map[string]interface{}{k: v}); len(refs) > 0 {
+			templated[k] = true
+		}
+	}
+	if len(templated) == 0 {
+		return config, templated, nil
+	}
+
+	scope := resolver.Scope{
+		Context: jobContext.Flatten(),
+		Tasks:   make(map[string]map[string]interface{}),
+		Pending: make(map[string]string),
+	}
+	for _, jt := range jobTasks {
+		if jt.Task == nil {
+			continue
+		}
+		if jt.Status == models.JobTaskStatusCompleted && jt.Result != nil {
+			scope.Tasks[jt.Task.Name] = jt.Result
+		} else {
+			scope.Pending[jt.Task.Name] = string(jt.Status)
+		}
+	}
 
-		if err := s.jobContextRepo.Set(jobID, key, valueStr); err != nil {
+	if s.projects != nil {
+		if scope.Secrets, err = s.projects.Secrets(projectID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	resolved, err := resolver.Resolve(config, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	return models.TaskConfig(resolved), templated, nil
+}
+
+// saveResultToContext 将执行结果按原始类型保存到 Job Context 的指定作用域
+func (s *TaskExecutorService) saveResultToContext(jobID int64, scope string, result map[string]interface{}) error {
+	for key, value := range result {
+		if err := s.jobContextRepo.Set(jobID, scope, key, value); err != nil {
 			return fmt.Errorf("failed to set context %s: %w", key, err)
 		}
 	}
 	return nil
 }
 
-// AutoExecuteJobTasks 自动执行作业的所有任务
+// AutoExecuteJobTasks 自动执行作业的所有任务；遇到人工或审批任务时等待其处理完成后继续
 func (s *TaskExecutorService) AutoExecuteJobTasks(ctx context.Context, jobID int64) error {
-	logger.Infof("Starting auto execution for job %d", jobID)
+	return s.autoExecute(ctx, jobID, true)
+}
+
+// ExecuteJobTasks 在当前 goroutine 中连续执行作业的自动化任务（任务之间不等待），
+// 作业结束或下一个待执行任务需要人工处理时返回，用于进程内同步执行
+func (s *TaskExecutorService) ExecuteJobTasks(ctx context.Context, jobID int64) error {
+	return s.autoExecute(ctx, jobID, false)
+}
 
-	// 启动作业
-	if err := s.engine.StartJob(jobID); err != nil {
-		return fmt.Errorf("failed to start job: %w", err)
+// autoExecute 按序执行作业的待执行任务；wait 为 true 时每个任务后短暂等待，并轮询等待非自动化任务被处理，
+// 否则遇到非自动化任务即返回
+func (s *TaskExecutorService) autoExecute(ctx context.Context, jobID int64, wait bool) error {
+	ctx = logger.WithFields(ctx, "job_id", jobID)
+	logger.Ctx(ctx).Infof("Starting auto execution for job %d", jobID)
+
+	// 启动待执行的作业；已在运行的作业（如重试失败任务后）从下一个待执行任务继续
+	job, err := s.jobRepo.GetByID(jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	switch job.Status {
+	case models.JobStatusPending:
+		if err := s.engine.StartJob(jobID); err != nil {
+			return fmt.Errorf("failed to start job: %w", err)
+		}
+	case models.JobStatusRunning:
+	default:
+		logger.Ctx(ctx).Infof("Job %d is %s, skipping auto execution", jobID, job.Status)
+		return nil
 	}
 
 	// 循环执行任务
@@ -171,6 +413,16 @@ func (s *TaskExecutorService) AutoExecuteJobTasks(ctx context.Context, jobID int
 		default:
 		}
 
+		// 作业被取消或已结束时停止
+		job, err := s.jobRepo.GetByID(jobID)
+		if err != nil {
+			return fmt.Errorf("failed to get job: %w", err)
+		}
+		if job.Status != models.JobStatusRunning {
+			logger.Ctx(ctx).Infof("Job %d is %s, stopping auto execution", jobID, job.Status)
+			return nil
+		}
+
 		// 获取下一个待执行的任务
 		nextTask, err := s.engine.GetNextTask(jobID)
 		if err != nil {
@@ -179,25 +431,69 @@ func (s *TaskExecutorService) AutoExecuteJobTasks(ctx context.Context, jobID int
 
 		// 如果没有下一个任务，说明流程已完成
 		if nextTask == nil {
-			logger.Infof("No more tasks for job %d, job completed", jobID)
+			logger.Ctx(ctx).Infof("No more tasks for job %d, job completed", jobID)
 			break
 		}
 
-		logger.Infof("Executing next task: job_task_id=%d, task_id=%d, sequence=%d",
+		if !wait {
+			task, err := s.taskRepo.GetByID(nextTask.TaskID)
+			if err != nil {
+				return fmt.Errorf("failed to get task: %w", err)
+			}
+			if task.TaskType != models.TaskTypeAutomated {
+				logger.Ctx(ctx).Infof("Job %d is waiting for %s task %d, stopping execution", jobID, task.TaskType, nextTask.ID)
+				return nil
+			}
+		}
+
+		logger.Ctx(ctx).Infof("Executing next task: job_task_id=%d, task_id=%d, sequence=%d",
 			nextTask.ID, nextTask.TaskID, nextTask.Sequence)
 
 		// 执行任务
 		if err := s.ExecuteTask(ctx, nextTask.ID); err != nil {
-			logger.Errorf("Failed to execute task %d: %v", nextTask.ID, err)
+			logger.Ctx(ctx).Errorf("Failed to execute task %d: %v", nextTask.ID, err)
 			// 继续尝试下一个任务，或者根据策略决定是否中断
 			// 这里选择中断
 			return fmt.Errorf("task execution failed: %w", err)
 		}
 
 		// 短暂等待，避免过快执行
-		time.Sleep(1 * time.Second)
+		if wait {
+			time.Sleep(1 * time.Second)
+		}
 	}
 
-	logger.Infof("Job %d auto execution completed", jobID)
+	logger.Ctx(ctx).Infof("Job %d auto execution completed", jobID)
 	return nil
 }
+
+// AutoExecuteJobs 自动执行多个作业，最多 concurrency 个作业同时执行，每个作业最长执行 timeout；
+// 所有作业结束或 ctx 取消后返回
+func (s *TaskExecutorService) AutoExecuteJobs(ctx context.Context, jobIDs []int64, concurrency int, timeout time.Duration) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for _, jobID := range jobIDs {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		wg.Add(1)
+		go func(jobID int64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			jobCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if err := s.AutoExecuteJobTasks(jobCtx, jobID); err != nil {
+				logger.Ctx(ctx).Errorf("Auto execution failed for job %d: %v", jobID, err)
+			}
+		}(jobID)
+	}
+}
@@ -0,0 +1,486 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+const (
+	// WebhookSignatureHeader 签名请求头：sha256=HMAC-SHA256(secret, timestamp + "." + body)
+	WebhookSignatureHeader = "X-GoWorkFlow-Signature"
+	// WebhookTimestampHeader 签名时间戳（Unix 秒）请求头
+	WebhookTimestampHeader = "X-GoWorkFlow-Timestamp"
+	// WebhookEventHeader 事件类型请求头
+	WebhookEventHeader = "X-GoWorkFlow-Event"
+	// WebhookDeliveryHeader 投递记录 ID 请求头，重试时保持不变
+	WebhookDeliveryHeader = "X-GoWorkFlow-Delivery"
+
+	webhookPollInterval   = time.Second
+	webhookBatchSize      = 50
+	webhookMaxBackoff     = time.Hour
+	webhookResponseLimit  = 2048
+	webhookEventQueueSize = 1024
+)
+
+// WebhookPayload Webhook 投递内容
+type WebhookPayload struct {
+	EventID    int64        `json:"event_id"`
+	Type       events.Type  `json:"type"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Data       events.Event `json:"data"`
+}
+
+// WebhookService Webhook 服务：管理订阅，将事件总线上的事件投递到订阅地址并在失败时按指数退避重试
+type WebhookService struct {
+	repo        repository.WebhookRepository
+	deliveries  repository.WebhookDeliveryRepository
+	flowRepo    repository.FlowRepository
+	authz       *Authorizer
+	bus         *events.Bus
+	client      *http.Client
+	maxAttempts int
+	retryBase   time.Duration
+
+	wake     chan struct{}
+	mu       sync.Mutex
+	inFlight map[int64]bool
+}
+
+// NewWebhookService 创建 Webhook 服务
+func NewWebhookService(
+	repo repository.WebhookRepository,
+	deliveries repository.WebhookDeliveryRepository,
+	flowRepo repository.FlowRepository,
+	authz *Authorizer,
+	bus *events.Bus,
+	maxAttempts int,
+	retryBase time.Duration,
+	timeout time.Duration,
+) *WebhookService {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &WebhookService{
+		repo:        repo,
+		deliveries:  deliveries,
+		flowRepo:    flowRepo,
+		authz:       authz,
+		bus:         bus,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		retryBase:   retryBase,
+		wake:        make(chan struct{}, 1),
+		inFlight:    make(map[int64]bool),
+	}
+}
+
+// Start 启动事件订阅与投递循环，ctx 取消后停止
+func (s *WebhookService) Start(ctx context.Context) {
+	sub := s.bus.SubscribeBuffered(events.Filter{}, webhookEventQueueSize)
+
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if err := s.enqueue(event); err != nil {
+					logger.Ctx(ctx).With("event_id", event.ID, "job_id", event.JobID).Errorf("Failed to enqueue webhook deliveries for event %s: %v", event.Type, err)
+				}
+			}
+		}
+	}()
+
+	go s.dispatch(ctx)
+}
+
+// projectRepo 返回只读写 ctx 中当前项目订阅的仓储
+func (s *WebhookService) projectRepo(ctx context.Context) repository.WebhookRepository {
+	return s.repo.WithProject(ProjectIDFromContext(ctx))
+}
+
+// checkEdit 校验能否修改订阅：只订阅单个流程时流程须属于当前项目，并拥有该流程的编辑权限；
+// 订阅项目内所有流程时须拥有全局编辑权限
+func (s *WebhookService) checkEdit(ctx context.Context, flowID sql.NullInt64) error {
+	if flowID.Valid {
+		if _, err := s.flowRepo.WithProject(ProjectIDFromContext(ctx)).GetByID(flowID.Int64); err != nil {
+			return validationErrorf("flow %d not found", flowID.Int64)
+		}
+	}
+	return s.authz.Check(ctx, PermFlowEdit, flowID.Int64)
+}
+
+// CreateSubscription 在当前项目下创建订阅
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := validateWebhookSubscription(sub); err != nil {
+		return err
+	}
+	if err := s.checkEdit(ctx, sub.FlowID); err != nil {
+		return err
+	}
+	return s.projectRepo(ctx).Create(sub)
+}
+
+// GetSubscription 获取当前项目的订阅
+func (s *WebhookService) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return s.projectRepo(ctx).GetByID(id)
+}
+
+// ListSubscriptions 获取当前项目的订阅列表
+func (s *WebhookService) ListSubscriptions(ctx context.Context, limit, offset int) ([]models.WebhookSubscription, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.projectRepo(ctx).List(limit, offset)
+}
+
+// UpdateSubscription 更新当前项目的订阅，须同时拥有修改前后订阅范围的编辑权限
+func (s *WebhookService) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := validateWebhookSubscription(sub); err != nil {
+		return err
+	}
+	repo := s.projectRepo(ctx)
+	existing, err := repo.GetByID(sub.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEdit(ctx, existing.FlowID); err != nil {
+		return err
+	}
+	if sub.FlowID != existing.FlowID {
+		if err := s.checkEdit(ctx, sub.FlowID); err != nil {
+			return err
+		}
+	}
+	return repo.Update(sub)
+}
+
+// DeleteSubscription 删除当前项目的订阅
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int64) error {
+	repo := s.projectRepo(ctx)
+	sub, err := repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEdit(ctx, sub.FlowID); err != nil {
+		return err
+	}
+	return repo.Delete(id)
+}
+
+// ListDeliveries 获取当前项目订阅的投递记录
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, limit, offset int) ([]models.WebhookDelivery, error) {
+	if _, err := s.projectRepo(ctx).GetByID(subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.deliveries.ListBySubscriptionID(subscriptionID, limit, offset)
+}
+
+// GetDelivery 获取当前项目订阅的投递记录及其所有尝试
+func (s *WebhookService) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.projectDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.deliveries.ListAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.AttemptLogs = attempts
+	return delivery, nil
+}
+
+// Redeliver 以相同内容创建新的投递记录并立即投递，原记录及其尝试保持不变；须拥有订阅的编辑权限
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	original, err := s.projectDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.projectRepo(ctx).GetByID(original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEdit(ctx, sub.FlowID); err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	if err := s.deliveries.Create(delivery); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return delivery, nil
+}
+
+// projectDelivery 获取投递记录并校验其订阅属于当前项目
+func (s *WebhookService) projectDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.deliveries.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.projectRepo(ctx).GetByID(delivery.SubscriptionID); err != nil {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+// enqueue 为匹配事件的每个启用订阅创建投递记录，订阅只接收所属项目的事件
+func (s *WebhookService) enqueue(event events.Event) error {
+	subs, err := s.repo.ListActive()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(WebhookPayload{
+		EventID:    event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	created := false
+	for _, sub := range subs {
+		if sub.ProjectID != event.ProjectID || !sub.EventTypes.Includes(string(event.Type)) {
+			continue
+		}
+		if sub.FlowID.Valid && sub.FlowID.Int64 != event.FlowID {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      string(event.Type),
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  sql.NullTime{Time: event.OccurredAt, Valid: true},
+		}
+		if err := s.deliveries.Create(delivery); err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if created {
+		s.notify()
+	}
+	return nil
+}
+
+// notify 唤醒投递循环
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch 轮询到期的投递记录并发送；投递记录持久化在数据库中，服务重启后继续重试
+func (s *WebhookService) dispatch(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		due, err := s.deliveries.ListDue(time.Now(), webhookBatchSize)
+		if err != nil {
+			logger.Errorf("Failed to list due webhook deliveries: %v", err)
+			continue
+		}
+
+		for i := range due {
+			delivery := due[i]
+			if !s.claim(delivery.ID) {
+				continue
+			}
+			go func() {
+				defer s.release(delivery.ID)
+				s.deliver(ctx, &delivery)
+			}()
+		}
+	}
+}
+
+func (s *WebhookService) claim(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inFlight[id] {
+		return false
+	}
+	s.inFlight[id] = true
+	return true
+}
+
+func (s *WebhookService) release(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, id)
+}
+
+// deliver 发送一次投递并记录尝试结果
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	log := logger.Ctx(ctx).With("webhook_id", delivery.SubscriptionID, "delivery_id", delivery.ID)
+	sub, err := s.repo.GetByID(delivery.SubscriptionID)
+	if err != nil {
+		// 订阅已不可读时不再重试，否则投递会一直处于待投递状态
+		log.Errorf("Failed to get webhook: %v", err)
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = fmt.Sprintf("subscription unavailable: %v", err)
+		delivery.NextAttemptAt = sql.NullTime{}
+		if err := s.deliveries.Update(delivery); err != nil {
+			log.Errorf("Failed to update webhook delivery: %v", err)
+		}
+		return
+	}
+
+	delivery.Attempts++
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+	}
+
+	start := time.Now()
+	statusCode, body, sendErr := s.send(ctx, sub, delivery)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	attempt.ResponseBody = body
+	if statusCode > 0 {
+		attempt.StatusCode = sql.NullInt64{Int64: int64(statusCode), Valid: true}
+		delivery.LastStatusCode = attempt.StatusCode
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.NextAttemptAt = sql.NullTime{}
+		delivery.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	case delivery.Attempts >= s.maxAttempts:
+		attempt.Error = sendErr.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = sql.NullTime{}
+	default:
+		attempt.Error = sendErr.Error()
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = sql.NullTime{Time: time.Now().Add(s.backoff(delivery.Attempts)), Valid: true}
+	}
+
+	if sendErr != nil {
+		log.Warnf("Webhook delivery attempt %d failed: %v", delivery.Attempts, sendErr)
+	} else {
+		log.Debugf("Webhook delivered with status %d", statusCode)
+	}
+	if err := s.deliveries.CreateAttempt(attempt); err != nil {
+		log.Errorf("Failed to record webhook delivery attempt: %v", err)
+	}
+	if err := s.deliveries.Update(delivery); err != nil {
+		log.Errorf("Failed to update webhook delivery: %v", err)
+	}
+}
+
+// send 发送签名请求，2xx 视为成功
+func (s *WebhookService) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoWorkFlow-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if sub.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(sub.Secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// backoff 第 n 次失败后的重试间隔：retryBase * 2^(n-1)，上限 1 小时
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	d := s.retryBase
+	for i := 1; i < attempts && d < webhookMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > webhookMaxBackoff {
+		d = webhookMaxBackoff
+	}
+	return d
+}
+
+// SignWebhookPayload 计算签名：hex(HMAC-SHA256(secret, timestamp + "." + body))
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateWebhookSubscription 校验订阅地址与事件类型
+func validateWebhookSubscription(sub *models.WebhookSubscription) error {
+	if sub.Name == "" {
+		return validationErrorf("name is required")
+	}
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return validationErrorf("url must be an absolute http(s) URL")
+	}
+	for _, t := range sub.EventTypes {
+		if t != "*" && !events.Type(t).IsValid() {
+			return validationErrorf("unknown event type: %s", t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/auth"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+)
+
+func TestWebhookSubscriptionPermissions(t *testing.T) {
+	db := newTestDB(t)
+	flows := repository.NewFlowRepository(db)
+	roles := repository.NewRoleBindingRepository(db)
+
+	projectB := &models.Project{Name: "B", Slug: "b"}
+	if err := repository.NewProjectRepository(db).Create(projectB); err != nil {
+		t.Fatal(err)
+	}
+	flowA := &models.Flow{Name: "a", Version: "1.0", IsActive: true}
+	if err := flows.WithProject(models.DefaultProjectID).Create(flowA); err != nil {
+		t.Fatal(err)
+	}
+	flowB := &models.Flow{Name: "b", Version: "1.0", IsActive: true}
+	if err := flows.WithProject(projectB.ID).Create(flowB); err != nil {
+		t.Fatal(err)
+	}
+
+	const admin, editor, viewer = 1, 2, 3
+	for _, binding := range []models.RoleBinding{
+		{UserID: admin, Role: RoleAdmin},
+		{UserID: editor, Role: RoleFlowEditor, FlowID: flowA.ID},
+		{UserID: viewer, Role: RoleViewer},
+	} {
+		if err := roles.Create(&binding); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewWebhookService(repository.NewWebhookRepository(db), repository.NewWebhookDeliveryRepository(db),
+		flows, NewAuthorizer(roles), nil, 1, 0, 0)
+	as := func(userID int64) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID})
+		return WithProjectID(ctx, models.DefaultProjectID)
+	}
+	newSub := func(flowID int64) *models.WebhookSubscription {
+		return &models.WebhookSubscription{
+			Name:     "hook",
+			URL:      "https://example.com/hook",
+			FlowID:   sql.NullInt64{Int64: flowID, Valid: flowID > 0},
+			IsActive: true,
+		}
+	}
+
+	var forbidden *ForbiddenError
+	var invalid *ValidationError
+	tests := []struct {
+		name    string
+		userID  int64
+		flowID  int64
+		wantErr interface{}
+	}{
+		{name: "viewer on flow", userID: viewer, flowID: flowA.ID, wantErr: &forbidden},
+		{name: "viewer on project", userID: viewer, wantErr: &forbidden},
+		{name: "flow editor on own flow", userID: editor, flowID: flowA.ID},
+		{name: "flow editor on project", userID: editor, wantErr: &forbidden},
+		{name: "flow of another project", userID: admin, flowID: flowB.ID, wantErr: &invalid},
+		{name: "admin on project", userID: admin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CreateSubscription(as(tt.userID), newSub(tt.flowID))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("CreateSubscription() error = %v", err)
+				}
+				return
+			}
+			if !errors.As(err, tt.wantErr) {
+				t.Fatalf("CreateSubscription() error = %v, want %T", err, tt.wantErr)
+			}
+		})
+	}
+
+	sub := newSub(flowA.ID)
+	if err := svc.CreateSubscription(as(admin), sub); err != nil {
+		t.Fatal(err)
+	}
+	sub.FlowID = sql.NullInt64{}
+	if err := svc.UpdateSubscription(as(editor), sub); !errors.As(err, &forbidden) {
+		t.Fatalf("UpdateSubscription() widening to project error = %v, want ForbiddenError", err)
+	}
+	if err := svc.DeleteSubscription(as(viewer), sub.ID); !errors.As(err, &forbidden) {
+		t.Fatalf("viewer DeleteSubscription() error = %v, want ForbiddenError", err)
+	}
+	if err := svc.DeleteSubscription(as(editor), sub.ID); err != nil {
+		t.Fatalf("flow editor DeleteSubscription() error = %v", err)
+	}
+}
+
+// unavailableWebhookRepository 读取订阅总是失败的仓储
+type unavailableWebhookRepository struct {
+	repository.WebhookRepository
+}
+
+func (unavailableWebhookRepository) GetByID(id int64) (*models.WebhookSubscription, error) {
+	return nil, errors.New("database is locked")
+}
+
+// recordingDeliveryRepository 记录更新过的投递
+type recordingDeliveryRepository struct {
+	repository.WebhookDeliveryRepository
+	updated []models.WebhookDelivery
+}
+
+func (r *recordingDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	r.updated = append(r.updated, *delivery)
+	return nil
+}
+
+func TestWebhookDeliverUnavailableSubscription(t *testing.T) {
+	deliveries := &recordingDeliveryRepository{}
+	svc := NewWebhookService(unavailableWebhookRepository{}, deliveries, nil, nil, nil, 5, time.Second, time.Second)
+
+	delivery := &models.WebhookDelivery{
+		ID:             1,
+		SubscriptionID: 2,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	svc.deliver(context.Background(), delivery)
+
+	if len(deliveries.updated) != 1 {
+		t.Fatalf("delivery updated %d times, want 1", len(deliveries.updated))
+	}
+	got := deliveries.updated[0]
+	if got.Status != models.WebhookDeliveryFailed || got.NextAttemptAt.Valid || !strings.Contains(got.LastError, "database is locked") {
+		t.Fatalf("delivery = %+v, want failed without next attempt", got)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	tests := []struct {
+		secret    string
+		timestamp string
+		body      string
+	}{
+		{secret: "s3cret", timestamp: "1700000000", body: `{"event":"job.completed"}`},
+		{secret: "s3cret", timestamp: "1700000001", body: `{"event":"job.completed"}`},
+		{secret: "other", timestamp: "1700000000", body: ""},
+	}
+	seen := make(map[string]bool)
+	for _, tt := range tests {
+		mac := hmac.New(sha256.New, []byte(tt.secret))
+		mac.Write([]byte(tt.timestamp + "." + tt.body))
+		want := hex.EncodeToString(mac.Sum(nil))
+
+		got := SignWebhookPayload(tt.secret, tt.timestamp, []byte(tt.body))
+		if got != want {
+			t.Fatalf("SignWebhookPayload(%q, %q, %q) = %s, want %s", tt.secret, tt.timestamp, tt.body, got, want)
+		}
+		if seen[got] {
+			t.Fatalf("SignWebhookPayload(%q, %q, %q) repeats an earlier signature", tt.secret, tt.timestamp, tt.body)
+		}
+		seen[got] = true
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	svc := &WebhookService{retryBase: 10 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 4, want: 80 * time.Second},
+		{attempts: 9, want: 2560 * time.Second},
+		{attempts: 10, want: time.Hour},
+		{attempts: 1000, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := svc.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/cfrs2005/GoWorkFlow/internal/engine"
 	"github.com/cfrs2005/GoWorkFlow/internal/models"
@@ -11,18 +14,22 @@ import (
 
 // WorkflowService 工作流服务接口
 type WorkflowService interface {
+	// WithContext 返回以 ctx 中的认证主体做权限校验、并以 ctx 记录追踪的服务
+	WithContext(ctx context.Context) WorkflowService
+
 	// Task 管理
 	CreateTask(task *models.Task) error
 	GetTask(id int64) (*models.Task, error)
-	ListTasks(limit, offset int) ([]models.Task, error)
+	ListTasks(filter models.TaskFilter) ([]models.Task, *models.Page, error)
 	UpdateTask(task *models.Task) error
 	DeleteTask(id int64) error
 
 	// Flow 管理
 	CreateFlow(flow *models.Flow, taskIDs []int64) error
+	CreateFlowWithTasks(flow *models.Flow, flowTasks []models.FlowTask) error
 	GetFlow(id int64) (*models.Flow, error)
 	GetFlowWithTasks(id int64) (*models.Flow, []models.FlowTask, error)
-	ListFlows(limit, offset int) ([]models.Flow, error)
+	ListFlows(filter models.FlowFilter) ([]models.Flow, *models.Page, error)
 	UpdateFlow(flow *models.Flow) error
 	DeleteFlow(id int64) error
 	AddTaskToFlow(flowID, taskID int64, sequence int, isOptional, allowRollback bool) error
@@ -31,8 +38,16 @@ type WorkflowService interface {
 	CreateJob(flowID int64, jobName string, createdBy int64) (*models.Job, error)
 	GetJob(id int64) (*models.Job, error)
 	GetJobWithTasks(id int64) (*models.Job, []models.JobTask, error)
-	ListJobs(limit, offset int) ([]models.Job, error)
+	ListJobs(filter models.JobFilter) ([]models.Job, *models.Page, error)
 	StartJob(jobID int64) error
+	CancelJob(jobID int64, operatorID int64, reason string) error
+	RerunJob(jobID int64, opts models.JobRerunOptions) (*models.JobRerun, error)
+
+	// 作业批次
+	CreateJobBatch(batch *models.JobBatch, items []models.JobBatchItem) ([]models.Job, error)
+	GetJobBatch(id int64) (*models.JobBatchSummary, error)
+	CancelJobBatch(id int64, operatorID int64, reason string) (*models.JobBatchResult, error)
+	RetryFailedJobBatch(id int64, operatorID int64) (*models.JobBatchResult, error)
 
 	// JobTask 操作
 	StartTask(jobTaskID int64, executorID int64) error
@@ -40,17 +55,29 @@ type WorkflowService interface {
 	FailTask(jobTaskID int64, errorMessage string) error
 	SkipTask(jobTaskID int64, operatorID int64) error
 	RollbackTask(jobTaskID int64, operatorID int64, targetSequence int) error
+	RetryTask(jobTaskID int64, operatorID int64) error
+	ReassignTask(jobTaskID int64, operatorID int64, assigneeID int64) error
 	GetNextTask(jobID int64) (*models.JobTask, error)
+	GetJobTask(id int64) (*models.JobTask, error)
+
+	// 审计日志
+	ListJobLogs(jobID int64) ([]models.JobTaskLog, error)
+	ListJobTaskLogs(jobTaskID int64) ([]models.JobTaskLog, error)
 }
 
 type workflowService struct {
+	ctx          context.Context
 	db           *sql.DB
 	taskRepo     repository.TaskRepository
 	flowRepo     repository.FlowRepository
 	flowTaskRepo repository.FlowTaskRepository
 	jobRepo      repository.JobRepository
 	jobTaskRepo  repository.JobTaskRepository
+	logRepo      repository.JobTaskLogRepository
 	engine       engine.WorkflowEngine
+	authz        *Authorizer
+	batchRepo    repository.JobBatchRepository
+	contextRepo  repository.JobContextRepository
 }
 
 // NewWorkflowService 创建工作流服务
@@ -61,22 +88,96 @@ func NewWorkflowService(
 	flowTaskRepo repository.FlowTaskRepository,
 	jobRepo repository.JobRepository,
 	jobTaskRepo repository.JobTaskRepository,
+	logRepo repository.JobTaskLogRepository,
 	engine engine.WorkflowEngine,
+	authz *Authorizer,
+	batchRepo repository.JobBatchRepository,
+	contextRepo repository.JobContextRepository,
 ) WorkflowService {
 	return &workflowService{
+		ctx:          context.Background(),
 		db:           db,
 		taskRepo:     taskRepo,
 		flowRepo:     flowRepo,
 		flowTaskRepo: flowTaskRepo,
 		jobRepo:      jobRepo,
 		jobTaskRepo:  jobTaskRepo,
+		logRepo:      logRepo,
 		engine:       engine,
+		authz:        authz,
+		batchRepo:    batchRepo,
+		contextRepo:  contextRepo,
+	}
+}
+
+// WithContext 返回以 ctx 中的认证主体做权限校验、并以 ctx 记录追踪的服务
+func (s *workflowService) WithContext(ctx context.Context) WorkflowService {
+	clone := *s
+	clone.ctx = ctx
+	clone.engine = s.engine.WithContext(ctx)
+
+	// 按当前项目隔离任务定义、流程与作业；作业任务经由所属作业校验
+	if projectID := ProjectIDFromContext(ctx); projectID != 0 {
+		clone.taskRepo = s.taskRepo.WithProject(projectID)
+		clone.flowRepo = s.flowRepo.WithProject(projectID)
+		clone.jobRepo = s.jobRepo.WithProject(projectID)
+		clone.batchRepo = s.batchRepo.WithProject(projectID)
 	}
+	return &clone
+}
+
+// checkJob 校验当前用户在作业所属流程上的权限
+func (s *workflowService) checkJob(perm Permission, jobID int64) error {
+	job, err := s.jobRepo.GetByID(jobID)
+	if err != nil {
+		return err
+	}
+	return s.authz.Check(s.ctx, perm, job.FlowID)
+}
+
+// checkJobTask 校验当前用户在作业任务所属流程上的权限
+func (s *workflowService) checkJobTask(perm Permission, jobTaskID int64) error {
+	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
+	if err != nil {
+		return err
+	}
+	return s.checkJob(perm, jobTask.JobID)
+}
+
+// checkCompleteTask 完成审批任务需要审批权限，其他任务需要作业操作权限
+func (s *workflowService) checkCompleteTask(jobTaskID int64) error {
+	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
+	if err != nil {
+		return err
+	}
+	job, err := s.jobRepo.GetByID(jobTask.JobID)
+	if err != nil {
+		return err
+	}
+	flowTask, err := s.flowTaskRepo.GetByID(jobTask.FlowTaskID)
+	if err != nil {
+		return err
+	}
+	if flowTask.Task, err = s.taskRepo.GetByID(jobTask.TaskID); err != nil {
+		return err
+	}
+
+	if flowTask.Task.TaskType == models.TaskTypeApproval {
+		return s.authz.CheckApproval(s.ctx, job.FlowID, approversOf(flowTask.EffectiveConfig()))
+	}
+	return s.authz.Check(s.ctx, PermJobOperate, job.FlowID)
 }
 
 // Task 管理方法
 
 func (s *workflowService) CreateTask(task *models.Task) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, 0); err != nil {
+		return err
+	}
+	task.ProjectID = ProjectIDFromContext(s.ctx)
+	if err := validateTaskConfig(task); err != nil {
+		return err
+	}
 	return s.taskRepo.Create(task)
 }
 
@@ -84,24 +185,85 @@ func (s *workflowService) GetTask(id int64) (*models.Task, error) {
 	return s.taskRepo.GetByID(id)
 }
 
-func (s *workflowService) ListTasks(limit, offset int) ([]models.Task, error) {
-	if limit <= 0 {
-		limit = 20
-	}
-	return s.taskRepo.List(limit, offset)
+func (s *workflowService) ListTasks(filter models.TaskFilter) ([]models.Task, *models.Page, error) {
+	normalizeListQuery(&filter.ListQuery)
+	items, page, err := s.taskRepo.List(filter)
+	return items, page, listQueryError(err)
 }
 
 func (s *workflowService) UpdateTask(task *models.Task) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, 0); err != nil {
+		return err
+	}
+	if err := validateTaskConfig(task); err != nil {
+		return err
+	}
+	if _, err := s.taskRepo.GetByID(task.ID); err != nil {
+		return err
+	}
 	return s.taskRepo.Update(task)
 }
 
 func (s *workflowService) DeleteTask(id int64) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, 0); err != nil {
+		return err
+	}
+	if _, err := s.taskRepo.GetByID(id); err != nil {
+		return err
+	}
 	return s.taskRepo.Delete(id)
 }
 
 // Flow 管理方法
 
 func (s *workflowService) CreateFlow(flow *models.Flow, taskIDs []int64) error {
+	flowTasks := make([]models.FlowTask, len(taskIDs))
+	for i, taskID := range taskIDs {
+		flowTasks[i] = models.FlowTask{
+			TaskID:        taskID,
+			Sequence:      i + 1,
+			IsOptional:    false,
+			AllowRollback: true,
+		}
+	}
+	return s.CreateFlowWithTasks(flow, flowTasks)
+}
+
+func (s *workflowService) CreateFlowWithTasks(flow *models.Flow, flowTasks []models.FlowTask) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, 0); err != nil {
+		return err
+	}
+	flow.ProjectID = ProjectIDFromContext(s.ctx)
+	// 关联任务定义并校验任务间的输入/输出契约
+	taskIDs := make([]int64, len(flowTasks))
+	for i := range flowTasks {
+		taskIDs[i] = flowTasks[i].TaskID
+		if flowTasks[i].Sequence == 0 {
+			flowTasks[i].Sequence = i + 1
+		}
+	}
+	tasks, err := s.taskRepo.GetByIDs(taskIDs)
+	if err != nil {
+		return err
+	}
+	taskByID := make(map[int64]models.Task, len(tasks))
+	for _, task := range tasks {
+		taskByID[task.ID] = task
+	}
+	for i := range flowTasks {
+		task, ok := taskByID[flowTasks[i].TaskID]
+		if !ok {
+			return fmt.Errorf("task %d not found", flowTasks[i].TaskID)
+		}
+		flowTasks[i].Task = &task
+	}
+	sort.SliceStable(flowTasks, func(i, j int) bool {
+		return flowTasks[i].Sequence < flowTasks[j].Sequence
+	})
+	if err := validateFlowContracts(flow.Inputs, flowTasks); err != nil {
+		return err
+	}
+
 	// 开始事务
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -110,20 +272,15 @@ func (s *workflowService) CreateFlow(flow *models.Flow, taskIDs []int64) error {
 	defer tx.Rollback()
 
 	// 创建流程
-	if err := s.flowRepo.Create(flow); err != nil {
+	if err := s.flowRepo.WithTx(tx).Create(flow); err != nil {
 		return err
 	}
 
 	// 添加任务到流程
-	for i, taskID := range taskIDs {
-		flowTask := &models.FlowTask{
-			FlowID:        flow.ID,
-			TaskID:        taskID,
-			Sequence:      i + 1,
-			IsOptional:    false,
-			AllowRollback: true,
-		}
-		if err := s.flowTaskRepo.Create(flowTask); err != nil {
+	flowTaskRepo := s.flowTaskRepo.WithTx(tx)
+	for i := range flowTasks {
+		flowTasks[i].FlowID = flow.ID
+		if err := flowTaskRepo.Create(&flowTasks[i]); err != nil {
 			return err
 		}
 	}
@@ -139,22 +296,59 @@ func (s *workflowService) GetFlowWithTasks(id int64) (*models.Flow, []models.Flo
 	return s.flowRepo.GetFlowWithTasks(id)
 }
 
-func (s *workflowService) ListFlows(limit, offset int) ([]models.Flow, error) {
-	if limit <= 0 {
-		limit = 20
-	}
-	return s.flowRepo.List(limit, offset)
+func (s *workflowService) ListFlows(filter models.FlowFilter) ([]models.Flow, *models.Page, error) {
+	normalizeListQuery(&filter.ListQuery)
+	items, page, err := s.flowRepo.List(filter)
+	return items, page, listQueryError(err)
 }
 
 func (s *workflowService) UpdateFlow(flow *models.Flow) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, flow.ID); err != nil {
+		return err
+	}
+	// 流程输入变化后重新校验契约
+	_, flowTasks, err := s.flowRepo.GetFlowWithTasks(flow.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateFlowContracts(flow.Inputs, flowTasks); err != nil {
+		return err
+	}
 	return s.flowRepo.Update(flow)
 }
 
 func (s *workflowService) DeleteFlow(id int64) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, id); err != nil {
+		return err
+	}
+	if _, err := s.flowRepo.GetByID(id); err != nil {
+		return err
+	}
 	return s.flowRepo.Delete(id)
 }
 
 func (s *workflowService) AddTaskToFlow(flowID, taskID int64, sequence int, isOptional, allowRollback bool) error {
+	if err := s.authz.Check(s.ctx, PermFlowEdit, flowID); err != nil {
+		return err
+	}
+	flow, flowTasks, err := s.flowRepo.GetFlowWithTasks(flowID)
+	if err != nil {
+		return err
+	}
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+
+	// 将新任务插入到对应位置后校验契约
+	flowTasks = append(flowTasks, models.FlowTask{Sequence: sequence, Task: task})
+	sort.SliceStable(flowTasks, func(i, j int) bool {
+		return flowTasks[i].Sequence < flowTasks[j].Sequence
+	})
+	if err := validateFlowContracts(flow.Inputs, flowTasks); err != nil {
+		return err
+	}
+
 	flowTask := &models.FlowTask{
 		FlowID:        flowID,
 		TaskID:        taskID,
@@ -168,6 +362,12 @@ func (s *workflowService) AddTaskToFlow(flowID, taskID int64, sequence int, isOp
 // Job 管理方法
 
 func (s *workflowService) CreateJob(flowID int64, jobName string, createdBy int64) (*models.Job, error) {
+	if _, err := s.flowRepo.GetByID(flowID); err != nil {
+		return nil, err
+	}
+	if err := s.authz.Check(s.ctx, PermJobOperate, flowID); err != nil {
+		return nil, err
+	}
 	return s.engine.CreateJob(flowID, jobName, createdBy)
 }
 
@@ -179,39 +379,132 @@ func (s *workflowService) GetJobWithTasks(id int64) (*models.Job, []models.JobTa
 	return s.jobRepo.GetJobWithTasks(id)
 }
 
-func (s *workflowService) ListJobs(limit, offset int) ([]models.Job, error) {
-	if limit <= 0 {
-		limit = 20
-	}
-	return s.jobRepo.List(limit, offset)
+func (s *workflowService) ListJobs(filter models.JobFilter) ([]models.Job, *models.Page, error) {
+	normalizeListQuery(&filter.ListQuery)
+	items, page, err := s.jobRepo.List(filter)
+	return items, page, listQueryError(err)
 }
 
 func (s *workflowService) StartJob(jobID int64) error {
+	if err := s.checkJob(PermJobOperate, jobID); err != nil {
+		return err
+	}
 	return s.engine.StartJob(jobID)
 }
 
+func (s *workflowService) CancelJob(jobID int64, operatorID int64, reason string) error {
+	if err := s.checkJob(PermJobOperate, jobID); err != nil {
+		return err
+	}
+	return s.engine.CancelJob(jobID, operatorID, reason)
+}
+
 // JobTask 操作方法
 
 func (s *workflowService) StartTask(jobTaskID int64, executorID int64) error {
+	if err := s.checkJobTask(PermJobOperate, jobTaskID); err != nil {
+		return err
+	}
 	return s.engine.StartTask(jobTaskID, executorID)
 }
 
 func (s *workflowService) CompleteTask(jobTaskID int64, result models.TaskResult) error {
+	if err := s.checkCompleteTask(jobTaskID); err != nil {
+		return err
+	}
 	return s.engine.CompleteTask(jobTaskID, result)
 }
 
 func (s *workflowService) FailTask(jobTaskID int64, errorMessage string) error {
+	if err := s.checkJobTask(PermJobOperate, jobTaskID); err != nil {
+		return err
+	}
 	return s.engine.FailTask(jobTaskID, errorMessage)
 }
 
 func (s *workflowService) SkipTask(jobTaskID int64, operatorID int64) error {
+	if err := s.checkJobTask(PermTaskSkip, jobTaskID); err != nil {
+		return err
+	}
 	return s.engine.SkipTask(jobTaskID, operatorID)
 }
 
 func (s *workflowService) RollbackTask(jobTaskID int64, operatorID int64, targetSequence int) error {
+	if err := s.checkJobTask(PermTaskRollback, jobTaskID); err != nil {
+		return err
+	}
 	return s.engine.RollbackTask(jobTaskID, operatorID, targetSequence)
 }
 
+func (s *workflowService) RetryTask(jobTaskID int64, operatorID int64) error {
+	if err := s.checkJobTask(PermJobOperate, jobTaskID); err != nil {
+		return err
+	}
+	return s.engine.RetryTask(jobTaskID, operatorID)
+}
+
+func (s *workflowService) ReassignTask(jobTaskID int64, operatorID int64, assigneeID int64) error {
+	if err := s.checkJobTask(PermJobOperate, jobTaskID); err != nil {
+		return err
+	}
+	return s.engine.ReassignTask(jobTaskID, operatorID, assigneeID)
+}
+
 func (s *workflowService) GetNextTask(jobID int64) (*models.JobTask, error) {
+	if _, err := s.jobRepo.GetByID(jobID); err != nil {
+		return nil, err
+	}
 	return s.engine.GetNextTask(jobID)
 }
+
+func (s *workflowService) GetJobTask(id int64) (*models.JobTask, error) {
+	jobTask, err := s.jobTaskRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.jobRepo.GetByID(jobTask.JobID); err != nil {
+		return nil, err
+	}
+	return jobTask, nil
+}
+
+// 审计日志方法
+
+func (s *workflowService) ListJobLogs(jobID int64) ([]models.JobTaskLog, error) {
+	if _, err := s.jobRepo.GetByID(jobID); err != nil {
+		return nil, err
+	}
+	return s.logRepo.ListByJobID(jobID)
+}
+
+func (s *workflowService) ListJobTaskLogs(jobTaskID int64) ([]models.JobTaskLog, error) {
+	jobTask, err := s.jobTaskRepo.GetByID(jobTaskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.jobRepo.GetByID(jobTask.JobID); err != nil {
+		return nil, err
+	}
+	return s.logRepo.ListByJobTaskID(jobTaskID)
+}
+
+// defaultPageSize 列表默认每页数量
+const defaultPageSize = 20
+
+// normalizeListQuery 补全列表查询的默认分页参数
+func normalizeListQuery(q *models.ListQuery) {
+	if q.Limit <= 0 {
+		q.Limit = defaultPageSize
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+}
+
+// listQueryError 将无效的排序字段、游标转为参数校验错误
+func listQueryError(err error) error {
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return &ValidationError{Message: err.Error()}
+	}
+	return err
+}
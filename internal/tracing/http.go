@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// traceparentHeader W3C Trace Context 请求头
+const traceparentHeader = "traceparent"
+
+// Extract 从请求头解析 traceparent（格式 00-{trace-id}-{span-id}-{flags}）
+func Extract(h http.Header) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(h.Get(traceparentHeader)), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, false
+	}
+	return sc, sc.IsValid()
+}
+
+// Inject 将上下文中的当前 Span 写入请求头，供下游服务继续追踪
+func Inject(ctx context.Context, h http.Header) {
+	sc, ok := parentFromContext(ctx)
+	if !ok || !sc.IsValid() {
+		return
+	}
+	h.Set(traceparentHeader, "00-"+sc.TraceID.String()+"-"+sc.SpanID.String()+"-01")
+}
+
+// Middleware 为每个请求创建服务端 Span，Span 名称取 ServeMux 匹配的路由模式，并延续请求头中的 traceparent
+func Middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h, route := mux.Handler(r)
+		if sub, ok := h.(routeMatcher); ok {
+			if _, pattern := sub.Handler(r); pattern != "" {
+				route = pattern
+			}
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx := r.Context()
+		if sc, ok := Extract(r.Header); ok {
+			ctx = ContextWithRemoteParent(ctx, sc)
+		}
+		ctx, span := Start(ctx, r.Method+" "+route,
+			WithKind(SpanKindServer),
+			WithAttributes(
+				String("http.method", r.Method),
+				String("http.route", route),
+				String("http.target", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(Int("http.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.RecordError(httpError(http.StatusText(rec.status)))
+		}
+	})
+}
+
+type httpError string
+
+func (e httpError) Error() string { return string(e) }
+
+// routeMatcher 挂载在 ServeMux 下的子路由（如 /api/v1），用于取得更细的路由模式
+type routeMatcher interface {
+	Handler(r *http.Request) (http.Handler, string)
+}
+
+// statusRecorder 记录响应状态码，并保留 Flush 以支持 SSE
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,327 @@
+// Package tracing 提供轻量的分布式追踪：W3C traceparent 传播，以 OTLP/HTTP（JSON）或标准输出导出 Span
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID 追踪 ID
+type TraceID [16]byte
+
+// String 返回十六进制表示
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid 判断是否为非零 ID
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID Span ID
+type SpanID [8]byte
+
+// String 返回十六进制表示
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid 判断是否为非零 ID
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext 跨进程传播的 Span 标识
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid 判断 TraceID 与 SpanID 是否均有效
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind Span 类型，取值与 OTLP 一致
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// String 返回类型名称
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// Attr Span 属性
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// String 字符串属性
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int64 整数属性
+func Int64(key string, value int64) Attr { return Attr{Key: key, Value: value} }
+
+// Int 整数属性
+func Int(key string, value int) Attr { return Attr{Key: key, Value: int64(value)} }
+
+// Bool 布尔属性
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+// Float64 浮点属性
+func Float64(key string, value float64) Attr { return Attr{Key: key, Value: value} }
+
+// Event Span 内的事件
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attr
+}
+
+// Span 一段被追踪的操作。未启用追踪时 Start 返回 nil，Span 的所有方法对 nil 安全
+type Span struct {
+	mu         sync.Mutex
+	name       string
+	kind       SpanKind
+	sc         SpanContext
+	parent     SpanID
+	start      time.Time
+	end        time.Time
+	attributes []Attr
+	events     []Event
+	err        string
+	failed     bool
+	ended      bool
+}
+
+// SpanContext 返回 Span 标识
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttributes 设置属性
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent 添加事件
+func (s *Span) AddEvent(name string, attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, Event{Name: name, Time: time.Now(), Attributes: attrs})
+	s.mu.Unlock()
+}
+
+// RecordError 记录错误并将 Span 状态置为失败，err 为 nil 时忽略
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, Event{Name: "exception", Time: time.Now(), Attributes: []Attr{String("exception.message", err.Error())}})
+	s.err = err.Error()
+	s.failed = true
+	s.mu.Unlock()
+}
+
+// End 结束 Span 并提交导出，重复调用无效
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if p := current.Load(); p != nil {
+		p.enqueue(s)
+	}
+}
+
+// Option Span 选项
+type Option func(*Span)
+
+// WithKind 设置 Span 类型
+func WithKind(kind SpanKind) Option {
+	return func(s *Span) { s.kind = kind }
+}
+
+// WithAttributes 设置初始属性
+func WithAttributes(attrs ...Attr) Option {
+	return func(s *Span) { s.attributes = append(s.attributes, attrs...) }
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// SpanFromContext 返回上下文中的当前 Span，不存在时返回 nil
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// ContextWithSpan 返回携带 Span 的上下文
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// ContextWithRemoteParent 返回以 sc 为父 Span 的上下文（如来自请求头或作业根 Span）
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, spanKey{}, (*Span)(nil))
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// parentFromContext 返回上下文中的父 Span 标识，本地 Span 优先
+func parentFromContext(ctx context.Context) (SpanContext, bool) {
+	if s := SpanFromContext(ctx); s != nil {
+		return s.sc, true
+	}
+	if sc, ok := ctx.Value(remoteKey{}).(SpanContext); ok {
+		return sc, true
+	}
+	return SpanContext{}, false
+}
+
+// Enabled 判断是否已启用追踪导出
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Start 开始一个 Span，存在父 Span 时作为其子 Span，否则开始新的追踪。未启用追踪时返回原上下文与 nil
+func Start(ctx context.Context, name string, opts ...Option) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	s := &Span{name: name, kind: SpanKindInternal, start: time.Now()}
+	if parent, ok := parentFromContext(ctx); ok {
+		s.sc.TraceID = parent.TraceID
+		s.parent = parent.SpanID
+	} else {
+		s.sc.TraceID = newTraceID()
+	}
+	s.sc.SpanID = newSpanID()
+	for _, opt := range opts {
+		opt(s)
+	}
+	return ContextWithSpan(ctx, s), s
+}
+
+// StartChild 仅在上下文已有父 Span 时开始子 Span，用于 SQL 等高频操作，避免产生大量孤立追踪
+func StartChild(ctx context.Context, name string, opts ...Option) (context.Context, *Span) {
+	if _, ok := parentFromContext(ctx); !ok {
+		return ctx, nil
+	}
+	return Start(ctx, name, opts...)
+}
+
+// RecordSpan 直接记录一个已完成的 Span（如根据作业起止时间补记的作业根 Span）
+func RecordSpan(sc SpanContext, name string, start, end time.Time, err error, attrs ...Attr) {
+	p := current.Load()
+	if p == nil || !sc.IsValid() {
+		return
+	}
+	s := &Span{name: name, kind: SpanKindInternal, sc: sc, start: start, end: end, attributes: attrs, ended: true}
+	if err != nil {
+		s.err = err.Error()
+		s.failed = true
+	}
+	p.enqueue(s)
+}
+
+// JobSpanContext 作业根 Span 标识，由作业 ID 与创建时间确定性生成，
+// 使同一作业在不同请求、不同时间执行的任务都归属同一条追踪
+func JobSpanContext(jobID int64, createdAt time.Time) SpanContext {
+	sum := sha256.Sum256([]byte("goworkflow/job/" + strconv.FormatInt(jobID, 10) + "/" + strconv.FormatInt(createdAt.UnixNano(), 10)))
+	var sc SpanContext
+	copy(sc.TraceID[:], sum[:16])
+	copy(sc.SpanID[:], sum[16:24])
+	return sc
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// spanData 导出用的 Span 快照
+type spanData struct {
+	Name       string
+	Kind       SpanKind
+	Context    SpanContext
+	Parent     SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes []Attr
+	Events     []Event
+	Failed     bool
+	Error      string
+}
+
+func (s *Span) snapshot() spanData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return spanData{
+		Name:       s.name,
+		Kind:       s.kind,
+		Context:    s.sc,
+		Parent:     s.parent,
+		Start:      s.start,
+		End:        s.end,
+		Attributes: append([]Attr(nil), s.attributes...),
+		Events:     append([]Event(nil), s.events...),
+		Failed:     s.failed,
+		Error:      s.err,
+	}
+}
+
+// unixNano 以字符串表示纳秒时间戳（OTLP JSON 中 64 位整数使用字符串）
+func unixNano(t time.Time) string {
+	return strconv.FormatUint(uint64(t.UnixNano()), 10)
+}
+
+var current atomic.Pointer[processor]
@@ -0,0 +1,8 @@
+-- 005_executor_contracts.sql
+-- 流程输入声明，用于保存流程时校验执行器必填参数的来源
+
+ALTER TABLE flows
+    ADD COLUMN input_keys JSON COMMENT '流程输入键（由作业上下文提供）' AFTER version;
+
+-- YouTube 视频分析流程的 video_url 由作业上下文提供
+UPDATE flows SET input_keys = JSON_ARRAY('video_url') WHERE name = 'YouTube 视频智能分析';
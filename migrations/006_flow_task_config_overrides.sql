@@ -0,0 +1,5 @@
+-- 006_flow_task_config_overrides.sql
+-- 流程任务级配置覆盖，支持 ${context.<key>} / ${tasks.<name>.result.<key>} 模板表达式
+
+ALTER TABLE flow_tasks
+    ADD COLUMN config_overrides JSON COMMENT '任务配置覆盖（优先于任务定义中的配置）' AFTER condition_config;
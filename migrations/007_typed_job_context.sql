@@ -0,0 +1,15 @@
+-- 007_typed_job_context.sql
+-- 作业上下文值改为 JSON 类型，并增加作用域：shared 为共享作用域，task:<任务名称> 为任务输出命名空间
+
+ALTER TABLE job_context
+    ADD COLUMN scope VARCHAR(255) NOT NULL DEFAULT 'shared' COMMENT '作用域：shared 或 task:<任务名称>' AFTER job_id,
+    ADD COLUMN value_json JSON AFTER context_value;
+
+-- 旧数据均为字符串，迁移到共享作用域
+UPDATE job_context SET value_json = JSON_QUOTE(context_value) WHERE context_value IS NOT NULL;
+
+ALTER TABLE job_context
+    DROP INDEX unique_job_context,
+    DROP COLUMN context_value,
+    CHANGE COLUMN value_json context_value JSON COMMENT '上下文值（JSON）',
+    ADD UNIQUE KEY unique_job_context (job_id, scope, context_key);
@@ -0,0 +1,19 @@
+-- 008_artifacts.sql
+-- 作业制品表：记录执行器写入存储后端的文件
+
+CREATE TABLE IF NOT EXISTS artifacts (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    job_id BIGINT NOT NULL COMMENT '作业ID',
+    job_task_id BIGINT COMMENT '作业任务ID',
+    name VARCHAR(255) NOT NULL COMMENT '制品名称',
+    content_type VARCHAR(255) NOT NULL COMMENT '内容类型',
+    size BIGINT NOT NULL DEFAULT 0 COMMENT '字节数',
+    checksum CHAR(64) NOT NULL COMMENT 'SHA-256 校验和',
+    backend VARCHAR(20) NOT NULL COMMENT '存储后端：local/s3',
+    storage_key VARCHAR(1024) NOT NULL COMMENT '存储对象键',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (job_task_id) REFERENCES job_tasks(id) ON DELETE SET NULL,
+    INDEX idx_job_id (job_id),
+    INDEX idx_job_task_id (job_task_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作业制品表';
@@ -0,0 +1,12 @@
+-- 009_execution_logs.sql
+-- 执行日志表：记录执行器在每次作业任务执行中输出的日志行
+
+CREATE TABLE IF NOT EXISTS execution_logs (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    job_task_id BIGINT NOT NULL COMMENT '作业任务ID',
+    level VARCHAR(10) NOT NULL COMMENT '级别：info/warn/error',
+    message TEXT NOT NULL COMMENT '日志内容',
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (job_task_id) REFERENCES job_tasks(id) ON DELETE CASCADE,
+    INDEX idx_job_task_id (job_task_id, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='执行日志表';
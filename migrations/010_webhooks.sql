@@ -0,0 +1,47 @@
+-- 010_webhooks.sql
+-- Webhook 订阅、投递记录与投递尝试
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL COMMENT '订阅名称',
+    url VARCHAR(2048) NOT NULL COMMENT '回调地址',
+    event_types JSON NOT NULL COMMENT '订阅的事件类型，空数组表示全部',
+    flow_id BIGINT COMMENT '仅订阅该流程的事件',
+    secret VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'HMAC 签名密钥',
+    is_active BOOLEAN DEFAULT TRUE COMMENT '是否启用',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE,
+    INDEX idx_is_active (is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook 订阅表';
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    subscription_id BIGINT NOT NULL COMMENT '订阅ID',
+    event_type VARCHAR(50) NOT NULL COMMENT '事件类型',
+    payload JSON NOT NULL COMMENT '投递内容',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态：pending/succeeded/failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    last_status_code INT COMMENT '最近一次响应状态码',
+    last_error TEXT COMMENT '最近一次错误',
+    next_attempt_at TIMESTAMP NULL COMMENT '下次尝试时间',
+    delivered_at TIMESTAMP NULL COMMENT '投递成功时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    INDEX idx_subscription_id (subscription_id),
+    INDEX idx_due (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook 投递记录表';
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    delivery_id BIGINT NOT NULL COMMENT '投递记录ID',
+    attempt INT NOT NULL COMMENT '第几次尝试',
+    status_code INT COMMENT '响应状态码',
+    error TEXT COMMENT '错误信息',
+    response_body TEXT COMMENT '响应内容（截断）',
+    duration_ms BIGINT NOT NULL DEFAULT 0 COMMENT '耗时（毫秒）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    INDEX idx_delivery_id (delivery_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook 投递尝试表';
@@ -0,0 +1,16 @@
+-- 011_api_keys.sql
+-- API Key 表：仅保存密钥的 SHA-256 哈希，明文只在创建时返回一次
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL COMMENT '名称（用途说明）',
+    prefix VARCHAR(16) NOT NULL COMMENT '密钥前缀，用于识别',
+    key_hash CHAR(64) NOT NULL COMMENT '密钥 SHA-256 哈希',
+    user_id BIGINT NOT NULL COMMENT '所属用户ID',
+    expires_at TIMESTAMP NULL COMMENT '过期时间，为空表示不过期',
+    last_used_at TIMESTAMP NULL COMMENT '最近使用时间',
+    revoked_at TIMESTAMP NULL COMMENT '吊销时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_key_hash (key_hash),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API Key 表';
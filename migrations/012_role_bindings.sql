@@ -0,0 +1,13 @@
+-- 012_role_bindings.sql
+-- 角色绑定表：flow_id 为 0 表示全局角色，否则为指定流程上的授权
+
+CREATE TABLE IF NOT EXISTS role_bindings (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_id BIGINT NOT NULL COMMENT '用户ID',
+    role VARCHAR(64) NOT NULL COMMENT '角色：内置角色或审批任务 approvers 中引用的角色名',
+    flow_id BIGINT NOT NULL DEFAULT 0 COMMENT '流程ID，0 表示全局',
+    created_by BIGINT COMMENT '授权人ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_user_role_flow (user_id, role, flow_id),
+    INDEX idx_flow_id (flow_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色绑定表';
@@ -0,0 +1,47 @@
+-- 013_projects.sql
+-- 项目（命名空间）：隔离任务定义、流程与作业，已有数据归入默认项目
+
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL COMMENT '项目名称',
+    slug VARCHAR(64) NOT NULL COMMENT '项目标识，可代替ID用于 X-Project-ID',
+    description TEXT COMMENT '项目描述',
+    allowed_executors JSON COMMENT '允许使用的执行器，为空表示不限制',
+    created_by BIGINT COMMENT '创建人ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_slug (slug)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='项目表';
+
+INSERT INTO projects (id, name, slug, description) VALUES (1, 'Default', 'default', '默认项目')
+ON DUPLICATE KEY UPDATE id = id;
+
+-- 项目成员：owner 可管理成员、密钥与执行器白名单
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id BIGINT NOT NULL COMMENT '项目ID',
+    user_id BIGINT NOT NULL COMMENT '用户ID',
+    role VARCHAR(20) NOT NULL DEFAULT 'member' COMMENT '成员角色：owner, member',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, user_id),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='项目成员表';
+
+-- 项目密钥：通过 ${secrets.<name>} 在任务配置中引用，接口只返回名称
+CREATE TABLE IF NOT EXISTS project_secrets (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    project_id BIGINT NOT NULL COMMENT '项目ID',
+    name VARCHAR(100) NOT NULL COMMENT '密钥名称',
+    value TEXT NOT NULL COMMENT '密钥值',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_project_name (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='项目密钥表';
+
+ALTER TABLE tasks ADD COLUMN project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID' AFTER id,
+    ADD INDEX idx_project_id (project_id);
+ALTER TABLE flows ADD COLUMN project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID' AFTER id,
+    ADD INDEX idx_project_id (project_id);
+ALTER TABLE jobs ADD COLUMN project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID' AFTER id,
+    ADD INDEX idx_project_id (project_id);
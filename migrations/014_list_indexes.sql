@@ -0,0 +1,19 @@
+-- 014_list_indexes.sql
+-- 列表查询索引：按项目过滤后按状态、流程、创建人、时间筛选与排序；
+-- 二级索引隐含主键 id，可直接用于 (排序列, id) 键集分页
+
+ALTER TABLE jobs
+    ADD INDEX idx_project_status_created (project_id, status, created_at),
+    ADD INDEX idx_project_flow_created (project_id, flow_id, created_at),
+    ADD INDEX idx_project_created (project_id, created_at),
+    ADD INDEX idx_project_updated (project_id, updated_at),
+    ADD INDEX idx_created_by (created_by);
+
+ALTER TABLE flows
+    ADD INDEX idx_project_active_name (project_id, is_active, name),
+    ADD INDEX idx_project_updated (project_id, updated_at),
+    ADD INDEX idx_created_by (created_by);
+
+ALTER TABLE tasks
+    ADD INDEX idx_project_type (project_id, task_type),
+    ADD INDEX idx_project_updated (project_id, updated_at);
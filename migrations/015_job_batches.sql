@@ -0,0 +1,21 @@
+-- 015_job_batches.sql
+-- 作业批次：按一组输入行批量创建同一流程的作业，可限制并发自动执行，并按批次汇总、取消与重试
+
+CREATE TABLE IF NOT EXISTS job_batches (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID',
+    flow_id BIGINT NOT NULL COMMENT '流程ID',
+    name VARCHAR(200) NOT NULL COMMENT '批次名称',
+    total_jobs INT NOT NULL DEFAULT 0 COMMENT '作业数量',
+    auto_execute BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否自动执行',
+    concurrency INT NOT NULL DEFAULT 1 COMMENT '自动执行的最大并发作业数',
+    created_by BIGINT COMMENT '创建人ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_project_created (project_id, created_at),
+    INDEX idx_flow_id (flow_id),
+    FOREIGN KEY (flow_id) REFERENCES flows(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作业批次表';
+
+ALTER TABLE jobs ADD COLUMN batch_id BIGINT NULL COMMENT '所属批次ID' AFTER flow_id,
+    ADD INDEX idx_batch_status (batch_id, status);
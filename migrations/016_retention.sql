@@ -0,0 +1,46 @@
+-- 016_retention.sql
+-- 作业保留策略、归档表与清理记录：后台清理器按流程策略删除或归档已结束的旧作业
+
+CREATE TABLE IF NOT EXISTS retention_policies (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    flow_id BIGINT COMMENT '流程ID，为空表示未单独配置策略的流程使用的默认策略',
+    keep_days INT NOT NULL DEFAULT 0 COMMENT '已完成、已取消作业保留天数，0 表示不按时间清理',
+    keep_jobs INT NOT NULL DEFAULT 0 COMMENT '每个流程保留最近的已完成、已取消作业数，0 表示不按数量清理',
+    failed_keep_days INT NOT NULL DEFAULT 0 COMMENT '失败作业保留天数，0 表示沿用 keep_days',
+    action VARCHAR(20) NOT NULL DEFAULT 'delete' COMMENT '清理方式：delete/archive_file/archive_table',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE COMMENT '是否启用',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_flow_id (flow_id),
+    FOREIGN KEY (flow_id) REFERENCES flows(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作业保留策略表';
+
+CREATE TABLE IF NOT EXISTS job_archives (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    job_id BIGINT NOT NULL COMMENT '原作业ID',
+    project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID',
+    flow_id BIGINT NOT NULL COMMENT '流程ID',
+    job_name VARCHAR(200) NOT NULL COMMENT '作业名称',
+    status VARCHAR(20) NOT NULL COMMENT '作业状态',
+    data JSON NOT NULL COMMENT '作业、作业任务、审计日志、上下文与制品信息',
+    job_created_at TIMESTAMP NULL COMMENT '作业创建时间',
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_job_id (job_id),
+    INDEX idx_flow_archived (flow_id, archived_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作业归档表';
+
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否只统计不清理',
+    jobs_deleted INT NOT NULL DEFAULT 0 COMMENT '删除的作业数（含已归档）',
+    jobs_archived INT NOT NULL DEFAULT 0 COMMENT '归档的作业数',
+    files_removed INT NOT NULL DEFAULT 0 COMMENT '删除的报告文件数',
+    artifacts_removed INT NOT NULL DEFAULT 0 COMMENT '删除的制品对象数',
+    details JSON NOT NULL COMMENT '按流程统计的清理明细',
+    error TEXT COMMENT '清理中断时的错误',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL,
+    INDEX idx_started_at (started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作业清理记录表';
+
+ALTER TABLE jobs ADD INDEX idx_flow_status_completed (flow_id, status, completed_at);
@@ -0,0 +1,6 @@
+-- 017_job_rerun.sql
+-- 作业重新运行：新作业记录其来源作业，来源作业被删除（如按保留策略清理）时置空
+
+ALTER TABLE jobs ADD COLUMN rerun_of BIGINT NULL COMMENT '重新运行的来源作业ID' AFTER batch_id,
+    ADD INDEX idx_rerun_of (rerun_of),
+    ADD CONSTRAINT fk_jobs_rerun_of FOREIGN KEY (rerun_of) REFERENCES jobs(id) ON DELETE SET NULL;
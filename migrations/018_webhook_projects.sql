@@ -0,0 +1,9 @@
+-- 018_webhook_projects.sql
+-- Webhook 订阅归属项目，只接收本项目的事件；已有订阅按所订阅流程归入其项目，未限定流程的归入默认项目
+
+ALTER TABLE webhook_subscriptions ADD COLUMN project_id BIGINT NOT NULL DEFAULT 1 COMMENT '所属项目ID' AFTER id,
+    ADD INDEX idx_project_id (project_id);
+
+UPDATE webhook_subscriptions ws
+INNER JOIN flows f ON ws.flow_id = f.id
+SET ws.project_id = f.project_id;
@@ -0,0 +1,10 @@
+// Package migrations 内置 SQLite 与 PostgreSQL 的数据库迁移，由服务启动时执行；
+// MySQL 迁移（本目录下的 *.sql）仍通过 source 手动导入
+package migrations
+
+import "embed"
+
+// FS 按方言分目录的迁移文件：sqlite/*.sql、postgres/*.sql
+//
+//go:embed sqlite/*.sql postgres/*.sql
+var FS embed.FS
@@ -0,0 +1,362 @@
+-- 001_init_schema.sql（SQLite）
+-- 与 MySQL 迁移 001、003、005-017 执行后的表结构一致：JSON 列存为 TEXT，时间存为 UTC 文本，
+-- updated_at 由触发器维护（对应 MySQL 的 ON UPDATE CURRENT_TIMESTAMP）
+
+-- 项目（命名空间）
+CREATE TABLE IF NOT EXISTS projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(64) NOT NULL,
+    description TEXT,
+    allowed_executors TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (slug)
+);
+
+INSERT OR IGNORE INTO projects (id, name, slug, description) VALUES (1, 'Default', 'default', '默认项目');
+
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members (user_id);
+
+CREATE TABLE IF NOT EXISTS project_secrets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, name)
+);
+
+-- 任务定义
+CREATE TABLE IF NOT EXISTS tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id BIGINT NOT NULL DEFAULT 1,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    task_type VARCHAR(50) NOT NULL,
+    config TEXT,
+    is_active BOOLEAN DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_tasks_name ON tasks (name);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_type ON tasks (project_id, task_type);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_updated ON tasks (project_id, updated_at);
+
+-- 流程定义
+CREATE TABLE IF NOT EXISTS flows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id BIGINT NOT NULL DEFAULT 1,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    version VARCHAR(20) DEFAULT '1.0.0',
+    input_keys TEXT,
+    is_active BOOLEAN DEFAULT 1,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_flows_name ON flows (name);
+CREATE INDEX IF NOT EXISTS idx_flows_project_active_name ON flows (project_id, is_active, name);
+CREATE INDEX IF NOT EXISTS idx_flows_project_updated ON flows (project_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_flows_created_by ON flows (created_by);
+
+-- 流程任务关联
+CREATE TABLE IF NOT EXISTS flow_tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flow_id BIGINT NOT NULL REFERENCES flows(id) ON DELETE CASCADE,
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    sequence INT NOT NULL,
+    is_optional BOOLEAN DEFAULT 0,
+    allow_rollback BOOLEAN DEFAULT 1,
+    condition_config TEXT,
+    config_overrides TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (flow_id, sequence)
+);
+CREATE INDEX IF NOT EXISTS idx_flow_tasks_task_id ON flow_tasks (task_id);
+
+-- 作业批次
+CREATE TABLE IF NOT EXISTS job_batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id BIGINT NOT NULL DEFAULT 1,
+    flow_id BIGINT NOT NULL REFERENCES flows(id),
+    name VARCHAR(200) NOT NULL,
+    total_jobs INT NOT NULL DEFAULT 0,
+    auto_execute BOOLEAN NOT NULL DEFAULT 0,
+    concurrency INT NOT NULL DEFAULT 1,
+    created_by BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_job_batches_project_created ON job_batches (project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_job_batches_flow_id ON job_batches (flow_id);
+
+-- 作业实例
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id BIGINT NOT NULL DEFAULT 1,
+    flow_id BIGINT NOT NULL REFERENCES flows(id),
+    batch_id BIGINT,
+    rerun_of BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    current_task_seq INT,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_flow_status_completed ON jobs (flow_id, status, completed_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs (created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_project_status_created ON jobs (project_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_project_flow_created ON jobs (project_id, flow_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_project_created ON jobs (project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_project_updated ON jobs (project_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_by ON jobs (created_by);
+CREATE INDEX IF NOT EXISTS idx_jobs_batch_status ON jobs (batch_id, status);
+CREATE INDEX IF NOT EXISTS idx_jobs_rerun_of ON jobs (rerun_of);
+
+-- 作业任务执行记录
+CREATE TABLE IF NOT EXISTS job_tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    flow_task_id BIGINT NOT NULL REFERENCES flow_tasks(id),
+    task_id BIGINT NOT NULL REFERENCES tasks(id),
+    sequence INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    is_skipped BOOLEAN DEFAULT 0,
+    executor_id BIGINT,
+    result TEXT,
+    error_message TEXT,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_job_tasks_status ON job_tasks (status);
+CREATE INDEX IF NOT EXISTS idx_job_tasks_sequence ON job_tasks (job_id, sequence);
+
+-- 作业任务日志
+CREATE TABLE IF NOT EXISTS job_task_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_task_id BIGINT NOT NULL REFERENCES job_tasks(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL,
+    operator_id BIGINT,
+    message TEXT,
+    metadata TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_job_task_logs_job_task_id ON job_task_logs (job_task_id);
+CREATE INDEX IF NOT EXISTS idx_job_task_logs_action ON job_task_logs (action);
+CREATE INDEX IF NOT EXISTS idx_job_task_logs_created_at ON job_task_logs (created_at);
+
+-- 作业上下文：scope 为 shared 或 task:<任务名称>，值为 JSON
+CREATE TABLE IF NOT EXISTS job_context (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    scope VARCHAR(255) NOT NULL DEFAULT 'shared',
+    context_key VARCHAR(255) NOT NULL,
+    context_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (job_id, scope, context_key)
+);
+
+-- 作业制品
+CREATE TABLE IF NOT EXISTS artifacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    job_task_id BIGINT REFERENCES job_tasks(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    checksum CHAR(64) NOT NULL,
+    backend VARCHAR(20) NOT NULL,
+    storage_key VARCHAR(1024) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_artifacts_job_id ON artifacts (job_id);
+CREATE INDEX IF NOT EXISTS idx_artifacts_job_task_id ON artifacts (job_task_id);
+
+-- 执行日志（毫秒精度）
+CREATE TABLE IF NOT EXISTS execution_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_task_id BIGINT NOT NULL REFERENCES job_tasks(id) ON DELETE CASCADE,
+    level VARCHAR(10) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_execution_logs_job_task_id ON execution_logs (job_task_id, id);
+
+-- Webhook 订阅、投递记录与投递尝试
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    event_types TEXT NOT NULL,
+    flow_id BIGINT REFERENCES flows(id) ON DELETE CASCADE,
+    secret VARCHAR(255) NOT NULL DEFAULT '',
+    is_active BOOLEAN DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_is_active ON webhook_subscriptions (is_active);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_status_code INT,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    response_body TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
+
+-- API Key 与角色绑定
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    user_id BIGINT NOT NULL,
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (key_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+
+CREATE TABLE IF NOT EXISTS role_bindings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(64) NOT NULL,
+    flow_id BIGINT NOT NULL DEFAULT 0,
+    created_by BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, role, flow_id)
+);
+CREATE INDEX IF NOT EXISTS idx_role_bindings_flow_id ON role_bindings (flow_id);
+
+-- 作业保留策略、归档与清理记录
+CREATE TABLE IF NOT EXISTS retention_policies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    flow_id BIGINT REFERENCES flows(id) ON DELETE CASCADE,
+    keep_days INT NOT NULL DEFAULT 0,
+    keep_jobs INT NOT NULL DEFAULT 0,
+    failed_keep_days INT NOT NULL DEFAULT 0,
+    action VARCHAR(20) NOT NULL DEFAULT 'delete',
+    is_active BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_retention_policies_flow_id ON retention_policies (flow_id);
+
+CREATE TABLE IF NOT EXISTS job_archives (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id BIGINT NOT NULL,
+    project_id BIGINT NOT NULL DEFAULT 1,
+    flow_id BIGINT NOT NULL,
+    job_name VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    data TEXT NOT NULL,
+    job_created_at TIMESTAMP NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (job_id)
+);
+CREATE INDEX IF NOT EXISTS idx_job_archives_flow_archived ON job_archives (flow_id, archived_at);
+
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dry_run BOOLEAN NOT NULL DEFAULT 0,
+    jobs_deleted INT NOT NULL DEFAULT 0,
+    jobs_archived INT NOT NULL DEFAULT 0,
+    files_removed INT NOT NULL DEFAULT 0,
+    artifacts_removed INT NOT NULL DEFAULT 0,
+    details TEXT NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL
+);
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs (started_at);
+
+-- updated_at 触发器：语句未显式修改 updated_at 时刷新为当前时间
+CREATE TRIGGER IF NOT EXISTS trg_projects_updated_at AFTER UPDATE ON projects
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_project_secrets_updated_at AFTER UPDATE ON project_secrets
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE project_secrets SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_tasks_updated_at AFTER UPDATE ON tasks
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_flows_updated_at AFTER UPDATE ON flows
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE flows SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_flow_tasks_updated_at AFTER UPDATE ON flow_tasks
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE flow_tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_job_batches_updated_at AFTER UPDATE ON job_batches
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE job_batches SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_jobs_updated_at AFTER UPDATE ON jobs
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_job_tasks_updated_at AFTER UPDATE ON job_tasks
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE job_tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_job_context_updated_at AFTER UPDATE ON job_context
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE job_context SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_webhook_subscriptions_updated_at AFTER UPDATE ON webhook_subscriptions
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE webhook_subscriptions SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_webhook_deliveries_updated_at AFTER UPDATE ON webhook_deliveries
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE webhook_deliveries SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
+
+CREATE TRIGGER IF NOT EXISTS trg_retention_policies_updated_at AFTER UPDATE ON retention_policies
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN UPDATE retention_policies SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id; END;
@@ -0,0 +1,7 @@
+-- 002_webhook_projects.sql（SQLite），对应 MySQL 迁移 018
+-- Webhook 订阅归属项目，只接收本项目的事件；已有订阅按所订阅流程归入其项目
+ALTER TABLE webhook_subscriptions ADD COLUMN project_id BIGINT NOT NULL DEFAULT 1;
+UPDATE webhook_subscriptions
+SET project_id = (SELECT f.project_id FROM flows f WHERE f.id = webhook_subscriptions.flow_id)
+WHERE flow_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_project_id ON webhook_subscriptions (project_id);
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// CreateAPIKeyRequest 创建 API Key 请求
+type CreateAPIKeyRequest struct {
+	Name      string `json:"name"`
+	UserID    int64  `json:"user_id,omitempty"`    // 仅在未启用认证时使用
+	ExpiresIn string `json:"expires_in,omitempty"` // 有效期，如 720h；为空表示不过期
+}
+
+// CreateRoleBindingRequest 授予角色请求
+type CreateRoleBindingRequest struct {
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role"`
+	FlowID int64  `json:"flow_id"` // 为 0 表示全局角色
+}
+
+// ProjectRequest 创建或更新项目请求
+type ProjectRequest struct {
+	Name             string   `json:"name"`
+	Slug             string   `json:"slug"`
+	Description      string   `json:"description"`
+	AllowedExecutors []string `json:"allowed_executors"`
+}
+
+// WhoAmI 返回当前认证主体
+func (c *Client) WhoAmI(ctx context.Context) (*Principal, error) {
+	var p Principal
+	if err := c.get(ctx, "/auth/me", nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListAPIKeys 获取当前用户的 API Key
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	err := c.get(ctx, "/api-keys", nil, &keys)
+	return keys, err
+}
+
+// CreateAPIKey 创建 API Key，返回的 Key 为明文且只返回一次
+func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	var key CreatedAPIKey
+	if err := c.post(ctx, "/api-keys", req, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey 吊销 API Key
+func (c *Client) RevokeAPIKey(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/api-keys/%d", id))
+}
+
+// ListRoles 列出内置角色及其权限
+func (c *Client) ListRoles(ctx context.Context) (map[string][]string, error) {
+	var roles map[string][]string
+	err := c.get(ctx, "/roles", nil, &roles)
+	return roles, err
+}
+
+// ListRoleBindings 按用户或流程查询角色绑定，参数为 0 表示不按该条件过滤
+func (c *Client) ListRoleBindings(ctx context.Context, userID, flowID int64) ([]RoleBinding, error) {
+	query := url.Values{}
+	if userID > 0 {
+		query.Set("user_id", strconv.FormatInt(userID, 10))
+	}
+	if flowID > 0 {
+		query.Set("flow_id", strconv.FormatInt(flowID, 10))
+	}
+	var bindings []RoleBinding
+	err := c.get(ctx, "/role-bindings", query, &bindings)
+	return bindings, err
+}
+
+// CreateRoleBinding 授予角色
+func (c *Client) CreateRoleBinding(ctx context.Context, req CreateRoleBindingRequest) (*RoleBinding, error) {
+	var binding RoleBinding
+	if err := c.post(ctx, "/role-bindings", req, &binding); err != nil {
+		return nil, err
+	}
+	return &binding, nil
+}
+
+// DeleteRoleBinding 撤销角色
+func (c *Client) DeleteRoleBinding(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/role-bindings/%d", id))
+}
+
+// ListProjects 获取当前用户可访问的项目
+func (c *Client) ListProjects(ctx context.Context, opts ListOptions) ([]Project, error) {
+	var projects []Project
+	err := c.get(ctx, "/projects", opts.query(), &projects)
+	return projects, err
+}
+
+// IterateProjects 逐页遍历项目
+func (c *Client) IterateProjects(opts ListOptions) *Iterator[Project] {
+	return newIterator(opts, withoutPage(c.ListProjects))
+}
+
+// CreateProject 创建项目，当前用户成为项目 owner
+func (c *Client) CreateProject(ctx context.Context, req ProjectRequest) (*Project, error) {
+	var project Project
+	if err := c.post(ctx, "/projects", req, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProject 获取项目
+func (c *Client) GetProject(ctx context.Context, id int64) (*Project, error) {
+	var project Project
+	if err := c.get(ctx, pathf("/projects/%d", id), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// UpdateProject 更新项目
+func (c *Client) UpdateProject(ctx context.Context, id int64, req ProjectRequest) (*Project, error) {
+	var project Project
+	if err := c.put(ctx, pathf("/projects/%d", id), nil, req, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListProjectMembers 获取项目成员
+func (c *Client) ListProjectMembers(ctx context.Context, projectID int64) ([]ProjectMember, error) {
+	var members []ProjectMember
+	err := c.get(ctx, pathf("/projects/%d/members", projectID), nil, &members)
+	return members, err
+}
+
+// AddProjectMember 添加成员或修改成员角色，role 为 owner 或 member
+func (c *Client) AddProjectMember(ctx context.Context, projectID, userID int64, role string) (*ProjectMember, error) {
+	var member ProjectMember
+	body := ProjectMember{UserID: userID, Role: role}
+	if err := c.post(ctx, pathf("/projects/%d/members", projectID), body, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveProjectMember 移除成员
+func (c *Client) RemoveProjectMember(ctx context.Context, projectID, userID int64) error {
+	return c.del(ctx, pathf("/projects/%d/members/%d", projectID, userID))
+}
+
+// ListProjectSecrets 获取项目密钥名称
+func (c *Client) ListProjectSecrets(ctx context.Context, projectID int64) ([]ProjectSecret, error) {
+	var secrets []ProjectSecret
+	err := c.get(ctx, pathf("/projects/%d/secrets", projectID), nil, &secrets)
+	return secrets, err
+}
+
+// SetProjectSecret 写入项目密钥，同名时覆盖
+func (c *Client) SetProjectSecret(ctx context.Context, projectID int64, name, value string) error {
+	body := map[string]string{"name": name, "value": value}
+	return c.put(ctx, pathf("/projects/%d/secrets", projectID), nil, body, nil)
+}
+
+// DeleteProjectSecret 删除项目密钥
+func (c *Client) DeleteProjectSecret(ctx context.Context, projectID int64, name string) error {
+	return c.del(ctx, pathf("/projects/%d/secrets/%s", projectID, name))
+}
+
+// ListExecutors 列出执行器及其输入/输出契约
+func (c *Client) ListExecutors(ctx context.Context) ([]ExecutorDescriptor, error) {
+	var executors []ExecutorDescriptor
+	err := c.get(ctx, "/executors", nil, &executors)
+	return executors, err
+}
+
+// GetLogLevel 获取服务端日志级别
+func (c *Client) GetLogLevel(ctx context.Context) (string, error) {
+	var resp struct {
+		Level string `json:"level"`
+	}
+	err := c.get(ctx, "/log-level", nil, &resp)
+	return resp.Level, err
+}
+
+// SetLogLevel 调整服务端日志级别
+func (c *Client) SetLogLevel(ctx context.Context, level string) error {
+	return c.put(ctx, "/log-level", nil, map[string]string{"level": level}, nil)
+}
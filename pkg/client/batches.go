@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// CreateJobBatchRequest 批量创建作业请求
+type CreateJobBatchRequest struct {
+	FlowID int64  `json:"flow_id"`
+	Name   string `json:"name,omitempty"` // 默认为流程名称加创建时间
+	// Rows 每行创建一个作业：job_name 为作业名称（默认为批次名称加行号），其余键为流程输入
+	Rows        []map[string]interface{} `json:"rows"`
+	AutoExecute bool                     `json:"auto_execute,omitempty"` // 创建后由服务端启动并自动执行
+	Concurrency int                      `json:"concurrency,omitempty"`  // 自动执行的最大并发作业数，0 表示服务端默认值
+	CreatedBy   int64                    `json:"created_by,omitempty"`   // 仅在未启用认证时使用
+}
+
+// CreateJobBatch 在同一事务中按输入行批量创建作业，任一行无效时不创建任何作业
+func (c *Client) CreateJobBatch(ctx context.Context, req CreateJobBatchRequest) (*JobBatchDetail, error) {
+	var detail JobBatchDetail
+	if err := c.post(ctx, "/job-batches", req, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// GetJobBatch 获取批次及其作业按状态的统计
+func (c *Client) GetJobBatch(ctx context.Context, id int64) (*JobBatchSummary, error) {
+	var summary JobBatchSummary
+	if err := c.get(ctx, pathf("/job-batches/%d", id), nil, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// CancelJobBatch 取消批次中所有待执行与执行中的作业
+func (c *Client) CancelJobBatch(ctx context.Context, id int64, reason string) (*JobBatchResult, error) {
+	var result JobBatchResult
+	if err := c.post(ctx, pathf("/job-batches/%d:cancel", id), map[string]string{"reason": reason}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RetryFailedJobBatch 重试批次中所有失败的作业；批次开启自动执行时服务端按批次并发数重新执行
+func (c *Client) RetryFailedJobBatch(ctx context.Context, id int64) (*JobBatchResult, error) {
+	var result JobBatchResult
+	if err := c.post(ctx, pathf("/job-batches/%d:retry-failed", id), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WaitForJobBatch 轮询批次直到所有作业结束，interval 为 0 时使用 DefaultPollInterval
+func (c *Client) WaitForJobBatch(ctx context.Context, id int64, interval time.Duration) (*JobBatchSummary, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		summary, err := c.GetJobBatch(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if summary.Finished {
+			return summary, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return summary, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package client 是 GoWorkFlow /api/v1 接口的 Go 客户端
+//
+//	c := client.New("http://localhost:8080", client.WithToken("gwf_xxx"), client.WithProject("ai-team"))
+//	job, err := c.CreateJob(ctx, client.CreateJobRequest{FlowID: 1, JobName: "nightly"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 默认重试策略：仅对幂等请求（GET、PUT、DELETE）在网络错误或 429、502、503、504 时重试
+const (
+	defaultMaxRetries = 3
+	defaultRetryWait  = 200 * time.Millisecond
+	maxRetryWait      = 5 * time.Second
+)
+
+// Client GoWorkFlow API 客户端，可在多个 goroutine 中并发使用
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	project    string
+	userAgent  string
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// Option 客户端选项
+type Option func(*Client)
+
+// WithToken 设置凭据（API Key 或 JWT），以 Authorization: Bearer 发送
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithProject 设置默认项目（项目 ID 或标识），以 X-Project-ID 发送
+func WithProject(ref string) Option {
+	return func(c *Client) { c.project = ref }
+}
+
+// WithHTTPClient 使用自定义 http.Client（超时、代理、TLS 等）
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetry 设置幂等请求的最大重试次数与首次重试等待时间，等待时间按指数增长；maxRetries 为 0 表示不重试
+func WithRetry(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New 创建客户端，baseURL 为服务地址，如 http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		userAgent:  "goworkflow-go-client",
+		maxRetries: defaultMaxRetries,
+		retryWait:  defaultRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ForProject 返回在指定项目中操作的客户端副本
+func (c *Client) ForProject(ref string) *Client {
+	clone := *c
+	clone.project = ref
+	return &clone
+}
+
+// envelope 统一响应结构
+type envelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Page    *Page           `json:"page"`
+}
+
+// get、post、put、del 发送请求并将响应的 data 解码到 out，out 为 nil 时忽略 data
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, nil, body, out)
+}
+
+func (c *Client) put(ctx context.Context, path string, query url.Values, body, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, query, body, out)
+}
+
+func (c *Client) del(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	_, err := c.doPage(ctx, method, path, query, body, out)
+	return err
+}
+
+// list 发送列表请求，返回响应中的分页信息
+func (c *Client) list(ctx context.Context, path string, query url.Values, out interface{}) (*Page, error) {
+	return c.doPage(ctx, http.MethodGet, path, query, nil, out)
+}
+
+func (c *Client) doPage(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*Page, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("encode request body: %w", err)
+		}
+	}
+
+	resp, err := c.send(ctx, method, path, query, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, newAPIError(resp)
+	}
+	if out == nil {
+		return nil, nil
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(env.Data) == 0 || string(env.Data) == "null" {
+		return env.Page, nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return nil, fmt.Errorf("decode response data: %w", err)
+	}
+	return env.Page, nil
+}
+
+// send 发送请求，幂等请求按重试策略重试；返回的响应须由调用方关闭
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, payload []byte) (*http.Response, error) {
+	u := c.baseURL + APIPrefix + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	retries := 0
+	if isIdempotent(method) {
+		retries = c.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		if c.project != "" {
+			req.Header.Set("X-Project-ID", c.project)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt >= retries || !shouldRetry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.backoff(attempt)):
+		}
+	}
+}
+
+// backoff 第 attempt 次重试前的等待时间：指数增长并加入随机抖动
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryWait << attempt
+	if wait <= 0 || wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// APIPrefix 客户端访问的接口版本前缀
+const APIPrefix = "/api/v1"
+
+// pathf 构造路径，字符串参数按路径段转义
+func pathf(format string, args ...interface{}) string {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok {
+			args[i] = url.PathEscape(s)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
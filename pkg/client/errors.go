@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// 与接口错误码对应的错误，可用 errors.Is 判断，如 errors.Is(err, client.ErrNotFound)
+var (
+	ErrBadRequest       = errors.New("bad request")        // 400 参数错误或契约校验失败
+	ErrUnauthorized     = errors.New("unauthorized")       // 401 未认证
+	ErrForbidden        = errors.New("forbidden")          // 403 权限不足或不是项目成员
+	ErrNotFound         = errors.New("not found")          // 404 资源不存在或不属于当前项目
+	ErrMethodNotAllowed = errors.New("method not allowed") // 405 方法不支持
+	ErrServer           = errors.New("server error")       // 5xx 服务端错误
+)
+
+// APIError 接口返回的错误响应
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Code       int    // 响应体中的 code
+	Message    string // 响应体中的 message
+	RequestID  string // X-Request-ID，排查问题时提供给服务端
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("goworkflow: %d %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("goworkflow: %d %s", e.StatusCode, e.Message)
+}
+
+// Unwrap 返回状态码对应的错误
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusMethodNotAllowed:
+		return ErrMethodNotAllowed
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
+
+// newAPIError 从错误响应构造 APIError，响应体不是统一结构时以状态文本作为 message
+func newAPIError(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Code:       resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var env envelope
+	if json.Unmarshal(body, &env) == nil && env.Message != "" {
+		apiErr.Code = env.Code
+		apiErr.Message = env.Message
+	} else {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// CreateFlowRequest 创建流程请求，Tasks 与 TaskIDs 二选一，Tasks 可指定流程级配置覆盖
+type CreateFlowRequest struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Version     string         `json:"version"`
+	Inputs      []string       `json:"inputs,omitempty"`
+	TaskIDs     []int64        `json:"task_ids,omitempty"`
+	Tasks       []FlowTaskSpec `json:"tasks,omitempty"`
+	CreatedBy   int64          `json:"created_by,omitempty"` // 仅在未启用认证时使用
+}
+
+// FlowTaskSpec 流程中的一个任务，AllowRollback 为 nil 时默认允许打回
+type FlowTaskSpec struct {
+	TaskID          int64                  `json:"task_id"`
+	IsOptional      bool                   `json:"is_optional"`
+	AllowRollback   *bool                  `json:"allow_rollback,omitempty"`
+	ConfigOverrides map[string]interface{} `json:"config_overrides,omitempty"`
+}
+
+// FlowQuery 流程列表查询条件，排序字段可为 id、name、created_at、updated_at
+type FlowQuery struct {
+	ListOptions
+	Filter
+	CreatedBy int64
+	IsActive  ActiveFilter // 默认只返回启用的流程
+}
+
+func (q FlowQuery) query() url.Values {
+	v := q.ListOptions.query()
+	q.Filter.apply(v)
+	setInt64(v, "created_by", q.CreatedBy)
+	if q.IsActive != ActiveDefault {
+		v.Set("is_active", string(q.IsActive))
+	}
+	return v
+}
+
+// ListFlows 获取启用的流程列表
+func (c *Client) ListFlows(ctx context.Context, opts ListOptions) ([]Flow, error) {
+	flows, _, err := c.SearchFlows(ctx, FlowQuery{ListOptions: opts})
+	return flows, err
+}
+
+// SearchFlows 按条件查询流程，返回当前页与分页信息（总数、下一页游标）
+func (c *Client) SearchFlows(ctx context.Context, q FlowQuery) ([]Flow, *Page, error) {
+	var flows []Flow
+	page, err := c.list(ctx, "/flows", q.query(), &flows)
+	return flows, page, err
+}
+
+// IterateFlows 逐页遍历符合条件的流程
+func (c *Client) IterateFlows(q FlowQuery) *Iterator[Flow] {
+	return newIterator(q.ListOptions, func(ctx context.Context, opts ListOptions) ([]Flow, *Page, error) {
+		q.ListOptions = opts
+		return c.SearchFlows(ctx, q)
+	})
+}
+
+// CreateFlow 创建流程
+func (c *Client) CreateFlow(ctx context.Context, req CreateFlowRequest) (*Flow, error) {
+	var flow Flow
+	if err := c.post(ctx, "/flows", req, &flow); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+// GetFlow 获取流程详情（包含任务编排）
+func (c *Client) GetFlow(ctx context.Context, id int64) (*FlowDetail, error) {
+	var detail FlowDetail
+	if err := c.get(ctx, pathf("/flows/%d", id), nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// UpdateFlow 更新流程，flow.ID 指定要更新的流程
+func (c *Client) UpdateFlow(ctx context.Context, flow *Flow) (*Flow, error) {
+	var updated Flow
+	if err := c.put(ctx, pathf("/flows/%d", flow.ID), nil, flow, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteFlow 删除流程
+func (c *Client) DeleteFlow(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/flows/%d", id))
+}
+
+// ListFlowTasks 获取流程的任务编排
+func (c *Client) ListFlowTasks(ctx context.Context, flowID int64) ([]FlowTask, error) {
+	var flowTasks []FlowTask
+	err := c.get(ctx, pathf("/flows/%d/tasks", flowID), nil, &flowTasks)
+	return flowTasks, err
+}
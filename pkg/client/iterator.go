@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultPageSize 迭代器每页请求的条数
+const DefaultPageSize = 100
+
+// ListOptions 列表接口的分页与排序参数，Limit 为 0 时使用服务端默认值
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   string // 排序字段，前缀 - 表示降序，如 -created_at；仅任务、流程与作业列表支持
+	Cursor string // 上一页 Page.NextCursor，指定时忽略 Offset
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// Filter 任务、流程与作业列表的通用筛选条件，零值表示不限
+type Filter struct {
+	Name          string // 名称包含
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+func (f Filter) apply(q url.Values) {
+	if f.Name != "" {
+		q.Set("name", f.Name)
+	}
+	setTime(q, "created_after", f.CreatedAfter)
+	setTime(q, "created_before", f.CreatedBefore)
+	setTime(q, "updated_after", f.UpdatedAfter)
+	setTime(q, "updated_before", f.UpdatedBefore)
+}
+
+func setTime(q url.Values, name string, t time.Time) {
+	if !t.IsZero() {
+		q.Set(name, t.Format(time.RFC3339Nano))
+	}
+}
+
+func setInt64(q url.Values, name string, v int64) {
+	if v > 0 {
+		q.Set(name, strconv.FormatInt(v, 10))
+	}
+}
+
+// ActiveFilter 按启用状态筛选
+type ActiveFilter string
+
+const (
+	ActiveDefault ActiveFilter = ""      // 服务端默认：任务不限，流程只返回启用的
+	ActiveOnly    ActiveFilter = "true"  // 只返回启用的
+	InactiveOnly  ActiveFilter = "false" // 只返回停用的
+	ActiveAny     ActiveFilter = "all"   // 不限
+)
+
+// pageFetcher 拉取一页记录，page 为 nil 表示接口不返回分页信息
+type pageFetcher[T any] func(ctx context.Context, opts ListOptions) ([]T, *Page, error)
+
+// withoutPage 适配只返回记录的列表方法
+func withoutPage[T any](list func(ctx context.Context, opts ListOptions) ([]T, error)) pageFetcher[T] {
+	return func(ctx context.Context, opts ListOptions) ([]T, *Page, error) {
+		items, err := list(ctx, opts)
+		return items, nil, err
+	}
+}
+
+// Iterator 按页拉取列表的迭代器，接口返回游标时按游标翻页，否则按偏移量翻页：
+//
+//	it := c.IterateJobs(client.JobQuery{Statuses: []client.JobStatus{client.JobStatusFailed}})
+//	for it.Next(ctx) {
+//		job := it.Value()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	fetch pageFetcher[T]
+	opts  ListOptions
+	page  []T
+	index int
+	cur   T
+	done  bool
+	err   error
+}
+
+func newIterator[T any](opts ListOptions, fetch pageFetcher[T]) *Iterator[T] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageSize
+	}
+	return &Iterator[T]{fetch: fetch, opts: opts}
+}
+
+// Next 前进到下一条记录，没有更多记录或出错时返回 false
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		items, page, err := it.fetch(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if page != nil {
+			it.done = page.NextCursor == ""
+			it.opts.Cursor = page.NextCursor
+		} else {
+			// 不足一页说明已是最后一页
+			it.done = len(items) < it.opts.Limit
+			it.opts.Offset += len(items)
+		}
+		it.page, it.index = items, 0
+		if len(items) == 0 {
+			return false
+		}
+	}
+	it.cur = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Value 返回当前记录
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err 返回迭代过程中的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All 拉取剩余的全部记录
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CreateJobRequest 创建作业请求
+type CreateJobRequest struct {
+	FlowID    int64  `json:"flow_id"`
+	JobName   string `json:"job_name"`
+	CreatedBy int64  `json:"created_by,omitempty"` // 仅在未启用认证时使用
+}
+
+// JobContext 作业上下文：共享作用域与按任务名称划分的任务作用域
+type JobContext struct {
+	Shared map[string]interface{}            `json:"shared"`
+	Tasks  map[string]map[string]interface{} `json:"tasks"`
+}
+
+// JobQuery 作业列表查询条件，排序字段可为 id、name、status、created_at、updated_at
+type JobQuery struct {
+	ListOptions
+	Filter
+	Statuses  []JobStatus // 任一状态
+	FlowID    int64
+	BatchID   int64
+	RerunOf   int64 // 重新运行的来源作业
+	CreatedBy int64
+}
+
+func (q JobQuery) query() url.Values {
+	v := q.ListOptions.query()
+	q.Filter.apply(v)
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, s := range q.Statuses {
+			statuses[i] = string(s)
+		}
+		v.Set("status", strings.Join(statuses, ","))
+	}
+	setInt64(v, "flow_id", q.FlowID)
+	setInt64(v, "batch_id", q.BatchID)
+	setInt64(v, "rerun_of", q.RerunOf)
+	setInt64(v, "created_by", q.CreatedBy)
+	return v
+}
+
+// ListJobs 获取作业列表
+func (c *Client) ListJobs(ctx context.Context, opts ListOptions) ([]Job, error) {
+	jobs, _, err := c.SearchJobs(ctx, JobQuery{ListOptions: opts})
+	return jobs, err
+}
+
+// SearchJobs 按条件查询作业，返回当前页与分页信息（总数、下一页游标）
+func (c *Client) SearchJobs(ctx context.Context, q JobQuery) ([]Job, *Page, error) {
+	var jobs []Job
+	page, err := c.list(ctx, "/jobs", q.query(), &jobs)
+	return jobs, page, err
+}
+
+// IterateJobs 逐页遍历符合条件的作业
+func (c *Client) IterateJobs(q JobQuery) *Iterator[Job] {
+	return newIterator(q.ListOptions, func(ctx context.Context, opts ListOptions) ([]Job, *Page, error) {
+		q.ListOptions = opts
+		return c.SearchJobs(ctx, q)
+	})
+}
+
+// CreateJob 基于流程创建作业
+func (c *Client) CreateJob(ctx context.Context, req CreateJobRequest) (*Job, error) {
+	var job Job
+	if err := c.post(ctx, "/jobs", req, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJob 获取作业详情（包含所有作业任务）
+func (c *Client) GetJob(ctx context.Context, id int64) (*JobDetail, error) {
+	var detail JobDetail
+	if err := c.get(ctx, pathf("/jobs/%d", id), nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// StartJob 启动作业
+func (c *Client) StartJob(ctx context.Context, id int64) error {
+	return c.post(ctx, pathf("/jobs/%d:start", id), nil, nil)
+}
+
+// CancelJob 取消待执行或执行中的作业，执行中的任务标记为失败
+func (c *Client) CancelJob(ctx context.Context, id int64, reason string) error {
+	return c.post(ctx, pathf("/jobs/%d:cancel", id), map[string]string{"reason": reason}, nil)
+}
+
+// RerunJobRequest 重新运行作业请求
+type RerunJobRequest struct {
+	JobName       string                 `json:"job_name,omitempty"`       // 默认为来源作业名称加 (rerun)
+	LatestVersion bool                   `json:"latest_version,omitempty"` // 使用同名流程的最新启用版本
+	Inputs        map[string]interface{} `json:"inputs,omitempty"`         // 覆盖来源作业的输入
+	ReuseOutputs  bool                   `json:"reuse_outputs,omitempty"`  // 复用未变化的上游任务输出
+	CreatedBy     int64                  `json:"created_by,omitempty"`     // 仅在未启用认证时使用
+}
+
+// RerunJob 以来源作业的输入创建新作业（待执行状态），返回新作业及复用输出的任务
+func (c *Client) RerunJob(ctx context.Context, id int64, req RerunJobRequest) (*JobRerun, error) {
+	var rerun JobRerun
+	if err := c.post(ctx, pathf("/jobs/%d:rerun", id), req, &rerun); err != nil {
+		return nil, err
+	}
+	return &rerun, nil
+}
+
+// AutoExecuteJob 在服务端后台依次自动执行作业的所有任务，立即返回
+func (c *Client) AutoExecuteJob(ctx context.Context, id int64) error {
+	return c.post(ctx, pathf("/jobs/%d:auto-execute", id), nil, nil)
+}
+
+// GetNextTask 获取下一个待执行的作业任务，没有待执行任务时返回 nil
+func (c *Client) GetNextTask(ctx context.Context, jobID int64) (*JobTask, error) {
+	var raw json.RawMessage
+	if err := c.get(ctx, pathf("/jobs/%d/next-task", jobID), nil, &raw); err != nil {
+		return nil, err
+	}
+	var task JobTask
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, &task); err != nil {
+		return nil, fmt.Errorf("decode response data: %w", err)
+	}
+	if task.ID == 0 {
+		return nil, nil
+	}
+	return &task, nil
+}
+
+// GetJobContext 获取作业的全部上下文
+func (c *Client) GetJobContext(ctx context.Context, jobID int64) (*JobContext, error) {
+	var jc JobContext
+	if err := c.get(ctx, pathf("/jobs/%d/context", jobID), nil, &jc); err != nil {
+		return nil, err
+	}
+	return &jc, nil
+}
+
+// GetJobContextScope 获取作业上下文的单个作用域，scope 为 shared 或 task:<任务名称>
+func (c *Client) GetJobContextScope(ctx context.Context, jobID int64, scope string) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	err := c.get(ctx, pathf("/jobs/%d/context", jobID), url.Values{"scope": {scope}}, &values)
+	return values, err
+}
+
+// UpdateJobContext 合并写入作业上下文，scope 为空时写入共享作用域
+func (c *Client) UpdateJobContext(ctx context.Context, jobID int64, scope string, values map[string]interface{}) error {
+	var query url.Values
+	if scope != "" {
+		query = url.Values{"scope": {scope}}
+	}
+	return c.put(ctx, pathf("/jobs/%d/context", jobID), query, values, nil)
+}
+
+// ListJobLogs 获取作业的审计日志
+func (c *Client) ListJobLogs(ctx context.Context, jobID int64) ([]JobTaskLog, error) {
+	var logs []JobTaskLog
+	err := c.get(ctx, pathf("/jobs/%d/logs", jobID), nil, &logs)
+	return logs, err
+}
+
+// ListArtifacts 获取作业的制品列表
+func (c *Client) ListArtifacts(ctx context.Context, jobID int64) ([]Artifact, error) {
+	var artifacts []Artifact
+	err := c.get(ctx, pathf("/jobs/%d/artifacts", jobID), nil, &artifacts)
+	return artifacts, err
+}
+
+// DownloadArtifact 下载制品内容，返回的 ReadCloser 须由调用方关闭
+func (c *Client) DownloadArtifact(ctx context.Context, jobID, artifactID int64) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, http.MethodGet, pathf("/jobs/%d/artifacts/%d", jobID, artifactID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// ListJobTasks 获取作业的任务列表
+func (c *Client) ListJobTasks(ctx context.Context, jobID int64) ([]JobTask, error) {
+	var tasks []JobTask
+	err := c.get(ctx, pathf("/jobs/%d/tasks", jobID), nil, &tasks)
+	return tasks, err
+}
+
+// GetJobTask 获取作业任务
+func (c *Client) GetJobTask(ctx context.Context, jobID, jobTaskID int64) (*JobTask, error) {
+	var task JobTask
+	if err := c.get(ctx, pathf("/jobs/%d/tasks/%d", jobID, jobTaskID), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// taskAction 调用作业任务动作接口
+func (c *Client) taskAction(ctx context.Context, jobID, jobTaskID int64, action string, body interface{}) error {
+	return c.post(ctx, pathf("/jobs/%d/tasks/%d:%s", jobID, jobTaskID, action), body, nil)
+}
+
+// StartTask 开始执行任务；启用认证时执行人为当前用户，executorID 被忽略
+func (c *Client) StartTask(ctx context.Context, jobID, jobTaskID, executorID int64) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "start", map[string]int64{"executor_id": executorID})
+}
+
+// CompleteTask 完成任务
+func (c *Client) CompleteTask(ctx context.Context, jobID, jobTaskID int64, result map[string]interface{}) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "complete", map[string]interface{}{"result": result})
+}
+
+// FailTask 标记任务失败
+func (c *Client) FailTask(ctx context.Context, jobID, jobTaskID int64, errorMessage string) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "fail", map[string]string{"error_message": errorMessage})
+}
+
+// SkipTask 跳过可选任务
+func (c *Client) SkipTask(ctx context.Context, jobID, jobTaskID, operatorID int64) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "skip", map[string]int64{"operator_id": operatorID})
+}
+
+// RollbackTask 打回到指定序号的任务
+func (c *Client) RollbackTask(ctx context.Context, jobID, jobTaskID, operatorID int64, targetSequence int) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "rollback", map[string]int64{
+		"operator_id":     operatorID,
+		"target_sequence": int64(targetSequence),
+	})
+}
+
+// RetryTask 重试失败的任务
+func (c *Client) RetryTask(ctx context.Context, jobID, jobTaskID, operatorID int64) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "retry", map[string]int64{"operator_id": operatorID})
+}
+
+// ReassignTask 将任务转派给其他执行人
+func (c *Client) ReassignTask(ctx context.Context, jobID, jobTaskID, operatorID, assigneeID int64) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "reassign", map[string]int64{
+		"operator_id": operatorID,
+		"assignee_id": assigneeID,
+	})
+}
+
+// ExecuteTask 在服务端后台执行单个自动化任务，立即返回
+func (c *Client) ExecuteTask(ctx context.Context, jobID, jobTaskID int64) error {
+	return c.taskAction(ctx, jobID, jobTaskID, "execute", nil)
+}
+
+// ListJobTaskLogs 获取作业任务的审计日志
+func (c *Client) ListJobTaskLogs(ctx context.Context, jobID, jobTaskID int64) ([]JobTaskLog, error) {
+	var logs []JobTaskLog
+	err := c.get(ctx, pathf("/jobs/%d/tasks/%d/logs", jobID, jobTaskID), nil, &logs)
+	return logs, err
+}
+
+// ListExecutionLogs 获取作业任务 afterID 之后的执行日志，limit 为 0 时使用服务端默认值
+func (c *Client) ListExecutionLogs(ctx context.Context, jobID, jobTaskID, afterID int64, limit int) ([]ExecutionLog, error) {
+	query := url.Values{}
+	if afterID > 0 {
+		query.Set("after_id", strconv.FormatInt(afterID, 10))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var logs []ExecutionLog
+	err := c.get(ctx, pathf("/jobs/%d/tasks/%d/execution-logs", jobID, jobTaskID), query, &logs)
+	return logs, err
+}
@@ -0,0 +1,50 @@
+package client
+
+import "context"
+
+// RetentionPolicyRequest 设置保留策略请求，按 FlowID 覆盖已有策略
+type RetentionPolicyRequest struct {
+	FlowID         *int64 `json:"flow_id"`          // 为空表示默认策略
+	KeepDays       int    `json:"keep_days"`        // 已完成、已取消作业保留天数，0 表示不按时间清理
+	KeepJobs       int    `json:"keep_jobs"`        // 保留最近的已完成、已取消作业数，0 表示不按数量清理
+	FailedKeepDays int    `json:"failed_keep_days"` // 失败作业保留天数，0 表示沿用 KeepDays
+	Action         string `json:"action,omitempty"` // delete（默认）、archive_file 或 archive_table
+	IsActive       *bool  `json:"is_active,omitempty"`
+}
+
+// ListRetentionPolicies 获取全部保留策略
+func (c *Client) ListRetentionPolicies(ctx context.Context) ([]RetentionPolicy, error) {
+	var policies []RetentionPolicy
+	err := c.get(ctx, "/retention-policies", nil, &policies)
+	return policies, err
+}
+
+// SetRetentionPolicy 设置流程或默认保留策略
+func (c *Client) SetRetentionPolicy(ctx context.Context, req RetentionPolicyRequest) (*RetentionPolicy, error) {
+	var policy RetentionPolicy
+	if err := c.put(ctx, "/retention-policies", nil, req, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeleteRetentionPolicy 删除保留策略，流程随之改用默认策略
+func (c *Client) DeleteRetentionPolicy(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/retention-policies/%d", id))
+}
+
+// RunRetention 立即按当前策略清理一次，dryRun 时只统计将被清理的作业
+func (c *Client) RunRetention(ctx context.Context, dryRun bool) (*RetentionRun, error) {
+	var run RetentionRun
+	if err := c.post(ctx, "/retention-runs", map[string]bool{"dry_run": dryRun}, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListRetentionRuns 获取清理记录，最近的在前
+func (c *Client) ListRetentionRuns(ctx context.Context, opts ListOptions) ([]RetentionRun, error) {
+	var runs []RetentionRun
+	err := c.get(ctx, "/retention-runs", opts.query(), &runs)
+	return runs, err
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EventFilter 事件订阅过滤条件，零值字段表示不过滤
+type EventFilter struct {
+	JobID  int64
+	FlowID int64
+	Types  []string
+}
+
+// ErrStopStream 由回调返回以正常结束订阅
+var ErrStopStream = errors.New("stop stream")
+
+// sseMessage 一条 Server-Sent Events 消息
+type sseMessage struct {
+	event string
+	data  string
+}
+
+// StreamEvents 订阅作业与任务事件，每个事件调用一次 fn；
+// 直到 ctx 取消、服务端关闭连接或 fn 返回错误（返回 ErrStopStream 时结果为 nil）。
+// 长连接不应设置 http.Client.Timeout，请用 ctx 控制时长
+func (c *Client) StreamEvents(ctx context.Context, filter EventFilter, fn func(Event) error) error {
+	query := url.Values{}
+	if filter.JobID > 0 {
+		query.Set("job_id", strconv.FormatInt(filter.JobID, 10))
+	}
+	if filter.FlowID > 0 {
+		query.Set("flow_id", strconv.FormatInt(filter.FlowID, 10))
+	}
+	if len(filter.Types) > 0 {
+		query.Set("types", strings.Join(filter.Types, ","))
+	}
+
+	return c.stream(ctx, "/events", query, func(msg sseMessage) error {
+		var e Event
+		if err := json.Unmarshal([]byte(msg.data), &e); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		return fn(e)
+	})
+}
+
+// StreamExecutionLogs 实时跟踪作业任务 afterID 之后的执行日志，任务结束后返回其最终状态
+func (c *Client) StreamExecutionLogs(ctx context.Context, jobID, jobTaskID, afterID int64, fn func(ExecutionLog) error) (JobTaskStatus, error) {
+	query := url.Values{}
+	if afterID > 0 {
+		query.Set("after_id", strconv.FormatInt(afterID, 10))
+	}
+
+	var status JobTaskStatus
+	err := c.stream(ctx, pathf("/jobs/%d/tasks/%d/execution-logs:stream", jobID, jobTaskID), query, func(msg sseMessage) error {
+		switch msg.event {
+		case "log":
+			var line ExecutionLog
+			if err := json.Unmarshal([]byte(msg.data), &line); err != nil {
+				return fmt.Errorf("decode execution log: %w", err)
+			}
+			return fn(line)
+		case "end":
+			var end struct {
+				Status JobTaskStatus `json:"status"`
+			}
+			json.Unmarshal([]byte(msg.data), &end)
+			status = end.Status
+			return ErrStopStream
+		case "error":
+			var e struct {
+				Message string `json:"message"`
+			}
+			json.Unmarshal([]byte(msg.data), &e)
+			return fmt.Errorf("goworkflow: execution log stream: %s", e.Message)
+		}
+		return nil
+	})
+	return status, err
+}
+
+// stream 读取 Server-Sent Events 流，忽略注释行（keepalive）
+func (c *Client) stream(ctx context.Context, path string, query url.Values, fn func(sseMessage) error) error {
+	resp, err := c.send(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newAPIError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var msg sseMessage
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) == 0 {
+				continue
+			}
+			msg.data = strings.Join(data, "\n")
+			if err := fn(msg); err != nil {
+				if errors.Is(err, ErrStopStream) {
+					return nil
+				}
+				return err
+			}
+			msg, data = sseMessage{}, nil
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "event:"):
+			msg.event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
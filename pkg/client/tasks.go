@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// TaskQuery 任务列表查询条件，排序字段可为 id、name、created_at、updated_at
+type TaskQuery struct {
+	ListOptions
+	Filter
+	TaskType TaskType
+	IsActive ActiveFilter
+}
+
+func (q TaskQuery) query() url.Values {
+	v := q.ListOptions.query()
+	q.Filter.apply(v)
+	if q.TaskType != "" {
+		v.Set("task_type", string(q.TaskType))
+	}
+	if q.IsActive != ActiveDefault {
+		v.Set("is_active", string(q.IsActive))
+	}
+	return v
+}
+
+// ListTasks 获取任务列表
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) ([]Task, error) {
+	tasks, _, err := c.SearchTasks(ctx, TaskQuery{ListOptions: opts})
+	return tasks, err
+}
+
+// SearchTasks 按条件查询任务，返回当前页与分页信息（总数、下一页游标）
+func (c *Client) SearchTasks(ctx context.Context, q TaskQuery) ([]Task, *Page, error) {
+	var tasks []Task
+	page, err := c.list(ctx, "/tasks", q.query(), &tasks)
+	return tasks, page, err
+}
+
+// IterateTasks 逐页遍历符合条件的任务
+func (c *Client) IterateTasks(q TaskQuery) *Iterator[Task] {
+	return newIterator(q.ListOptions, func(ctx context.Context, opts ListOptions) ([]Task, *Page, error) {
+		q.ListOptions = opts
+		return c.SearchTasks(ctx, q)
+	})
+}
+
+// CreateTask 创建任务
+func (c *Client) CreateTask(ctx context.Context, task *Task) (*Task, error) {
+	var created Task
+	if err := c.post(ctx, "/tasks", task, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetTask 获取任务
+func (c *Client) GetTask(ctx context.Context, id int64) (*Task, error) {
+	var task Task
+	if err := c.get(ctx, pathf("/tasks/%d", id), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask 更新任务，task.ID 指定要更新的任务
+func (c *Client) UpdateTask(ctx context.Context, task *Task) (*Task, error) {
+	var updated Task
+	if err := c.put(ctx, pathf("/tasks/%d", task.ID), nil, task, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTask 删除任务
+func (c *Client) DeleteTask(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/tasks/%d", id))
+}
@@ -0,0 +1,405 @@
+package client
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// 以下类型与接口 JSON 结构一一对应
+
+// TaskType 任务类型
+type TaskType string
+
+const (
+	TaskTypeManual    TaskType = "manual"
+	TaskTypeAutomated TaskType = "automated"
+	TaskTypeApproval  TaskType = "approval"
+)
+
+// JobStatus 作业状态
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// IsFinished 判断作业是否已结束
+func (s JobStatus) IsFinished() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed || s == JobStatusCancelled
+}
+
+// JobTaskStatus 作业任务状态
+type JobTaskStatus string
+
+const (
+	JobTaskStatusPending    JobTaskStatus = "pending"
+	JobTaskStatusRunning    JobTaskStatus = "running"
+	JobTaskStatusCompleted  JobTaskStatus = "completed"
+	JobTaskStatusFailed     JobTaskStatus = "failed"
+	JobTaskStatusSkipped    JobTaskStatus = "skipped"
+	JobTaskStatusRolledBack JobTaskStatus = "rolled_back"
+)
+
+// Task 任务定义
+type Task struct {
+	ID          int64                  `json:"id"`
+	ProjectID   int64                  `json:"project_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	TaskType    TaskType               `json:"task_type"`
+	Config      map[string]interface{} `json:"config"`
+	IsActive    bool                   `json:"is_active"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// Flow 流程
+type Flow struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Version     string    `json:"version"`
+	Inputs      []string  `json:"inputs"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FlowTask 流程中的任务编排
+type FlowTask struct {
+	ID              int64                  `json:"id"`
+	FlowID          int64                  `json:"flow_id"`
+	TaskID          int64                  `json:"task_id"`
+	Sequence        int                    `json:"sequence"`
+	IsOptional      bool                   `json:"is_optional"`
+	AllowRollback   bool                   `json:"allow_rollback"`
+	ConditionConfig map[string]interface{} `json:"condition_config"`
+	ConfigOverrides map[string]interface{} `json:"config_overrides"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	Task            *Task                  `json:"task,omitempty"`
+}
+
+// FlowDetail 流程详情（包含任务编排）
+type FlowDetail struct {
+	Flow      *Flow      `json:"flow"`
+	FlowTasks []FlowTask `json:"flow_tasks"`
+}
+
+// Job 作业
+type Job struct {
+	ID             int64         `json:"id"`
+	ProjectID      int64         `json:"project_id"`
+	FlowID         int64         `json:"flow_id"`
+	BatchID        sql.NullInt64 `json:"batch_id"`
+	RerunOf        sql.NullInt64 `json:"rerun_of"`
+	JobName        string        `json:"job_name"`
+	Status         JobStatus     `json:"status"`
+	CurrentTaskSeq sql.NullInt64 `json:"current_task_seq"`
+	StartedAt      sql.NullTime  `json:"started_at"`
+	CompletedAt    sql.NullTime  `json:"completed_at"`
+	CreatedBy      int64         `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// JobTask 作业任务
+type JobTask struct {
+	ID           int64                  `json:"id"`
+	JobID        int64                  `json:"job_id"`
+	FlowTaskID   int64                  `json:"flow_task_id"`
+	TaskID       int64                  `json:"task_id"`
+	Sequence     int                    `json:"sequence"`
+	Status       JobTaskStatus          `json:"status"`
+	IsSkipped    bool                   `json:"is_skipped"`
+	ExecutorID   sql.NullInt64          `json:"executor_id"`
+	Result       map[string]interface{} `json:"result"`
+	ErrorMessage string                 `json:"error_message"`
+	StartedAt    sql.NullTime           `json:"started_at"`
+	CompletedAt  sql.NullTime           `json:"completed_at"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Task         *Task                  `json:"task,omitempty"`
+	FlowTask     *FlowTask              `json:"flow_task,omitempty"`
+}
+
+// JobDetail 作业详情（包含所有作业任务）
+type JobDetail struct {
+	Job      *Job      `json:"job"`
+	JobTasks []JobTask `json:"job_tasks"`
+}
+
+// JobBatch 作业批次
+type JobBatch struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	FlowID      int64     `json:"flow_id"`
+	Name        string    `json:"name"`
+	TotalJobs   int       `json:"total_jobs"`
+	AutoExecute bool      `json:"auto_execute"`
+	Concurrency int       `json:"concurrency"`
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// JobBatchDetail 批次及其作业
+type JobBatchDetail struct {
+	Batch *JobBatch `json:"batch"`
+	Jobs  []Job     `json:"jobs"`
+}
+
+// JobBatchSummary 批次及其作业按状态的统计
+type JobBatchSummary struct {
+	Batch    *JobBatch         `json:"batch"`
+	Counts   map[JobStatus]int `json:"counts"`
+	Finished bool              `json:"finished"` // 所有作业均已结束
+}
+
+// JobBatchResult 批次级操作（取消、重试）的结果
+type JobBatchResult struct {
+	JobIDs []int64           `json:"job_ids"`          // 已处理的作业
+	Errors map[string]string `json:"errors,omitempty"` // 处理失败的作业 ID 及原因
+}
+
+// TaskReuse 重新运行时复用来源作业输出的任务
+type TaskReuse struct {
+	Sequence        int    `json:"sequence"`
+	TaskName        string `json:"task_name"`
+	SourceJobTaskID int64  `json:"source_job_task_id"`
+	JobTaskID       int64  `json:"job_task_id"`
+}
+
+// JobRerun 重新运行创建的作业及复用输出的任务
+type JobRerun struct {
+	Job         *Job        `json:"job"`
+	ReusedTasks []TaskReuse `json:"reused_tasks"`
+}
+
+// JobTaskLog 审计日志
+type JobTaskLog struct {
+	ID         int64                  `json:"id"`
+	JobTaskID  int64                  `json:"job_task_id"`
+	Action     string                 `json:"action"`
+	OperatorID sql.NullInt64          `json:"operator_id"`
+	Message    string                 `json:"message"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// ExecutionLog 执行日志
+type ExecutionLog struct {
+	ID        int64     `json:"id"`
+	JobTaskID int64     `json:"job_task_id"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Page 列表分页信息
+type Page struct {
+	Total      int64  `json:"total"`                 // 符合条件的总数
+	Limit      int    `json:"limit"`                 // 每页数量
+	Offset     int    `json:"offset"`                // 偏移量
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标，没有更多数据时为空
+}
+
+// Artifact 制品
+type Artifact struct {
+	ID          int64         `json:"id"`
+	JobID       int64         `json:"job_id"`
+	JobTaskID   sql.NullInt64 `json:"job_task_id"`
+	Name        string        `json:"name"`
+	ContentType string        `json:"content_type"`
+	Size        int64         `json:"size"`
+	Checksum    string        `json:"checksum"`
+	Backend     string        `json:"backend"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Event 作业/作业任务事件
+type Event struct {
+	ID         int64                  `json:"id"`
+	Type       string                 `json:"type"`
+	JobID      int64                  `json:"job_id"`
+	FlowID     int64                  `json:"flow_id"`
+	JobTaskID  int64                  `json:"job_task_id,omitempty"`
+	Status     string                 `json:"status"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// WebhookSubscription Webhook 订阅
+type WebhookSubscription struct {
+	ID         int64         `json:"id"`
+	Name       string        `json:"name"`
+	URL        string        `json:"url"`
+	EventTypes []string      `json:"event_types"`
+	FlowID     sql.NullInt64 `json:"flow_id"`
+	IsActive   bool          `json:"is_active"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// WebhookDelivery Webhook 投递记录
+type WebhookDelivery struct {
+	ID             int64                    `json:"id"`
+	SubscriptionID int64                    `json:"subscription_id"`
+	EventType      string                   `json:"event_type"`
+	Payload        json.RawMessage          `json:"payload"`
+	Status         string                   `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	LastStatusCode sql.NullInt64            `json:"last_status_code"`
+	LastError      string                   `json:"last_error"`
+	NextAttemptAt  sql.NullTime             `json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime             `json:"delivered_at"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	AttemptLogs    []WebhookDeliveryAttempt `json:"attempt_logs,omitempty"`
+}
+
+// WebhookDeliveryAttempt 单次投递尝试
+type WebhookDeliveryAttempt struct {
+	ID           int64         `json:"id"`
+	DeliveryID   int64         `json:"delivery_id"`
+	Attempt      int           `json:"attempt"`
+	StatusCode   sql.NullInt64 `json:"status_code"`
+	Error        string        `json:"error"`
+	ResponseBody string        `json:"response_body"`
+	DurationMs   int64         `json:"duration_ms"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// RetentionPolicy 作业保留策略
+type RetentionPolicy struct {
+	ID             int64         `json:"id"`
+	FlowID         sql.NullInt64 `json:"flow_id"` // 为空表示默认策略
+	KeepDays       int           `json:"keep_days"`
+	KeepJobs       int           `json:"keep_jobs"`
+	FailedKeepDays int           `json:"failed_keep_days"`
+	Action         string        `json:"action"` // delete、archive_file 或 archive_table
+	IsActive       bool          `json:"is_active"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// RetentionFlowResult 单个流程的清理结果
+type RetentionFlowResult struct {
+	FlowID       int64    `json:"flow_id"`
+	PolicyID     int64    `json:"policy_id"`
+	Action       string   `json:"action"`
+	JobIDs       []int64  `json:"job_ids"`
+	ArchiveFiles []string `json:"archive_files"`
+	FilesRemoved []string `json:"files_removed"`
+	Artifacts    int      `json:"artifacts"`
+}
+
+// RetentionRun 一次清理的结果
+type RetentionRun struct {
+	ID               int64                 `json:"id"`
+	DryRun           bool                  `json:"dry_run"`
+	JobsDeleted      int                   `json:"jobs_deleted"`
+	JobsArchived     int                   `json:"jobs_archived"`
+	FilesRemoved     int                   `json:"files_removed"`
+	ArtifactsRemoved int                   `json:"artifacts_removed"`
+	Details          []RetentionFlowResult `json:"details"`
+	Error            string                `json:"error"`
+	StartedAt        time.Time             `json:"started_at"`
+	FinishedAt       sql.NullTime          `json:"finished_at"`
+}
+
+// ExecutorDescriptor 执行器输入/输出契约
+type ExecutorDescriptor struct {
+	Name    string       `json:"name"`
+	Params  []ParamSpec  `json:"params"`
+	Outputs []OutputSpec `json:"outputs"`
+}
+
+// ParamSpec 执行器参数声明
+type ParamSpec struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// OutputSpec 执行器输出声明
+type OutputSpec struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Optional    bool   `json:"optional,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Principal 认证主体
+type Principal struct {
+	UserID   int64  `json:"user_id"`
+	Subject  string `json:"subject"`
+	Method   string `json:"method"`
+	APIKeyID int64  `json:"api_key_id,omitempty"`
+}
+
+// APIKey API Key（不含明文）
+type APIKey struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	UserID     int64        `json:"user_id"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// CreatedAPIKey 新建的 API Key，Key 为明文，只返回一次
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// RoleBinding 角色绑定
+type RoleBinding struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	FlowID    int64     `json:"flow_id"`
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Project 项目
+type Project struct {
+	ID               int64     `json:"id"`
+	Name             string    `json:"name"`
+	Slug             string    `json:"slug"`
+	Description      string    `json:"description"`
+	AllowedExecutors []string  `json:"allowed_executors"`
+	CreatedBy        int64     `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ProjectMember 项目成员
+type ProjectMember struct {
+	ProjectID int64     `json:"project_id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProjectSecret 项目密钥（只含名称）
+type ProjectSecret struct {
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"project_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval 等待作业结束时的默认轮询间隔
+const DefaultPollInterval = 2 * time.Second
+
+// ErrJobFailed 作业以失败或取消结束，可用 errors.Is 判断
+var ErrJobFailed = errors.New("job failed")
+
+// RunOptions RunJob 的选项
+type RunOptions struct {
+	// AutoExecute 启动后由服务端依次自动执行所有任务；为 false 时只启动作业，由执行人或其他系统推进
+	AutoExecute bool
+	// PollInterval 轮询间隔，0 表示 DefaultPollInterval
+	PollInterval time.Duration
+}
+
+// RunJob 创建并启动作业，等待其结束后返回作业详情；
+// 作业失败或取消时同时返回详情与包装了 ErrJobFailed 的错误。等待时长由 ctx 控制
+func (c *Client) RunJob(ctx context.Context, req CreateJobRequest, opts RunOptions) (*JobDetail, error) {
+	job, err := c.CreateJob(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("create job: %w", err)
+	}
+	if err := c.StartJob(ctx, job.ID); err != nil {
+		return nil, fmt.Errorf("start job %d: %w", job.ID, err)
+	}
+	if opts.AutoExecute {
+		if err := c.AutoExecuteJob(ctx, job.ID); err != nil {
+			return nil, fmt.Errorf("auto-execute job %d: %w", job.ID, err)
+		}
+	}
+	return c.WaitForJob(ctx, job.ID, opts.PollInterval)
+}
+
+// WaitForJob 轮询作业直到结束，interval 为 0 时使用 DefaultPollInterval；
+// 作业失败或取消时同时返回详情与包装了 ErrJobFailed 的错误
+func (c *Client) WaitForJob(ctx context.Context, jobID int64, interval time.Duration) (*JobDetail, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		detail, err := c.GetJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if detail.Job.Status.IsFinished() {
+			if detail.Job.Status != JobStatusCompleted {
+				return detail, jobFailure(detail)
+			}
+			return detail, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return detail, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobFailure 以失败任务的错误信息构造 ErrJobFailed
+func jobFailure(detail *JobDetail) error {
+	for _, t := range detail.JobTasks {
+		if t.Status == JobTaskStatusFailed {
+			return fmt.Errorf("%w: job %d %s at task %d: %s", ErrJobFailed, detail.Job.ID, detail.Job.Status, t.Sequence, t.ErrorMessage)
+		}
+	}
+	return fmt.Errorf("%w: job %d %s", ErrJobFailed, detail.Job.ID, detail.Job.Status)
+}
@@ -0,0 +1,82 @@
+package client
+
+import "context"
+
+// WebhookRequest 创建或更新 Webhook 订阅的请求；更新时未设置的指针字段保持原值
+type WebhookRequest struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	FlowID     *int64   `json:"flow_id,omitempty"`
+	Secret     *string  `json:"secret,omitempty"`
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+// ListWebhooks 获取 Webhook 订阅列表
+func (c *Client) ListWebhooks(ctx context.Context, opts ListOptions) ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	err := c.get(ctx, "/webhooks", opts.query(), &subs)
+	return subs, err
+}
+
+// IterateWebhooks 逐页遍历 Webhook 订阅
+func (c *Client) IterateWebhooks(opts ListOptions) *Iterator[WebhookSubscription] {
+	return newIterator(opts, withoutPage(c.ListWebhooks))
+}
+
+// CreateWebhook 创建 Webhook 订阅
+func (c *Client) CreateWebhook(ctx context.Context, req WebhookRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.post(ctx, "/webhooks", req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetWebhook 获取 Webhook 订阅
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.get(ctx, pathf("/webhooks/%d", id), nil, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateWebhook 更新 Webhook 订阅，未提供 Secret 时保留原密钥
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, req WebhookRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.put(ctx, pathf("/webhooks/%d", id), nil, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// DeleteWebhook 删除 Webhook 订阅
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	return c.del(ctx, pathf("/webhooks/%d", id))
+}
+
+// ListWebhookDeliveries 获取订阅的投递记录
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID int64, opts ListOptions) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := c.get(ctx, pathf("/webhooks/%d/deliveries", webhookID), opts.query(), &deliveries)
+	return deliveries, err
+}
+
+// GetWebhookDelivery 获取投递详情（包含每次尝试）
+func (c *Client) GetWebhookDelivery(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := c.get(ctx, pathf("/webhook-deliveries/%d", id), nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RedeliverWebhook 以相同内容重新投递，返回新建的投递记录
+func (c *Client) RedeliverWebhook(ctx context.Context, deliveryID int64) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := c.post(ctx, pathf("/webhook-deliveries/%d:redeliver", deliveryID), nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
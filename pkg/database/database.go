@@ -4,17 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
-
-	_ "github.com/go-sql-driver/mysql"
 )
 
 // DB 数据库连接实例
 type DB struct {
 	*sql.DB
+	Dialect Dialect
 }
 
 // Config 数据库配置
 type Config struct {
+	Driver          string // mysql（默认）、sqlite 或 postgres
 	DSN             string
 	MaxOpenConns    int
 	MaxIdleConns    int
@@ -23,7 +23,12 @@ type Config struct {
 
 // NewDB 创建数据库连接
 func NewDB(cfg Config) (*DB, error) {
-	db, err := sql.Open("mysql", cfg.DSN)
+	dialect, err := ParseDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(dialect.driverName(), cfg.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -52,7 +57,7 @@ func NewDB(cfg Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, Dialect: dialect}, nil
 }
 
 // Close 关闭数据库连接
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/stdlib"
+	"modernc.org/sqlite"
+)
+
+// Dialect 数据库方言，仓储按方言生成插入、冲突更新与锁定语句
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// sqliteTimeLayout SQLite 中时间的文本格式（UTC），与 CURRENT_TIMESTAMP 一致，可直接按字符串比较
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999999"
+
+// ParseDialect 解析配置中的驱动名称，空值为 MySQL
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", "mysql":
+		return MySQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q (supported: mysql, sqlite, postgres)", name)
+}
+
+// DialectOf 根据连接使用的驱动返回方言
+func DialectOf(db *sql.DB) Dialect {
+	switch db.Driver().(type) {
+	case *sqlite.Driver:
+		return SQLite
+	case *stdlib.Driver:
+		return Postgres
+	case *mysql.MySQLDriver:
+		return MySQL
+	}
+	return MySQL
+}
+
+// driverName 返回 database/sql 中注册的驱动名称
+func (d Dialect) driverName() string {
+	switch d {
+	case SQLite:
+		return "sqlite"
+	case Postgres:
+		return "pgx"
+	}
+	return "mysql"
+}
+
+// Rebind 将 ? 占位符改写为方言的占位符：PostgreSQL 为 $1、$2…，字符串字面量中的 ? 保持不变
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+	n := 0
+	quoted := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// ConvertArgs 转换查询参数：SQLite 中时间统一存为 UTC 文本，与默认值 CURRENT_TIMESTAMP 可比较
+func (d Dialect) ConvertArgs(args []interface{}) []interface{} {
+	if d != SQLite {
+		return args
+	}
+
+	var converted []interface{}
+	for i, arg := range args {
+		value := arg
+		if valuer, ok := arg.(driver.Valuer); ok {
+			if _, isBytes := arg.([]byte); !isBytes {
+				if v, err := valuer.Value(); err == nil {
+					value = v
+				}
+			}
+		}
+		t, ok := value.(time.Time)
+		if !ok {
+			continue
+		}
+		if converted == nil {
+			converted = append([]interface{}(nil), args...)
+		}
+		converted[i] = t.UTC().Format(sqliteTimeLayout)
+	}
+	if converted == nil {
+		return args
+	}
+	return converted
+}
+
+// Returning 返回插入语句取回自增 ID 的后缀：PostgreSQL 不支持 LastInsertId，须使用 RETURNING
+func (d Dialect) Returning(column string) string {
+	if d == Postgres {
+		return " RETURNING " + column
+	}
+	return ""
+}
+
+// Upsert 返回唯一键冲突时更新指定列的子句，conflict 为构成唯一键的列（MySQL 不需要）
+func (d Dialect) Upsert(conflict []string, columns ...string) string {
+	sets := make([]string, len(columns))
+	if d == MySQL {
+		for i, c := range columns {
+			sets[i] = c + " = VALUES(" + c + ")"
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+	for i, c := range columns {
+		sets[i] = c + " = excluded." + c
+	}
+	return " ON CONFLICT (" + strings.Join(conflict, ", ") + ") DO UPDATE SET " + strings.Join(sets, ", ")
+}
+
+// ForUpdate 返回锁定查询行的后缀，SQLite 以数据库级写锁代替行锁
+func (d Dialect) ForUpdate() string {
+	if d == SQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// Like 返回不区分大小写的模糊匹配运算符
+func (d Dialect) Like() string {
+	if d == Postgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
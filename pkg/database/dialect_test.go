@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDialectRebind(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{dialect: MySQL, query: "SELECT * FROM jobs WHERE id = ? AND status = ?", want: "SELECT * FROM jobs WHERE id = ? AND status = ?"},
+		{dialect: SQLite, query: "SELECT * FROM jobs WHERE id = ?", want: "SELECT * FROM jobs WHERE id = ?"},
+		{dialect: Postgres, query: "SELECT * FROM jobs WHERE id = ? AND status = ?", want: "SELECT * FROM jobs WHERE id = $1 AND status = $2"},
+		{dialect: Postgres, query: "SELECT '?' AS q, name FROM tasks WHERE name = ? AND note <> 'a?b'", want: "SELECT '?' AS q, name FROM tasks WHERE name = $1 AND note <> 'a?b'"},
+		{dialect: Postgres, query: "SELECT 1", want: "SELECT 1"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Rebind(tt.query); got != tt.want {
+			t.Errorf("%s Rebind(%q) = %q, want %q", tt.dialect, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDialectUpsertReturning(t *testing.T) {
+	tests := []struct {
+		dialect       Dialect
+		wantUpsert    string
+		wantReturning string
+	}{
+		{dialect: MySQL, wantUpsert: " ON DUPLICATE KEY UPDATE context_value = VALUES(context_value), updated_by = VALUES(updated_by)"},
+		{dialect: SQLite, wantUpsert: " ON CONFLICT (job_id, context_key) DO UPDATE SET context_value = excluded.context_value, updated_by = excluded.updated_by"},
+		{dialect: Postgres, wantUpsert: " ON CONFLICT (job_id, context_key) DO UPDATE SET context_value = excluded.context_value, updated_by = excluded.updated_by", wantReturning: " RETURNING id"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Upsert([]string{"job_id", "context_key"}, "context_value", "updated_by"); got != tt.wantUpsert {
+			t.Errorf("%s Upsert() = %q, want %q", tt.dialect, got, tt.wantUpsert)
+		}
+		if got := tt.dialect.Returning("id"); got != tt.wantReturning {
+			t.Errorf("%s Returning() = %q, want %q", tt.dialect, got, tt.wantReturning)
+		}
+	}
+}
+
+func TestDialectConvertArgs(t *testing.T) {
+	at := time.Date(2024, 5, 1, 18, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	args := []interface{}{int64(1), at, "x"}
+
+	got := SQLite.ConvertArgs(args)
+	if got[1] != at.UTC().Format(sqliteTimeLayout) || got[0] != int64(1) || got[2] != "x" {
+		t.Fatalf("SQLite ConvertArgs() = %v", got)
+	}
+	if args[1] != at {
+		t.Fatalf("SQLite ConvertArgs() modified its input: %v", args)
+	}
+	if got := Postgres.ConvertArgs(args); got[1] != at {
+		t.Fatalf("Postgres ConvertArgs() = %v, want arguments unchanged", got)
+	}
+}
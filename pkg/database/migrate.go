@@ -0,0 +1,81 @@
+package database
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Migrate 按文件名顺序执行 fsys 中当前方言目录（如 sqlite/）下尚未执行的 *.sql 迁移，每个文件在一个事务中执行，
+// 已执行的文件记录在 schema_migrations 表中。方言目录不存在时（如 MySQL）不做任何操作，返回本次执行的文件
+func (db *DB) Migrate(fsys fs.FS) ([]string, error) {
+	files, err := fs.Glob(fsys, string(db.Dialect)+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	done := make(map[string]bool)
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		done[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	var applied []string
+	for _, file := range files {
+		version := strings.TrimSuffix(path.Base(file), ".sql")
+		if done[version] {
+			continue
+		}
+		script, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return applied, err
+		}
+		if err := db.applyMigration(version, string(script)); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", file, err)
+		}
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+// applyMigration 在事务中执行迁移脚本并记录版本
+func (db *DB) applyMigration(version, script string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(db.Dialect.Rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,164 @@
+// Package httprouter 基于标准库的轻量 HTTP 路由，支持按方法匹配、{name} 路径参数以及 {name}:verb 形式的自定义动作
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// segment 路由模式中的一段：字面量，或参数（可带 :verb 后缀）
+type segment struct {
+	literal string
+	param   string
+	verb    string
+}
+
+type route struct {
+	method   string
+	pattern  string
+	segments []segment
+	handler  http.Handler
+}
+
+// Router 按注册顺序匹配路由；路径匹配但方法不匹配时返回 405 并带上 Allow 头
+type Router struct {
+	routes []route
+}
+
+// New 创建路由
+func New() *Router {
+	return &Router{}
+}
+
+// Handle 注册路由，pattern 形如 /api/v1/jobs/{id}/tasks/{taskId}:complete
+func (rt *Router) Handle(method, pattern string, handler http.Handler) {
+	rt.routes = append(rt.routes, route{
+		method:   method,
+		pattern:  pattern,
+		segments: parsePattern(pattern),
+		handler:  handler,
+	})
+}
+
+// HandleFunc 注册处理函数
+func (rt *Router) HandleFunc(method, pattern string, handler http.HandlerFunc) {
+	rt.Handle(method, pattern, handler)
+}
+
+// Handler 返回请求匹配的处理器与路由模式，未匹配时模式为空；与 http.ServeMux.Handler 语义一致，便于指标与追踪按模式打标签
+func (rt *Router) Handler(r *http.Request) (http.Handler, string) {
+	h, pattern, _ := rt.match(r)
+	if pattern == "" {
+		return http.NotFoundHandler(), ""
+	}
+	return h, pattern
+}
+
+// ServeHTTP 实现 http.Handler
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, pattern, params := rt.match(r)
+	if pattern == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if params != nil {
+		r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+	}
+	h.ServeHTTP(w, r)
+}
+
+// match 查找匹配的路由；只有路径匹配时返回 405 处理器
+func (rt *Router) match(r *http.Request) (http.Handler, string, map[string]string) {
+	parts := splitPath(r.URL.Path)
+	var allowed []string
+	var allowedPattern string
+	for _, rte := range rt.routes {
+		params, ok := matchSegments(rte.segments, parts)
+		if !ok {
+			continue
+		}
+		if rte.method == r.Method || (r.Method == http.MethodHead && rte.method == http.MethodGet) {
+			return rte.handler, rte.pattern, params
+		}
+		allowed = append(allowed, rte.method)
+		allowedPattern = rte.pattern
+	}
+	if len(allowed) > 0 {
+		allow := strings.Join(allowed, ", ")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", allow)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}), allowedPattern, nil
+	}
+	return nil, "", nil
+}
+
+func parsePattern(pattern string) []segment {
+	parts := splitPath(pattern)
+	segments := make([]segment, len(parts))
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") {
+			end := strings.Index(part, "}")
+			segments[i] = segment{param: part[1:end], verb: part[end+1:]}
+			continue
+		}
+		segments[i] = segment{literal: part}
+	}
+	return segments
+}
+
+// matchSegments 逐段匹配；不带 verb 的参数不匹配含冒号的段，避免 {id} 吞掉 {id}:action
+func matchSegments(segments []segment, parts []string) (map[string]string, bool) {
+	if len(segments) != len(parts) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, seg := range segments {
+		part := parts[i]
+		if seg.param == "" {
+			if part != seg.literal {
+				return nil, false
+			}
+			continue
+		}
+		if seg.verb != "" {
+			if !strings.HasSuffix(part, seg.verb) {
+				return nil, false
+			}
+			part = strings.TrimSuffix(part, seg.verb)
+		} else if strings.Contains(part, ":") {
+			return nil, false
+		}
+		if part == "" {
+			return nil, false
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[seg.param] = part
+	}
+	return params, true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+type paramsKey struct{}
+
+// Param 读取路径参数，不存在时返回空字符串
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+// Int64Param 读取整数路径参数
+func Int64Param(r *http.Request, name string) (int64, error) {
+	return strconv.ParseInt(Param(r, name), 10, 64)
+}
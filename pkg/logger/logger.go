@@ -1,36 +1,228 @@
+// Package logger 结构化分级日志：支持 logfmt 与 JSON 输出、运行时调整级别，
+// 并通过 context.Context 传递 job_id、job_task_id、request_id 等关联字段
 package logger
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 var (
-	InfoLogger  *log.Logger
-	ErrorLogger *log.Logger
+	level   = new(slog.LevelVar)
+	current atomic.Pointer[slog.Logger]
 )
 
 func init() {
-	InfoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+	Setup(os.Stdout, "logfmt")
+}
+
+// Setup 设置日志输出与格式（logfmt 或 json）
+func Setup(w io.Writer, format string) error {
+	opts := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// 与原 log.Lshortfile 一致，只保留文件名与行号
+			if a.Key == slog.SourceKey {
+				if src, ok := a.Value.Any().(*slog.Source); ok {
+					return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+				}
+			}
+			return a
+		},
+	}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "logfmt", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+	current.Store(slog.New(contextHandler{h}))
+	return nil
+}
+
+// SetLevel 运行时设置日志级别：debug、info、warn、error
+func SetLevel(s string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("unknown log level: %s", s)
+	}
+	level.Set(l)
+	return nil
+}
+
+// GetLevel 返回当前日志级别
+func GetLevel() string {
+	return strings.ToLower(level.Level().String())
+}
+
+type fieldsKey struct{}
+
+// WithFields 返回附加了关联字段（键值对）的上下文，经该上下文输出的日志均携带这些字段；同名字段以新值为准
+func WithFields(ctx context.Context, kv ...interface{}) context.Context {
+	replaced := make(map[interface{}]bool, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		replaced[kv[i]] = true
+	}
+
+	var fields []interface{}
+	existing := Fields(ctx)
+	for i := 0; i+1 < len(existing); i += 2 {
+		if !replaced[existing[i]] {
+			fields = append(fields, existing[i], existing[i+1])
+		}
+	}
+	fields = append(fields, kv...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Fields 返回上下文中的关联字段
+func Fields(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+	return fields
+}
+
+// contextHandler 输出日志时追加上下文中的关联字段
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if fields := Fields(ctx); len(fields) > 0 {
+		r.Add(fields...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Entry 绑定上下文及附加字段的日志记录器
+type Entry struct {
+	ctx   context.Context
+	attrs []interface{}
+}
+
+// Ctx 返回携带 ctx 关联字段的日志记录器
+func Ctx(ctx context.Context) *Entry {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Entry{ctx: ctx}
+}
+
+// With 返回附加了字段（键值对）的日志记录器
+func (e *Entry) With(kv ...interface{}) *Entry {
+	return &Entry{ctx: e.ctx, attrs: append(append([]interface{}(nil), e.attrs...), kv...)}
+}
+
+// Debugf 格式化记录调试日志
+func (e *Entry) Debugf(format string, v ...interface{}) {
+	e.log(slog.LevelDebug, fmt.Sprintf(format, v...))
+}
+
+// Infof 格式化记录信息日志
+func (e *Entry) Infof(format string, v ...interface{}) {
+	e.log(slog.LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// Warnf 格式化记录警告日志
+func (e *Entry) Warnf(format string, v ...interface{}) {
+	e.log(slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// Errorf 格式化记录错误日志
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	e.log(slog.LevelError, fmt.Sprintf(format, v...))
+}
+
+// Log 以指定级别（debug、info、warn、error）记录日志，用于转发其他来源的日志
+func (e *Entry) Log(levelName string, message string) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(levelName)); err != nil {
+		l = slog.LevelInfo
+	}
+	e.log(l, message)
+}
+
+// log 记录日志，调用位置取调用 Entry 方法或包级函数的代码
+func (e *Entry) log(l slog.Level, message string) {
+	logAt(e.ctx, l, message, e.attrs, 4)
+}
+
+func logAt(ctx context.Context, l slog.Level, message string, attrs []interface{}, skip int) {
+	logger := current.Load()
+	if !logger.Enabled(ctx, l) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+	r := slog.NewRecord(time.Now(), l, message, pcs[0])
+	r.Add(attrs...)
+	logger.Handler().Handle(ctx, r)
+}
+
+// Debug 记录调试日志
+func Debug(v ...interface{}) {
+	logAt(context.Background(), slog.LevelDebug, sprintln(v...), nil, 3)
+}
+
+// Debugf 格式化记录调试日志
+func Debugf(format string, v ...interface{}) {
+	logAt(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...), nil, 3)
 }
 
 // Info 记录信息日志
 func Info(v ...interface{}) {
-	InfoLogger.Println(v...)
+	logAt(context.Background(), slog.LevelInfo, sprintln(v...), nil, 3)
 }
 
 // Infof 格式化记录信息日志
 func Infof(format string, v ...interface{}) {
-	InfoLogger.Printf(format, v...)
+	logAt(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...), nil, 3)
+}
+
+// Warn 记录警告日志
+func Warn(v ...interface{}) {
+	logAt(context.Background(), slog.LevelWarn, sprintln(v...), nil, 3)
+}
+
+// Warnf 格式化记录警告日志
+func Warnf(format string, v ...interface{}) {
+	logAt(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...), nil, 3)
 }
 
 // Error 记录错误日志
 func Error(v ...interface{}) {
-	ErrorLogger.Println(v...)
+	logAt(context.Background(), slog.LevelError, sprintln(v...), nil, 3)
 }
 
 // Errorf 格式化记录错误日志
 func Errorf(format string, v ...interface{}) {
-	ErrorLogger.Printf(format, v...)
+	logAt(context.Background(), slog.LevelError, fmt.Sprintf(format, v...), nil, 3)
+}
+
+// sprintln 与 log.Println 一致拼接参数，去掉结尾换行
+func sprintln(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
 }
@@ -0,0 +1,265 @@
+// Package openapi 根据路由注册信息与 Go 类型生成 OpenAPI 3 文档
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Document OpenAPI 3 文档
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+
+	schemas *schemaBuilder
+	once    sync.Once
+	body    []byte
+}
+
+// Info 文档信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server 服务地址，paths 相对于该地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem 同一路径下按小写方法名索引的操作
+type PathItem map[string]*Operation
+
+// Components 可复用组件
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Operation 接口操作
+type Operation struct {
+	Tags        []string            `json:"tags,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter 路径、查询或请求头参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType 内容类型对应的结构
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Op 注册路由时附带的接口说明
+type Op struct {
+	Tag          string      // 分组
+	Summary      string      // 摘要
+	Description  string      // 详细说明
+	Query        []Parameter // 查询参数，见 QueryInt、QueryString
+	Body         interface{} // 请求体类型的零值，nil 表示无请求体
+	BodyOptional bool        // 请求体可省略（如 ID 已由路径给出的动作接口）
+	Result       interface{} // 响应 data 字段类型的零值，nil 表示只返回 message
+	Page         interface{} // 列表接口响应 page 字段类型的零值
+	Status       int         // 成功状态码，默认 200
+	Stream       bool        // 响应为 Server-Sent Events
+	Binary       bool        // 响应为文件下载
+}
+
+// QueryInt 整数查询参数
+func QueryInt(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "integer", Format: "int64"}}
+}
+
+// QueryString 字符串查询参数
+func QueryString(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// envelopeName 统一响应结构（response.Response）的组件名
+const envelopeName = "Response"
+
+// New 创建文档，paths 相对于 baseURL；包含统一响应结构与 Bearer / X-API-Key 认证方式（认证可选）
+func New(baseURL, title, version, description string) *Document {
+	d := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version, Description: description},
+		Servers: []Server{{URL: baseURL}},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", Description: "API Key（gwf_ 前缀）或 JWT"},
+				"apiKey":     {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}, {"apiKey": {}}, {}},
+	}
+	d.schemas = newSchemaBuilder()
+	d.Components.Schemas = d.schemas.components
+	d.Components.Schemas[envelopeName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Description: "0 表示成功，失败时为 HTTP 状态码"},
+			"message": {Type: "string"},
+			"data":    {Description: "业务数据"},
+		},
+		Required: []string{"code", "message"},
+	}
+	return d
+}
+
+// Add 添加接口；path 中的 {name} 生成路径参数，以 id/Id 结尾的参数为整数
+func (d *Document) Add(method, path string, op Op) {
+	operation := &Operation{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(method, path),
+		Responses:   make(map[string]Response),
+	}
+	if op.Tag != "" {
+		operation.Tags = []string{op.Tag}
+	}
+	for _, name := range pathParams(path) {
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "Id") {
+			schema = &Schema{Type: "integer", Format: "int64"}
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	operation.Parameters = append(operation.Parameters, op.Query...)
+
+	if op.Body != nil {
+		operation.RequestBody = &RequestBody{
+			Required: !op.BodyOptional,
+			Content:  map[string]MediaType{"application/json": {Schema: d.schemas.schemaOf(op.Body)}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	switch {
+	case op.Stream:
+		operation.Responses[strconv.Itoa(status)] = Response{
+			Description: "Server-Sent Events",
+			Content:     map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}},
+		}
+	case op.Binary:
+		operation.Responses[strconv.Itoa(status)] = Response{
+			Description: "文件内容",
+			Content:     map[string]MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	default:
+		operation.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: d.envelope(op.Result, op.Page)}},
+		}
+	}
+	errorContent := map[string]MediaType{"application/json": {Schema: ref(envelopeName)}}
+	operation.Responses["default"] = Response{Description: "错误，message 为错误信息", Content: errorContent}
+
+	item := d.Paths[path]
+	if item == nil {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+}
+
+// envelope 以统一响应结构包装 data 与 page 类型
+func (d *Document) envelope(result, page interface{}) *Schema {
+	if result == nil {
+		return ref(envelopeName)
+	}
+	props := map[string]*Schema{"data": d.schemas.schemaOf(result)}
+	if page != nil {
+		props["page"] = d.schemas.schemaOf(page)
+	}
+	return &Schema{AllOf: []*Schema{
+		ref(envelopeName),
+		{Type: "object", Properties: props},
+	}}
+}
+
+// ServeHTTP 以 JSON 输出文档，首次请求时序列化并缓存
+func (d *Document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.once.Do(func() {
+		d.body, _ = json.MarshalIndent(d, "", "  ")
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(d.body)
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			return names
+		}
+		end := strings.Index(path[start:], "}")
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// operationID 由方法与路径生成，如 POST /jobs/{id}:start -> postJobsIdStart
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	upper := true
+	for _, c := range path {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
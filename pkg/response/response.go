@@ -10,6 +10,7 @@ type Response struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Page    interface{} `json:"page,omitempty"` // 列表接口的分页信息
 }
 
 // JSON 返回 JSON 响应
@@ -28,6 +29,16 @@ func Success(w http.ResponseWriter, data interface{}) {
 	})
 }
 
+// Paged 列表成功响应，data 为当前页数据，page 为总数与下一页游标等分页信息
+func Paged(w http.ResponseWriter, data interface{}, page interface{}) {
+	JSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    data,
+		Page:    page,
+	})
+}
+
 // Created 创建成功响应
 func Created(w http.ResponseWriter, data interface{}) {
 	JSON(w, http.StatusCreated, Response{
@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"github.com/cfrs2005/GoWorkFlow/internal/engine"
+	"github.com/cfrs2005/GoWorkFlow/internal/events"
+	"github.com/cfrs2005/GoWorkFlow/internal/executor"
+	"github.com/cfrs2005/GoWorkFlow/internal/models"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+)
+
+// 引擎与任务执行服务
+type (
+	WorkflowEngine      = engine.WorkflowEngine
+	TaskExecutorService = service.TaskExecutorService
+)
+
+// 仓储接口
+type (
+	TaskRepository       = repository.TaskRepository
+	FlowRepository       = repository.FlowRepository
+	FlowTaskRepository   = repository.FlowTaskRepository
+	JobRepository        = repository.JobRepository
+	JobTaskRepository    = repository.JobTaskRepository
+	JobTaskLogRepository = repository.JobTaskLogRepository
+	JobContextRepository = repository.JobContextRepository
+	JobBatchRepository   = repository.JobBatchRepository
+)
+
+// 数据模型
+type (
+	Task            = models.Task
+	TaskType        = models.TaskType
+	TaskConfig      = models.TaskConfig
+	Flow            = models.Flow
+	FlowInputs      = models.FlowInputs
+	FlowTask        = models.FlowTask
+	Job             = models.Job
+	JobStatus       = models.JobStatus
+	JobTask         = models.JobTask
+	JobTaskStatus   = models.JobTaskStatus
+	TaskResult      = models.TaskResult
+	JobTaskLog      = models.JobTaskLog
+	JobBatch        = models.JobBatch
+	JobBatchItem    = models.JobBatchItem
+	JobBatchSummary = models.JobBatchSummary
+	TaskReuse       = models.TaskReuse
+)
+
+// 列表查询
+type (
+	ListQuery  = models.ListQuery
+	Page       = models.Page
+	TaskFilter = models.TaskFilter
+	FlowFilter = models.FlowFilter
+	JobFilter  = models.JobFilter
+)
+
+// ContextScopeShared 作业上下文的共享作用域：作业输入及显式写入的共享数据
+const ContextScopeShared = models.ContextScopeShared
+
+// TaskContextScope 返回任务输出所在的作业上下文作用域
+func TaskContextScope(taskName string) string {
+	return models.TaskContextScope(taskName)
+}
+
+// ParseTaskContextScope 解析任务输出作用域，返回任务名称
+func ParseTaskContextScope(scope string) (string, bool) {
+	return models.ParseTaskContextScope(scope)
+}
+
+// 任务类型
+const (
+	TaskTypeManual    = models.TaskTypeManual
+	TaskTypeAutomated = models.TaskTypeAutomated
+	TaskTypeApproval  = models.TaskTypeApproval
+)
+
+// 作业状态
+const (
+	JobStatusPending   = models.JobStatusPending
+	JobStatusRunning   = models.JobStatusRunning
+	JobStatusCompleted = models.JobStatusCompleted
+	JobStatusFailed    = models.JobStatusFailed
+	JobStatusCancelled = models.JobStatusCancelled
+)
+
+// 作业任务状态
+const (
+	JobTaskStatusPending    = models.JobTaskStatusPending
+	JobTaskStatusRunning    = models.JobTaskStatusRunning
+	JobTaskStatusCompleted  = models.JobTaskStatusCompleted
+	JobTaskStatusFailed     = models.JobTaskStatusFailed
+	JobTaskStatusSkipped    = models.JobTaskStatusSkipped
+	JobTaskStatusRolledBack = models.JobTaskStatusRolledBack
+)
+
+// 执行器
+type (
+	Executor    = executor.Executor
+	JobContext  = executor.JobContext
+	TaskOutputs = executor.TaskOutputs
+)
+
+// 执行器契约：实现 Describer 的执行器声明参数与输出，执行前后据此校验
+type (
+	Describer     = executor.Describer
+	Descriptor    = executor.Descriptor
+	ParamSpec     = executor.ParamSpec
+	ParamType     = executor.ParamType
+	OutputSpec    = executor.OutputSpec
+	ContractError = executor.ContractError
+)
+
+// 参数类型
+const (
+	ParamTypeString  = executor.ParamTypeString
+	ParamTypeInteger = executor.ParamTypeInteger
+	ParamTypeNumber  = executor.ParamTypeNumber
+	ParamTypeBoolean = executor.ParamTypeBoolean
+	ParamTypeObject  = executor.ParamTypeObject
+	ParamTypeArray   = executor.ParamTypeArray
+	ParamTypeAny     = executor.ParamTypeAny
+)
+
+// RegisterExecutor 注册全局执行器，任务配置的 executor 字段按名称引用
+func RegisterExecutor(e Executor) {
+	executor.RegisterExecutor(e)
+}
+
+// 状态变更事件
+type (
+	EventBus     = events.Bus
+	Event        = events.Event
+	EventType    = events.Type
+	EventFilter  = events.Filter
+	Subscription = events.Subscription
+)
+
+// NewEventBus 创建事件总线，通过 WithEventBus 传入后可订阅作业与任务状态变更
+func NewEventBus() *EventBus {
+	return events.NewBus()
+}
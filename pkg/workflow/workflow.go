@@ -0,0 +1,162 @@
+// Package workflow 将工作流引擎嵌入其他 Go 程序运行，默认使用内存仓储，也可用于不依赖 MySQL 的流程单元测试
+//
+//	wf := workflow.New(workflow.WithSynchronousExecution())
+//	workflow.RegisterExecutor(greeter)
+//	task := &workflow.Task{Name: "greet", TaskType: workflow.TaskTypeAutomated, Config: workflow.TaskConfig{"executor": "greeter"}, IsActive: true}
+//	wf.Tasks.Create(task)
+//	flow := &workflow.Flow{Name: "hello", Version: "1.0", IsActive: true}
+//	wf.Flows.Create(flow)
+//	wf.FlowTasks.Create(&workflow.FlowTask{FlowID: flow.ID, TaskID: task.ID, Sequence: 1})
+//	job, err := wf.Engine.CreateJob(flow.ID, "demo", 0)
+//	err = wf.ExecuteJob(ctx, job.ID)
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cfrs2005/GoWorkFlow/internal/engine"
+	"github.com/cfrs2005/GoWorkFlow/internal/repository"
+	"github.com/cfrs2005/GoWorkFlow/internal/service"
+	"github.com/cfrs2005/GoWorkFlow/pkg/logger"
+)
+
+// backgroundJobTimeout 后台执行单个作业的最长时间
+const backgroundJobTimeout = 30 * time.Minute
+
+// Repositories 引擎与任务执行服务使用的仓储
+type Repositories struct {
+	Tasks       TaskRepository
+	Flows       FlowRepository
+	FlowTasks   FlowTaskRepository
+	Jobs        JobRepository
+	JobTasks    JobTaskRepository
+	JobTaskLogs JobTaskLogRepository
+	JobContexts JobContextRepository
+	JobBatches  JobBatchRepository
+}
+
+// NewMemoryRepositories 创建共享同一份内存数据的仓储，数据随进程退出丢失
+func NewMemoryRepositories() Repositories {
+	store := repository.NewMemoryStore()
+	return Repositories{
+		Tasks:       repository.NewMemoryTaskRepository(store),
+		Flows:       repository.NewMemoryFlowRepository(store),
+		FlowTasks:   repository.NewMemoryFlowTaskRepository(store),
+		Jobs:        repository.NewMemoryJobRepository(store),
+		JobTasks:    repository.NewMemoryJobTaskRepository(store),
+		JobTaskLogs: repository.NewMemoryJobTaskLogRepository(store),
+		JobContexts: repository.NewMemoryJobContextRepository(store),
+		JobBatches:  repository.NewMemoryJobBatchRepository(store),
+	}
+}
+
+// NewSQLRepositories 创建基于数据库的仓储（MySQL、SQLite 或 PostgreSQL，表结构须已迁移）
+func NewSQLRepositories(db *sql.DB) Repositories {
+	return Repositories{
+		Tasks:       repository.NewTaskRepository(db),
+		Flows:       repository.NewFlowRepository(db),
+		FlowTasks:   repository.NewFlowTaskRepository(db),
+		Jobs:        repository.NewJobRepository(db),
+		JobTasks:    repository.NewJobTaskRepository(db),
+		JobTaskLogs: repository.NewJobTaskLogRepository(db),
+		JobContexts: repository.NewJobContextRepository(db),
+		JobBatches:  repository.NewJobBatchRepository(db),
+	}
+}
+
+// NewWorkflowEngine 创建工作流引擎；使用内存仓储时 db 传 nil，bus 为 nil 时不发布状态变更事件
+func NewWorkflowEngine(db *sql.DB, repos Repositories, bus *EventBus) WorkflowEngine {
+	return engine.NewWorkflowEngine(
+		db,
+		repos.Jobs,
+		repos.JobTasks,
+		repos.JobTaskLogs,
+		repos.Flows,
+		repos.FlowTasks,
+		bus,
+		repos.JobContexts,
+		repos.JobBatches,
+	)
+}
+
+// NewTaskExecutorService 创建任务执行服务；嵌入模式下不校验权限与项目执行器白名单，不记录制品、执行日志与指标
+func NewTaskExecutorService(repos Repositories, workflowEngine WorkflowEngine) *TaskExecutorService {
+	return service.NewTaskExecutorService(
+		repos.Jobs,
+		repos.JobTasks,
+		repos.JobContexts,
+		repos.Tasks,
+		repos.FlowTasks,
+		workflowEngine,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// Runtime 嵌入式运行的工作流：仓储、引擎与任务执行服务
+type Runtime struct {
+	Repositories
+	Engine   WorkflowEngine
+	Executor *TaskExecutorService
+
+	db          *sql.DB
+	bus         *EventBus
+	synchronous bool
+}
+
+// Option 运行选项
+type Option func(*Runtime)
+
+// WithDB 使用数据库存储（表结构须已迁移），默认使用内存仓储
+func WithDB(db *sql.DB) Option {
+	return func(r *Runtime) { r.db = db }
+}
+
+// WithEventBus 将作业与任务状态变更事件发布到 bus
+func WithEventBus(bus *EventBus) Option {
+	return func(r *Runtime) { r.bus = bus }
+}
+
+// WithSynchronousExecution ExecuteJob 在调用方 goroutine 中执行作业，返回时作业已结束或正等待人工处理
+func WithSynchronousExecution() Option {
+	return func(r *Runtime) { r.synchronous = true }
+}
+
+// New 创建嵌入式运行的工作流
+func New(opts ...Option) *Runtime {
+	r := &Runtime{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.db != nil {
+		r.Repositories = NewSQLRepositories(r.db)
+	} else {
+		r.Repositories = NewMemoryRepositories()
+	}
+	r.Engine = NewWorkflowEngine(r.db, r.Repositories, r.bus)
+	r.Executor = NewTaskExecutorService(r.Repositories, r.Engine)
+	return r
+}
+
+// ExecuteJob 自动执行作业的自动化任务。同步执行时连续执行到作业结束，或下一个任务为人工、审批任务时返回，
+// 返回任务执行错误；否则在后台执行（遇到人工、审批任务时等待其处理完成，最长 30 分钟）并立即返回，执行错误写入日志
+func (r *Runtime) ExecuteJob(ctx context.Context, jobID int64) error {
+	if r.synchronous {
+		return r.Executor.ExecuteJobTasks(ctx, jobID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundJobTimeout)
+	go func() {
+		defer cancel()
+		if err := r.Executor.AutoExecuteJobTasks(ctx, jobID); err != nil {
+			logger.Ctx(ctx).Errorf("Auto execution failed for job %d: %v", jobID, err)
+		}
+	}()
+	return nil
+}
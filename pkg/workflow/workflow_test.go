@@ -0,0 +1,151 @@
+package workflow_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/cfrs2005/GoWorkFlow/pkg/workflow"
+)
+
+type funcExecutor struct {
+	name string
+	fn   func(input map[string]interface{}) (map[string]interface{}, error)
+}
+
+func (e funcExecutor) Name() string { return e.name }
+
+func (e funcExecutor) Execute(ctx context.Context, input map[string]interface{}, jobContext *workflow.JobContext) (map[string]interface{}, error) {
+	return e.fn(input)
+}
+
+func init() {
+	workflow.RegisterExecutor(funcExecutor{name: "workflow_test.ok", fn: func(input map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"ok": true}, nil
+	}})
+	workflow.RegisterExecutor(funcExecutor{name: "workflow_test.fail", fn: func(input map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("disk full")
+	}})
+}
+
+type step struct {
+	taskType workflow.TaskType
+	executor string
+}
+
+// createFlow 创建按 steps 顺序执行的流程
+func createFlow(t *testing.T, wf *workflow.Runtime, steps []step) *workflow.Flow {
+	t.Helper()
+	flow := &workflow.Flow{Name: t.Name(), Version: "1.0", IsActive: true}
+	if err := wf.Flows.Create(flow); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range steps {
+		task := &workflow.Task{Name: string(s.taskType) + "-" + s.executor, TaskType: s.taskType, IsActive: true}
+		if s.executor != "" {
+			task.Config = workflow.TaskConfig{"executor": s.executor}
+		}
+		if err := wf.Tasks.Create(task); err != nil {
+			t.Fatal(err)
+		}
+		if err := wf.FlowTasks.Create(&workflow.FlowTask{FlowID: flow.ID, TaskID: task.ID, Sequence: i + 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return flow
+}
+
+func TestExecuteJobSynchronously(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []step
+		wantErr   bool
+		wantJob   workflow.JobStatus
+		wantTasks []workflow.JobTaskStatus
+	}{
+		{
+			name:      "completes",
+			steps:     []step{{workflow.TaskTypeAutomated, "workflow_test.ok"}, {workflow.TaskTypeAutomated, "workflow_test.ok"}},
+			wantJob:   workflow.JobStatusCompleted,
+			wantTasks: []workflow.JobTaskStatus{workflow.JobTaskStatusCompleted, workflow.JobTaskStatusCompleted},
+		},
+		{
+			name:      "stops at approval",
+			steps:     []step{{workflow.TaskTypeAutomated, "workflow_test.ok"}, {workflow.TaskTypeApproval, ""}, {workflow.TaskTypeAutomated, "workflow_test.ok"}},
+			wantJob:   workflow.JobStatusRunning,
+			wantTasks: []workflow.JobTaskStatus{workflow.JobTaskStatusCompleted, workflow.JobTaskStatusPending, workflow.JobTaskStatusPending},
+		},
+		{
+			name:      "failing executor",
+			steps:     []step{{workflow.TaskTypeAutomated, "workflow_test.ok"}, {workflow.TaskTypeAutomated, "workflow_test.fail"}, {workflow.TaskTypeAutomated, "workflow_test.ok"}},
+			wantErr:   true,
+			wantJob:   workflow.JobStatusFailed,
+			wantTasks: []workflow.JobTaskStatus{workflow.JobTaskStatusCompleted, workflow.JobTaskStatusFailed, workflow.JobTaskStatusPending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := workflow.New(workflow.WithSynchronousExecution())
+			flow := createFlow(t, wf, tt.steps)
+			job, err := wf.Engine.CreateJob(flow.ID, tt.name, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := wf.ExecuteJob(context.Background(), job.ID); (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			job, err = wf.Jobs.GetByID(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.Status != tt.wantJob {
+				t.Fatalf("job status = %s, want %s", job.Status, tt.wantJob)
+			}
+			jobTasks, err := wf.JobTasks.GetByJobID(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(jobTasks) != len(tt.wantTasks) {
+				t.Fatalf("got %d job tasks, want %d", len(jobTasks), len(tt.wantTasks))
+			}
+			for i, jobTask := range jobTasks {
+				if jobTask.Status != tt.wantTasks[i] {
+					t.Errorf("job task %d status = %s, want %s", jobTask.Sequence, jobTask.Status, tt.wantTasks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRerunJobRollsBackOnError(t *testing.T) {
+	wf := workflow.New(workflow.WithSynchronousExecution())
+	flow := createFlow(t, wf, []step{{workflow.TaskTypeAutomated, "workflow_test.ok"}})
+	source, err := wf.Engine.CreateJob(flow.ID, "source", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rerun := &workflow.Job{FlowID: flow.ID, JobName: "rerun", RerunOf: sql.NullInt64{Int64: source.ID, Valid: true}}
+	err = wf.Engine.RerunJob(rerun, map[string]interface{}{"region": "cn"}, []workflow.TaskReuse{{Sequence: 9}})
+	if err == nil {
+		t.Fatal("RerunJob() with unknown sequence succeeded")
+	}
+
+	if _, err := wf.Jobs.GetByID(rerun.ID); err == nil {
+		t.Fatalf("job %d was kept after RerunJob failed", rerun.ID)
+	}
+	jobTasks, err := wf.JobTasks.GetByJobID(rerun.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobContext, err := wf.JobContexts.GetByJobID(rerun.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobTasks) != 0 || len(jobContext) != 0 {
+		t.Fatalf("RerunJob() left %d job tasks and context %v", len(jobTasks), jobContext)
+	}
+}
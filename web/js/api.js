@@ -1,14 +1,56 @@
 // API Client for GoWorkFlow
 
 const API_BASE = '/api';
+const TOKEN_KEY = 'gwf_token';
+const PROJECT_KEY = 'gwf_project';
 
 class APIClient {
+    // 认证凭据（API Key 或 JWT），服务端启用认证时需要
+    getToken() {
+        return localStorage.getItem(TOKEN_KEY) || '';
+    }
+
+    setToken(token) {
+        if (token) {
+            localStorage.setItem(TOKEN_KEY, token);
+        } else {
+            localStorage.removeItem(TOKEN_KEY);
+        }
+    }
+
+    // 当前项目（ID 或标识），为空时服务端使用默认项目
+    getProject() {
+        return localStorage.getItem(PROJECT_KEY) || '';
+    }
+
+    setProject(project) {
+        if (project) {
+            localStorage.setItem(PROJECT_KEY, project);
+        } else {
+            localStorage.removeItem(PROJECT_KEY);
+        }
+    }
+
+    headers() {
+        const headers = { 'Content-Type': 'application/json' };
+        const token = this.getToken();
+        if (token) headers['Authorization'] = `Bearer ${token}`;
+        const project = this.getProject();
+        if (project) headers['X-Project-ID'] = project;
+        return headers;
+    }
+
+    // EventSource 无法设置请求头，凭据通过 access_token 查询参数传递
+    withToken(url) {
+        const token = this.getToken();
+        if (!token) return url;
+        return `${url}${url.includes('?') ? '&' : '?'}access_token=${encodeURIComponent(token)}`;
+    }
+
     async request(method, url, data = null) {
         const options = {
             method,
-            headers: {
-                'Content-Type': 'application/json',
-            },
+            headers: this.headers(),
         };
 
         if (data) {
@@ -18,6 +60,14 @@ class APIClient {
         try {
             const response = await fetch(`${API_BASE}${url}`, options);
 
+            if (response.status === 401) {
+                const token = window.prompt('请输入 API Key 或访问令牌');
+                if (token) {
+                    this.setToken(token.trim());
+                    return this.request(method, url, data);
+                }
+            }
+
             if (!response.ok) {
                 const error = await response.json();
                 throw new Error(error.message || 'API request failed');
@@ -122,6 +172,32 @@ class APIClient {
     async updateJobContext(jobId, context) {
         return this.request('PUT', `/jobs/${jobId}/context`, context);
     }
+
+    // Execution Logs
+    async getExecutionLogs(jobTaskId, afterId = 0) {
+        return this.request('GET', `/job-tasks/${jobTaskId}/execution-logs?after_id=${afterId}`);
+    }
+
+    executionLogStreamURL(jobTaskId) {
+        const project = this.getProject();
+        const query = project ? `?project_id=${encodeURIComponent(project)}` : '';
+        return this.withToken(`${API_BASE}/job-tasks/${jobTaskId}/execution-logs/stream${query}`);
+    }
+
+    // Projects
+    async getProjects() {
+        return this.request('GET', '/projects');
+    }
+
+    // Events
+    eventStreamURL(filter = {}) {
+        const params = new URLSearchParams();
+        if (filter.jobId) params.set('job_id', filter.jobId);
+        if (filter.flowId) params.set('flow_id', filter.flowId);
+        if (filter.types) params.set('types', filter.types.join(','));
+        const query = params.toString();
+        return this.withToken(`${API_BASE}/events${query ? '?' + query : ''}`);
+    }
 }
 
 // Global API instance
@@ -34,7 +34,10 @@ function appData() {
             // Load initial content
             await this.loadPageContent(this.currentPage);
 
-            // Auto-refresh every 30 seconds for dashboard and jobs
+            // Refresh dashboard and jobs as soon as job state changes
+            this.subscribeEvents();
+
+            // Fallback auto-refresh every 30 seconds for dashboard and jobs
             setInterval(() => {
                 if (this.currentPage === 'dashboard' || this.currentPage === 'jobs') {
                     this.refreshData();
@@ -42,6 +45,29 @@ function appData() {
             }, 30000);
         },
 
+        // Subscribe to job / job task state changes pushed by the server
+        subscribeEvents() {
+            if (!window.EventSource) return;
+
+            let refreshTimer = null;
+            const stream = new EventSource(api.eventStreamURL());
+            const onEvent = () => {
+                if (this.currentPage !== 'dashboard' && this.currentPage !== 'jobs') return;
+                if (this.currentPage === 'jobs' && !jobsData.autoRefresh) return;
+
+                // Coalesce bursts of events (e.g. task completed + job completed) into one refresh
+                clearTimeout(refreshTimer);
+                refreshTimer = setTimeout(() => this.refreshData(), 300);
+            };
+
+            [
+                'job.created', 'job.started', 'job.completed', 'job.failed', 'job.cancelled',
+                'job_task.started', 'job_task.completed', 'job_task.failed', 'job_task.skipped',
+                'job_task.rolled_back', 'job_task.retried', 'job_task.reassigned',
+                'approval.required',
+            ].forEach(type => stream.addEventListener(type, onEvent));
+        },
+
         // Load page content
         async loadPageContent(page) {
             this.loading = true;
@@ -0,0 +1,221 @@
+// API Explorer：读取 /api/openapi.json，按分组列出接口并支持在页面中直接调用
+
+const SPEC_URL = '/api/openapi.json';
+const TOKEN_KEY = 'gwf_token';
+const PROJECT_KEY = 'gwf_project';
+const METHODS = ['get', 'post', 'put', 'delete'];
+
+let spec = null;
+
+function el(tag, attrs = {}, ...children) {
+    const node = document.createElement(tag);
+    for (const [key, value] of Object.entries(attrs)) {
+        if (key === 'class') node.className = value;
+        else if (key.startsWith('on')) node.addEventListener(key.slice(2), value);
+        else node.setAttribute(key, value);
+    }
+    for (const child of children) {
+        if (child == null) continue;
+        node.append(child instanceof Node ? child : document.createTextNode(String(child)));
+    }
+    return node;
+}
+
+// resolve 展开 $ref 引用
+function resolve(schema) {
+    if (schema && schema.$ref) {
+        const name = schema.$ref.split('/').pop();
+        return spec.components.schemas[name] || {};
+    }
+    return schema || {};
+}
+
+// example 根据结构生成示例值，用于预填请求体与展示响应格式
+function example(schema, depth = 0) {
+    schema = resolve(schema);
+    if (depth > 4) return null;
+    if (schema.allOf) {
+        return schema.allOf.reduce((acc, s) => Object.assign(acc, example(s, depth + 1)), {});
+    }
+    switch (schema.type) {
+        case 'object': {
+            const obj = {};
+            for (const [name, prop] of Object.entries(schema.properties || {})) {
+                obj[name] = example(prop, depth + 1);
+            }
+            return obj;
+        }
+        case 'array':
+            return [example(schema.items, depth + 1)];
+        case 'integer':
+        case 'number':
+            return 0;
+        case 'boolean':
+            return false;
+        case 'string':
+            return schema.format === 'date-time' ? new Date(0).toISOString() : '';
+        default:
+            return null;
+    }
+}
+
+function pretty(value) {
+    return JSON.stringify(value, null, 2);
+}
+
+function requestHeaders() {
+    const headers = { 'Content-Type': 'application/json' };
+    const token = document.getElementById('token').value.trim();
+    if (token) headers['Authorization'] = `Bearer ${token}`;
+    const project = document.getElementById('project').value.trim();
+    if (project) headers['X-Project-ID'] = project;
+    return headers;
+}
+
+async function send(method, path, op, inputs, bodyInput, output) {
+    let url = path;
+    const query = new URLSearchParams();
+    for (const param of op.parameters || []) {
+        const value = inputs[param.name].value.trim();
+        if (param.in === 'path') {
+            if (!value) {
+                output.replaceChildren(el('p', { class: 'status' }, `缺少路径参数 ${param.name}`));
+                return;
+            }
+            url = url.replace(`{${param.name}}`, encodeURIComponent(value));
+        } else if (param.in === 'query' && value) {
+            query.set(param.name, value);
+        }
+    }
+    url = spec.servers[0].url + url + (query.toString() ? `?${query}` : '');
+
+    const headers = requestHeaders();
+    const projectParam = (op.parameters || []).find(p => p.in === 'header');
+    if (projectParam && inputs[projectParam.name].value.trim()) {
+        headers[projectParam.name] = inputs[projectParam.name].value.trim();
+    }
+
+    const options = { method: method.toUpperCase(), headers };
+    if (bodyInput && bodyInput.value.trim()) {
+        options.body = bodyInput.value;
+    }
+
+    output.replaceChildren(el('p', { class: 'muted' }, '请求中...'));
+    try {
+        const resp = await fetch(url, options);
+        const text = await resp.text();
+        let body = text;
+        try {
+            body = pretty(JSON.parse(text));
+        } catch (e) {
+            // 非 JSON 响应原样展示
+        }
+        output.replaceChildren(
+            el('p', { class: 'status' }, `${resp.status} ${resp.statusText}  ${options.method} ${url}`),
+            el('pre', {}, body),
+        );
+    } catch (err) {
+        output.replaceChildren(el('p', { class: 'status' }, `请求失败：${err.message}`));
+    }
+}
+
+function renderOperation(method, path, op) {
+    const inputs = {};
+    const body = el('div', { class: 'op-body' });
+
+    if (op.description) body.append(el('p', { class: 'muted' }, op.description));
+
+    if ((op.parameters || []).length > 0) {
+        const table = el('table');
+        for (const param of op.parameters) {
+            const input = el('input', { placeholder: param.schema.type });
+            if (param.name === 'X-Project-ID') input.placeholder = '使用页面顶部的项目';
+            inputs[param.name] = input;
+            table.append(el('tr', {},
+                el('td', {}, param.name, param.required ? ' *' : ''),
+                el('td', {}, param.in),
+                el('td', {}, param.description || ''),
+                el('td', {}, input),
+            ));
+        }
+        body.append(el('h3', {}, '参数'), table);
+    }
+
+    let bodyInput = null;
+    if (op.requestBody) {
+        const schema = op.requestBody.content['application/json'].schema;
+        bodyInput = el('textarea');
+        bodyInput.value = pretty(example(schema));
+        body.append(el('h3', {}, op.requestBody.required ? '请求体 *' : '请求体（可省略）'), bodyInput);
+    }
+
+    for (const [status, resp] of Object.entries(op.responses)) {
+        if (status === 'default') continue;
+        const [type, media] = Object.entries(resp.content || {})[0] || [];
+        const sample = type === 'application/json' ? pretty(example(media.schema)) : type;
+        body.append(el('h3', {}, `响应 ${status}`), el('pre', {}, sample));
+    }
+
+    const output = el('div');
+    body.append(
+        el('button', { onclick: () => send(method, path, op, inputs, bodyInput, output) }, '发送请求'),
+        output,
+    );
+
+    return el('details', { class: 'op' },
+        el('summary', {},
+            el('span', { class: `method ${method}` }, method.toUpperCase()),
+            el('span', { class: 'path' }, path),
+            el('span', { class: 'summary' }, op.summary || ''),
+        ),
+        body,
+    );
+}
+
+function render() {
+    document.getElementById('title').textContent = `${spec.info.title} ${spec.info.version}`;
+    document.getElementById('description').textContent = spec.info.description || '';
+
+    const groups = new Map();
+    for (const [path, item] of Object.entries(spec.paths)) {
+        for (const method of METHODS) {
+            const op = item[method];
+            if (!op) continue;
+            const tag = (op.tags || ['other'])[0];
+            if (!groups.has(tag)) groups.set(tag, []);
+            groups.get(tag).push([method, path, op]);
+        }
+    }
+
+    const nav = document.getElementById('tags');
+    const container = document.getElementById('operations');
+    container.replaceChildren();
+    for (const [tag, ops] of groups) {
+        ops.sort((a, b) => a[1].localeCompare(b[1]) || METHODS.indexOf(a[0]) - METHODS.indexOf(b[0]));
+        nav.append(el('a', { href: `#tag-${tag}` }, `${tag} (${ops.length})`));
+        container.append(el('h2', { id: `tag-${tag}` }, tag));
+        for (const [method, path, op] of ops) {
+            container.append(renderOperation(method, path, op));
+        }
+    }
+}
+
+async function init() {
+    // 与控制台共用凭据与项目设置
+    const token = document.getElementById('token');
+    const project = document.getElementById('project');
+    token.value = localStorage.getItem(TOKEN_KEY) || '';
+    project.value = localStorage.getItem(PROJECT_KEY) || '';
+    token.addEventListener('change', () => localStorage.setItem(TOKEN_KEY, token.value.trim()));
+    project.addEventListener('change', () => localStorage.setItem(PROJECT_KEY, project.value.trim()));
+
+    try {
+        const resp = await fetch(SPEC_URL);
+        spec = await resp.json();
+        render();
+    } catch (err) {
+        document.getElementById('operations').replaceChildren(el('p', { class: 'status' }, `加载 ${SPEC_URL} 失败：${err.message}`));
+    }
+}
+
+init();
@@ -353,7 +353,7 @@ async function runYouTubeAnalysis(flowId) {
         // 自动执行作业
         const response = await fetch('/api/jobs/auto-execute', {
             method: 'POST',
-            headers: { 'Content-Type': 'application/json' },
+            headers: api.headers(),
             body: JSON.stringify({ job_id: job.data.id }),
         });
 
@@ -5,6 +5,7 @@ let jobsData = {
     selectedJob: null,
     jobTasks: [],
     autoRefresh: true,
+    logStream: null,
 };
 
 async function loadJobs() {
@@ -137,7 +138,8 @@ function renderJobRow(job) {
 async function viewJobDetails(jobId) {
     try {
         const response = await api.getJob(jobId);
-        jobsData.selectedJob = response.data;
+        jobsData.selectedJob = response.data.job || response.data;
+        jobsData.jobTasks = response.data.job_tasks || [];
 
         const content = document.getElementById('jobDetailsContent');
         content.innerHTML = `
@@ -215,6 +217,47 @@ async function viewJobDetails(jobId) {
                         ` : ''}
                     </div>
                 </div>
+
+                <!-- Job Tasks -->
+                ${jobsData.jobTasks.length > 0 ? `
+                    <div>
+                        <label class="text-sm text-gray-600 font-semibold mb-2 block">作业任务</label>
+                        <div class="table-container">
+                            <table>
+                                <thead>
+                                    <tr>
+                                        <th>序号</th>
+                                        <th>任务</th>
+                                        <th>状态</th>
+                                        <th>操作</th>
+                                    </tr>
+                                </thead>
+                                <tbody>
+                                    ${jobsData.jobTasks.map(jt => `
+                                        <tr>
+                                            <td>${jt.sequence}</td>
+                                            <td>${jt.task ? jt.task.name : '#' + jt.task_id}</td>
+                                            <td><span class="${getStatusClass(jt.status)}">${formatStatus(jt.status)}</span></td>
+                                            <td>
+                                                <button onclick="followTaskLog(${jt.id})" class="btn btn-sm btn-secondary">
+                                                    ${jt.status === 'running' || jt.status === 'pending' ? '跟踪日志' : '查看日志'}
+                                                </button>
+                                            </td>
+                                        </tr>
+                                    `).join('')}
+                                </tbody>
+                            </table>
+                        </div>
+                    </div>
+                ` : ''}
+
+                <!-- Execution Log -->
+                <div id="taskLogPanel" style="display: none;">
+                    <label class="text-sm text-gray-600 font-semibold mb-2 block">
+                        执行日志 <span id="taskLogStatus" class="text-xs text-gray-500"></span>
+                    </label>
+                    <pre id="taskLogOutput" class="bg-gray-50 p-3 rounded text-xs overflow-x-auto" style="max-height: 300px; overflow-y: auto;"></pre>
+                </div>
             </div>
         `;
 
@@ -225,9 +268,54 @@ async function viewJobDetails(jobId) {
 }
 
 function closeJobDetailsModal() {
+    stopTaskLogStream();
     document.getElementById('jobDetailsModal').style.display = 'none';
 }
 
+// Follow a job task's execution log via Server-Sent Events
+function followTaskLog(jobTaskId) {
+    stopTaskLogStream();
+
+    const panel = document.getElementById('taskLogPanel');
+    const output = document.getElementById('taskLogOutput');
+    const status = document.getElementById('taskLogStatus');
+    panel.style.display = 'block';
+    output.textContent = '';
+    status.textContent = `作业任务 #${jobTaskId} · 连接中...`;
+
+    const stream = new EventSource(api.executionLogStreamURL(jobTaskId));
+    jobsData.logStream = stream;
+
+    stream.onopen = () => {
+        status.textContent = `作业任务 #${jobTaskId} · 跟踪中`;
+    };
+
+    stream.addEventListener('log', (event) => {
+        const line = JSON.parse(event.data);
+        const time = new Date(line.created_at).toLocaleTimeString('zh-CN');
+        output.textContent += `[${time}] ${line.level.toUpperCase()} ${line.message}\n`;
+        output.scrollTop = output.scrollHeight;
+    });
+
+    stream.addEventListener('end', (event) => {
+        const data = JSON.parse(event.data);
+        status.textContent = `作业任务 #${jobTaskId} · ${formatStatus(data.status)}`;
+        stopTaskLogStream();
+    });
+
+    stream.onerror = () => {
+        // EventSource 会自动重连并携带 Last-Event-ID，这里只更新状态
+        status.textContent = `作业任务 #${jobTaskId} · 重新连接中...`;
+    };
+}
+
+function stopTaskLogStream() {
+    if (jobsData.logStream) {
+        jobsData.logStream.close();
+        jobsData.logStream = null;
+    }
+}
+
 async function startJobNow(jobId) {
     if (!confirm(`确定启动作业 #${jobId} 吗？`)) return;
 